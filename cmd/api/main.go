@@ -10,6 +10,7 @@ import (
 	"hr-system/internal/handlers"
 	"hr-system/internal/jobs"
 	"hr-system/internal/middleware"
+	"hr-system/internal/models"
 	"hr-system/internal/repositories"
 	"hr-system/internal/repository"
 	"hr-system/internal/routes"
@@ -19,6 +20,7 @@ import (
 
 func main() {
 	cfg := config.Load()
+	leaveYear := models.LeaveYear{StartMonth: cfg.Leave.YearStartMonth}
 
 	connStr := fmt.Sprintf(
 		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
@@ -44,7 +46,11 @@ func main() {
 	lbRepo := repository.NewLeaveBalanceRepository()
 	lrRepo := repository.NewLeaveRequestRepository()
 	holidayRepo := repository.NewHolidayRepository()
+	blackoutRepo := repository.NewLeaveBlackoutRepository()
 	attRepo := repository.NewAttendanceRepository()
+	loanRepo := repository.NewLoanRepository()
+	encashmentRepo := repository.NewLeaveEncashmentRepository()
+	leaveSettingsRepo := repository.NewLeaveSettingsRepository()
 
 	// Workflow Repositories
 	workflowRepo := repository.NewWorkflowRepository()
@@ -64,7 +70,7 @@ func main() {
 
 	// Services — Phase 2 (create some services early for workflow dependencies)
 	ltService := services.NewLeaveTypeService(ltRepo)
-	lbService := services.NewLeaveBalanceService(lbRepo, ltRepo, empRepo)
+	lbService := services.NewLeaveBalanceService(lbRepo, ltRepo, empRepo, leaveYear)
 
 	// Password Policy Service
 	passwordPolicyService := services.NewPasswordPolicyService(passwordPolicyRepo, passwordHistoryRepo)
@@ -86,13 +92,16 @@ func main() {
 	ecService := services.NewEmergencyContactService(ecRepo, empRepo)
 
 	// Workflow Service (create after leave balance service for dependency injection)
-	// Note: LeaveRequestRepo, LeaveBalanceService, and EmailService are passed to enable full workflow functionality
-	workflowService := services.NewWorkflowService(workflowRepo, instanceRepo, taskRepo, historyRepo, userRepo, empRepo, lrRepo, lbService, emailService)
+	// Note: LeaveRequestRepo, LoanRepo, LeaveEncashmentRepo, LeaveBalanceService, and EmailService are passed to enable full workflow functionality
+	workflowService := services.NewWorkflowService(workflowRepo, instanceRepo, taskRepo, historyRepo, userRepo, empRepo, lrRepo, loanRepo, encashmentRepo, lbService, emailService)
 
 	// Services — Phase 2 (continued)
-	lrService := services.NewLeaveRequestService(lrRepo, lbService, ltRepo, holidayRepo, empRepo, workflowService)
+	lrService := services.NewLeaveRequestService(lrRepo, lbService, ltRepo, holidayRepo, blackoutRepo, deptRepo, empRepo, leaveSettingsRepo, workflowService, cfg.Leave)
 	holidayService := services.NewHolidayService(holidayRepo)
+	blackoutService := services.NewLeaveBlackoutService(blackoutRepo, deptRepo)
 	attService := services.NewAttendanceService(attRepo, holidayRepo, empRepo)
+	leaveDocService := services.NewLeaveDocumentService(lrRepo, ltRepo, lbService, empRepo, userRepo, emailService, cfg.Leave)
+	carryForwardService := services.NewLeaveCarryForwardService(lbRepo, lrRepo, holidayRepo, empRepo, emailService, cfg.Leave)
 
 	// Seed predefined roles
 	if err := roleService.InitializePredefinedRoles(); err != nil {
@@ -114,6 +123,17 @@ func main() {
 		log.Println("Leave types initialized")
 	}
 
+	// Move leave data onto the leave year start before anything reads balances by year
+	if report, err := lrService.SyncLeaveYear(); err != nil {
+		log.Fatalf("Failed to apply leave year start: %v", err)
+	} else if report != nil {
+		log.Printf("Leave year start changed from %s to %s: %d leave requests moved, %d failed",
+			report.PreviousStartMonth, report.StartMonth, len(report.Moved), len(report.Failed))
+		for _, f := range report.Failed {
+			log.Printf("Warning: leave request %s not moved: %s", f.LeaveRequestID, f.Error)
+		}
+	}
+
 	// Handlers — Phase 1
 	authHandler := handlers.NewAuthHandler(userService)
 	userHandler := handlers.NewUserHandler(userService)
@@ -129,21 +149,79 @@ func main() {
 	lbHandler := handlers.NewLeaveBalanceHandler(lbService, empService)
 	lrHandler := handlers.NewLeaveRequestHandler(lrService, empService)
 	holidayHandler := handlers.NewHolidayHandler(holidayService)
+	blackoutHandler := handlers.NewLeaveBlackoutHandler(blackoutService)
+	leaveDocHandler := handlers.NewLeaveDocumentHandler(leaveDocService)
 	attHandler := handlers.NewAttendanceHandler(attService, empService)
 
+	// Overtime
+	overtimeRuleRepo := repository.NewOvertimeRuleRepository()
+	overtimeService := services.NewOvertimeService(overtimeRuleRepo, attRepo, holidayRepo)
+	overtimeHandler := handlers.NewOvertimeHandler(overtimeService)
+
 	// Payslip
 	payslipRepo := repository.NewPayslipRepository()
-	payslipService := services.NewPayslipService(payslipRepo, empRepo, posRepo, lbRepo)
-	payslipHandler := handlers.NewPayslipHandler(payslipService)
+	payInputRepo := repository.NewPayInputRepository()
+	retroPayRepo := repository.NewRetroPayRepository()
+	settlementRepo := repository.NewFinalSettlementRepository()
+	payslipService := services.NewPayslipService(payslipRepo, empRepo, posRepo, encashmentRepo, loanRepo, payInputRepo, retroPayRepo, settlementRepo, overtimeService, cfg.Payroll.Currency)
+	payslipPDFService := services.NewPayslipPDFService(payslipRepo, empRepo, lbRepo, payInputRepo, retroPayRepo, cfg.Company, leaveYear)
+	payslipHandler := handlers.NewPayslipHandler(payslipService, payslipPDFService)
 
 	// Payroll
 	payrollRepo := repository.NewPayrollRepository()
-	payrollService := services.NewPayrollService(payrollRepo, payslipService, empRepo, emailService)
+	payrollRunItemRepo := repository.NewPayrollRunItemRepository()
+	payGroupRepo := repository.NewPayGroupRepository()
+	payrollService := services.NewPayrollService(payrollRepo, payrollRunItemRepo, payGroupRepo, payslipService, payslipPDFService, empRepo, emailService)
 	payrollHandler := handlers.NewPayrollHandler(payrollService)
+	payGroupService := services.NewPayGroupService(payGroupRepo, empRepo)
+	payGroupHandler := handlers.NewPayGroupHandler(payGroupService)
+
+	// One-off earnings and deductions
+	payInputService := services.NewPayInputService(payInputRepo, empRepo, posRepo, payslipRepo)
+	payInputHandler := handlers.NewPayInputHandler(payInputService)
+
+	// Retroactive pay arrears
+	retroPayService := services.NewRetroPayService(retroPayRepo, payslipRepo, empRepo, payslipService)
+	retroPayHandler := handlers.NewRetroPayHandler(retroPayService)
+
+	// Final settlements for leavers
+	settlementService := services.NewFinalSettlementService(settlementRepo, empRepo, posRepo, lbRepo, ltRepo, loanRepo, payGroupRepo, payslipRepo, payslipService, cfg.Payroll, leaveYear)
+	empService.SetSettlementService(settlementService)
+	settlementHandler := handlers.NewFinalSettlementHandler(settlementService)
+
+	// Loans and salary advances
+	loanService := services.NewLoanService(loanRepo, empRepo, posRepo, workflowService)
+	loanHandler := handlers.NewLoanHandler(loanService)
+
+	// Leave encashment, paid through payroll
+	encashmentService := services.NewLeaveEncashmentService(encashmentRepo, ltRepo, lbService, empRepo, posRepo, workflowService)
+	encashmentHandler := handlers.NewLeaveEncashmentHandler(encashmentService)
+
+	// Bank accounts and payment files
+	bankAccountRepo := repository.NewBankAccountRepository()
+	bankAccountService := services.NewBankAccountService(bankAccountRepo, empRepo)
+	bankAccountHandler := handlers.NewBankAccountHandler(bankAccountService)
+	paymentBatchRepo := repository.NewPaymentBatchRepository()
+	paymentBatchService := services.NewPaymentBatchService(paymentBatchRepo, payrollRepo, payslipRepo, bankAccountRepo, empRepo, cfg.Company)
+	paymentBatchHandler := handlers.NewPaymentBatchHandler(paymentBatchService)
+
+	// General ledger
+	glMappingRepo := repository.NewGLMappingRepository()
+	glJournalService := services.NewGLJournalService(glMappingRepo, payrollRepo, payslipRepo, empRepo, deptRepo, cfg.Payroll)
+	glJournalHandler := handlers.NewGLJournalHandler(glJournalService)
+
+	// Annual tax documents
+	taxCertificateService := services.NewTaxCertificateService(payslipRepo, empRepo, posRepo, cfg.Company)
+	taxCertificateHandler := handlers.NewTaxCertificateHandler(taxCertificateService, payslipService)
+
+	// Calendar subscription feeds
+	calendarFeedRepo := repository.NewCalendarFeedRepository()
+	calendarFeedService := services.NewCalendarFeedService(calendarFeedRepo, lrRepo, holidayRepo, empRepo, cfg.Company)
+	calendarFeedHandler := handlers.NewCalendarFeedHandler(calendarFeedService)
 
 	// Dashboard
 	adminDashRepo := repository.NewAdminDashboardRepository()
-	dashboardService := services.NewDashboardService(empRepo, posRepo, deptRepo, lbRepo, lrRepo, adminDashRepo)
+	dashboardService := services.NewDashboardService(empRepo, posRepo, deptRepo, lbRepo, lrRepo, adminDashRepo, leaveYear)
 	dashboardHandler := handlers.NewDashboardHandler(dashboardService)
 
 	// Workflow Handler
@@ -156,10 +234,14 @@ func main() {
 	passwordPolicyHandler := handlers.NewPasswordPolicyHandler(passwordPolicyService, userService)
 
 	// Background jobs
-	jobs.NewMonthlyLeaveAccrualJob(empRepo, lbRepo, ltRepo).Start()
+	jobs.NewMonthlyLeaveAccrualJob(empRepo, lbRepo, ltRepo, leaveYear).Start()
 	log.Println("Monthly leave accrual job scheduled")
-	jobs.NewYearEndCarryForwardJob(lbRepo, ltRepo).Start()
+	jobs.NewYearEndCarryForwardJob(lbRepo, ltRepo, leaveYear).Start()
 	log.Println("Year-end carry-forward job scheduled")
+	jobs.NewLeaveDocumentEnforcementJob(leaveDocService).Start()
+	log.Println("Leave document enforcement job scheduled")
+	jobs.NewCarryForwardExpiryJob(carryForwardService).Start()
+	log.Println("Carry-forward expiry job scheduled")
 
 	// Register routes
 	routes.RegisterRoutes(
@@ -167,9 +249,22 @@ func main() {
 		ltHandler, lbHandler, lrHandler, attHandler, holidayHandler, dashboardHandler,
 		workflowHandler, workflowAdminHandler,
 	)
+	routes.RegisterLeaveBlackoutRoutes(blackoutHandler)
+	routes.RegisterLeaveDocumentRoutes(leaveDocHandler)
+	routes.RegisterLeaveEncashmentRoutes(encashmentHandler)
 	routes.RegisterPasswordPolicyRoutes(passwordPolicyHandler)
 	routes.RegisterPayslipRoutes(payslipHandler)
 	routes.RegisterPayrollRoutes(payrollHandler)
+	routes.RegisterPayGroupRoutes(payGroupHandler)
+	routes.RegisterPayInputRoutes(payInputHandler)
+	routes.RegisterRetroPayRoutes(retroPayHandler)
+	routes.RegisterFinalSettlementRoutes(settlementHandler)
+	routes.RegisterLoanRoutes(loanHandler)
+	routes.RegisterPaymentRoutes(bankAccountHandler, paymentBatchHandler)
+	routes.RegisterGLRoutes(glJournalHandler)
+	routes.RegisterTaxRoutes(taxCertificateHandler)
+	routes.RegisterCalendarFeedRoutes(calendarFeedHandler)
+	routes.RegisterOvertimeRoutes(overtimeHandler)
 
 	// Apply CORS middleware globally to the default mux
 	handler := middleware.CORS(http.DefaultServeMux)
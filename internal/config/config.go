@@ -2,6 +2,11 @@ package config
 
 import (
 	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"hr-system/pkg/money"
 
 	"github.com/joho/godotenv"
 )
@@ -16,6 +21,54 @@ type Config struct {
 	ServerPort string
 	JWTSecret  string
 	Email      EmailConfig
+	Company    CompanyConfig
+	Payroll    PayrollConfig
+	Leave      LeaveConfig
+}
+
+// CompanyConfig holds the branding printed on generated documents such as payslips.
+type CompanyConfig struct {
+	Name     string
+	Address  string
+	LogoPath string // optional PNG/JPEG logo
+	// Account salaries are paid from, printed on bank payment files
+	BankCode          string
+	BankAccountNumber string
+	// PayslipPassword selects the password for payslip PDFs: "" (none),
+	// "national_id" or "date_of_birth" (DDMMYYYY)
+	PayslipPassword string
+}
+
+// PayrollConfig holds payroll accounting settings.
+type PayrollConfig struct {
+	// EmployerPensionRate is the employer pension contribution as a percent of gross
+	// pay, posted to the GL journal. Zero disables the pension lines.
+	EmployerPensionRate float64
+	// Currency is the ISO 4217 code every payroll and payslip amount is denominated in.
+	Currency money.Currency
+	// NoticePeriodDays is the working days of pay in lieu of notice on a final settlement
+	// when the company ends the employment. Resignations get none unless HR sets it.
+	NoticePeriodDays int
+	// GratuityDaysPerYear is the working days of basic pay owed per completed year of
+	// service on leaving, once the employee has served GratuityMinYears.
+	GratuityDaysPerYear float64
+	GratuityMinYears    int
+}
+
+// LeaveConfig holds the organisation's leave calendar.
+type LeaveConfig struct {
+	// YearStartMonth is the month leave years begin in; balances, accrual and
+	// carry-forward all follow it. January gives calendar-year leave.
+	YearStartMonth time.Month
+	// AutoApproveShortening keeps the approval of approved leave that is amended to
+	// cover less time; any other amendment goes back through approval.
+	AutoApproveShortening bool
+	// AttachmentDir is where files uploaded to leave requests, such as sick notes, are
+	// stored.
+	AttachmentDir string
+	// CarryForwardReminderDays are the days before carried-forward leave expires on which
+	// employees with unused carried days are reminded, e.g. 30 and 7.
+	CarryForwardReminderDays []int
 }
 
 type EmailConfig struct {
@@ -53,6 +106,27 @@ func Load() *Config {
 			UseSSL:     getEnv("EMAIL_USE_SSL", "false") == "true",
 			AuthMethod: getEnv("EMAIL_AUTH_METHOD", "PLAIN"),
 		},
+		Company: CompanyConfig{
+			Name:              getEnv("COMPANY_NAME", "HR System"),
+			Address:           getEnv("COMPANY_ADDRESS", ""),
+			LogoPath:          getEnv("COMPANY_LOGO_PATH", ""),
+			BankCode:          getEnv("COMPANY_BANK_CODE", ""),
+			BankAccountNumber: getEnv("COMPANY_BANK_ACCOUNT", ""),
+			PayslipPassword:   getEnv("PAYSLIP_PDF_PASSWORD", ""),
+		},
+		Payroll: PayrollConfig{
+			EmployerPensionRate: getEnvFloat("EMPLOYER_PENSION_RATE", 0),
+			Currency:            money.Currency(getEnv("PAYROLL_CURRENCY", string(money.DefaultCurrency))),
+			NoticePeriodDays:    getEnvInt("NOTICE_PERIOD_DAYS", 22),
+			GratuityDaysPerYear: getEnvFloat("GRATUITY_DAYS_PER_YEAR", 15),
+			GratuityMinYears:    getEnvInt("GRATUITY_MIN_YEARS", 1),
+		},
+		Leave: LeaveConfig{
+			YearStartMonth:           getEnvMonth("LEAVE_YEAR_START_MONTH", time.January),
+			AutoApproveShortening:    getEnv("LEAVE_AUTO_APPROVE_SHORTENING", "true") == "true",
+			AttachmentDir:            getEnv("LEAVE_ATTACHMENT_DIR", "uploads/leave-attachments"),
+			CarryForwardReminderDays: getEnvInts("LEAVE_CARRY_FORWARD_REMINDER_DAYS", []int{30, 7}),
+		},
 	}
 }
 
@@ -62,3 +136,39 @@ func getEnv(key, fallback string) string {
 	}
 	return fallback
 }
+
+func getEnvFloat(key string, fallback float64) float64 {
+	if v, err := strconv.ParseFloat(os.Getenv(key), 64); err == nil {
+		return v
+	}
+	return fallback
+}
+
+func getEnvInt(key string, fallback int) int {
+	if v, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		return v
+	}
+	return fallback
+}
+
+// getEnvInts reads a comma-separated list of whole numbers, ignoring entries that are not
+func getEnvInts(key string, fallback []int) []int {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback
+	}
+	var out []int
+	for _, part := range strings.Split(v, ",") {
+		if n, err := strconv.Atoi(strings.TrimSpace(part)); err == nil {
+			out = append(out, n)
+		}
+	}
+	return out
+}
+
+func getEnvMonth(key string, fallback time.Month) time.Month {
+	if v := getEnvInt(key, 0); v >= 1 && v <= 12 {
+		return time.Month(v)
+	}
+	return fallback
+}
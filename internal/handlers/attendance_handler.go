@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
@@ -141,6 +142,35 @@ func (h *AttendanceHandler) Update(w http.ResponseWriter, r *http.Request) {
 	utils.RespondJSON(w, http.StatusOK, a)
 }
 
+// ApproveOvertime approves the overtime on an attendance record so it is paid in payroll
+func (h *AttendanceHandler) ApproveOvertime(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid attendance ID")
+		return
+	}
+	userID, _ := middleware.GetUserIDFromContext(r.Context())
+	approver := h.getEmployeeByUser(userID)
+	if approver == nil {
+		utils.RespondError(w, http.StatusBadRequest, "No employee record for approver")
+		return
+	}
+	// HR and admins can approve anyone's overtime; managers only their reports'
+	user, ok := r.Context().Value(middleware.UserKey).(*models.User)
+	canApproveAny := ok && user != nil && user.Role != nil &&
+		(user.Role.Name == models.RoleSuperAdmin || user.Role.Name == models.RoleHRManager)
+	a, err := h.service.ApproveOvertime(id, approver.ID, canApproveAny)
+	if err != nil {
+		if errors.Is(err, services.ErrOvertimeApprovalScope) {
+			utils.RespondError(w, http.StatusForbidden, err.Error())
+			return
+		}
+		utils.RespondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, a)
+}
+
 func (h *AttendanceHandler) GetSummary(w http.ResponseWriter, r *http.Request) {
 	q := r.URL.Query()
 	employeeID, err := uuid.Parse(q.Get("employee_id"))
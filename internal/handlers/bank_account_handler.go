@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"net/http"
+
+	"hr-system/internal/middleware"
+	"hr-system/internal/models"
+	"hr-system/internal/services"
+	"hr-system/pkg/utils"
+
+	"github.com/google/uuid"
+)
+
+type BankAccountHandler struct {
+	service *services.BankAccountService
+}
+
+func NewBankAccountHandler(service *services.BankAccountService) *BankAccountHandler {
+	return &BankAccountHandler{service: service}
+}
+
+type bankAccountRequest struct {
+	BankName      string `json:"bank_name"`
+	BankCode      string `json:"bank_code"`
+	BranchCode    string `json:"branch_code"`
+	AccountNumber string `json:"account_number"`
+	AccountName   string `json:"account_name"`
+}
+
+// ListForEmployee returns an employee's bank account history
+func (h *BankAccountHandler) ListForEmployee(w http.ResponseWriter, r *http.Request) {
+	employeeID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid employee ID")
+		return
+	}
+
+	accounts, err := h.service.ListByEmployee(employeeID)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, "Failed to list bank accounts")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, accounts)
+}
+
+// SubmitForEmployee records new bank details for an employee, pending approval
+func (h *BankAccountHandler) SubmitForEmployee(w http.ResponseWriter, r *http.Request) {
+	employeeID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid employee ID")
+		return
+	}
+	h.submit(w, r, employeeID)
+}
+
+// GetMine returns the current user's bank account history
+func (h *BankAccountHandler) GetMine(w http.ResponseWriter, r *http.Request) {
+	emp, ok := h.currentEmployee(w, r)
+	if !ok {
+		return
+	}
+
+	accounts, err := h.service.ListByEmployee(emp.ID)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, "Failed to list bank accounts")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, accounts)
+}
+
+// SubmitMine lets an employee request a change to their own bank details
+func (h *BankAccountHandler) SubmitMine(w http.ResponseWriter, r *http.Request) {
+	emp, ok := h.currentEmployee(w, r)
+	if !ok {
+		return
+	}
+	h.submit(w, r, emp.ID)
+}
+
+// ListPending returns bank account changes awaiting approval
+func (h *BankAccountHandler) ListPending(w http.ResponseWriter, r *http.Request) {
+	accounts, err := h.service.ListPending()
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, "Failed to list bank accounts")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, accounts)
+}
+
+// Approve activates a pending bank account, superseding the previous one
+func (h *BankAccountHandler) Approve(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid bank account ID")
+		return
+	}
+
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		utils.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	account, err := h.service.Approve(id, userID)
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, account)
+}
+
+// Reject declines a pending bank account change
+func (h *BankAccountHandler) Reject(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid bank account ID")
+		return
+	}
+
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		utils.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req struct {
+		Reason string `json:"reason"`
+	}
+	if err := utils.DecodeJson(r, &req); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	account, err := h.service.Reject(id, userID, req.Reason)
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, account)
+}
+
+func (h *BankAccountHandler) submit(w http.ResponseWriter, r *http.Request, employeeID uuid.UUID) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		utils.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req bankAccountRequest
+	if err := utils.DecodeJson(r, &req); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	account := &models.EmployeeBankAccount{
+		EmployeeID:    employeeID,
+		BankName:      req.BankName,
+		BankCode:      req.BankCode,
+		BranchCode:    req.BranchCode,
+		AccountNumber: req.AccountNumber,
+		AccountName:   req.AccountName,
+	}
+	if err := h.service.RequestChange(account, userID); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusCreated, account)
+}
+
+func (h *BankAccountHandler) currentEmployee(w http.ResponseWriter, r *http.Request) (*models.Employee, bool) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		utils.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return nil, false
+	}
+
+	emp, err := h.service.GetEmployeeByUserID(userID)
+	if err != nil {
+		utils.RespondError(w, http.StatusNotFound, "No employee record found for this user")
+		return nil, false
+	}
+	return emp, true
+}
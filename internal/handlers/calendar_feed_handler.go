@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"hr-system/internal/middleware"
+	"hr-system/internal/services"
+	"hr-system/pkg/utils"
+)
+
+type CalendarFeedHandler struct {
+	service *services.CalendarFeedService
+}
+
+func NewCalendarFeedHandler(service *services.CalendarFeedService) *CalendarFeedHandler {
+	return &CalendarFeedHandler{service: service}
+}
+
+// GetMyFeeds returns the caller's calendar subscription URLs
+func (h *CalendarFeedHandler) GetMyFeeds(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserIDFromContext(r.Context())
+	feeds, err := h.service.Feeds(userID, baseURL(r))
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, feeds)
+}
+
+// ResetMyFeeds issues new subscription URLs and revokes the old ones
+func (h *CalendarFeedHandler) ResetMyFeeds(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserIDFromContext(r.Context())
+	feeds, err := h.service.Reset(userID, baseURL(r))
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, feeds)
+}
+
+func (h *CalendarFeedHandler) MyLeave(w http.ResponseWriter, r *http.Request) {
+	h.serveFeed(w, r, h.service.MyLeave)
+}
+
+func (h *CalendarFeedHandler) TeamLeave(w http.ResponseWriter, r *http.Request) {
+	h.serveFeed(w, r, h.service.TeamLeave)
+}
+
+func (h *CalendarFeedHandler) Holidays(w http.ResponseWriter, r *http.Request) {
+	h.serveFeed(w, r, h.service.Holidays)
+}
+
+func (h *CalendarFeedHandler) serveFeed(w http.ResponseWriter, r *http.Request, render func(token string) ([]byte, error)) {
+	body, err := render(r.PathValue("token"))
+	if err != nil {
+		if errors.Is(err, services.ErrFeedNotFound) {
+			utils.RespondError(w, http.StatusNotFound, "Calendar feed not found")
+			return
+		}
+		utils.RespondError(w, http.StatusInternalServerError, "Failed to build calendar feed")
+		return
+	}
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Cache-Control", "private, max-age=300")
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
+
+// baseURL is the scheme and host the request reached the API on
+func baseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
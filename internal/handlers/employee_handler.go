@@ -106,12 +106,16 @@ func (h *EmployeeHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	emp.ID = id
-	if err := h.service.Update(&emp); err != nil {
+	warning, err := h.service.Update(&emp)
+	if err != nil {
 		utils.RespondError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 	updated, _ := h.service.GetByID(id)
-	utils.RespondJSON(w, http.StatusOK, updated)
+	utils.RespondJSON(w, http.StatusOK, struct {
+		*models.Employee
+		Warning string `json:"warning,omitempty"`
+	}{updated, warning})
 }
 
 func (h *EmployeeHandler) Delete(w http.ResponseWriter, r *http.Request) {
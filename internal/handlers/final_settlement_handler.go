@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"net/http"
+
+	"hr-system/internal/middleware"
+	"hr-system/internal/models"
+	"hr-system/internal/services"
+	"hr-system/pkg/utils"
+
+	"github.com/google/uuid"
+)
+
+type FinalSettlementHandler struct {
+	service *services.FinalSettlementService
+}
+
+func NewFinalSettlementHandler(service *services.FinalSettlementService) *FinalSettlementHandler {
+	return &FinalSettlementHandler{service: service}
+}
+
+// List returns final settlements, optionally filtered by status
+func (h *FinalSettlementHandler) List(w http.ResponseWriter, r *http.Request) {
+	settlements, err := h.service.List(models.FinalSettlementStatus(r.URL.Query().Get("status")))
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, "Failed to list settlements")
+		return
+	}
+	if settlements == nil {
+		settlements = []models.FinalSettlement{}
+	}
+
+	utils.RespondJSON(w, http.StatusOK, settlements)
+}
+
+// GetByID returns a settlement statement
+func (h *FinalSettlementHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid settlement ID")
+		return
+	}
+
+	settlement, err := h.service.GetByID(id)
+	if err != nil {
+		utils.RespondError(w, http.StatusNotFound, "Settlement not found")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, settlement)
+}
+
+// GetForEmployee returns an employee's pending or approved settlement
+func (h *FinalSettlementHandler) GetForEmployee(w http.ResponseWriter, r *http.Request) {
+	employeeID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid employee ID")
+		return
+	}
+
+	settlement, err := h.service.GetForEmployee(employeeID)
+	if err != nil {
+		utils.RespondError(w, http.StatusNotFound, "No final settlement for this employee")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, settlement)
+}
+
+// Prepare calculates (or recalculates) a leaver's settlement statement for approval.
+// notice_days overrides the default pay in lieu of notice.
+func (h *FinalSettlementHandler) Prepare(w http.ResponseWriter, r *http.Request) {
+	employeeID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid employee ID")
+		return
+	}
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		utils.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req struct {
+		NoticeDays *int   `json:"notice_days"`
+		Notes      string `json:"notes"`
+	}
+	if err := utils.DecodeJson(r, &req); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	settlement, err := h.service.Prepare(employeeID, services.SettlementOptions{
+		NoticeDays: req.NoticeDays,
+		Notes:      req.Notes,
+	}, &userID)
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, settlement)
+}
+
+// Approve approves a settlement and issues the final payslip
+func (h *FinalSettlementHandler) Approve(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid settlement ID")
+		return
+	}
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		utils.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	settlement, err := h.service.Approve(id, userID)
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, settlement)
+}
+
+// Cancel withdraws a settlement that has not been approved
+func (h *FinalSettlementHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid settlement ID")
+		return
+	}
+
+	if err := h.service.Cancel(id); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, map[string]string{"message": "Settlement cancelled"})
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"hr-system/internal/models"
+	"hr-system/internal/services"
+	"hr-system/pkg/utils"
+
+	"github.com/google/uuid"
+)
+
+type GLJournalHandler struct {
+	service *services.GLJournalService
+}
+
+func NewGLJournalHandler(service *services.GLJournalService) *GLJournalHandler {
+	return &GLJournalHandler{service: service}
+}
+
+// Components lists the payroll components that can be mapped to GL accounts
+func (h *GLJournalHandler) Components(w http.ResponseWriter, r *http.Request) {
+	utils.RespondJSON(w, http.StatusOK, map[string][]models.GLComponent{
+		"debit":  models.GLDebitComponents,
+		"credit": models.GLCreditComponents,
+	})
+}
+
+// ListMappings returns all GL account mappings
+func (h *GLJournalHandler) ListMappings(w http.ResponseWriter, r *http.Request) {
+	mappings, err := h.service.ListMappings()
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, "Failed to list GL mappings")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, mappings)
+}
+
+// SaveMapping creates or replaces the GL account for a component, optionally per department
+func (h *GLJournalHandler) SaveMapping(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Component    string `json:"component"`
+		DepartmentID string `json:"department_id"`
+		AccountCode  string `json:"account_code"`
+		AccountName  string `json:"account_name"`
+		CostCentre   string `json:"cost_centre"`
+	}
+	if err := utils.DecodeJson(r, &req); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	mapping := &models.GLAccountMapping{
+		Component:   models.GLComponent(req.Component),
+		AccountCode: req.AccountCode,
+		AccountName: req.AccountName,
+		CostCentre:  req.CostCentre,
+	}
+	if req.DepartmentID != "" {
+		deptID, err := uuid.Parse(req.DepartmentID)
+		if err != nil {
+			utils.RespondError(w, http.StatusBadRequest, "Invalid department_id")
+			return
+		}
+		mapping.DepartmentID = &deptID
+	}
+
+	saved, err := h.service.SaveMapping(mapping)
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, saved)
+}
+
+// DeleteMapping removes a GL account mapping
+func (h *GLJournalHandler) DeleteMapping(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid mapping ID")
+		return
+	}
+
+	if err := h.service.DeleteMapping(id); err != nil {
+		utils.RespondError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, map[string]string{
+		"message": "GL mapping deleted successfully",
+	})
+}
+
+// Journal returns the GL journal for a completed payroll (?format=csv to download)
+func (h *GLJournalHandler) Journal(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid payroll ID")
+		return
+	}
+
+	journal, err := h.service.Journal(id)
+	if err != nil {
+		utils.RespondError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	if r.URL.Query().Get("format") != "csv" {
+		utils.RespondJSON(w, http.StatusOK, journal)
+		return
+	}
+
+	data, err := h.service.ExportCSV(journal)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, "Failed to export journal")
+		return
+	}
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", journal.Reference+".csv"))
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
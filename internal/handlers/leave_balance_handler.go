@@ -3,7 +3,6 @@ package handlers
 import (
 	"net/http"
 	"strconv"
-	"time"
 
 	"hr-system/internal/interfaces"
 	"hr-system/internal/middleware"
@@ -23,13 +22,26 @@ func NewLeaveBalanceHandler(svc *services.LeaveBalanceService, empSvc *services.
 	return &LeaveBalanceHandler{service: svc, empService: empSvc}
 }
 
+// LeaveYear describes the organisation's leave calendar and the leave year running today
+func (h *LeaveBalanceHandler) LeaveYear(w http.ResponseWriter, r *http.Request) {
+	ly := h.service.LeaveYear()
+	year := h.service.CurrentYear()
+	utils.RespondJSON(w, http.StatusOK, map[string]interface{}{
+		"start_month": ly.FirstMonth(),
+		"year":        year,
+		"label":       ly.Label(year),
+		"start_date":  ly.Start(year).Format("2006-01-02"),
+		"end_date":    ly.End(year).Format("2006-01-02"),
+	})
+}
+
 func (h *LeaveBalanceHandler) GetMyBalances(w http.ResponseWriter, r *http.Request) {
 	user, ok := r.Context().Value(middleware.UserKey).(*models.User)
 	if !ok {
 		utils.RespondError(w, http.StatusUnauthorized, "Unauthorized")
 		return
 	}
-	year := time.Now().Year()
+	year := h.service.CurrentYear()
 	if y := r.URL.Query().Get("year"); y != "" {
 		if v, err := strconv.Atoi(y); err == nil {
 			year = v
@@ -67,7 +79,7 @@ func (h *LeaveBalanceHandler) GetByEmployee(w http.ResponseWriter, r *http.Reque
 		utils.RespondError(w, http.StatusBadRequest, "Invalid employee ID")
 		return
 	}
-	year := time.Now().Year()
+	year := h.service.CurrentYear()
 	if y := r.URL.Query().Get("year"); y != "" {
 		if v, err := strconv.Atoi(y); err == nil {
 			year = v
@@ -82,6 +94,29 @@ func (h *LeaveBalanceHandler) GetByEmployee(w http.ResponseWriter, r *http.Reque
 	utils.RespondJSON(w, http.StatusOK, balances)
 }
 
+// GetLedger lists the ledger entries against an employee's balances for ?year= (default
+// the current leave year)
+func (h *LeaveBalanceHandler) GetLedger(w http.ResponseWriter, r *http.Request) {
+	employeeID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid employee ID")
+		return
+	}
+	year := h.service.CurrentYear()
+	if y := r.URL.Query().Get("year"); y != "" {
+		if v, err := strconv.Atoi(y); err == nil {
+			year = v
+		}
+	}
+
+	entries, err := h.service.Ledger(employeeID, year)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, "Failed to get leave ledger")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, entries)
+}
+
 func (h *LeaveBalanceHandler) Initialize(w http.ResponseWriter, r *http.Request) {
 	year, err := strconv.Atoi(r.PathValue("year"))
 	if err != nil {
@@ -112,8 +147,8 @@ func (h *LeaveBalanceHandler) Adjust(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req struct {
-		Delta  int    `json:"delta"`
-		Reason string `json:"reason"`
+		Delta  float64 `json:"delta"`
+		Reason string  `json:"reason"`
 	}
 	if err := utils.DecodeJson(r, &req); err != nil {
 		utils.RespondError(w, http.StatusBadRequest, "Invalid request body")
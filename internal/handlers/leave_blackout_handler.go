@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"hr-system/internal/models"
+	"hr-system/internal/services"
+	"hr-system/pkg/utils"
+
+	"github.com/google/uuid"
+)
+
+type LeaveBlackoutHandler struct {
+	service *services.LeaveBlackoutService
+}
+
+func NewLeaveBlackoutHandler(service *services.LeaveBlackoutService) *LeaveBlackoutHandler {
+	return &LeaveBlackoutHandler{service: service}
+}
+
+func (h *LeaveBlackoutHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var b models.LeaveBlackout
+	if err := utils.DecodeJson(r, &b); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := h.service.Create(&b); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.RespondJSON(w, http.StatusCreated, b)
+}
+
+// List returns blackouts, only those ending on or after ?from=YYYY-MM-DD when given
+func (h *LeaveBlackoutHandler) List(w http.ResponseWriter, r *http.Request) {
+	var from *time.Time
+	if v := r.URL.Query().Get("from"); v != "" {
+		t, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			utils.RespondError(w, http.StatusBadRequest, "Invalid from format, use YYYY-MM-DD")
+			return
+		}
+		from = &t
+	}
+	blackouts, err := h.service.List(from)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, "Failed to list blackouts")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, blackouts)
+}
+
+func (h *LeaveBlackoutHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid blackout ID")
+		return
+	}
+	var b models.LeaveBlackout
+	if err := utils.DecodeJson(r, &b); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	b.ID = id
+	if err := h.service.Update(&b); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	updated, _ := h.service.GetByID(id)
+	utils.RespondJSON(w, http.StatusOK, updated)
+}
+
+func (h *LeaveBlackoutHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid blackout ID")
+		return
+	}
+	if err := h.service.Delete(id); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]string{"message": "Blackout deleted"})
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"hr-system/internal/middleware"
+	"hr-system/internal/models"
+	"hr-system/internal/services"
+	"hr-system/pkg/utils"
+
+	"github.com/google/uuid"
+)
+
+// maxLeaveAttachmentUpload leaves room for the multipart framing around a 10MB file
+const maxLeaveAttachmentUpload = 11 << 20
+
+type LeaveDocumentHandler struct {
+	service *services.LeaveDocumentService
+}
+
+func NewLeaveDocumentHandler(service *services.LeaveDocumentService) *LeaveDocumentHandler {
+	return &LeaveDocumentHandler{service: service}
+}
+
+// Upload attaches a file, sent as multipart form data in the 'file' field, to a leave request
+func (h *LeaveDocumentHandler) Upload(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid request ID")
+		return
+	}
+	user, ok := r.Context().Value(middleware.UserKey).(*models.User)
+	if !ok || user == nil {
+		utils.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxLeaveAttachmentUpload)
+	f, header, err := r.FormFile("file")
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "A file is required in the 'file' field")
+		return
+	}
+	defer f.Close()
+
+	a := &models.LeaveRequestAttachment{
+		FileName:    header.Filename,
+		ContentType: header.Header.Get("Content-Type"),
+	}
+	if err := h.service.Upload(id, user, a, f); err != nil {
+		if errors.Is(err, services.ErrLeaveDocumentScope) {
+			utils.RespondError(w, http.StatusForbidden, err.Error())
+			return
+		}
+		utils.RespondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.RespondJSON(w, http.StatusCreated, a)
+}
+
+// Download sends an attachment's file. Employees can download files on their own requests;
+// HR and managers on anyone's.
+func (h *LeaveDocumentHandler) Download(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid request ID")
+		return
+	}
+	attachmentID, err := uuid.Parse(r.PathValue("aid"))
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid attachment ID")
+		return
+	}
+	user, ok := r.Context().Value(middleware.UserKey).(*models.User)
+	if !ok || user == nil {
+		utils.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	canViewAll := user.Role != nil && (user.Role.Name == models.RoleSuperAdmin ||
+		user.Role.Name == models.RoleHRManager || user.Role.Name == models.RoleManager)
+
+	a, f, err := h.service.Open(id, attachmentID, user, canViewAll)
+	switch {
+	case errors.Is(err, services.ErrAttachmentNotFound):
+		utils.RespondError(w, http.StatusNotFound, "Attachment not found")
+		return
+	case errors.Is(err, services.ErrLeaveDocumentScope):
+		utils.RespondError(w, http.StatusForbidden, err.Error())
+		return
+	case err != nil:
+		utils.RespondError(w, http.StatusInternalServerError, "Failed to open attachment")
+		return
+	}
+	defer f.Close()
+
+	w.Header().Set("Content-Type", a.ContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", a.FileName))
+	w.Header().Set("Content-Length", strconv.FormatInt(a.FileSize, 10))
+	w.WriteHeader(http.StatusOK)
+	io.Copy(w, f)
+}
+
+// MarkReceived records that HR has a request's required document, e.g. handed in on paper
+func (h *LeaveDocumentHandler) MarkReceived(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid request ID")
+		return
+	}
+	if err := h.service.MarkReceived(id); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]string{"message": "Leave document marked as received"})
+}
@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"net/http"
+
+	"hr-system/internal/middleware"
+	"hr-system/internal/models"
+	"hr-system/internal/services"
+	"hr-system/pkg/utils"
+
+	"github.com/google/uuid"
+)
+
+type LeaveEncashmentHandler struct {
+	service *services.LeaveEncashmentService
+}
+
+func NewLeaveEncashmentHandler(service *services.LeaveEncashmentService) *LeaveEncashmentHandler {
+	return &LeaveEncashmentHandler{service: service}
+}
+
+type leaveEncashmentRequest struct {
+	LeaveTypeID uuid.UUID `json:"leave_type_id"`
+	Days        float64   `json:"days"`
+	Reason      string    `json:"reason"`
+}
+
+// List returns all leave encashments, optionally filtered by status
+func (h *LeaveEncashmentHandler) List(w http.ResponseWriter, r *http.Request) {
+	encashments, err := h.service.List(models.LeaveEncashmentStatus(r.URL.Query().Get("status")))
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, "Failed to list leave encashments")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, encashments)
+}
+
+// GetByID returns an encashment - own encashments, or any for SuperAdmin/HRManager
+func (h *LeaveEncashmentHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	e, ok := h.accessibleEncashment(w, r)
+	if !ok {
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, e)
+}
+
+// GetMine returns the current user's leave encashments
+func (h *LeaveEncashmentHandler) GetMine(w http.ResponseWriter, r *http.Request) {
+	emp, ok := h.currentEmployee(w, r)
+	if !ok {
+		return
+	}
+
+	encashments, err := h.service.ListByEmployee(emp.ID)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, "Failed to list leave encashments")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, encashments)
+}
+
+// RequestMine lets an employee ask to be paid for unused leave
+func (h *LeaveEncashmentHandler) RequestMine(w http.ResponseWriter, r *http.Request) {
+	emp, ok := h.currentEmployee(w, r)
+	if !ok {
+		return
+	}
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		utils.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req leaveEncashmentRequest
+	if err := utils.DecodeJson(r, &req); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	e := &models.LeaveEncashment{
+		EmployeeID:  emp.ID,
+		LeaveTypeID: req.LeaveTypeID,
+		Days:        req.Days,
+		Reason:      req.Reason,
+	}
+	if err := h.service.Request(e, userID); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusCreated, e)
+}
+
+// ListForEmployee returns an employee's leave encashments
+func (h *LeaveEncashmentHandler) ListForEmployee(w http.ResponseWriter, r *http.Request) {
+	employeeID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid employee ID")
+		return
+	}
+
+	encashments, err := h.service.ListByEmployee(employeeID)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, "Failed to list leave encashments")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, encashments)
+}
+
+// Cancel withdraws a pending encashment - own encashments, or any for SuperAdmin/HRManager
+func (h *LeaveEncashmentHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	e, ok := h.accessibleEncashment(w, r)
+	if !ok {
+		return
+	}
+
+	cancelled, err := h.service.Cancel(e.ID)
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, cancelled)
+}
+
+// accessibleEncashment loads the encashment in the path, checking that the caller owns it
+// unless they are SuperAdmin or HRManager.
+func (h *LeaveEncashmentHandler) accessibleEncashment(w http.ResponseWriter, r *http.Request) (*models.LeaveEncashment, bool) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid leave encashment ID")
+		return nil, false
+	}
+
+	user, ok := r.Context().Value(middleware.UserKey).(*models.User)
+	if !ok || user == nil {
+		utils.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return nil, false
+	}
+
+	e, err := h.service.GetByID(id)
+	if err != nil {
+		utils.RespondError(w, http.StatusNotFound, "Leave encashment not found")
+		return nil, false
+	}
+
+	if user.Role == nil || (user.Role.Name != models.RoleSuperAdmin && user.Role.Name != models.RoleHRManager) {
+		emp, err := h.service.GetEmployeeByUserID(user.UserID)
+		if err != nil || emp.ID != e.EmployeeID {
+			utils.RespondError(w, http.StatusForbidden, "You can only access your own leave encashments")
+			return nil, false
+		}
+	}
+	return e, true
+}
+
+func (h *LeaveEncashmentHandler) currentEmployee(w http.ResponseWriter, r *http.Request) (*models.Employee, bool) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		utils.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return nil, false
+	}
+
+	emp, err := h.service.GetEmployeeByUserID(userID)
+	if err != nil {
+		utils.RespondError(w, http.StatusNotFound, "No employee record found for this user")
+		return nil, false
+	}
+	return emp, true
+}
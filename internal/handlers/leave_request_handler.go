@@ -1,7 +1,9 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"time"
 
 	"hr-system/internal/interfaces"
 	"hr-system/internal/middleware"
@@ -35,9 +37,40 @@ func (h *LeaveRequestHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	req.EmployeeID = emp.ID
+	req.RuleOverrideReason, req.RuleOverriddenBy = "", nil
 
 	if err := h.service.Create(&req); err != nil {
-		utils.RespondError(w, http.StatusBadRequest, err.Error())
+		respondLeaveError(w, err)
+		return
+	}
+	utils.RespondJSON(w, http.StatusCreated, req)
+}
+
+// CreateForEmployee lets HR file leave for an employee. Leave rules the request breaks are
+// overridden when rule_override_reason is given.
+func (h *LeaveRequestHandler) CreateForEmployee(w http.ResponseWriter, r *http.Request) {
+	employeeID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid employee ID")
+		return
+	}
+	userID, _ := middleware.GetUserIDFromContext(r.Context())
+	hr := h.getEmployeeByUser(userID)
+	if hr == nil {
+		utils.RespondError(w, http.StatusBadRequest, "No employee record linked to your account")
+		return
+	}
+
+	var req models.LeaveRequest
+	if err := utils.DecodeJson(r, &req); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	req.EmployeeID = employeeID
+	req.RuleOverriddenBy = &hr.ID
+
+	if err := h.service.Create(&req); err != nil {
+		respondLeaveError(w, err)
 		return
 	}
 	utils.RespondJSON(w, http.StatusCreated, req)
@@ -62,7 +95,8 @@ func (h *LeaveRequestHandler) GetMyRequests(w http.ResponseWriter, r *http.Reque
 func (h *LeaveRequestHandler) List(w http.ResponseWriter, r *http.Request) {
 	pag := utils.ParsePagination(r)
 	filter := interfaces.LeaveRequestFilter{
-		Status: r.URL.Query().Get("status"),
+		Status:         r.URL.Query().Get("status"),
+		DocumentStatus: r.URL.Query().Get("document_status"),
 	}
 	if v := r.URL.Query().Get("employee_id"); v != "" {
 		if id, err := uuid.Parse(v); err == nil {
@@ -91,6 +125,73 @@ func (h *LeaveRequestHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 	utils.RespondJSON(w, http.StatusOK, req)
 }
 
+func (h *LeaveRequestHandler) Amend(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid request ID")
+		return
+	}
+	userID, _ := middleware.GetUserIDFromContext(r.Context())
+	emp := h.getEmployeeByUser(userID)
+	if emp == nil {
+		utils.RespondError(w, http.StatusBadRequest, "No employee record linked to your account")
+		return
+	}
+
+	var input interfaces.AmendLeaveInput
+	if err := utils.DecodeJson(r, &input); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	input.RuleOverrideReason, input.RuleOverriddenBy = "", nil
+
+	req, err := h.service.Amend(id, emp.ID, input)
+	if err != nil {
+		respondLeaveError(w, err)
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, req)
+}
+
+// AmendForEmployee lets HR amend an employee's leave request. Leave rules the amended
+// request breaks are overridden when rule_override_reason is given.
+func (h *LeaveRequestHandler) AmendForEmployee(w http.ResponseWriter, r *http.Request) {
+	employeeID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid employee ID")
+		return
+	}
+	id, err := uuid.Parse(r.PathValue("rid"))
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid request ID")
+		return
+	}
+	userID, _ := middleware.GetUserIDFromContext(r.Context())
+	hr := h.getEmployeeByUser(userID)
+	if hr == nil {
+		utils.RespondError(w, http.StatusBadRequest, "No employee record linked to your account")
+		return
+	}
+	if existing, err := h.service.GetByID(id); err != nil || existing.EmployeeID != employeeID {
+		utils.RespondError(w, http.StatusNotFound, "Leave request not found")
+		return
+	}
+
+	var input interfaces.AmendLeaveInput
+	if err := utils.DecodeJson(r, &input); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	input.RuleOverriddenBy = &hr.ID
+
+	req, err := h.service.Amend(id, hr.ID, input)
+	if err != nil {
+		respondLeaveError(w, err)
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, req)
+}
+
 func (h *LeaveRequestHandler) Cancel(w http.ResponseWriter, r *http.Request) {
 	id, err := uuid.Parse(r.PathValue("id"))
 	if err != nil {
@@ -152,6 +253,101 @@ func (h *LeaveRequestHandler) Reject(w http.ResponseWriter, r *http.Request) {
 	utils.RespondJSON(w, http.StatusOK, map[string]string{"message": "Leave request rejected"})
 }
 
+// RebaseLeaveYears recharges live requests to the leave years they fall in after the leave
+// year start has changed (?from=YYYY-MM-DD limits it to requests ending on or after that date)
+func (h *LeaveRequestHandler) RebaseLeaveYears(w http.ResponseWriter, r *http.Request) {
+	var from *time.Time
+	if v := r.URL.Query().Get("from"); v != "" {
+		t, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			utils.RespondError(w, http.StatusBadRequest, "Invalid from format, use YYYY-MM-DD")
+			return
+		}
+		from = &t
+	}
+
+	report, err := h.service.RebaseLeaveYears(from)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, "Failed to rebase leave years")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, report)
+}
+
+// Calendar shows the team's leave between ?from= and ?to= (YYYY-MM-DD, default the next 30
+// days) for ?department_id= or ?manager_id=, defaulting to the caller's own reports.
+// Managers are limited to their own reporting line.
+func (h *LeaveRequestHandler) Calendar(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	from := time.Now().Truncate(24 * time.Hour)
+	if v := q.Get("from"); v != "" {
+		t, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			utils.RespondError(w, http.StatusBadRequest, "Invalid from format, use YYYY-MM-DD")
+			return
+		}
+		from = t
+	}
+	to := from.AddDate(0, 0, 30)
+	if v := q.Get("to"); v != "" {
+		t, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			utils.RespondError(w, http.StatusBadRequest, "Invalid to format, use YYYY-MM-DD")
+			return
+		}
+		to = t
+	}
+
+	var departmentID, managerID *uuid.UUID
+	if v := q.Get("department_id"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			utils.RespondError(w, http.StatusBadRequest, "Invalid department ID")
+			return
+		}
+		departmentID = &id
+	}
+	if v := q.Get("manager_id"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			utils.RespondError(w, http.StatusBadRequest, "Invalid manager ID")
+			return
+		}
+		managerID = &id
+	}
+	// HR and admins can view any calendar; managers only their own reporting line
+	user, ok := r.Context().Value(middleware.UserKey).(*models.User)
+	canViewAny := ok && user != nil && user.Role != nil &&
+		(user.Role.Name == models.RoleSuperAdmin || user.Role.Name == models.RoleHRManager)
+	userID, _ := middleware.GetUserIDFromContext(r.Context())
+	emp := h.getEmployeeByUser(userID)
+	var viewerID uuid.UUID
+	switch {
+	case emp != nil:
+		viewerID = emp.ID
+	case !canViewAny:
+		utils.RespondError(w, http.StatusBadRequest, "No employee record linked to your account")
+		return
+	case departmentID == nil && managerID == nil:
+		utils.RespondError(w, http.StatusBadRequest, "department_id or manager_id is required")
+		return
+	}
+	if departmentID == nil && managerID == nil {
+		managerID = &viewerID
+	}
+
+	cal, err := h.service.Calendar(departmentID, managerID, from, to, viewerID, canViewAny)
+	if err != nil {
+		if errors.Is(err, services.ErrLeaveCalendarScope) {
+			utils.RespondError(w, http.StatusForbidden, err.Error())
+			return
+		}
+		utils.RespondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, cal)
+}
+
 func (h *LeaveRequestHandler) getEmployeeByUser(userID uuid.UUID) *models.Employee {
 	emps, _, err := h.empService.List(interfaces.EmployeeFilter{}, 1, 500)
 	if err != nil {
@@ -165,3 +361,19 @@ func (h *LeaveRequestHandler) getEmployeeByUser(userID uuid.UUID) *models.Employ
 	}
 	return nil
 }
+
+// respondLeaveError reports broken leave rules as 422 with the list of violations and any
+// other error as a bad request
+func respondLeaveError(w http.ResponseWriter, err error) {
+	var ruleErr *services.LeaveRuleError
+	if errors.As(err, &ruleErr) {
+		utils.RespondJSON(w, http.StatusUnprocessableEntity, map[string]interface{}{
+			"error": map[string]interface{}{
+				"message":    err.Error(),
+				"violations": ruleErr.Violations,
+			},
+		})
+		return
+	}
+	utils.RespondError(w, http.StatusBadRequest, err.Error())
+}
@@ -19,7 +19,7 @@ func NewLeaveTypeHandler(service *services.LeaveTypeService) *LeaveTypeHandler {
 }
 
 func (h *LeaveTypeHandler) Create(w http.ResponseWriter, r *http.Request) {
-	var lt models.LeaveType
+	lt := models.LeaveType{DocumentGraceDays: models.DefaultDocumentGraceDays}
 	if err := utils.DecodeJson(r, &lt); err != nil {
 		utils.RespondError(w, http.StatusBadRequest, "Invalid request body")
 		return
@@ -0,0 +1,260 @@
+package handlers
+
+import (
+	"net/http"
+
+	"hr-system/internal/middleware"
+	"hr-system/internal/models"
+	"hr-system/internal/services"
+	"hr-system/pkg/money"
+	"hr-system/pkg/utils"
+
+	"github.com/google/uuid"
+)
+
+type LoanHandler struct {
+	service *services.LoanService
+}
+
+func NewLoanHandler(service *services.LoanService) *LoanHandler {
+	return &LoanHandler{service: service}
+}
+
+type loanRequest struct {
+	LoanType     models.LoanType `json:"loan_type"`
+	Principal    money.Money     `json:"principal"`
+	InterestRate float64         `json:"interest_rate"`
+	Instalments  int             `json:"instalments"`
+	StartMonth   int             `json:"start_month"`
+	StartYear    int             `json:"start_year"`
+	Reason       string          `json:"reason"`
+}
+
+// List returns all loans, optionally filtered by status
+func (h *LoanHandler) List(w http.ResponseWriter, r *http.Request) {
+	loans, err := h.service.List(models.LoanStatus(r.URL.Query().Get("status")))
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, "Failed to list loans")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, loans)
+}
+
+// GetByID returns a loan with its repayments - own loans, or any for SuperAdmin/HRManager
+func (h *LoanHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	loan, ok := h.accessibleLoan(w, r)
+	if !ok {
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, loan)
+}
+
+// ListForEmployee returns an employee's loans and their outstanding balances
+func (h *LoanHandler) ListForEmployee(w http.ResponseWriter, r *http.Request) {
+	employeeID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid employee ID")
+		return
+	}
+
+	loans, err := h.service.ListByEmployee(employeeID)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, "Failed to list loans")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, loans)
+}
+
+// CreateForEmployee records a loan on behalf of an employee and sends it for approval
+func (h *LoanHandler) CreateForEmployee(w http.ResponseWriter, r *http.Request) {
+	employeeID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid employee ID")
+		return
+	}
+	h.create(w, r, employeeID, true)
+}
+
+// GetMine returns the current user's loans and their outstanding balances
+func (h *LoanHandler) GetMine(w http.ResponseWriter, r *http.Request) {
+	emp, ok := h.currentEmployee(w, r)
+	if !ok {
+		return
+	}
+
+	loans, err := h.service.ListByEmployee(emp.ID)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, "Failed to list loans")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, loans)
+}
+
+// RequestMine lets an employee apply for a loan or salary advance. Interest is set by HR.
+func (h *LoanHandler) RequestMine(w http.ResponseWriter, r *http.Request) {
+	emp, ok := h.currentEmployee(w, r)
+	if !ok {
+		return
+	}
+	h.create(w, r, emp.ID, false)
+}
+
+// UpdateTerms changes the amount, interest and schedule of a pending loan
+func (h *LoanHandler) UpdateTerms(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid loan ID")
+		return
+	}
+
+	var req loanRequest
+	if err := utils.DecodeJson(r, &req); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	loan, err := h.service.UpdateTerms(id, &models.EmployeeLoan{
+		Principal:    req.Principal,
+		InterestRate: req.InterestRate,
+		Instalments:  req.Instalments,
+		StartMonth:   req.StartMonth,
+		StartYear:    req.StartYear,
+	})
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, loan)
+}
+
+// Cancel withdraws a pending loan request - own loans, or any for SuperAdmin/HRManager
+func (h *LoanHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	loan, ok := h.accessibleLoan(w, r)
+	if !ok {
+		return
+	}
+
+	cancelled, err := h.service.Cancel(loan.ID)
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, cancelled)
+}
+
+// Settle records an early repayment; without an amount the whole balance is settled
+func (h *LoanHandler) Settle(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid loan ID")
+		return
+	}
+
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		utils.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req struct {
+		Amount money.Money `json:"amount"`
+		Notes  string      `json:"notes"`
+	}
+	if err := utils.DecodeJson(r, &req); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	loan, err := h.service.Settle(id, req.Amount, req.Notes, userID)
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, loan)
+}
+
+func (h *LoanHandler) create(w http.ResponseWriter, r *http.Request, employeeID uuid.UUID, allowInterest bool) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		utils.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req loanRequest
+	if err := utils.DecodeJson(r, &req); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	loan := &models.EmployeeLoan{
+		EmployeeID:  employeeID,
+		LoanType:    req.LoanType,
+		Principal:   req.Principal,
+		Instalments: req.Instalments,
+		StartMonth:  req.StartMonth,
+		StartYear:   req.StartYear,
+		Reason:      req.Reason,
+	}
+	if allowInterest {
+		loan.InterestRate = req.InterestRate
+	}
+	if err := h.service.Request(loan, userID); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusCreated, loan)
+}
+
+// accessibleLoan loads the loan in the path, checking that the caller owns it unless
+// they are SuperAdmin or HRManager.
+func (h *LoanHandler) accessibleLoan(w http.ResponseWriter, r *http.Request) (*models.EmployeeLoan, bool) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid loan ID")
+		return nil, false
+	}
+
+	user, ok := r.Context().Value(middleware.UserKey).(*models.User)
+	if !ok || user == nil {
+		utils.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return nil, false
+	}
+
+	loan, err := h.service.GetByID(id)
+	if err != nil {
+		utils.RespondError(w, http.StatusNotFound, "Loan not found")
+		return nil, false
+	}
+
+	if user.Role == nil || (user.Role.Name != models.RoleSuperAdmin && user.Role.Name != models.RoleHRManager) {
+		emp, err := h.service.GetEmployeeByUserID(user.UserID)
+		if err != nil || emp.ID != loan.EmployeeID {
+			utils.RespondError(w, http.StatusForbidden, "You can only access your own loans")
+			return nil, false
+		}
+	}
+	return loan, true
+}
+
+func (h *LoanHandler) currentEmployee(w http.ResponseWriter, r *http.Request) (*models.Employee, bool) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		utils.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return nil, false
+	}
+
+	emp, err := h.service.GetEmployeeByUserID(userID)
+	if err != nil {
+		utils.RespondError(w, http.StatusNotFound, "No employee record found for this user")
+		return nil, false
+	}
+	return emp, true
+}
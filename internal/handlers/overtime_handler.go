@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"net/http"
+
+	"hr-system/internal/models"
+	"hr-system/internal/services"
+	"hr-system/pkg/utils"
+)
+
+type OvertimeHandler struct {
+	service *services.OvertimeService
+}
+
+func NewOvertimeHandler(service *services.OvertimeService) *OvertimeHandler {
+	return &OvertimeHandler{service: service}
+}
+
+// GetRules returns the overtime pay rules
+func (h *OvertimeHandler) GetRules(w http.ResponseWriter, r *http.Request) {
+	utils.RespondJSON(w, http.StatusOK, h.service.GetRules())
+}
+
+// UpdateRules replaces the overtime pay rules
+func (h *OvertimeHandler) UpdateRules(w http.ResponseWriter, r *http.Request) {
+	var rule models.OvertimeRule
+	if err := utils.DecodeJson(r, &rule); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := h.service.UpdateRules(&rule); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, h.service.GetRules())
+}
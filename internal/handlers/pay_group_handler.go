@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"hr-system/internal/models"
+	"hr-system/internal/services"
+	"hr-system/pkg/utils"
+
+	"github.com/google/uuid"
+)
+
+type PayGroupHandler struct {
+	service *services.PayGroupService
+}
+
+func NewPayGroupHandler(service *services.PayGroupService) *PayGroupHandler {
+	return &PayGroupHandler{service: service}
+}
+
+type payGroupRequest struct {
+	Name          string              `json:"name"`
+	Description   string              `json:"description"`
+	PayFrequency  models.PayFrequency `json:"pay_frequency"`
+	CalendarStart string              `json:"calendar_start"` // YYYY-MM-DD
+	PayDayOffset  int                 `json:"pay_day_offset"`
+	IsDefault     bool                `json:"is_default"`
+	IsActive      *bool               `json:"is_active"`
+}
+
+func (req payGroupRequest) toModel(w http.ResponseWriter) (*models.PayGroup, bool) {
+	start, err := time.Parse("2006-01-02", req.CalendarStart)
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid calendar_start format, use YYYY-MM-DD")
+		return nil, false
+	}
+	g := &models.PayGroup{
+		Name:          req.Name,
+		Description:   req.Description,
+		PayFrequency:  req.PayFrequency,
+		CalendarStart: start,
+		PayDayOffset:  req.PayDayOffset,
+		IsDefault:     req.IsDefault,
+		IsActive:      true,
+	}
+	if req.IsActive != nil {
+		g.IsActive = *req.IsActive
+	}
+	return g, true
+}
+
+// List returns pay groups with their member counts (?include_inactive=true for all)
+func (h *PayGroupHandler) List(w http.ResponseWriter, r *http.Request) {
+	includeInactive, _ := strconv.ParseBool(r.URL.Query().Get("include_inactive"))
+
+	groups, err := h.service.List(includeInactive)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, "Failed to list pay groups")
+		return
+	}
+	if groups == nil {
+		groups = []models.PayGroup{}
+	}
+
+	utils.RespondJSON(w, http.StatusOK, groups)
+}
+
+func (h *PayGroupHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid pay group ID")
+		return
+	}
+
+	group, err := h.service.GetByID(id)
+	if err != nil {
+		utils.RespondError(w, http.StatusNotFound, "Pay group not found")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, group)
+}
+
+func (h *PayGroupHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req payGroupRequest
+	if err := utils.DecodeJson(r, &req); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	group, ok := req.toModel(w)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Create(group); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusCreated, group)
+}
+
+func (h *PayGroupHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid pay group ID")
+		return
+	}
+
+	var req payGroupRequest
+	if err := utils.DecodeJson(r, &req); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	group, ok := req.toModel(w)
+	if !ok {
+		return
+	}
+
+	updated, err := h.service.Update(id, group)
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, updated)
+}
+
+// Delete removes a pay group that has no payroll runs; its members move to the default group
+func (h *PayGroupHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid pay group ID")
+		return
+	}
+
+	if err := h.service.Delete(id); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, map[string]string{"message": "Pay group deleted"})
+}
+
+// Members returns the active employees paid with the group
+func (h *PayGroupHandler) Members(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid pay group ID")
+		return
+	}
+
+	employees, err := h.service.Members(id)
+	if err != nil {
+		utils.RespondError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if employees == nil {
+		employees = []models.Employee{}
+	}
+
+	utils.RespondJSON(w, http.StatusOK, employees)
+}
+
+// AssignMembers moves employees into the group
+func (h *PayGroupHandler) AssignMembers(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid pay group ID")
+		return
+	}
+
+	var req struct {
+		EmployeeIDs []uuid.UUID `json:"employee_ids"`
+	}
+	if err := utils.DecodeJson(r, &req); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	assigned, err := h.service.AssignMembers(id, req.EmployeeIDs)
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, map[string]int{"assigned": assigned})
+}
+
+// Calendar returns the group's pay periods for a year (?year=, defaults to the current year)
+func (h *PayGroupHandler) Calendar(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid pay group ID")
+		return
+	}
+
+	year := time.Now().Year()
+	if v := r.URL.Query().Get("year"); v != "" {
+		if y, err := strconv.Atoi(v); err == nil {
+			year = y
+		}
+	}
+
+	periods, err := h.service.Calendar(id, year)
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, periods)
+}
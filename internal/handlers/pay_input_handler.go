@@ -0,0 +1,226 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"hr-system/internal/middleware"
+	"hr-system/internal/models"
+	"hr-system/internal/services"
+	"hr-system/pkg/money"
+	"hr-system/pkg/utils"
+
+	"github.com/google/uuid"
+)
+
+// Largest pay input file accepted by Upload
+const maxPayInputUpload = 5 << 20
+
+type PayInputHandler struct {
+	service *services.PayInputService
+}
+
+func NewPayInputHandler(service *services.PayInputService) *PayInputHandler {
+	return &PayInputHandler{service: service}
+}
+
+type payInputRequest struct {
+	EmployeeID   uuid.UUID           `json:"employee_id"`
+	Month        int                 `json:"month"`
+	Year         int                 `json:"year"`
+	Category     string              `json:"category"`
+	InputType    models.PayInputType `json:"input_type"`
+	Description  string              `json:"description"`
+	Amount       money.Money         `json:"amount"`
+	TaxTreatment models.TaxTreatment `json:"tax_treatment"`
+	Reference    string              `json:"reference"`
+}
+
+func (req payInputRequest) toModel() *models.PayInput {
+	return &models.PayInput{
+		EmployeeID:   req.EmployeeID,
+		Month:        req.Month,
+		Year:         req.Year,
+		Category:     req.Category,
+		InputType:    req.InputType,
+		Description:  req.Description,
+		Amount:       req.Amount,
+		TaxTreatment: req.TaxTreatment,
+		Reference:    req.Reference,
+	}
+}
+
+// Categories returns the kinds of earnings and deductions that can be recorded
+func (h *PayInputHandler) Categories(w http.ResponseWriter, r *http.Request) {
+	utils.RespondJSON(w, http.StatusOK, h.service.Categories())
+}
+
+// List returns the inputs for a pay period, optionally for one employee
+func (h *PayInputHandler) List(w http.ResponseWriter, r *http.Request) {
+	month, year, ok := periodFromQuery(w, r)
+	if !ok {
+		return
+	}
+
+	var employeeID *uuid.UUID
+	if v := r.URL.Query().Get("employee_id"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			utils.RespondError(w, http.StatusBadRequest, "Invalid employee ID")
+			return
+		}
+		employeeID = &id
+	}
+
+	inputs, err := h.service.List(employeeID, month, year)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, "Failed to list pay inputs")
+		return
+	}
+	if inputs == nil {
+		inputs = []models.PayInput{}
+	}
+
+	utils.RespondJSON(w, http.StatusOK, inputs)
+}
+
+// Create records a single earning or deduction
+func (h *PayInputHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		utils.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req payInputRequest
+	if err := utils.DecodeJson(r, &req); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	input := req.toModel()
+	if err := h.service.Create(input, userID); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusCreated, input)
+}
+
+// Update changes an input that has not been paid yet
+func (h *PayInputHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid pay input ID")
+		return
+	}
+
+	var req payInputRequest
+	if err := utils.DecodeJson(r, &req); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	input, err := h.service.Update(id, req.toModel())
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, input)
+}
+
+// Delete removes an input that has not been paid yet
+func (h *PayInputHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid pay input ID")
+		return
+	}
+
+	if err := h.service.Delete(id); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, map[string]string{"message": "Pay input deleted"})
+}
+
+// Upload imports a CSV of inputs for the period given by ?month=&year=. The file is
+// sent as the "file" field of a multipart form or as the raw request body. With
+// ?validate_only=true nothing is saved; otherwise the rows are saved only if all are
+// valid, and a 422 with the row report is returned when any is not.
+func (h *PayInputHandler) Upload(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		utils.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	month, year, ok := periodFromQuery(w, r)
+	if !ok {
+		return
+	}
+	validateOnly, _ := strconv.ParseBool(r.URL.Query().Get("validate_only"))
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxPayInputUpload)
+	var file io.Reader = r.Body
+	reference := "upload"
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		f, header, err := r.FormFile("file")
+		if err != nil {
+			utils.RespondError(w, http.StatusBadRequest, "A CSV file is required in the 'file' field")
+			return
+		}
+		defer f.Close()
+		file = f
+		reference = header.Filename
+	}
+
+	report, err := h.service.Upload(file, month, year, reference, validateOnly, userID)
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	status := http.StatusOK
+	if report.Saved {
+		status = http.StatusCreated
+	} else if report.ErrorRows > 0 {
+		status = http.StatusUnprocessableEntity
+	}
+	utils.RespondJSON(w, status, report)
+}
+
+// ValidatePeriod reports problems with a period's inputs before payroll is processed
+func (h *PayInputHandler) ValidatePeriod(w http.ResponseWriter, r *http.Request) {
+	month, year, ok := periodFromQuery(w, r)
+	if !ok {
+		return
+	}
+
+	report, err := h.service.ValidatePeriod(month, year)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, "Failed to validate pay inputs")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, report)
+}
+
+// periodFromQuery reads the required month and year query parameters
+func periodFromQuery(w http.ResponseWriter, r *http.Request) (int, int, bool) {
+	month, err := strconv.Atoi(r.URL.Query().Get("month"))
+	if err != nil || month < 1 || month > 12 {
+		utils.RespondError(w, http.StatusBadRequest, "month must be between 1 and 12")
+		return 0, 0, false
+	}
+	year, err := strconv.Atoi(r.URL.Query().Get("year"))
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid year")
+		return 0, 0, false
+	}
+	return month, year, true
+}
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"hr-system/internal/middleware"
+	"hr-system/internal/services"
+	"hr-system/pkg/utils"
+
+	"github.com/google/uuid"
+)
+
+type PaymentBatchHandler struct {
+	service *services.PaymentBatchService
+}
+
+func NewPaymentBatchHandler(service *services.PaymentBatchService) *PaymentBatchHandler {
+	return &PaymentBatchHandler{service: service}
+}
+
+// Generate creates a bank transfer file for a completed payroll's unpaid payslips
+func (h *PaymentBatchHandler) Generate(w http.ResponseWriter, r *http.Request) {
+	payrollID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid payroll ID")
+		return
+	}
+
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		utils.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req struct {
+		Format    string `json:"format"`
+		ValueDate string `json:"value_date"`
+	}
+	if err := utils.DecodeJson(r, &req); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	var valueDate time.Time
+	if req.ValueDate != "" {
+		valueDate, err = time.Parse("2006-01-02", req.ValueDate)
+		if err != nil {
+			utils.RespondError(w, http.StatusBadRequest, "Invalid value_date format, use YYYY-MM-DD")
+			return
+		}
+	}
+
+	batch, err := h.service.Generate(payrollID, req.Format, valueDate, userID)
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusCreated, batch)
+}
+
+// ListByPayroll returns the payment batches generated for a payroll
+func (h *PaymentBatchHandler) ListByPayroll(w http.ResponseWriter, r *http.Request) {
+	payrollID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid payroll ID")
+		return
+	}
+
+	batches, err := h.service.ListByPayroll(payrollID)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, "Failed to list payment batches")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, batches)
+}
+
+// Download streams a previously generated payment file
+func (h *PaymentBatchHandler) Download(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid payment batch ID")
+		return
+	}
+
+	batch, err := h.service.GetByID(id)
+	if err != nil {
+		utils.RespondError(w, http.StatusNotFound, "Payment batch not found")
+		return
+	}
+
+	w.Header().Set("Content-Type", batch.ContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", batch.FileName))
+	w.Header().Set("Content-Length", strconv.Itoa(len(batch.Content)))
+	w.Header().Set("X-Batch-Reference", batch.BatchReference)
+	w.WriteHeader(http.StatusOK)
+	w.Write(batch.Content)
+}
+
+// Formats lists the supported bank file formats
+func (h *PaymentBatchHandler) Formats(w http.ResponseWriter, r *http.Request) {
+	utils.RespondJSON(w, http.StatusOK, h.service.Formats())
+}
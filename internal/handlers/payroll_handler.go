@@ -2,9 +2,11 @@ package handlers
 
 import (
 	"net/http"
+	"strconv"
 	"time"
 
 	"hr-system/internal/middleware"
+	"hr-system/internal/models"
 	"hr-system/internal/services"
 	"hr-system/pkg/utils"
 
@@ -19,11 +21,15 @@ func NewPayrollHandler(service *services.PayrollService) *PayrollHandler {
 	return &PayrollHandler{service: service}
 }
 
-// Create opens a new payroll period
+// Create opens a new payroll run for a pay group (the default group if none is given).
+// For regular runs end_date may be omitted; it is taken from the group's pay calendar.
 func (h *PayrollHandler) Create(w http.ResponseWriter, r *http.Request) {
 	var req struct {
-		StartDate string `json:"start_date"`
-		EndDate   string `json:"end_date"`
+		PayGroupID   *uuid.UUID `json:"pay_group_id"`
+		StartDate    string     `json:"start_date"`
+		EndDate      string     `json:"end_date"`
+		RunType      string     `json:"run_type"`
+		BonusPercent float64    `json:"bonus_percent"`
 	}
 	if err := utils.DecodeJson(r, &req); err != nil {
 		utils.RespondError(w, http.StatusBadRequest, "Invalid request body")
@@ -35,13 +41,17 @@ func (h *PayrollHandler) Create(w http.ResponseWriter, r *http.Request) {
 		utils.RespondError(w, http.StatusBadRequest, "Invalid start_date format, use YYYY-MM-DD")
 		return
 	}
-	endDate, err := time.Parse("2006-01-02", req.EndDate)
-	if err != nil {
-		utils.RespondError(w, http.StatusBadRequest, "Invalid end_date format, use YYYY-MM-DD")
-		return
+	var endDate *time.Time
+	if req.EndDate != "" {
+		d, err := time.Parse("2006-01-02", req.EndDate)
+		if err != nil {
+			utils.RespondError(w, http.StatusBadRequest, "Invalid end_date format, use YYYY-MM-DD")
+			return
+		}
+		endDate = &d
 	}
 
-	payroll, err := h.service.Create(startDate, endDate)
+	payroll, err := h.service.Create(req.PayGroupID, startDate, endDate, models.PayrollRunType(req.RunType), req.BonusPercent)
 	if err != nil {
 		utils.RespondError(w, http.StatusBadRequest, err.Error())
 		return
@@ -67,12 +77,19 @@ func (h *PayrollHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 	utils.RespondJSON(w, http.StatusOK, payroll)
 }
 
-// List returns paginated payrolls
+// List returns paginated payrolls, optionally filtered by status and pay group
 func (h *PayrollHandler) List(w http.ResponseWriter, r *http.Request) {
 	pag := utils.ParsePagination(r)
 	status := r.URL.Query().Get("status")
 
-	payrolls, total, err := h.service.List(status, pag.Page, pag.PageSize)
+	var payGroupID *uuid.UUID
+	if v := r.URL.Query().Get("pay_group_id"); v != "" {
+		if id, err := uuid.Parse(v); err == nil {
+			payGroupID = &id
+		}
+	}
+
+	payrolls, total, err := h.service.List(status, payGroupID, pag.Page, pag.PageSize)
 	if err != nil {
 		utils.RespondError(w, http.StatusInternalServerError, "Failed to list payrolls")
 		return
@@ -86,7 +103,7 @@ func (h *PayrollHandler) List(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// Process generates payslips for all active employees and completes the payroll
+// Process generates draft payslips for all active employees for review
 func (h *PayrollHandler) Process(w http.ResponseWriter, r *http.Request) {
 	id, err := uuid.Parse(r.PathValue("id"))
 	if err != nil {
@@ -109,6 +126,166 @@ func (h *PayrollHandler) Process(w http.ResponseWriter, r *http.Request) {
 	utils.RespondJSON(w, http.StatusAccepted, payroll)
 }
 
+// RunItems lists each employee's outcome in the payroll run (filter with ?status=failed)
+func (h *PayrollHandler) RunItems(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid payroll ID")
+		return
+	}
+
+	items, err := h.service.ListRunItems(id, r.URL.Query().Get("status"))
+	if err != nil {
+		utils.RespondError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, items)
+}
+
+// RetryFailed regenerates payslips for employees that failed during processing
+func (h *PayrollHandler) RetryFailed(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid payroll ID")
+		return
+	}
+
+	payroll, err := h.service.RetryFailed(id)
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusAccepted, payroll)
+}
+
+// Variance compares the payroll's payslips against the previous period
+func (h *PayrollHandler) Variance(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid payroll ID")
+		return
+	}
+
+	threshold := 0.0
+	if v := r.URL.Query().Get("threshold"); v != "" {
+		if t, err := strconv.ParseFloat(v, 64); err == nil {
+			threshold = t
+		}
+	}
+
+	report, err := h.service.VarianceReport(id, threshold)
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, report)
+}
+
+// Approve finalises a draft payroll and marks it as completed
+func (h *PayrollHandler) Approve(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid payroll ID")
+		return
+	}
+
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		utils.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	payroll, err := h.service.Approve(id, userID)
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, payroll)
+}
+
+// Reject discards a draft payroll's payslips and reopens it
+func (h *PayrollHandler) Reject(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid payroll ID")
+		return
+	}
+
+	payroll, err := h.service.Reject(id)
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, payroll)
+}
+
+// Reverse voids a completed payroll's payslips and reopens it for re-run
+func (h *PayrollHandler) Reverse(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid payroll ID")
+		return
+	}
+
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		utils.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req struct {
+		Reason string `json:"reason"`
+	}
+	if err := utils.DecodeJson(r, &req); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	payroll, err := h.service.Reverse(id, req.Reason, userID)
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, payroll)
+}
+
+// Supplementary issues an off-cycle correction payslip for one employee
+func (h *PayrollHandler) Supplementary(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid payroll ID")
+		return
+	}
+
+	var req struct {
+		EmployeeID string `json:"employee_id"`
+		Reason     string `json:"reason"`
+	}
+	if err := utils.DecodeJson(r, &req); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	employeeID, err := uuid.Parse(req.EmployeeID)
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid employee_id")
+		return
+	}
+
+	payslip, err := h.service.IssueSupplementary(id, employeeID, req.Reason)
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusCreated, payslip)
+}
+
 // Cancel marks a payroll as cancelled
 func (h *PayrollHandler) Cancel(w http.ResponseWriter, r *http.Request) {
 	id, err := uuid.Parse(r.PathValue("id"))
@@ -1,10 +1,12 @@
 package handlers
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
 
 	"hr-system/internal/middleware"
+	"hr-system/internal/models"
 	"hr-system/internal/services"
 	"hr-system/pkg/utils"
 
@@ -12,11 +14,12 @@ import (
 )
 
 type PayslipHandler struct {
-	service *services.PayslipService
+	service    *services.PayslipService
+	pdfService *services.PayslipPDFService
 }
 
-func NewPayslipHandler(service *services.PayslipService) *PayslipHandler {
-	return &PayslipHandler{service: service}
+func NewPayslipHandler(service *services.PayslipService, pdfService *services.PayslipPDFService) *PayslipHandler {
+	return &PayslipHandler{service: service, pdfService: pdfService}
 }
 
 // Generate creates a payslip for an employee for the given month/year
@@ -63,6 +66,52 @@ func (h *PayslipHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 	utils.RespondJSON(w, http.StatusOK, payslip)
 }
 
+// DownloadPDF streams a payslip as a PDF. Employees may only download their own
+// payslips; SuperAdmin and HRManager may download any.
+func (h *PayslipHandler) DownloadPDF(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid payslip ID")
+		return
+	}
+
+	user, ok := r.Context().Value(middleware.UserKey).(*models.User)
+	if !ok || user == nil {
+		utils.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	payslip, err := h.service.GetByID(id)
+	if err != nil {
+		utils.RespondError(w, http.StatusNotFound, "Payslip not found")
+		return
+	}
+
+	if user.Role == nil || (user.Role.Name != models.RoleSuperAdmin && user.Role.Name != models.RoleHRManager) {
+		emp, err := h.service.GetEmployeeByUserID(user.UserID)
+		if err != nil || emp.ID != payslip.EmployeeID {
+			utils.RespondError(w, http.StatusForbidden, "You can only download your own payslips")
+			return
+		}
+		if payslip.Status != models.PayslipStatusFinal {
+			utils.RespondError(w, http.StatusNotFound, "Payslip not found")
+			return
+		}
+	}
+
+	data, filename, err := h.pdfService.Render(id)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
 // List returns paginated payslips with optional filters
 func (h *PayslipHandler) List(w http.ResponseWriter, r *http.Request) {
 	pag := utils.ParsePagination(r)
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"hr-system/internal/middleware"
+	"hr-system/internal/models"
+	"hr-system/internal/services"
+	"hr-system/pkg/utils"
+
+	"github.com/google/uuid"
+)
+
+type RetroPayHandler struct {
+	service *services.RetroPayService
+}
+
+func NewRetroPayHandler(service *services.RetroPayService) *RetroPayHandler {
+	return &RetroPayHandler{service: service}
+}
+
+// Calculate recalculates paid periods since effective_from for the given employees (or
+// everyone in a position) and records the differences as arrears for the next payroll.
+// With "preview": true the arrears are returned without being saved.
+func (h *RetroPayHandler) Calculate(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		utils.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req struct {
+		EmployeeIDs   []uuid.UUID `json:"employee_ids"`
+		PositionID    *uuid.UUID  `json:"position_id"`
+		EffectiveFrom string      `json:"effective_from"` // YYYY-MM-DD
+		Reason        string      `json:"reason"`
+		Preview       bool        `json:"preview"`
+	}
+	if err := utils.DecodeJson(r, &req); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	effectiveFrom, err := time.Parse("2006-01-02", req.EffectiveFrom)
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid effective_from format, use YYYY-MM-DD")
+		return
+	}
+
+	report, err := h.service.Calculate(services.RetroPayRequest{
+		EmployeeIDs:   req.EmployeeIDs,
+		PositionID:    req.PositionID,
+		EffectiveFrom: effectiveFrom,
+		Reason:        req.Reason,
+		Preview:       req.Preview,
+	}, userID)
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	status := http.StatusCreated
+	if req.Preview || len(report.Adjustments) == 0 {
+		status = http.StatusOK
+	}
+	utils.RespondJSON(w, status, report)
+}
+
+// List returns retro pay arrears (?employee_id=, ?status=pending|paid|cancelled)
+func (h *RetroPayHandler) List(w http.ResponseWriter, r *http.Request) {
+	var employeeID *uuid.UUID
+	if v := r.URL.Query().Get("employee_id"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			utils.RespondError(w, http.StatusBadRequest, "Invalid employee ID")
+			return
+		}
+		employeeID = &id
+	}
+
+	adjustments, err := h.service.List(employeeID, r.URL.Query().Get("status"))
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if adjustments == nil {
+		adjustments = []models.RetroPayAdjustment{}
+	}
+
+	utils.RespondJSON(w, http.StatusOK, adjustments)
+}
+
+// GetByID returns an adjustment with its per-period breakdown
+func (h *RetroPayHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid retro pay ID")
+		return
+	}
+
+	adjustment, err := h.service.GetByID(id)
+	if err != nil {
+		utils.RespondError(w, http.StatusNotFound, "Retro pay adjustment not found")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, adjustment)
+}
+
+// Cancel withdraws arrears that have not been paid yet
+func (h *RetroPayHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid retro pay ID")
+		return
+	}
+
+	if err := h.service.Cancel(id); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, map[string]string{"message": "Retro pay adjustment cancelled"})
+}
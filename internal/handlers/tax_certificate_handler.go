@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"hr-system/internal/middleware"
+	"hr-system/internal/services"
+	"hr-system/pkg/utils"
+
+	"github.com/google/uuid"
+)
+
+type TaxCertificateHandler struct {
+	service        *services.TaxCertificateService
+	payslipService *services.PayslipService
+}
+
+func NewTaxCertificateHandler(service *services.TaxCertificateService, payslipService *services.PayslipService) *TaxCertificateHandler {
+	return &TaxCertificateHandler{service: service, payslipService: payslipService}
+}
+
+// MyCertificate returns the current user's tax certificate (?year=, ?format=pdf)
+func (h *TaxCertificateHandler) MyCertificate(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		utils.RespondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	emp, err := h.service.GetEmployeeByUserID(userID)
+	if err != nil {
+		utils.RespondError(w, http.StatusNotFound, "No employee record found for this user")
+		return
+	}
+
+	h.certificate(w, r, emp.ID)
+}
+
+// EmployeeCertificate returns an employee's tax certificate (?year=, ?format=pdf)
+func (h *TaxCertificateHandler) EmployeeCertificate(w http.ResponseWriter, r *http.Request) {
+	employeeID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid employee ID")
+		return
+	}
+
+	h.certificate(w, r, employeeID)
+}
+
+// EmployeeYTD returns an employee's year-to-date accumulators (?year=)
+func (h *TaxCertificateHandler) EmployeeYTD(w http.ResponseWriter, r *http.Request) {
+	employeeID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, "Invalid employee ID")
+		return
+	}
+
+	ytd, err := h.payslipService.GetYTD(employeeID, taxYear(r))
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, "Failed to load YTD totals")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, ytd)
+}
+
+// AnnualReturn returns the employer annual return for a year (?year=, ?format=csv)
+func (h *TaxCertificateHandler) AnnualReturn(w http.ResponseWriter, r *http.Request) {
+	ret, err := h.service.AnnualReturn(taxYear(r))
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if r.URL.Query().Get("format") != "csv" {
+		utils.RespondJSON(w, http.StatusOK, ret)
+		return
+	}
+
+	data, err := h.service.AnnualReturnCSV(ret)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, "Failed to export annual return")
+		return
+	}
+	sendFile(w, "text/csv", fmt.Sprintf("annual-return-%d.csv", ret.Year), data)
+}
+
+func (h *TaxCertificateHandler) certificate(w http.ResponseWriter, r *http.Request, employeeID uuid.UUID) {
+	year := taxYear(r)
+
+	if r.URL.Query().Get("format") == "pdf" {
+		data, filename, err := h.service.CertificatePDF(employeeID, year)
+		if err != nil {
+			utils.RespondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		sendFile(w, "application/pdf", filename, data)
+		return
+	}
+
+	cert, err := h.service.Certificate(employeeID, year)
+	if err != nil {
+		utils.RespondError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, cert)
+}
+
+// taxYear reads ?year=, defaulting to the current year
+func taxYear(r *http.Request) int {
+	if y, err := strconv.Atoi(r.URL.Query().Get("year")); err == nil {
+		return y
+	}
+	return time.Now().Year()
+}
+
+// sendFile writes data as a file download
+func sendFile(w http.ResponseWriter, contentType, filename string, data []byte) {
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
@@ -12,6 +12,7 @@ type EmployeeFilter struct {
 	PositionID       *uuid.UUID
 	EmploymentStatus string
 	EmploymentType   string
+	PayGroupID       *uuid.UUID
 	IncludeDeleted   bool
 }
 
@@ -8,7 +8,7 @@ import (
 
 type AdjustBalanceInput struct {
 	LeaveBalanceID uuid.UUID
-	Delta          int
+	Delta          float64
 	Reason         string
 }
 
@@ -17,7 +17,7 @@ type LeaveBalanceInterface interface {
 	GetByEmployeeTypeYear(employeeID, leaveTypeID uuid.UUID, year int) (*models.LeaveBalance, error)
 	InitializeForEmployee(employeeID uuid.UUID, year int) error
 	Adjust(input AdjustBalanceInput) error
-	IncrementPending(employeeID, leaveTypeID uuid.UUID, year, days int) error
-	DecrementPending(employeeID, leaveTypeID uuid.UUID, year, days int) error
-	ApproveLeave(employeeID, leaveTypeID uuid.UUID, year, days int) error
+	IncrementPending(employeeID, leaveTypeID uuid.UUID, year int, days float64) error
+	DecrementPending(employeeID, leaveTypeID uuid.UUID, year int, days float64) error
+	ApproveLeave(employeeID, leaveTypeID uuid.UUID, year int, days float64) error
 }
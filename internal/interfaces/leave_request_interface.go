@@ -9,11 +9,27 @@ import (
 )
 
 type LeaveRequestFilter struct {
-	EmployeeID   *uuid.UUID
-	Status       string
-	StartDateGTE *time.Time
-	EndDateLTE   *time.Time
-	DepartmentID *uuid.UUID
+	EmployeeID     *uuid.UUID
+	Status         string
+	DocumentStatus string
+	StartDateGTE   *time.Time
+	EndDateLTE     *time.Time
+	DepartmentID   *uuid.UUID
+}
+
+// AmendLeaveInput holds the changes to a leave request; nil fields keep their current value
+type AmendLeaveInput struct {
+	LeaveTypeID *uuid.UUID           `json:"leave_type_id"`
+	StartDate   *time.Time           `json:"start_date"`
+	EndDate     *time.Time           `json:"end_date"`
+	StartPart   *models.LeaveDayPart `json:"start_part"`
+	EndPart     *models.LeaveDayPart `json:"end_part"`
+	Hours       *float64             `json:"hours"`
+	Reason      *string              `json:"reason"`
+
+	// Set when HR amends the request; leave rules it breaks are overridden with the reason
+	RuleOverrideReason string     `json:"rule_override_reason"`
+	RuleOverriddenBy   *uuid.UUID `json:"-"`
 }
 
 type LeaveRequestInterface interface {
@@ -21,6 +37,7 @@ type LeaveRequestInterface interface {
 	GetByID(id uuid.UUID) (*models.LeaveRequest, error)
 	List(filter LeaveRequestFilter, page, pageSize int) ([]models.LeaveRequest, int, error)
 	Cancel(id, employeeID uuid.UUID) error
+	Amend(id, employeeID uuid.UUID, input AmendLeaveInput) (*models.LeaveRequest, error)
 	Approve(id, reviewerID uuid.UUID, comment string) error
 	Reject(id, reviewerID uuid.UUID, comment string) error
 	HasOverlap(employeeID uuid.UUID, start, end time.Time, excludeID *uuid.UUID) (bool, error)
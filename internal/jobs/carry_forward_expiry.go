@@ -0,0 +1,50 @@
+package jobs
+
+import (
+	"log"
+	"time"
+
+	"hr-system/internal/services"
+)
+
+// CarryForwardExpiryJob runs every day at 06:00 UTC and expires carried-forward leave:
+//  1. Employees whose carried days expire within the configured reminder days, and
+//     who have not booked leave to use them, are emailed once per reminder.
+//  2. Once the expiry date has passed, carried days left unused are forfeited and
+//     written to the leave ledger. Carried days are used first, so leave taken
+//     before expiry comes out of them rather than the year's entitlement.
+type CarryForwardExpiryJob struct {
+	service *services.LeaveCarryForwardService
+}
+
+func NewCarryForwardExpiryJob(service *services.LeaveCarryForwardService) *CarryForwardExpiryJob {
+	return &CarryForwardExpiryJob{service: service}
+}
+
+// Start launches the job as a background goroutine.
+func (j *CarryForwardExpiryJob) Start() {
+	go j.loop()
+}
+
+func (j *CarryForwardExpiryJob) loop() {
+	waitUntilDailyRun("CarryForwardExpiry")
+	j.run()
+
+	ticker := time.NewTicker(24 * time.Hour)
+	defer ticker.Stop()
+	for range ticker.C {
+		j.run()
+	}
+}
+
+func (j *CarryForwardExpiryJob) run() {
+	log.Println("[CarryForwardExpiry] Expiring carried-forward leave")
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	result, err := j.service.ExpireCarryForwards(today)
+	if err != nil {
+		log.Printf("[CarryForwardExpiry] ERROR: could not list expiring carry-forwards: %v", err)
+		return
+	}
+	log.Printf("[CarryForwardExpiry] Done — reminded %d, forfeited %d, used in time %d, failed %d",
+		result.Reminded, result.Forfeited, result.Used, result.Failed)
+}
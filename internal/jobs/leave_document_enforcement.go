@@ -0,0 +1,61 @@
+package jobs
+
+import (
+	"log"
+	"time"
+
+	"hr-system/internal/services"
+)
+
+// LeaveDocumentEnforcementJob runs every day at 06:00 UTC and chases the documents (sick
+// notes) that leave types require:
+//  1. Employees whose leave has ended without the document are reminded once.
+//  2. Requests still without it after the leave type's grace days are escalated to HR
+//     or converted to unpaid leave, as the leave type's missing_document_action says.
+type LeaveDocumentEnforcementJob struct {
+	service *services.LeaveDocumentService
+}
+
+func NewLeaveDocumentEnforcementJob(service *services.LeaveDocumentService) *LeaveDocumentEnforcementJob {
+	return &LeaveDocumentEnforcementJob{service: service}
+}
+
+// Start launches the job as a background goroutine.
+func (j *LeaveDocumentEnforcementJob) Start() {
+	go j.loop()
+}
+
+func (j *LeaveDocumentEnforcementJob) loop() {
+	waitUntilDailyRun("LeaveDocuments")
+	j.run()
+
+	ticker := time.NewTicker(24 * time.Hour)
+	defer ticker.Stop()
+	for range ticker.C {
+		j.run()
+	}
+}
+
+func (j *LeaveDocumentEnforcementJob) run() {
+	log.Println("[LeaveDocuments] Chasing missing leave documents")
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	result, err := j.service.EnforceDocuments(today)
+	if err != nil {
+		log.Printf("[LeaveDocuments] ERROR: could not list requests awaiting documents: %v", err)
+		return
+	}
+	log.Printf("[LeaveDocuments] Done — reminded %d, escalated %d, converted %d, failed %d",
+		result.Reminded, result.Escalated, result.Converted, result.Failed)
+}
+
+// waitUntilDailyRun blocks until the next 06:00 UTC, logging under the job's prefix.
+func waitUntilDailyRun(prefix string) {
+	now := time.Now().UTC()
+	next := time.Date(now.Year(), now.Month(), now.Day(), 6, 0, 0, 0, time.UTC)
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	wait := time.Until(next)
+	log.Printf("[%s] Next run scheduled in %s (on %s UTC)", prefix, wait.Round(time.Minute), next.Format("2006-01-02 15:04"))
+	time.Sleep(wait)
+}
@@ -17,26 +17,29 @@ import (
 // Accrual logic:
 //   - Only employees with employment_status = "active" are credited.
 //   - 2 days are added to the earned_leave_days column of the leave_balances row.
-//   - If an employee has no balance record for the current year yet, one is
+//   - If an employee has no balance record for the current leave year yet, one is
 //     initialised first (with zero entitlement) before the credit is applied.
 //   - The Annual Leave type is identified by its code "AL".
 type MonthlyLeaveAccrualJob struct {
 	empRepo        *repository.EmployeeRepository
 	lbRepo         *repository.LeaveBalanceRepository
 	ltRepo         *repository.LeaveTypeRepository
-	daysPerAccrual int
+	daysPerAccrual float64
+	leaveYear      models.LeaveYear
 }
 
 func NewMonthlyLeaveAccrualJob(
 	empRepo *repository.EmployeeRepository,
 	lbRepo *repository.LeaveBalanceRepository,
 	ltRepo *repository.LeaveTypeRepository,
+	leaveYear models.LeaveYear,
 ) *MonthlyLeaveAccrualJob {
 	return &MonthlyLeaveAccrualJob{
 		empRepo:        empRepo,
 		lbRepo:         lbRepo,
 		ltRepo:         ltRepo,
 		daysPerAccrual: 2,
+		leaveYear:      leaveYear,
 	}
 }
 
@@ -66,7 +69,7 @@ func (j *MonthlyLeaveAccrualJob) loop() {
 
 // run executes one accrual cycle.
 func (j *MonthlyLeaveAccrualJob) run() {
-	year := time.Now().Year()
+	year := j.leaveYear.Of(time.Now())
 
 	// 1. Resolve the Annual Leave type by its well-known code.
 	lt, err := j.ltRepo.GetByCode("AL")
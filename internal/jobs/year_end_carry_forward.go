@@ -8,23 +8,28 @@ import (
 	"hr-system/internal/repository"
 )
 
-// YearEndCarryForwardJob runs once a year on 31 December at 23:00 UTC.
+// YearEndCarryForwardJob runs once a year at 23:00 UTC on the last day of the
+// leave year (31 December for calendar-year leave).
 // For every employee balance it:
-//  1. Calculates the remaining days (balance) for the ending year.
+//  1. Calculates the remaining days (balance) for the ending leave year.
 //  2. If the leave type allows carry-forward, caps the remainder at
 //     max_carry_forward_days and writes that value as carried_forward on
-//     the new year's balance row (creating it if it doesn't exist yet).
-//  3. Leave types with is_carry_forward_allowed=false are skipped (0 carries over).
+//     the new leave year's balance row (creating it if it doesn't exist yet).
+//  3. Records when the carried days expire, carry_forward_expiry_months into the
+//     new leave year; CarryForwardExpiryJob forfeits whatever is unused by then.
+//  4. Leave types with is_carry_forward_allowed=false are skipped (0 carries over).
 type YearEndCarryForwardJob struct {
-	lbRepo *repository.LeaveBalanceRepository
-	ltRepo *repository.LeaveTypeRepository
+	lbRepo    *repository.LeaveBalanceRepository
+	ltRepo    *repository.LeaveTypeRepository
+	leaveYear models.LeaveYear
 }
 
 func NewYearEndCarryForwardJob(
 	lbRepo *repository.LeaveBalanceRepository,
 	ltRepo *repository.LeaveTypeRepository,
+	leaveYear models.LeaveYear,
 ) *YearEndCarryForwardJob {
-	return &YearEndCarryForwardJob{lbRepo: lbRepo, ltRepo: ltRepo}
+	return &YearEndCarryForwardJob{lbRepo: lbRepo, ltRepo: ltRepo, leaveYear: leaveYear}
 }
 
 // Start launches the job as a background goroutine.
@@ -33,7 +38,7 @@ func (j *YearEndCarryForwardJob) Start() {
 }
 
 func (j *YearEndCarryForwardJob) loop() {
-	waitUntilYearEnd()
+	waitUntilYearEnd(j.leaveYear)
 	log.Println("[CarryForward] Running year-end carry-forward")
 	j.run()
 
@@ -47,7 +52,7 @@ func (j *YearEndCarryForwardJob) loop() {
 }
 
 func (j *YearEndCarryForwardJob) run() {
-	endingYear := time.Now().Year()
+	endingYear := j.leaveYear.Of(time.Now())
 	newYear := endingYear + 1
 
 	// Fetch all leave type carry-forward rules keyed by leave type ID.
@@ -91,8 +96,8 @@ func (j *YearEndCarryForwardJob) run() {
 
 		// Cap at max_carry_forward_days.
 		carryDays := remaining
-		if lt.MaxCarryForwardDays > 0 && carryDays > lt.MaxCarryForwardDays {
-			carryDays = lt.MaxCarryForwardDays
+		if maxDays := float64(lt.MaxCarryForwardDays); maxDays > 0 && carryDays > maxDays {
+			carryDays = maxDays
 		}
 
 		// Ensure a balance row exists for the new year.
@@ -118,8 +123,12 @@ func (j *YearEndCarryForwardJob) run() {
 			}
 		}
 
-		// Write the carry-forward days onto the new year's row.
-		if err := j.lbRepo.SetCarriedForward(newLB.ID, carryDays); err != nil {
+		// Write the carry-forward days and their expiry onto the new year's row.
+		var expiresOn *time.Time
+		if expiry, ok := lt.CarryForwardExpiry(j.leaveYear, newYear); ok {
+			expiresOn = &expiry
+		}
+		if err := j.lbRepo.SetCarriedForward(newLB.ID, carryDays, expiresOn); err != nil {
 			log.Printf("[CarryForward] WARN: could not set carried_forward for employee %s: %v", lb.EmployeeID, err)
 			skipped++
 			continue
@@ -130,13 +139,13 @@ func (j *YearEndCarryForwardJob) run() {
 	log.Printf("[CarryForward] Done — carried forward %d balances, skipped %d", carried, skipped)
 }
 
-// waitUntilYearEnd blocks until 31 December at 23:00 UTC.
-func waitUntilYearEnd() {
+// waitUntilYearEnd blocks until 23:00 UTC on the last day of the current leave year.
+func waitUntilYearEnd(leaveYear models.LeaveYear) {
 	now := time.Now().UTC()
-	yearEnd := time.Date(now.Year(), 12, 31, 23, 0, 0, 0, time.UTC)
+	yearEnd := leaveYear.End(leaveYear.Of(now)).Add(23 * time.Hour)
 	// If we're already past this year's run time, target next year's.
 	if now.After(yearEnd) {
-		yearEnd = time.Date(now.Year()+1, 12, 31, 23, 0, 0, 0, time.UTC)
+		yearEnd = leaveYear.End(leaveYear.Of(now) + 1).Add(23 * time.Hour)
 	}
 	wait := time.Until(yearEnd)
 	log.Printf("[CarryForward] Next run scheduled in %s (on %s UTC)", wait.Round(time.Minute), yearEnd.Format("2006-01-02 15:04"))
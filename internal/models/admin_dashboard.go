@@ -1,5 +1,7 @@
 package models
 
+import "hr-system/pkg/money"
+
 type AdminDashboard struct {
 	TotalEmployees     int                   `json:"total_employees"`
 	TotalDepartments   int                   `json:"total_departments"`
@@ -11,9 +13,9 @@ type AdminDashboard struct {
 }
 
 type MonthlyPayrollCost struct {
-	Month          string  `json:"month"`
-	Year           int     `json:"year"`
-	TotalNetSalary float64 `json:"total_net_salary"`
+	Month          string      `json:"month"`
+	Year           int         `json:"year"`
+	TotalNetSalary money.Money `json:"total_net_salary"`
 }
 
 type HiringTrend struct {
@@ -35,4 +35,9 @@ type Attendance struct {
 	Source        AttendanceSource `json:"source"`
 	CreatedAt     time.Time        `json:"created_at"`
 	UpdatedAt     time.Time        `json:"updated_at"`
+
+	// Overtime only counts towards pay once approved by a manager
+	OvertimeApproved   bool       `json:"overtime_approved"`
+	OvertimeApprovedBy *uuid.UUID `json:"overtime_approved_by,omitempty"`
+	OvertimeApprovedAt *time.Time `json:"overtime_approved_at,omitempty"`
 }
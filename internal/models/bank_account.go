@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type BankAccountStatus string
+
+const (
+	BankAccountPending    BankAccountStatus = "pending"
+	BankAccountApproved   BankAccountStatus = "approved"
+	BankAccountRejected   BankAccountStatus = "rejected"
+	BankAccountSuperseded BankAccountStatus = "superseded" // replaced by a newer approved account
+)
+
+// EmployeeBankAccount is the account an employee's net pay is transferred to.
+// Changes are submitted as pending records and take effect once approved.
+type EmployeeBankAccount struct {
+	ID              uuid.UUID         `json:"id"`
+	EmployeeID      uuid.UUID         `json:"employee_id"`
+	BankName        string            `json:"bank_name"`
+	BankCode        string            `json:"bank_code"`
+	BranchCode      string            `json:"branch_code"`
+	AccountNumber   string            `json:"account_number"`
+	AccountName     string            `json:"account_name"`
+	Status          BankAccountStatus `json:"status"`
+	RequestedBy     *uuid.UUID        `json:"requested_by,omitempty"`
+	ReviewedBy      *uuid.UUID        `json:"reviewed_by,omitempty"`
+	ReviewedAt      *time.Time        `json:"reviewed_at,omitempty"`
+	RejectionReason string            `json:"rejection_reason,omitempty"`
+	CreatedAt       time.Time         `json:"created_at"`
+	UpdatedAt       time.Time         `json:"updated_at"`
+
+	// Relations (populated on demand)
+	EmployeeName string `json:"employee_name,omitempty"`
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CalendarFeedToken is the secret in a user's calendar subscription URLs
+type CalendarFeedToken struct {
+	ID         uuid.UUID  `json:"id"`
+	UserID     uuid.UUID  `json:"user_id"`
+	Token      string     `json:"-"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CalendarFeeds are the iCalendar URLs a user can subscribe to. TeamLeave is only offered
+// to employees with people reporting to them.
+type CalendarFeeds struct {
+	MyLeave    string     `json:"my_leave"`
+	TeamLeave  string     `json:"team_leave,omitempty"`
+	Holidays   string     `json:"holidays"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
@@ -10,9 +10,10 @@ type EmployeeDashboardStats struct {
 
 	Holidays Holidays `json:"holidays_this_month"`
 
-	LeaveDaysThisMonth int `json:"leave_days_this_month"` //leave days earned this month
-	YearlyEntitlement  int `json:"yearly_entitlement"`    //total entitled for the year, excluding carried forward and earned leave days
-	LeaveRequests      int `json:"leave_requests"`
+	LeaveDaysThisMonth float64 `json:"leave_days_this_month"` //leave days earned this month
+	YearlyEntitlement  float64 `json:"yearly_entitlement"`    //total entitled for the year, excluding carried forward and earned leave days
+	LeaveYear          string  `json:"leave_year"`            //leave year the balances belong to, e.g. "2026/27"
+	LeaveRequests      int     `json:"leave_requests"`
 }
 
 type EmployeDetails struct {
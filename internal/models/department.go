@@ -13,6 +13,7 @@ type Department struct {
 	Description        string     `json:"description"`
 	ParentDepartmentID *uuid.UUID `json:"parent_department_id,omitempty"`
 	ManagerID          *uuid.UUID `json:"manager_id,omitempty"`
+	MinStaffOnDuty     int        `json:"min_staff_on_duty"` // employees needed at work on a working day, 0 for no minimum
 	IsActive           bool       `json:"is_active"`
 	CreatedAt          time.Time  `json:"created_at"`
 	UpdatedAt          time.Time  `json:"updated_at"`
@@ -49,6 +49,7 @@ type Employee struct {
 	TerminationDate    *time.Time       `json:"termination_date,omitempty"`
 	TerminationReason  string           `json:"termination_reason"`
 	ProfilePhotoURL    string           `json:"profile_photo_url"`
+	PayGroupID         *uuid.UUID       `json:"pay_group_id,omitempty"` // nil: paid with the default pay group
 	CreatedAt          time.Time        `json:"created_at"`
 	UpdatedAt          time.Time        `json:"updated_at"`
 	DeletedAt          *time.Time       `json:"deleted_at,omitempty"`
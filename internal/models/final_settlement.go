@@ -0,0 +1,75 @@
+package models
+
+import (
+	"time"
+
+	"hr-system/pkg/money"
+
+	"github.com/google/uuid"
+)
+
+type FinalSettlementStatus string
+
+const (
+	FinalSettlementPending   FinalSettlementStatus = "pending" // awaiting HR approval
+	FinalSettlementApproved  FinalSettlementStatus = "approved"
+	FinalSettlementCancelled FinalSettlementStatus = "cancelled"
+)
+
+// FinalSettlement is the statement of what an employee who leaves is owed: salary to
+// their last day, unused leave, pay in lieu of notice and gratuity, less tax and the
+// loans still outstanding. Once HR approves it, it is paid on a final payslip.
+type FinalSettlement struct {
+	ID              uuid.UUID        `json:"id"`
+	EmployeeID      uuid.UUID        `json:"employee_id"`
+	TerminationDate time.Time        `json:"termination_date"`
+	SeparationType  EmploymentStatus `json:"separation_type"` // terminated or resigned
+	// The pay period the final payslip covers. It is the one the termination date falls
+	// in, or the next one when that period has already been paid.
+	PeriodStart time.Time `json:"period_start"`
+	PeriodEnd   time.Time `json:"period_end"`
+	DaysWorked  int       `json:"days_worked"` // days of the period still to be paid salary for
+	PeriodDays  int       `json:"period_days"`
+
+	ProratedSalary  money.Money `json:"prorated_salary"` // base salary and allowances for DaysWorked
+	OtherEarnings   money.Money `json:"other_earnings"`  // overtime, one-off earnings and leave encashments in the period
+	LeaveDays       float64     `json:"leave_days"`      // unused annual leave encashed
+	LeaveEncashment money.Money `json:"leave_encashment"`
+	NoticeDays      int         `json:"notice_days"` // working days paid in lieu of notice
+	NoticePay       money.Money `json:"notice_pay"`
+	ServiceYears    int         `json:"service_years"` // completed years of service
+	Gratuity        money.Money `json:"gratuity"`
+	Arrears         money.Money `json:"arrears"` // unpaid retro pay arrears
+	GrossPay        money.Money `json:"gross_pay"`
+	IncomeTax       money.Money `json:"income_tax"`
+	OtherDeductions money.Money `json:"other_deductions"` // one-off deductions in the period
+	LoanRecovery    money.Money `json:"loan_recovery"`
+	LoanOutstanding money.Money `json:"loan_outstanding"` // loan balance the final pay cannot cover
+	NetPay          money.Money `json:"net_pay"`
+	Notes           string      `json:"notes,omitempty"`
+
+	Status     FinalSettlementStatus `json:"status"`
+	PayslipID  *uuid.UUID            `json:"payslip_id,omitempty"`
+	CreatedBy  *uuid.UUID            `json:"created_by,omitempty"`
+	ApprovedBy *uuid.UUID            `json:"approved_by,omitempty"`
+	ApprovedAt *time.Time            `json:"approved_at,omitempty"`
+	CreatedAt  time.Time             `json:"created_at"`
+	UpdatedAt  time.Time             `json:"updated_at"`
+
+	// Relations (populated on demand)
+	EmployeeName   string `json:"employee_name,omitempty"`
+	EmployeeNumber string `json:"employee_number,omitempty"`
+}
+
+// Period returns the pay period the final payslip covers
+func (s *FinalSettlement) Period() PayPeriod {
+	return PayPeriod{Start: s.PeriodStart, End: s.PeriodEnd}
+}
+
+// SalaryShare is the fraction of the period's salary still owed
+func (s *FinalSettlement) SalaryShare() float64 {
+	if s.PeriodDays == 0 {
+		return 0
+	}
+	return float64(s.DaysWorked) / float64(s.PeriodDays)
+}
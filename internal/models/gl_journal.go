@@ -0,0 +1,109 @@
+package models
+
+import (
+	"time"
+
+	"hr-system/pkg/money"
+
+	"github.com/google/uuid"
+)
+
+// GLComponent is a payroll amount that posts to a general-ledger account
+type GLComponent string
+
+// Debit components (expenses)
+const (
+	GLBasicSalary        GLComponent = "basic_salary"
+	GLHousingAllowance   GLComponent = "housing_allowance"
+	GLTransportAllowance GLComponent = "transport_allowance"
+	GLMedicalAllowance   GLComponent = "medical_allowance"
+	GLOvertime           GLComponent = "overtime"
+	GLBonus              GLComponent = "bonus"
+	GLLeavePay           GLComponent = "leave_pay"
+	GLEmployerPension    GLComponent = "employer_pension"
+	GLOtherEarnings      GLComponent = "other_earnings"
+	GLReimbursements     GLComponent = "reimbursements"
+	GLArrears            GLComponent = "arrears"
+	GLTermination        GLComponent = "termination_benefits"
+)
+
+// Credit components (liabilities, and recoveries of amounts owed by employees)
+const (
+	GLNetPay          GLComponent = "net_pay"
+	GLIncomeTax       GLComponent = "income_tax"
+	GLPensionPayable  GLComponent = "pension_payable"
+	GLLoanRecovery    GLComponent = "loan_recovery"
+	GLOtherDeductions GLComponent = "other_deductions"
+)
+
+// GLDebitComponents and GLCreditComponents list every component in journal order
+var (
+	GLDebitComponents = []GLComponent{
+		GLBasicSalary, GLHousingAllowance, GLTransportAllowance, GLMedicalAllowance,
+		GLOvertime, GLBonus, GLLeavePay, GLEmployerPension, GLOtherEarnings, GLReimbursements,
+		GLArrears, GLTermination,
+	}
+	GLCreditComponents = []GLComponent{GLNetPay, GLIncomeTax, GLPensionPayable, GLLoanRecovery, GLOtherDeductions}
+)
+
+// IsValid reports whether c is a known component
+func (c GLComponent) IsValid() bool {
+	if c.IsDebit() {
+		return true
+	}
+	for _, v := range GLCreditComponents {
+		if v == c {
+			return true
+		}
+	}
+	return false
+}
+
+// IsDebit reports whether the component is normally posted as a debit
+func (c GLComponent) IsDebit() bool {
+	for _, v := range GLDebitComponents {
+		if v == c {
+			return true
+		}
+	}
+	return false
+}
+
+// GLAccountMapping maps a payroll component to a GL account, optionally for one department
+type GLAccountMapping struct {
+	ID           uuid.UUID   `json:"id"`
+	Component    GLComponent `json:"component"`
+	DepartmentID *uuid.UUID  `json:"department_id,omitempty"`
+	AccountCode  string      `json:"account_code"`
+	AccountName  string      `json:"account_name"`
+	// CostCentre defaults to the department code when left blank
+	CostCentre string    `json:"cost_centre"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+
+	// Relations (populated on demand)
+	DepartmentName string `json:"department_name,omitempty"`
+}
+
+// JournalLine is one account/cost-centre posting in a payroll journal
+type JournalLine struct {
+	AccountCode    string      `json:"account_code"`
+	AccountName    string      `json:"account_name"`
+	CostCentre     string      `json:"cost_centre"`
+	DepartmentName string      `json:"department_name"`
+	Component      GLComponent `json:"component"`
+	Debit          money.Money `json:"debit"`
+	Credit         money.Money `json:"credit"`
+	Description    string      `json:"description"`
+}
+
+// PayrollJournal is the balanced GL journal for a completed payroll
+type PayrollJournal struct {
+	PayrollID   uuid.UUID     `json:"payroll_id"`
+	Reference   string        `json:"reference"`
+	PostingDate time.Time     `json:"posting_date"`
+	Period      string        `json:"period"`
+	TotalDebit  money.Money   `json:"total_debit"`
+	TotalCredit money.Money   `json:"total_credit"`
+	Lines       []JournalLine `json:"lines"`
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"math"
 	"time"
 
 	"github.com/google/uuid"
@@ -11,16 +12,27 @@ type LeaveBalance struct {
 	EmployeeID      uuid.UUID `json:"employee_id"`
 	LeaveTypeID     uuid.UUID `json:"leave_type_id"`
 	Year            int       `json:"year"`
-	TotalEntitled   int       `json:"total_entitled"` //total entitled for the year, excluding carried forward and earned leave days
-	Used            int       `json:"used"`
-	Pending         int       `json:"pending"`           //pending leave requests that are not yet approved
-	CarriedForward  int       `json:"carried_forward"`   //unused days from previous year that are carried forward
-	EarnedLeaveDays int       `json:"earned_leave_days"` //days earned through tenure, not counted in total_entitled
-	// Balance is computed: total_entitled + carried_forward + earned_leave_days - used - pending
-	Balance   int       `json:"balance"`
+	TotalEntitled   float64   `json:"total_entitled"` //total entitled for the year, excluding carried forward and earned leave days
+	Used            float64   `json:"used"`
+	Pending         float64   `json:"pending"`           //pending leave requests that are not yet approved
+	CarriedForward  float64   `json:"carried_forward"`   //unused days from previous year that are carried forward
+	EarnedLeaveDays float64   `json:"earned_leave_days"` //days earned through tenure, not counted in total_entitled
+	// CarriedForwardExpiresOn is the last day carried_forward can be used on, nil if it never expires
+	CarriedForwardExpiresOn *time.Time `json:"carried_forward_expires_on,omitempty"`
+	Forfeited               float64    `json:"forfeited"` //carried forward days left unused when they expired
+	// CarryForwardReminderDays is how many days before expiry the employee was last reminded
+	CarryForwardReminderDays *int `json:"-"`
+	// Balance is computed: total_entitled + carried_forward + earned_leave_days - used - pending - forfeited
+	Balance   float64   `json:"balance"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 
 	// Relations (populated on demand)
 	LeaveType *LeaveType `json:"leave_type,omitempty"`
 }
+
+// RoundLeaveDays rounds a fractional day count to two decimal places, the precision
+// leave days are stored with
+func RoundLeaveDays(days float64) float64 {
+	return math.Round(days*100) / 100
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LeaveBlackout is a period when leave cannot be taken, e.g. year-end close. Without a
+// department it applies to the whole company.
+type LeaveBlackout struct {
+	ID           uuid.UUID  `json:"id"`
+	Name         string     `json:"name"`
+	Description  string     `json:"description"`
+	DepartmentID *uuid.UUID `json:"department_id,omitempty"`
+	StartDate    time.Time  `json:"start_date"`
+	EndDate      time.Time  `json:"end_date"`
+	IsActive     bool       `json:"is_active"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// Leave rules a request can break
+const (
+	LeaveRuleBackdating         = "backdating"
+	LeaveRuleMinNotice          = "min_notice"
+	LeaveRuleMaxConsecutiveDays = "max_consecutive_days"
+	LeaveRuleMaxRequestsPerYear = "max_requests_per_year"
+	LeaveRuleBlackout           = "blackout"
+)
+
+// LeaveRuleViolation is a leave rule a request breaks. HR can file the request anyway
+// with an override reason.
+type LeaveRuleViolation struct {
+	Rule       string     `json:"rule"`
+	Message    string     `json:"message"`
+	BlackoutID *uuid.UUID `json:"blackout_id,omitempty"`
+}
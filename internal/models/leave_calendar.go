@@ -0,0 +1,66 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LeaveCalendar shows who in a department or a manager's team is away over a date range,
+// with the holidays in it and a count of people away on each day
+type LeaveCalendar struct {
+	From           time.Time            `json:"from"`
+	To             time.Time            `json:"to"`
+	DepartmentID   *uuid.UUID           `json:"department_id,omitempty"`
+	ManagerID      *uuid.UUID           `json:"manager_id,omitempty"`
+	Headcount      int                  `json:"headcount"`
+	MinStaffOnDuty int                  `json:"min_staff_on_duty"` // department minimum, 0 when none applies
+	Leave          []LeaveCalendarEntry `json:"leave"`
+	Holidays       []Holiday            `json:"holidays"`
+	Days           []LeaveCalendarDay   `json:"days"`
+}
+
+// LeaveCalendarEntry is one leave request on a calendar
+type LeaveCalendarEntry struct {
+	LeaveRequestID uuid.UUID          `json:"leave_request_id"`
+	EmployeeID     uuid.UUID          `json:"employee_id"`
+	EmployeeName   string             `json:"employee_name"`
+	DepartmentID   uuid.UUID          `json:"department_id"`
+	LeaveTypeName  string             `json:"leave_type_name"`
+	LeaveTypeCode  string             `json:"leave_type_code"`
+	StartDate      time.Time          `json:"start_date"`
+	EndDate        time.Time          `json:"end_date"`
+	StartPart      LeaveDayPart       `json:"start_part"`
+	EndPart        LeaveDayPart       `json:"end_part"`
+	Hours          float64            `json:"hours"`
+	TotalDays      float64            `json:"total_days"`
+	Status         LeaveRequestStatus `json:"status"`
+	UpdatedAt      time.Time          `json:"updated_at"`
+}
+
+// On reports whether the leave takes any part of day d
+func (e *LeaveCalendarEntry) On(d time.Time) bool {
+	return !d.Before(e.StartDate) && !d.After(e.EndDate)
+}
+
+// LeaveCalendarDay counts the people away on one day. Anyone off for part of the day
+// counts as away.
+type LeaveCalendarDay struct {
+	Date         time.Time `json:"date"`
+	WorkingDay   bool      `json:"working_day"`
+	Holiday      string    `json:"holiday,omitempty"`
+	Approved     int       `json:"approved"`     // people on approved leave
+	Pending      int       `json:"pending"`      // people whose leave is awaiting approval
+	Understaffed bool      `json:"understaffed"` // approved and pending leave would leave fewer than the minimum at work
+}
+
+// StaffingConflict is a working day on which a department would have fewer people at work
+// than its minimum if a leave request were approved
+type StaffingConflict struct {
+	DepartmentID   uuid.UUID `json:"department_id"`
+	DepartmentName string    `json:"department_name"`
+	Date           time.Time `json:"date"`
+	Headcount      int       `json:"headcount"`
+	Away           int       `json:"away"`
+	MinStaffOnDuty int       `json:"min_staff_on_duty"`
+}
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"time"
+
+	"hr-system/pkg/money"
+
+	"github.com/google/uuid"
+)
+
+type LeaveEncashmentStatus string
+
+const (
+	LeaveEncashmentPending   LeaveEncashmentStatus = "pending"
+	LeaveEncashmentApproved  LeaveEncashmentStatus = "approved"
+	LeaveEncashmentRejected  LeaveEncashmentStatus = "rejected"
+	LeaveEncashmentCancelled LeaveEncashmentStatus = "cancelled"
+)
+
+// LeaveEncashment is a request to be paid for unused leave. The days are held as pending
+// on the balance until the request is approved, when they are debited through a ledger
+// entry and Amount is paid on the employee's next regular payslip.
+type LeaveEncashment struct {
+	ID          uuid.UUID             `json:"id"`
+	EmployeeID  uuid.UUID             `json:"employee_id"`
+	LeaveTypeID uuid.UUID             `json:"leave_type_id"`
+	Year        int                   `json:"year"` // leave year the days come from
+	Days        float64               `json:"days"`
+	DailyRate   money.Money           `json:"daily_rate"` // from the base salary when requested
+	Amount      money.Money           `json:"amount"`
+	Reason      string                `json:"reason"`
+	Status      LeaveEncashmentStatus `json:"status"`
+	PayslipID   *uuid.UUID            `json:"payslip_id,omitempty"`
+	RequestedBy *uuid.UUID            `json:"requested_by,omitempty"`
+	ReviewedBy  *uuid.UUID            `json:"reviewed_by,omitempty"`
+	ReviewedAt  *time.Time            `json:"reviewed_at,omitempty"`
+	CreatedAt   time.Time             `json:"created_at"`
+	UpdatedAt   time.Time             `json:"updated_at"`
+
+	// Paid is computed: on a payslip that has not been voided
+	Paid bool `json:"paid"`
+
+	// Relations (populated on demand)
+	EmployeeName  string `json:"employee_name,omitempty"`
+	LeaveTypeName string `json:"leave_type_name,omitempty"`
+}
+
+type LeaveLedgerEntryType string
+
+const (
+	LeaveLedgerEncashment LeaveLedgerEntryType = "encashment"
+	LeaveLedgerForfeiture LeaveLedgerEntryType = "forfeiture" // carried forward days that expired unused
+)
+
+// LeaveLedgerEntry records a change to a leave balance made outside a leave request.
+// Days taken off the balance are negative.
+type LeaveLedgerEntry struct {
+	ID          uuid.UUID            `json:"id"`
+	EmployeeID  uuid.UUID            `json:"employee_id"`
+	LeaveTypeID uuid.UUID            `json:"leave_type_id"`
+	Year        int                  `json:"year"`
+	EntryType   LeaveLedgerEntryType `json:"entry_type"`
+	Days        float64              `json:"days"`
+	ReferenceID *uuid.UUID           `json:"reference_id,omitempty"` // e.g. the encashment
+	Notes       string               `json:"notes"`
+	CreatedBy   *uuid.UUID           `json:"created_by,omitempty"`
+	CreatedAt   time.Time            `json:"created_at"`
+
+	// Relations (populated on demand)
+	LeaveTypeName string `json:"leave_type_name,omitempty"`
+}
@@ -15,13 +15,41 @@ const (
 	LeaveStatusCancelled LeaveRequestStatus = "cancelled"
 )
 
+// LeaveDocumentStatus tracks the document (a sick note) a leave type requires
+type LeaveDocumentStatus string
+
+const (
+	LeaveDocumentNotRequired LeaveDocumentStatus = "not_required"
+	LeaveDocumentAwaited     LeaveDocumentStatus = "awaited"   // due by DocumentDueDate
+	LeaveDocumentReceived    LeaveDocumentStatus = "received"  // attached, or accepted by HR
+	LeaveDocumentEscalated   LeaveDocumentStatus = "escalated" // overdue and passed to HR
+	LeaveDocumentConverted   LeaveDocumentStatus = "converted" // overdue and turned into unpaid leave
+)
+
+// LeaveDayPart is the part of a day a leave request covers on its start or end date
+type LeaveDayPart string
+
+const (
+	LeaveDayFull LeaveDayPart = "full"
+	LeaveDayAM   LeaveDayPart = "am" // morning only
+	LeaveDayPM   LeaveDayPart = "pm" // afternoon only
+)
+
+// Valid reports whether the part is one of the known values
+func (p LeaveDayPart) Valid() bool {
+	return p == LeaveDayFull || p == LeaveDayAM || p == LeaveDayPM
+}
+
 type LeaveRequest struct {
 	ID            uuid.UUID          `json:"id"`
 	EmployeeID    uuid.UUID          `json:"employee_id"`
 	LeaveTypeID   uuid.UUID          `json:"leave_type_id"`
 	StartDate     time.Time          `json:"start_date"`
 	EndDate       time.Time          `json:"end_date"`
-	TotalDays     int                `json:"total_days"`
+	StartPart     LeaveDayPart       `json:"start_part"` // half-day selection on the start date
+	EndPart       LeaveDayPart       `json:"end_part"`   // half-day selection on the end date
+	Hours         float64            `json:"hours"`      // set for hourly leave on a single day
+	TotalDays     float64            `json:"total_days"`
 	Reason        string             `json:"reason"`
 	Status        LeaveRequestStatus `json:"status"`
 	ReviewedBy    *uuid.UUID         `json:"reviewed_by,omitempty"`
@@ -31,7 +59,85 @@ type LeaveRequest struct {
 	CreatedAt     time.Time          `json:"created_at"`
 	UpdatedAt     time.Time          `json:"updated_at"`
 
+	// Set when HR filed the request in spite of broken leave rules
+	RuleOverrideReason string     `json:"rule_override_reason,omitempty"`
+	RuleOverriddenBy   *uuid.UUID `json:"rule_overridden_by,omitempty"`
+
+	// The document the leave type requires: employees have the leave type's grace days
+	// after the leave ends to attach it
+	DocumentStatus     LeaveDocumentStatus `json:"document_status"`
+	DocumentDueDate    *time.Time          `json:"document_due_date,omitempty"`
+	DocumentRemindedAt *time.Time          `json:"document_reminded_at,omitempty"`
+
 	// Relations (populated on demand)
-	LeaveType *LeaveType `json:"leave_type,omitempty"`
-	Employee  *Employee  `json:"employee,omitempty"`
+	LeaveType   *LeaveType               `json:"leave_type,omitempty"`
+	Employee    *Employee                `json:"employee,omitempty"`
+	Portions    []LeaveRequestPortion    `json:"portions,omitempty"`
+	Amendments  []LeaveRequestAmendment  `json:"amendments,omitempty"`
+	Attachments []LeaveRequestAttachment `json:"attachments,omitempty"`
+
+	// Days the employee's department would be short-staffed, worked out when the request
+	// is filed and while it is pending
+	StaffingConflicts []StaffingConflict `json:"staffing_conflicts,omitempty"`
+}
+
+// LeaveRequestAmendment records a change to a leave request with the values it replaced.
+// Shortening approved leave can keep its approval; other changes go back for approval.
+type LeaveRequestAmendment struct {
+	ID                  uuid.UUID          `json:"id"`
+	LeaveRequestID      uuid.UUID          `json:"leave_request_id"`
+	PreviousLeaveTypeID uuid.UUID          `json:"previous_leave_type_id"`
+	PreviousStartDate   time.Time          `json:"previous_start_date"`
+	PreviousEndDate     time.Time          `json:"previous_end_date"`
+	PreviousStartPart   LeaveDayPart       `json:"previous_start_part"`
+	PreviousEndPart     LeaveDayPart       `json:"previous_end_part"`
+	PreviousHours       float64            `json:"previous_hours"`
+	PreviousTotalDays   float64            `json:"previous_total_days"`
+	PreviousReason      string             `json:"previous_reason"`
+	PreviousStatus      LeaveRequestStatus `json:"previous_status"`
+	PreviousReviewedBy  *uuid.UUID         `json:"previous_reviewed_by,omitempty"`
+	PreviousReviewedAt  *time.Time         `json:"previous_reviewed_at,omitempty"`
+	AutoApproved        bool               `json:"auto_approved"`
+	AmendedBy           uuid.UUID          `json:"amended_by"`
+	CreatedAt           time.Time          `json:"created_at"`
+}
+
+// LeaveRequestAttachment is a file uploaded to a leave request, such as a medical
+// certificate. The file is stored on disk under the configured attachment directory.
+type LeaveRequestAttachment struct {
+	ID             uuid.UUID  `json:"id"`
+	LeaveRequestID uuid.UUID  `json:"leave_request_id"`
+	FileName       string     `json:"file_name"`
+	ContentType    string     `json:"content_type"`
+	FileSize       int64      `json:"file_size"`
+	StoragePath    string     `json:"-"` // relative to the attachment directory
+	UploadedBy     *uuid.UUID `json:"uploaded_by,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// LeaveRequestPortion is the part of a leave request that falls in one leave year. A
+// request crossing a leave-year boundary is charged to each year's balance separately.
+type LeaveRequestPortion struct {
+	Year      int       `json:"year"`
+	StartDate time.Time `json:"start_date"`
+	EndDate   time.Time `json:"end_date"`
+	Days      float64   `json:"days"`
+}
+
+// PartOn returns the part of day d the request covers, or "" when it does not cover d.
+// Hourly leave has no set time of day, so it is treated as covering the whole day.
+func (r *LeaveRequest) PartOn(d time.Time) LeaveDayPart {
+	if d.Before(r.StartDate) || d.After(r.EndDate) {
+		return ""
+	}
+	if r.Hours > 0 {
+		return LeaveDayFull
+	}
+	if d.Equal(r.StartDate) && r.StartPart != "" && r.StartPart != LeaveDayFull {
+		return r.StartPart
+	}
+	if d.Equal(r.EndDate) && r.EndPart != "" && r.EndPart != LeaveDayFull {
+		return r.EndPart
+	}
+	return LeaveDayFull
 }
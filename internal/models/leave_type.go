@@ -17,16 +17,53 @@ type LeaveType struct {
 	MaxCarryForwardDays   int       `json:"max_carry_forward_days"`
 	RequiresApproval      bool      `json:"requires_approval"`
 	RequiresDocument      bool      `json:"requires_document"`
-	IsActive              bool      `json:"is_active"`
-	CreatedAt             time.Time `json:"created_at"`
-	UpdatedAt             time.Time `json:"updated_at"`
+	AllowHourly           bool      `json:"allow_hourly"`            // leave can be requested in hours
+	HoursPerDay           float64   `json:"hours_per_day"`           // hours that make up one day of leave
+	MinNoticeDays         int       `json:"min_notice_days"`         // calendar days' notice needed, 0 for none
+	MaxConsecutiveDays    float64   `json:"max_consecutive_days"`    // longest single request in days, 0 for no limit
+	MaxRequestsPerYear    int       `json:"max_requests_per_year"`   // requests per leave year, 0 for no limit
+	AllowBackdating       bool      `json:"allow_backdating"`        // leave can be requested after it started
+	BlackoutExempt        bool      `json:"blackout_exempt"`         // leave can be taken during blackouts
+	DocumentGraceDays     int       `json:"document_grace_days"`     // days after the leave ends to hand in a required document
+	MissingDocumentAction string    `json:"missing_document_action"` // escalate or convert_unpaid once the grace days pass
+	AllowEncashment       bool      `json:"allow_encashment"`        // unused days can be paid out on request
+	MaxEncashmentDays     float64   `json:"max_encashment_days"`     // days that can be encashed per leave year, 0 for no limit
+	EncashmentMinBalance  float64   `json:"encashment_min_balance"`  // days that must be left in the balance after encashing
+	// CarryForwardExpiryMonths is how many months into the new leave year carried-forward
+	// days can be used for before unused ones are forfeited, 0 if they never expire
+	CarryForwardExpiryMonths int       `json:"carry_forward_expiry_months"`
+	IsActive                 bool      `json:"is_active"`
+	CreatedAt                time.Time `json:"created_at"`
+	UpdatedAt                time.Time `json:"updated_at"`
 }
 
+// CarryForwardExpiry returns the last day days carried into a leave year can be used on,
+// e.g. 31 March for three months of calendar-year leave, and false if they never expire
+func (lt *LeaveType) CarryForwardExpiry(ly LeaveYear, year int) (time.Time, bool) {
+	if lt.CarryForwardExpiryMonths <= 0 {
+		return time.Time{}, false
+	}
+	return ly.Start(year).AddDate(0, lt.CarryForwardExpiryMonths, -1), true
+}
+
+// What happens to leave whose required document is not handed in within the grace days
+const (
+	MissingDocumentEscalate      = "escalate"       // HR is told to follow it up
+	MissingDocumentConvertUnpaid = "convert_unpaid" // the leave becomes unpaid leave
+)
+
+// DefaultDocumentGraceDays is how long after their leave employees have to hand in a
+// required document unless the leave type says otherwise
+const DefaultDocumentGraceDays = 2
+
+// DefaultHoursPerDay is the length of a working day used for hourly leave
+const DefaultHoursPerDay = 8.0
+
 // DefaultLeaveTypes returns the seeded leave types per the spec.
 func DefaultLeaveTypes() []LeaveType {
 	return []LeaveType{
-		{Code: "AL", Name: "Annual Leave", DefaultDaysPerYear: 21, IsPaid: true, IsCarryForwardAllowed: true, MaxCarryForwardDays: 5, RequiresApproval: true},
-		{Code: "SL", Name: "Sick Leave", DefaultDaysPerYear: 15, IsPaid: true, IsCarryForwardAllowed: false, RequiresApproval: true, RequiresDocument: true},
+		{Code: "AL", Name: "Annual Leave", DefaultDaysPerYear: 21, IsPaid: true, IsCarryForwardAllowed: true, MaxCarryForwardDays: 5, RequiresApproval: true, AllowEncashment: true, MaxEncashmentDays: 10, EncashmentMinBalance: 5},
+		{Code: "SL", Name: "Sick Leave", DefaultDaysPerYear: 15, IsPaid: true, IsCarryForwardAllowed: false, RequiresApproval: true, RequiresDocument: true, AllowHourly: true, AllowBackdating: true, BlackoutExempt: true},
 		{Code: "PL", Name: "Parental Leave", DefaultDaysPerYear: 90, IsPaid: true, IsCarryForwardAllowed: false, RequiresApproval: true},
 		{Code: "UL", Name: "Unpaid Leave", DefaultDaysPerYear: 0, IsPaid: false, IsCarryForwardAllowed: false, RequiresApproval: true},
 		{Code: "CL", Name: "Compassionate Leave", DefaultDaysPerYear: 5, IsPaid: true, IsCarryForwardAllowed: false, RequiresApproval: true},
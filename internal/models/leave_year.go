@@ -0,0 +1,79 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LeaveYear is the organisation's leave calendar. Each leave year runs for twelve months
+// from StartMonth and is identified by the calendar year it starts in, so with an April
+// start leave year 2026 runs from 1 April 2026 to 31 March 2027.
+type LeaveYear struct {
+	StartMonth time.Month `json:"start_month"`
+}
+
+// FirstMonth returns the month leave years start in, January when none is set
+func (ly LeaveYear) FirstMonth() time.Month {
+	if ly.StartMonth < time.January || ly.StartMonth > time.December {
+		return time.January
+	}
+	return ly.StartMonth
+}
+
+// Of returns the leave year t falls in
+func (ly LeaveYear) Of(t time.Time) int {
+	if t.Month() < ly.FirstMonth() {
+		return t.Year() - 1
+	}
+	return t.Year()
+}
+
+// Start returns the first day of a leave year
+func (ly LeaveYear) Start(year int) time.Time {
+	return time.Date(year, ly.FirstMonth(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// End returns the last day of a leave year
+func (ly LeaveYear) End(year int) time.Time {
+	return ly.Start(year).AddDate(1, 0, -1)
+}
+
+// MonthsElapsed returns the months of t's leave year up to and including t's month
+func (ly LeaveYear) MonthsElapsed(t time.Time) int {
+	start := ly.Start(ly.Of(t))
+	return (t.Year()-start.Year())*12 + int(t.Month()) - int(start.Month()) + 1
+}
+
+// Label names a leave year for people, e.g. "2026" or "2026/27"
+func (ly LeaveYear) Label(year int) string {
+	if ly.FirstMonth() == time.January {
+		return fmt.Sprintf("%d", year)
+	}
+	return fmt.Sprintf("%d/%02d", year, (year+1)%100)
+}
+
+// LeaveYearRebase reports the outcome of moving live leave requests onto the balances of
+// the leave years they fall in under the current leave calendar
+type LeaveYearRebase struct {
+	PreviousStartMonth time.Month       `json:"previous_start_month,omitempty"`
+	StartMonth         time.Month       `json:"start_month"`
+	Checked            int              `json:"checked"`
+	Moved              []LeaveYearMove  `json:"moved"`
+	Failed             []LeaveYearError `json:"failed"`
+}
+
+// LeaveYearMove is one request whose portions were charged to different leave years
+type LeaveYearMove struct {
+	LeaveRequestID uuid.UUID             `json:"leave_request_id"`
+	EmployeeID     uuid.UUID             `json:"employee_id"`
+	From           []LeaveRequestPortion `json:"from"`
+	To             []LeaveRequestPortion `json:"to"`
+}
+
+// LeaveYearError is a request that could not be moved
+type LeaveYearError struct {
+	LeaveRequestID uuid.UUID `json:"leave_request_id"`
+	Error          string    `json:"error"`
+}
@@ -0,0 +1,97 @@
+package models
+
+import (
+	"time"
+
+	"hr-system/pkg/money"
+
+	"github.com/google/uuid"
+)
+
+type LoanType string
+
+const (
+	LoanTypeLoan    LoanType = "loan"
+	LoanTypeAdvance LoanType = "advance" // salary advance, normally repaid from the next payslip
+)
+
+type LoanStatus string
+
+const (
+	LoanStatusPending   LoanStatus = "pending"
+	LoanStatusActive    LoanStatus = "active"
+	LoanStatusSettled   LoanStatus = "settled" // active with nothing left outstanding
+	LoanStatusRejected  LoanStatus = "rejected"
+	LoanStatusCancelled LoanStatus = "cancelled"
+)
+
+type LoanRepaymentType string
+
+const (
+	LoanRepaymentPayroll         LoanRepaymentType = "payroll"
+	LoanRepaymentEarlySettlement LoanRepaymentType = "early_settlement"
+	LoanRepaymentFinalPay        LoanRepaymentType = "final_pay"
+)
+
+// EmployeeLoan is a loan or salary advance repaid in monthly instalments deducted from
+// payslips, starting in StartMonth/StartYear.
+type EmployeeLoan struct {
+	ID               uuid.UUID   `json:"id"`
+	EmployeeID       uuid.UUID   `json:"employee_id"`
+	LoanType         LoanType    `json:"loan_type"`
+	Principal        money.Money `json:"principal"`
+	InterestRate     float64     `json:"interest_rate"` // flat annual percent
+	InterestAmount   money.Money `json:"interest_amount"`
+	TotalRepayable   money.Money `json:"total_repayable"`
+	Instalments      int         `json:"instalments"`
+	InstalmentAmount money.Money `json:"instalment_amount"`
+	StartMonth       int         `json:"start_month"`
+	StartYear        int         `json:"start_year"`
+	Reason           string      `json:"reason"`
+	Status           LoanStatus  `json:"status"`
+	RequestedBy      *uuid.UUID  `json:"requested_by,omitempty"`
+	ReviewedBy       *uuid.UUID  `json:"reviewed_by,omitempty"`
+	ReviewedAt       *time.Time  `json:"reviewed_at,omitempty"`
+	CreatedAt        time.Time   `json:"created_at"`
+	UpdatedAt        time.Time   `json:"updated_at"`
+
+	// Computed from repayments on payslips that have not been voided
+	AmountRepaid       money.Money `json:"amount_repaid"`
+	OutstandingBalance money.Money `json:"outstanding_balance"`
+
+	// Relations (populated on demand)
+	EmployeeName string          `json:"employee_name,omitempty"`
+	Repayments   []LoanRepayment `json:"repayments,omitempty"`
+}
+
+// ApplyTerms works out interest, total repayable and the instalment amount. Interest is
+// flat on the principal for the term; the instalment is rounded up to the cent so the
+// last deduction is never larger than the others.
+func (l *EmployeeLoan) ApplyTerms() {
+	years := float64(l.Instalments) / 12
+	l.InterestAmount = l.Principal.Percent(l.InterestRate * years)
+	l.TotalRepayable = l.Principal + l.InterestAmount
+	l.InstalmentAmount = l.TotalRepayable / money.Money(l.Instalments)
+	if l.InstalmentAmount*money.Money(l.Instalments) < l.TotalRepayable {
+		l.InstalmentAmount++
+	}
+}
+
+// LoanRepayment is an amount recovered against a loan, either by payroll deduction or
+// paid directly.
+type LoanRepayment struct {
+	ID            uuid.UUID         `json:"id"`
+	LoanID        uuid.UUID         `json:"loan_id"`
+	PayslipID     *uuid.UUID        `json:"payslip_id,omitempty"`
+	RepaymentType LoanRepaymentType `json:"repayment_type"`
+	Amount        money.Money       `json:"amount"`
+	Month         int               `json:"month"`
+	Year          int               `json:"year"`
+	Notes         string            `json:"notes,omitempty"`
+	RecordedBy    *uuid.UUID        `json:"recorded_by,omitempty"`
+	CreatedAt     time.Time         `json:"created_at"`
+
+	// Voided is set when the payslip the deduction was taken on has been voided; the
+	// amount no longer counts towards the loan.
+	Voided bool `json:"voided,omitempty"`
+}
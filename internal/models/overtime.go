@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"hr-system/pkg/money"
+
+	"github.com/google/uuid"
+)
+
+// OvertimeRule holds the organisation-wide overtime pay rules.
+type OvertimeRule struct {
+	ID                   uuid.UUID `json:"id"`
+	WeekdayMultiplier    float64   `json:"weekday_multiplier"`
+	WeekendMultiplier    float64   `json:"weekend_multiplier"`
+	HolidayMultiplier    float64   `json:"holiday_multiplier"`
+	StandardMonthlyHours float64   `json:"standard_monthly_hours"` // hourly rate = base salary / standard monthly hours
+	MaxMonthlyHours      float64   `json:"max_monthly_hours"`      // 0 = no cap
+	CreatedAt            time.Time `json:"created_at"`
+	UpdatedAt            time.Time `json:"updated_at"`
+}
+
+// DefaultOvertimeRule returns the rules used when none are configured.
+func DefaultOvertimeRule() *OvertimeRule {
+	return &OvertimeRule{
+		WeekdayMultiplier:    1.5,
+		WeekendMultiplier:    2.0,
+		HolidayMultiplier:    2.5,
+		StandardMonthlyHours: 176,
+		MaxMonthlyHours:      40,
+	}
+}
+
+// OvertimeSummary is the approved overtime for an employee over a pay period.
+type OvertimeSummary struct {
+	WeekdayHours float64     `json:"weekday_hours"`
+	WeekendHours float64     `json:"weekend_hours"`
+	HolidayHours float64     `json:"holiday_hours"`
+	PaidHours    float64     `json:"paid_hours"` // after the monthly cap
+	HourlyRate   money.Money `json:"hourly_rate"`
+	Amount       money.Money `json:"amount"`
+}
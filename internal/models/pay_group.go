@@ -0,0 +1,153 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PayFrequency string
+
+const (
+	PayFrequencyMonthly  PayFrequency = "monthly"
+	PayFrequencyBiweekly PayFrequency = "biweekly"
+	PayFrequencyWeekly   PayFrequency = "weekly"
+)
+
+// PeriodsPerYear returns how many pay periods a year holds at this frequency, or 0
+// for an unknown frequency.
+func (f PayFrequency) PeriodsPerYear() int {
+	switch f {
+	case PayFrequencyMonthly:
+		return 12
+	case PayFrequencyBiweekly:
+		return 26
+	case PayFrequencyWeekly:
+		return 52
+	}
+	return 0
+}
+
+// PayGroup is a set of employees paid together on their own frequency and calendar.
+// Employees not assigned to a group are paid with the default group.
+type PayGroup struct {
+	ID           uuid.UUID    `json:"id"`
+	Name         string       `json:"name"`
+	Description  string       `json:"description"`
+	PayFrequency PayFrequency `json:"pay_frequency"`
+	// CalendarStart is the first day of the group's first pay period. Monthly periods
+	// start on its day of the month; weekly and bi-weekly ones every 7 or 14 days from it.
+	CalendarStart time.Time `json:"calendar_start"`
+	PayDayOffset  int       `json:"pay_day_offset"` // days after the period end that employees are paid
+	IsDefault     bool      `json:"is_default"`
+	IsActive      bool      `json:"is_active"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+
+	// Relations (populated on demand)
+	MemberCount int `json:"member_count"`
+}
+
+// PayPeriod is one period of a pay group's calendar. Its tax month is the month it ends in.
+type PayPeriod struct {
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+	PayDate time.Time `json:"pay_date"`
+}
+
+// Month returns the tax month the period is reported in
+func (p PayPeriod) Month() int {
+	return int(p.End.Month())
+}
+
+// Year returns the tax year the period is reported in
+func (p PayPeriod) Year() int {
+	return p.End.Year()
+}
+
+// Contains reports whether t falls within the period
+func (p PayPeriod) Contains(t time.Time) bool {
+	d := dateOnly(t)
+	return !d.Before(p.Start) && !d.After(p.End)
+}
+
+// Label describes the period for people, e.g. "March 2026" or "02 Mar - 08 Mar 2026"
+func (p PayPeriod) Label() string {
+	if p.Start.Day() == 1 && p.End.Equal(p.Start.AddDate(0, 1, -1)) {
+		return fmt.Sprintf("%s %d", p.Start.Month(), p.Start.Year())
+	}
+	startFormat := "02 Jan"
+	if p.Start.Year() != p.End.Year() {
+		startFormat = "02 Jan 2006"
+	}
+	return fmt.Sprintf("%s - %s", p.Start.Format(startFormat), p.End.Format("02 Jan 2006"))
+}
+
+// Frequency infers the pay frequency from the period's length
+func (p PayPeriod) Frequency() PayFrequency {
+	switch int(p.End.Sub(p.Start).Hours()/24) + 1 {
+	case 7:
+		return PayFrequencyWeekly
+	case 14:
+		return PayFrequencyBiweekly
+	}
+	return PayFrequencyMonthly
+}
+
+// CalendarMonth returns the pay period covering a whole calendar month
+func CalendarMonth(month, year int) PayPeriod {
+	start := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, -1)
+	return PayPeriod{Start: start, End: end, PayDate: end}
+}
+
+// PeriodContaining returns the group's pay period that t falls in
+func (g *PayGroup) PeriodContaining(t time.Time) PayPeriod {
+	t = dateOnly(t)
+	anchor := dateOnly(g.CalendarStart)
+
+	var start, end time.Time
+	switch g.PayFrequency {
+	case PayFrequencyWeekly, PayFrequencyBiweekly:
+		length := 7
+		if g.PayFrequency == PayFrequencyBiweekly {
+			length = 14
+		}
+		days := int(t.Sub(anchor).Hours() / 24)
+		n := days / length
+		if days < 0 && days%length != 0 {
+			n-- // round towards the earlier period
+		}
+		start = anchor.AddDate(0, 0, n*length)
+		end = start.AddDate(0, 0, length-1)
+	default:
+		start = time.Date(t.Year(), t.Month(), anchor.Day(), 0, 0, 0, 0, time.UTC)
+		if start.After(t) {
+			start = start.AddDate(0, -1, 0)
+		}
+		end = start.AddDate(0, 1, -1)
+	}
+	return PayPeriod{Start: start, End: end, PayDate: end.AddDate(0, 0, g.PayDayOffset)}
+}
+
+// NextPeriod returns the pay period that follows p
+func (g *PayGroup) NextPeriod(p PayPeriod) PayPeriod {
+	return g.PeriodContaining(p.End.AddDate(0, 0, 1))
+}
+
+// Calendar returns the group's pay periods that end in the given year
+func (g *PayGroup) Calendar(year int) []PayPeriod {
+	var periods []PayPeriod
+	p := g.PeriodContaining(time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC))
+	for ; p.End.Year() <= year; p = g.NextPeriod(p) {
+		if p.End.Year() == year {
+			periods = append(periods, p)
+		}
+	}
+	return periods
+}
+
+func dateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
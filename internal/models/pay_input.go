@@ -0,0 +1,126 @@
+package models
+
+import (
+	"time"
+
+	"hr-system/pkg/money"
+
+	"github.com/google/uuid"
+)
+
+type PayInputType string
+
+const (
+	PayInputEarning   PayInputType = "earning"
+	PayInputDeduction PayInputType = "deduction"
+)
+
+// TaxTreatment says how a pay input affects PAYE. Earnings are taxable or not;
+// deductions come off taxable pay (pre-tax) or off net pay after tax (post-tax).
+type TaxTreatment string
+
+const (
+	TaxTreatmentTaxable    TaxTreatment = "taxable"
+	TaxTreatmentNonTaxable TaxTreatment = "non_taxable"
+	TaxTreatmentPreTax     TaxTreatment = "pre_tax"
+	TaxTreatmentPostTax    TaxTreatment = "post_tax"
+)
+
+// PayInputCategory describes a kind of one-off earning or deduction
+type PayInputCategory struct {
+	Code             string       `json:"code"`
+	Name             string       `json:"name"`
+	InputType        PayInputType `json:"input_type"`
+	DefaultTreatment TaxTreatment `json:"default_tax_treatment"`
+}
+
+// PayInputCategories lists the categories inputs can be recorded under
+var PayInputCategories = []PayInputCategory{
+	{"bonus", "Bonus", PayInputEarning, TaxTreatmentTaxable},
+	{"commission", "Commission", PayInputEarning, TaxTreatmentTaxable},
+	{"allowance", "One-off Allowance", PayInputEarning, TaxTreatmentTaxable},
+	{"reimbursement", "Reimbursement", PayInputEarning, TaxTreatmentNonTaxable},
+	{"other_earning", "Other Earning", PayInputEarning, TaxTreatmentTaxable},
+	{"union_dues", "Union Dues", PayInputDeduction, TaxTreatmentPostTax},
+	{"equipment_damage", "Damaged Equipment", PayInputDeduction, TaxTreatmentPostTax},
+	{"pension_contribution", "Voluntary Pension Contribution", PayInputDeduction, TaxTreatmentPreTax},
+	{"other_deduction", "Other Deduction", PayInputDeduction, TaxTreatmentPostTax},
+}
+
+// FindPayInputCategory returns the category with the given code
+func FindPayInputCategory(code string) (PayInputCategory, bool) {
+	for _, c := range PayInputCategories {
+		if c.Code == code {
+			return c, true
+		}
+	}
+	return PayInputCategory{}, false
+}
+
+// PayInput is a one-off earning or deduction for an employee in a pay period. It is
+// picked up by the payslip generated for that period.
+type PayInput struct {
+	ID           uuid.UUID    `json:"id"`
+	EmployeeID   uuid.UUID    `json:"employee_id"`
+	Month        int          `json:"month"`
+	Year         int          `json:"year"`
+	InputType    PayInputType `json:"input_type"`
+	Category     string       `json:"category"`
+	Description  string       `json:"description"`
+	Amount       money.Money  `json:"amount"`
+	TaxTreatment TaxTreatment `json:"tax_treatment"`
+	Reference    string       `json:"reference,omitempty"` // e.g. the upload file it came from
+	PayslipID    *uuid.UUID   `json:"payslip_id,omitempty"`
+	CreatedBy    *uuid.UUID   `json:"created_by,omitempty"`
+	CreatedAt    time.Time    `json:"created_at"`
+	UpdatedAt    time.Time    `json:"updated_at"`
+
+	// Processed is set once the input is on a draft or final payslip
+	Processed bool `json:"processed"`
+
+	// Relations (populated on demand)
+	EmployeeName   string `json:"employee_name,omitempty"`
+	EmployeeNumber string `json:"employee_number,omitempty"`
+}
+
+// PayInputRowResult is the validation outcome of one row of an uploaded file
+type PayInputRowResult struct {
+	Row            int       `json:"row"`
+	EmployeeNumber string    `json:"employee_number"`
+	Valid          bool      `json:"valid"`
+	Errors         []string  `json:"errors,omitempty"`
+	Input          *PayInput `json:"input,omitempty"`
+}
+
+// PayInputUploadReport summarises a CSV upload. Nothing is saved unless every row is valid.
+type PayInputUploadReport struct {
+	Month           int                 `json:"month"`
+	Year            int                 `json:"year"`
+	TotalRows       int                 `json:"total_rows"`
+	ValidRows       int                 `json:"valid_rows"`
+	ErrorRows       int                 `json:"error_rows"`
+	TotalEarnings   money.Money         `json:"total_earnings"`
+	TotalDeductions money.Money         `json:"total_deductions"`
+	Saved           bool                `json:"saved"`
+	Rows            []PayInputRowResult `json:"rows"`
+}
+
+// PayInputIssue is a problem found when checking a period's inputs before processing
+type PayInputIssue struct {
+	Severity     string     `json:"severity"` // "error" blocks payment of the input, "warning" is informational
+	EmployeeID   uuid.UUID  `json:"employee_id"`
+	EmployeeName string     `json:"employee_name"`
+	PayInputID   *uuid.UUID `json:"pay_input_id,omitempty"`
+	Message      string     `json:"message"`
+}
+
+// PayInputPeriodReport is the pre-processing validation report for a pay period
+type PayInputPeriodReport struct {
+	Month           int             `json:"month"`
+	Year            int             `json:"year"`
+	InputCount      int             `json:"input_count"`
+	EmployeeCount   int             `json:"employee_count"`
+	TotalEarnings   money.Money     `json:"total_earnings"`
+	TotalDeductions money.Money     `json:"total_deductions"`
+	Issues          []PayInputIssue `json:"issues"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"hr-system/pkg/money"
+
+	"github.com/google/uuid"
+)
+
+// PaymentBatch is a bank transfer file generated for a completed payroll
+type PaymentBatch struct {
+	ID             uuid.UUID   `json:"id"`
+	PayrollID      uuid.UUID   `json:"payroll_id"`
+	BatchReference string      `json:"batch_reference"`
+	Format         string      `json:"format"`
+	RecordCount    int         `json:"record_count"`
+	ControlTotal   money.Money `json:"control_total"`
+	FileName       string      `json:"file_name"`
+	ContentType    string      `json:"content_type"`
+	Content        []byte      `json:"-"`
+	GeneratedBy    *uuid.UUID  `json:"generated_by,omitempty"`
+	CreatedAt      time.Time   `json:"created_at"`
+}
+
+// PaymentLine is one credit transfer in a payment batch
+type PaymentLine struct {
+	EmployeeID     uuid.UUID   `json:"employee_id"`
+	EmployeeNumber string      `json:"employee_number"`
+	BankCode       string      `json:"bank_code"`
+	BranchCode     string      `json:"branch_code"`
+	AccountNumber  string      `json:"account_number"`
+	AccountName    string      `json:"account_name"`
+	Amount         money.Money `json:"amount"`
+	Narrative      string      `json:"narrative"`
+}
@@ -3,6 +3,8 @@ package models
 import (
 	"time"
 
+	"hr-system/pkg/money"
+
 	"github.com/google/uuid"
 )
 
@@ -11,23 +13,129 @@ type PayrollStatus string
 const (
 	PayrollStatusOpen       PayrollStatus = "OPEN"
 	PayrollStatusProcessing PayrollStatus = "PROCESSING"
+	PayrollStatusDraft      PayrollStatus = "DRAFT"
 	PayrollStatusCompleted  PayrollStatus = "COMPLETED"
 	PayrollStatusCancelled  PayrollStatus = "CANCELLED"
 )
 
+type PayrollRunType string
+
+const (
+	PayrollRunRegular PayrollRunType = "regular" // monthly salary run, one per period
+	PayrollRunBonus   PayrollRunType = "bonus"   // extra run paying a percentage of base salary
+)
+
 type Payroll struct {
-	ID              uuid.UUID     `json:"id"`
-	StartDate       time.Time     `json:"start_date"`
-	EndDate         time.Time     `json:"end_date"`
-	Status          PayrollStatus `json:"status"`
-	ProcessedBy     *uuid.UUID    `json:"processed_by"`
-	ProcessedAt     *time.Time    `json:"processed_at,omitempty"`
-	CreatedAt       time.Time     `json:"created_at"`
-	UpdatedAt       time.Time     `json:"updated_at"`
+	ID              uuid.UUID      `json:"id"`
+	PayGroupID      *uuid.UUID     `json:"pay_group_id,omitempty"`
+	StartDate       time.Time      `json:"start_date"`
+	EndDate         time.Time      `json:"end_date"`
+	PayDate         *time.Time     `json:"pay_date,omitempty"`
+	Status          PayrollStatus  `json:"status"`
+	RunType         PayrollRunType `json:"run_type"`
+	BonusPercent    float64        `json:"bonus_percent,omitempty"`
+	Currency        money.Currency `json:"currency"`
+	ProcessedBy     *uuid.UUID     `json:"processed_by"`
+	ProcessedAt     *time.Time     `json:"processed_at,omitempty"`
+	ApprovedBy      *uuid.UUID     `json:"approved_by,omitempty"`
+	ApprovedAt      *time.Time     `json:"approved_at,omitempty"`
+	TotalEmployees  int            `json:"total_employees"`
+	SucceededCount  int            `json:"succeeded_count"`
+	FailedCount     int            `json:"failed_count"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
 
 	// Relations (populated on demand)
-	ProcessedByName string    `json:"processed_by_name,omitempty"`
-	Payslips        []Payslip `json:"payslips,omitempty"`
-	TotalNetSalary  float64   `json:"total_net_salary,omitempty"`
-	EmployeeCount   int       `json:"employee_count,omitempty"`
+	PayGroupName    string            `json:"pay_group_name,omitempty"`
+	PayFrequency    PayFrequency      `json:"pay_frequency,omitempty"`
+	ProcessedByName string            `json:"processed_by_name,omitempty"`
+	ApprovedByName  string            `json:"approved_by_name,omitempty"`
+	Payslips        []Payslip         `json:"payslips,omitempty"`
+	Reversals       []PayrollReversal `json:"reversals,omitempty"`
+	TotalNetSalary  money.Money       `json:"total_net_salary,omitempty"`
+	EmployeeCount   int               `json:"employee_count,omitempty"`
+}
+
+// Period returns the pay period the payroll covers
+func (p *Payroll) Period() PayPeriod {
+	period := PayPeriod{Start: p.StartDate, End: p.EndDate, PayDate: p.EndDate}
+	if p.PayDate != nil {
+		period.PayDate = *p.PayDate
+	}
+	return period
+}
+
+type PayrollRunItemStatus string
+
+const (
+	PayrollRunItemPending   PayrollRunItemStatus = "pending"
+	PayrollRunItemSucceeded PayrollRunItemStatus = "succeeded"
+	PayrollRunItemFailed    PayrollRunItemStatus = "failed"
+)
+
+// PayrollRunItem tracks one employee's payslip generation within a payroll run
+type PayrollRunItem struct {
+	ID          uuid.UUID            `json:"id"`
+	PayrollID   uuid.UUID            `json:"payroll_id"`
+	EmployeeID  uuid.UUID            `json:"employee_id"`
+	Status      PayrollRunItemStatus `json:"status"`
+	ErrorReason string               `json:"error_reason,omitempty"`
+	PayslipID   *uuid.UUID           `json:"payslip_id,omitempty"`
+	Attempts    int                  `json:"attempts"`
+	CreatedAt   time.Time            `json:"created_at"`
+	UpdatedAt   time.Time            `json:"updated_at"`
+
+	// Relations (populated on demand)
+	EmployeeName string `json:"employee_name,omitempty"`
+}
+
+// PayrollReversal is the audit record of a completed payroll being reversed for re-run
+type PayrollReversal struct {
+	ID             uuid.UUID   `json:"id"`
+	PayrollID      uuid.UUID   `json:"payroll_id"`
+	Reason         string      `json:"reason"`
+	PayslipsVoided int         `json:"payslips_voided"`
+	TotalNetVoided money.Money `json:"total_net_voided"`
+	ReversedBy     *uuid.UUID  `json:"reversed_by,omitempty"`
+	ReversedByName string      `json:"reversed_by_name,omitempty"`
+	ReversedAt     time.Time   `json:"reversed_at"`
+}
+
+// VarianceFlag marks why a line in a payroll variance report needs attention
+type VarianceFlag string
+
+const (
+	VarianceFlagNone    VarianceFlag = ""
+	VarianceFlagJoiner  VarianceFlag = "joiner"  // paid this period but not last period
+	VarianceFlagLeaver  VarianceFlag = "leaver"  // paid last period but not this period
+	VarianceFlagOutlier VarianceFlag = "outlier" // net pay moved by more than the threshold
+)
+
+// PayrollVarianceLine compares one employee's draft payslip against the previous period
+type PayrollVarianceLine struct {
+	EmployeeID    uuid.UUID    `json:"employee_id"`
+	EmployeeName  string       `json:"employee_name"`
+	PreviousGross money.Money  `json:"previous_gross"`
+	CurrentGross  money.Money  `json:"current_gross"`
+	PreviousNet   money.Money  `json:"previous_net"`
+	CurrentNet    money.Money  `json:"current_net"`
+	Difference    money.Money  `json:"difference"`
+	PercentChange float64      `json:"percent_change"`
+	Flag          VarianceFlag `json:"flag,omitempty"`
+}
+
+// PayrollVarianceReport summarises a draft payroll against the previous period
+type PayrollVarianceReport struct {
+	PayrollID         uuid.UUID             `json:"payroll_id"`
+	Period            string                `json:"period"`
+	PreviousPeriod    string                `json:"previous_period"`
+	ThresholdPercent  float64               `json:"threshold_percent"`
+	CurrentHeadcount  int                   `json:"current_headcount"`
+	PreviousHeadcount int                   `json:"previous_headcount"`
+	CurrentTotalNet   money.Money           `json:"current_total_net"`
+	PreviousTotalNet  money.Money           `json:"previous_total_net"`
+	Joiners           int                   `json:"joiners"`
+	Leavers           int                   `json:"leavers"`
+	Outliers          int                   `json:"outliers"`
+	Lines             []PayrollVarianceLine `json:"lines"`
 }
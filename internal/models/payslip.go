@@ -3,26 +3,87 @@ package models
 import (
 	"time"
 
+	"hr-system/pkg/money"
+
 	"github.com/google/uuid"
 )
 
+type PayslipStatus string
+
+const (
+	PayslipStatusDraft PayslipStatus = "draft"
+	PayslipStatusFinal PayslipStatus = "final"
+	PayslipStatusVoid  PayslipStatus = "void"
+)
+
+type PayslipType string
+
+const (
+	PayslipTypeRegular       PayslipType = "regular"
+	PayslipTypeSupplementary PayslipType = "supplementary" // off-cycle correction carrying only the difference
+	PayslipTypeBonus         PayslipType = "bonus"         // produced by a bonus payroll run
+)
+
 type Payslip struct {
-	ID                 uuid.UUID `json:"id"`
-	EmployeeID         uuid.UUID `json:"employee_id"`
-	Month              int       `json:"month"`
-	Year               int       `json:"year"`
-	BaseSalary         float64   `json:"base_salary"`
-	HousingAllowance   float64   `json:"housing_allowance"`
-	TransportAllowance float64   `json:"transport_allowance"`
-	MedicalAllowance   float64   `json:"medical_allowance"`
-	GrossSalary        float64   `json:"gross_salary"`
-	IncomeTax          float64   `json:"income_tax"`
-	LeaveDays          float64   `json:"leave_days"`
-	NetSalary          float64   `json:"net_salary"`
-	CreatedAt          time.Time `json:"created_at"`
-	UpdatedAt          time.Time `json:"updated_at"`
+	ID                 uuid.UUID      `json:"id"`
+	PayrollID          *uuid.UUID     `json:"payroll_id,omitempty"`
+	EmployeeID         uuid.UUID      `json:"employee_id"`
+	Month              int            `json:"month"` // tax month: the month the pay period ends in
+	Year               int            `json:"year"`
+	PeriodStart        time.Time      `json:"period_start"`
+	PeriodEnd          time.Time      `json:"period_end"`
+	BaseSalary         money.Money    `json:"base_salary"`
+	HousingAllowance   money.Money    `json:"housing_allowance"`
+	TransportAllowance money.Money    `json:"transport_allowance"`
+	MedicalAllowance   money.Money    `json:"medical_allowance"`
+	OvertimeHours      float64        `json:"overtime_hours"`
+	OvertimePay        money.Money    `json:"overtime_pay"`
+	BonusPay           money.Money    `json:"bonus_pay"`
+	GrossSalary        money.Money    `json:"gross_salary"`
+	IncomeTax          money.Money    `json:"income_tax"`
+	LeaveDays          money.Money    `json:"leave_days"`          // flat-rate leave compensation on payslips issued before leave encashment
+	AdditionalEarnings money.Money    `json:"additional_earnings"` // taxable one-off earnings, included in gross
+	Reimbursements     money.Money    `json:"reimbursements"`      // non-taxable one-off earnings
+	PreTaxDeductions   money.Money    `json:"pre_tax_deductions"`
+	OtherDeductions    money.Money    `json:"other_deductions"` // post-tax one-off deductions
+	ArrearsPay         money.Money    `json:"arrears_pay"`      // retro pay arrears, included in gross
+	ArrearsTax         money.Money    `json:"arrears_tax"`      // tax on the arrears, included in income tax
+	EncashedLeave      money.Money    `json:"encashed_leave"`   // approved leave encashment requests, included in gross
+	LeaveEncashment    money.Money    `json:"leave_encashment"` // final pay: unused leave, included in gross
+	NoticePay          money.Money    `json:"notice_pay"`       // final pay: pay in lieu of notice, included in gross
+	Gratuity           money.Money    `json:"gratuity"`         // final pay: included in gross
+	LoanDeduction      money.Money    `json:"loan_deduction"`
+	NetSalary          money.Money    `json:"net_salary"`
+	Currency           money.Currency `json:"currency"`
+	Status             PayslipStatus  `json:"status"`
+	PayslipType        PayslipType    `json:"payslip_type"`
+	OriginalPayslipID  *uuid.UUID     `json:"original_payslip_id,omitempty"`
+	AdjustmentReason   string         `json:"adjustment_reason,omitempty"`
+	VoidReason         string         `json:"void_reason,omitempty"`
+	VoidedBy           *uuid.UUID     `json:"voided_by,omitempty"`
+	VoidedAt           *time.Time     `json:"voided_at,omitempty"`
+	PaymentBatchID     *uuid.UUID     `json:"payment_batch_id,omitempty"`
+	YTD                *PayslipYTD    `json:"ytd,omitempty"` // as at this payslip, set once final
+	CreatedAt          time.Time      `json:"created_at"`
+	UpdatedAt          time.Time      `json:"updated_at"`
 
 	// Relations (populated on demand)
-	EmployeeName string `json:"employee_name,omitempty"`
-	PositionName string `json:"position_name,omitempty"`
+	EmployeeName string            `json:"employee_name,omitempty"`
+	PositionName string            `json:"position_name,omitempty"`
+	PayInputs    []PayInput        `json:"pay_inputs,omitempty"`
+	Arrears      []RetroPayLine    `json:"arrears,omitempty"` // per-period breakdown of ArrearsPay
+	Encashments  []LeaveEncashment `json:"encashments,omitempty"`
+}
+
+// PayslipYTD holds an employee's year-to-date totals across final payslips
+type PayslipYTD struct {
+	Year         int         `json:"year"`
+	GrossSalary  money.Money `json:"gross_salary"`
+	IncomeTax    money.Money `json:"income_tax"`
+	OvertimePay  money.Money `json:"overtime_pay"`
+	BonusPay     money.Money `json:"bonus_pay"`
+	LeavePay     money.Money `json:"leave_pay,omitempty"`
+	NetSalary    money.Money `json:"net_salary"`
+	PayslipCount int         `json:"payslip_count,omitempty"`
+	UpdatedAt    *time.Time  `json:"updated_at,omitempty"`
 }
@@ -0,0 +1,83 @@
+package models
+
+import (
+	"time"
+
+	"hr-system/pkg/money"
+
+	"github.com/google/uuid"
+)
+
+type RetroPayStatus string
+
+const (
+	RetroPayStatusPending   RetroPayStatus = "pending" // waiting for, or paid on, the next regular payslip
+	RetroPayStatusCancelled RetroPayStatus = "cancelled"
+)
+
+// RetroPayAdjustment holds the arrears owed to an employee after prior pay periods were
+// recalculated under the current compensation and tax rules. The arrears are paid on the
+// employee's next regular payslip.
+type RetroPayAdjustment struct {
+	ID            uuid.UUID      `json:"id"`
+	EmployeeID    uuid.UUID      `json:"employee_id"`
+	EffectiveFrom time.Time      `json:"effective_from"`
+	Reason        string         `json:"reason"`
+	Status        RetroPayStatus `json:"status"`
+	GrossArrears  money.Money    `json:"gross_arrears"`
+	TaxArrears    money.Money    `json:"tax_arrears"`
+	NetArrears    money.Money    `json:"net_arrears"`
+	PayslipID     *uuid.UUID     `json:"payslip_id,omitempty"`
+	CreatedBy     *uuid.UUID     `json:"created_by,omitempty"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+
+	// Paid is set once the arrears are on a draft or final payslip
+	Paid bool `json:"paid"`
+
+	// Relations (populated on demand)
+	EmployeeName string         `json:"employee_name,omitempty"`
+	Lines        []RetroPayLine `json:"lines,omitempty"`
+}
+
+// RetroPayLine compares one recalculated pay period with what was paid for it. Previous
+// amounts include supplementary payslips and arrears from earlier retro calculations.
+type RetroPayLine struct {
+	ID                uuid.UUID   `json:"id"`
+	AdjustmentID      uuid.UUID   `json:"adjustment_id"`
+	PayslipID         uuid.UUID   `json:"payslip_id"` // the regular payslip originally issued for the period
+	Month             int         `json:"month"`
+	Year              int         `json:"year"`
+	PeriodStart       time.Time   `json:"period_start"`
+	PeriodEnd         time.Time   `json:"period_end"`
+	PreviousGross     money.Money `json:"previous_gross"`
+	RecalculatedGross money.Money `json:"recalculated_gross"`
+	GrossDifference   money.Money `json:"gross_difference"`
+	PreviousTax       money.Money `json:"previous_tax"`
+	RecalculatedTax   money.Money `json:"recalculated_tax"`
+	TaxDifference     money.Money `json:"tax_difference"`
+	NetDifference     money.Money `json:"net_difference"`
+}
+
+// Period returns the pay period the line recalculates
+func (l RetroPayLine) Period() PayPeriod {
+	return PayPeriod{Start: l.PeriodStart, End: l.PeriodEnd}
+}
+
+// RetroPaySkip is an employee left out of a retro calculation, and why
+type RetroPaySkip struct {
+	EmployeeID   uuid.UUID `json:"employee_id"`
+	EmployeeName string    `json:"employee_name,omitempty"`
+	Message      string    `json:"message"`
+}
+
+// RetroPayReport summarises a retro calculation across employees. In a preview nothing is saved.
+type RetroPayReport struct {
+	EffectiveFrom time.Time            `json:"effective_from"`
+	Preview       bool                 `json:"preview"`
+	GrossArrears  money.Money          `json:"gross_arrears"`
+	TaxArrears    money.Money          `json:"tax_arrears"`
+	NetArrears    money.Money          `json:"net_arrears"`
+	Adjustments   []RetroPayAdjustment `json:"adjustments"`
+	Skipped       []RetroPaySkip       `json:"skipped"`
+}
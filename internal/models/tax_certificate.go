@@ -0,0 +1,74 @@
+package models
+
+import (
+	"time"
+
+	"hr-system/pkg/money"
+
+	"github.com/google/uuid"
+)
+
+// TaxAmounts are the pay and tax figures reported on annual tax documents
+type TaxAmounts struct {
+	BasicSalary money.Money `json:"basic_salary"`
+	Allowances  money.Money `json:"allowances"`
+	OvertimePay money.Money `json:"overtime_pay"`
+	BonusPay    money.Money `json:"bonus_pay"`
+	LeavePay    money.Money `json:"leave_pay"`
+	GrossSalary money.Money `json:"gross_salary"`
+	IncomeTax   money.Money `json:"income_tax"`
+	NetSalary   money.Money `json:"net_salary"`
+}
+
+// Add accumulates other into a
+func (a *TaxAmounts) Add(other TaxAmounts) {
+	a.BasicSalary += other.BasicSalary
+	a.Allowances += other.Allowances
+	a.OvertimePay += other.OvertimePay
+	a.BonusPay += other.BonusPay
+	a.LeavePay += other.LeavePay
+	a.GrossSalary += other.GrossSalary
+	a.IncomeTax += other.IncomeTax
+	a.NetSalary += other.NetSalary
+}
+
+// TaxCertificateMonth is one month's line on an employee tax certificate
+type TaxCertificateMonth struct {
+	Month int `json:"month"`
+	TaxAmounts
+}
+
+// TaxCertificate is an employee's annual statement of pay and tax deducted (P9-style)
+type TaxCertificate struct {
+	Year            int                   `json:"year"`
+	EmployerName    string                `json:"employer_name"`
+	EmployerAddress string                `json:"employer_address,omitempty"`
+	EmployeeID      uuid.UUID             `json:"employee_id"`
+	EmployeeNumber  string                `json:"employee_number"`
+	EmployeeName    string                `json:"employee_name"`
+	NationalID      string                `json:"national_id"`
+	PositionName    string                `json:"position_name,omitempty"`
+	Months          []TaxCertificateMonth `json:"months"`
+	Totals          TaxAmounts            `json:"totals"`
+	GeneratedAt     time.Time             `json:"generated_at"`
+}
+
+// AnnualReturnLine is one employee's totals on the employer annual return
+type AnnualReturnLine struct {
+	EmployeeID     uuid.UUID `json:"employee_id"`
+	EmployeeNumber string    `json:"employee_number"`
+	EmployeeName   string    `json:"employee_name"`
+	NationalID     string    `json:"national_id"`
+	MonthsPaid     int       `json:"months_paid"`
+	TaxAmounts
+}
+
+// AnnualReturn summarises pay and tax deducted across all employees for a tax year
+type AnnualReturn struct {
+	Year          int                `json:"year"`
+	EmployerName  string             `json:"employer_name"`
+	EmployeeCount int                `json:"employee_count"`
+	Lines         []AnnualReturnLine `json:"lines"`
+	Totals        TaxAmounts         `json:"totals"`
+	GeneratedAt   time.Time          `json:"generated_at"`
+}
@@ -7,13 +7,17 @@ type WorkflowType string
 
 // Workflow type constants
 const (
-	WorkflowTypeLeaveRequest WorkflowType = "LEAVE_REQUEST"
+	WorkflowTypeLeaveRequest    WorkflowType = "LEAVE_REQUEST"
+	WorkflowTypeLoanRequest     WorkflowType = "LOAN_REQUEST"
+	WorkflowTypeLeaveEncashment WorkflowType = "LEAVE_ENCASHMENT"
 )
 
 // GetAllWorkflowTypes returns all available workflow types
 func GetAllWorkflowTypes() []WorkflowType {
 	return []WorkflowType{
 		WorkflowTypeLeaveRequest,
+		WorkflowTypeLoanRequest,
+		WorkflowTypeLeaveEncashment,
 	}
 }
 
@@ -32,6 +36,16 @@ func GetWorkflowTypeInfo() []WorkflowTypeInfo {
 			Name:        "Leave Request",
 			Description: "Workflow for managing employee leave requests and approvals",
 		},
+		{
+			Type:        WorkflowTypeLoanRequest,
+			Name:        "Loan Request",
+			Description: "Workflow for approving employee loans and salary advances",
+		},
+		{
+			Type:        WorkflowTypeLeaveEncashment,
+			Name:        "Leave Encashment",
+			Description: "Workflow for approving payouts of unused leave",
+		},
 	}
 }
 
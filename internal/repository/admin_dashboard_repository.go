@@ -42,8 +42,8 @@ func (r *AdminDashboardRepository) GetStats(from, to *time.Time) (*models.AdminD
 	// Total active departments
 	r.db.QueryRow(`SELECT COUNT(*) FROM departments WHERE is_active = true`).Scan(&dash.TotalDepartments)
 
-	// Active payrolls (OPEN or PROCESSING)
-	r.db.QueryRow(`SELECT COUNT(*) FROM payrolls WHERE status IN ('OPEN', 'PROCESSING')`).Scan(&dash.ActivePayrolls)
+	// Active payrolls (OPEN, PROCESSING or awaiting approval)
+	r.db.QueryRow(`SELECT COUNT(*) FROM payrolls WHERE status IN ('OPEN', 'PROCESSING', 'DRAFT')`).Scan(&dash.ActivePayrolls)
 
 	// Leave requests overview (within date range)
 	r.db.QueryRow(`SELECT COUNT(*) FROM leave_requests WHERE status = 'pending' AND created_at >= $1 AND created_at <= $2`, from, to).Scan(&dash.LeaveRequests.PendingRequests)
@@ -77,7 +77,8 @@ func (r *AdminDashboardRepository) GetStats(from, to *time.Time) (*models.AdminD
 		SELECT TO_CHAR(TO_DATE(month::text || '-' || year::text, 'MM-YYYY'), 'Month') AS month_name,
 		       year, COALESCE(SUM(net_salary), 0) AS total_net_salary
 		FROM payslips
-		WHERE (year > $1 OR (year = $1 AND month >= $2))
+		WHERE status = 'final'
+		  AND (year > $1 OR (year = $1 AND month >= $2))
 		  AND (year < $3 OR (year = $3 AND month <= $4))
 		GROUP BY year, month, month_name
 		ORDER BY year, month`,
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"database/sql"
+	"fmt"
 	"time"
 
 	"hr-system/internal/database"
@@ -35,20 +36,23 @@ func (r *AttendanceRepository) Create(a *models.Attendance) error {
 
 func (r *AttendanceRepository) GetByEmployeeAndDate(employeeID uuid.UUID, date time.Time) (*models.Attendance, error) {
 	return r.scanOne(r.db.QueryRow(`
-		SELECT id, employee_id, date, clock_in, clock_out, total_hours, status, overtime_hours, notes, source, created_at, updated_at
+		SELECT id, employee_id, date, clock_in, clock_out, total_hours, status, overtime_hours, notes, source, created_at, updated_at,
+		       overtime_approved, overtime_approved_by, overtime_approved_at
 		FROM attendance WHERE employee_id=$1 AND date=$2`,
 		employeeID, date.Format("2006-01-02")))
 }
 
 func (r *AttendanceRepository) GetByID(id uuid.UUID) (*models.Attendance, error) {
 	return r.scanOne(r.db.QueryRow(`
-		SELECT id, employee_id, date, clock_in, clock_out, total_hours, status, overtime_hours, notes, source, created_at, updated_at
+		SELECT id, employee_id, date, clock_in, clock_out, total_hours, status, overtime_hours, notes, source, created_at, updated_at,
+		       overtime_approved, overtime_approved_by, overtime_approved_at
 		FROM attendance WHERE id=$1`, id))
 }
 
 func (r *AttendanceRepository) ListByEmployee(employeeID uuid.UUID, from, to time.Time) ([]models.Attendance, error) {
 	rows, err := r.db.Query(`
-		SELECT id, employee_id, date, clock_in, clock_out, total_hours, status, overtime_hours, notes, source, created_at, updated_at
+		SELECT id, employee_id, date, clock_in, clock_out, total_hours, status, overtime_hours, notes, source, created_at, updated_at,
+		       overtime_approved, overtime_approved_by, overtime_approved_at
 		FROM attendance WHERE employee_id=$1 AND date>=$2 AND date<=$3 ORDER BY date`,
 		employeeID, from.Format("2006-01-02"), to.Format("2006-01-02"))
 	if err != nil {
@@ -61,7 +65,8 @@ func (r *AttendanceRepository) ListByEmployee(employeeID uuid.UUID, from, to tim
 func (r *AttendanceRepository) ListByDepartmentAndDate(departmentID uuid.UUID, date time.Time) ([]models.Attendance, error) {
 	rows, err := r.db.Query(`
 		SELECT a.id, a.employee_id, a.date, a.clock_in, a.clock_out, a.total_hours, a.status,
-		       a.overtime_hours, a.notes, a.source, a.created_at, a.updated_at
+		       a.overtime_hours, a.notes, a.source, a.created_at, a.updated_at,
+		       a.overtime_approved, a.overtime_approved_by, a.overtime_approved_at
 		FROM attendance a
 		JOIN employees e ON a.employee_id=e.id
 		WHERE e.department_id=$1 AND a.date=$2 AND e.deleted_at IS NULL
@@ -78,13 +83,50 @@ func (r *AttendanceRepository) Update(a *models.Attendance) error {
 	a.UpdatedAt = time.Now()
 	_, err := r.db.Exec(`
 		UPDATE attendance SET clock_in=$1, clock_out=$2, total_hours=$3, status=$4,
-		overtime_hours=$5, notes=$6, source=$7, updated_at=$8 WHERE id=$9`,
+		overtime_hours=$5, notes=$6, source=$7, updated_at=$8,
+		overtime_approved=$9, overtime_approved_by=$10, overtime_approved_at=$11 WHERE id=$12`,
 		a.ClockIn, a.ClockOut, a.TotalHours, a.Status,
-		a.OvertimeHours, a.Notes, a.Source, a.UpdatedAt, a.ID,
+		a.OvertimeHours, a.Notes, a.Source, a.UpdatedAt,
+		a.OvertimeApproved, a.OvertimeApprovedBy, a.OvertimeApprovedAt, a.ID,
 	)
 	return err
 }
 
+// ApproveOvertime marks the overtime on an attendance record as approved by a manager.
+func (r *AttendanceRepository) ApproveOvertime(id, approvedBy uuid.UUID) error {
+	result, err := r.db.Exec(`
+		UPDATE attendance SET overtime_approved=TRUE, overtime_approved_by=$1, overtime_approved_at=NOW(), updated_at=NOW()
+		WHERE id=$2 AND overtime_hours > 0`,
+		approvedBy, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("attendance record not found or has no overtime")
+	}
+	return nil
+}
+
+// ListApprovedOvertime returns attendance records with approved overtime in the given date range.
+func (r *AttendanceRepository) ListApprovedOvertime(employeeID uuid.UUID, from, to time.Time) ([]models.Attendance, error) {
+	rows, err := r.db.Query(`
+		SELECT id, employee_id, date, clock_in, clock_out, total_hours, status, overtime_hours, notes, source, created_at, updated_at,
+		       overtime_approved, overtime_approved_by, overtime_approved_at
+		FROM attendance
+		WHERE employee_id=$1 AND date>=$2 AND date<=$3 AND overtime_approved=TRUE AND overtime_hours > 0
+		ORDER BY date`,
+		employeeID, from.Format("2006-01-02"), to.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return r.scanRows(rows)
+}
+
 func (r *AttendanceRepository) SetClockIn(employeeID uuid.UUID, date time.Time, clockIn time.Time) (*models.Attendance, error) {
 	existing, err := r.GetByEmployeeAndDate(employeeID, date)
 	if err == sql.ErrNoRows {
@@ -169,10 +211,12 @@ func (r *AttendanceRepository) scanRows(rows *sql.Rows) ([]models.Attendance, er
 
 func (r *AttendanceRepository) scanOne(row rowScanner) (*models.Attendance, error) {
 	var a models.Attendance
-	var clockIn, clockOut sql.NullTime
+	var clockIn, clockOut, approvedAt sql.NullTime
+	var approvedBy sql.NullString
 	err := row.Scan(
 		&a.ID, &a.EmployeeID, &a.Date, &clockIn, &clockOut, &a.TotalHours, &a.Status,
 		&a.OvertimeHours, &a.Notes, &a.Source, &a.CreatedAt, &a.UpdatedAt,
+		&a.OvertimeApproved, &approvedBy, &approvedAt,
 	)
 	if err != nil {
 		return nil, err
@@ -183,5 +227,12 @@ func (r *AttendanceRepository) scanOne(row rowScanner) (*models.Attendance, erro
 	if clockOut.Valid {
 		a.ClockOut = &clockOut.Time
 	}
+	if approvedBy.Valid {
+		id, _ := uuid.Parse(approvedBy.String)
+		a.OvertimeApprovedBy = &id
+	}
+	if approvedAt.Valid {
+		a.OvertimeApprovedAt = &approvedAt.Time
+	}
 	return &a, nil
 }
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"hr-system/internal/database"
+	"hr-system/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type BankAccountRepository struct {
+	db *sql.DB
+}
+
+func NewBankAccountRepository() *BankAccountRepository {
+	return &BankAccountRepository{db: database.DB}
+}
+
+const bankAccountSelect = `
+	SELECT ba.id, ba.employee_id, ba.bank_name, ba.bank_code, ba.branch_code, ba.account_number,
+	       ba.account_name, ba.status, ba.requested_by, ba.reviewed_by, ba.reviewed_at,
+	       ba.rejection_reason, ba.created_at, ba.updated_at,
+	       CONCAT(e.first_name, ' ', e.last_name) AS employee_name
+	FROM employee_bank_accounts ba
+	JOIN employees e ON ba.employee_id = e.id`
+
+func (r *BankAccountRepository) Create(a *models.EmployeeBankAccount) error {
+	a.ID = uuid.New()
+	now := time.Now()
+	a.CreatedAt = now
+	a.UpdatedAt = now
+	_, err := r.db.Exec(`
+		INSERT INTO employee_bank_accounts (id, employee_id, bank_name, bank_code, branch_code, account_number, account_name, status, requested_by, created_at, updated_at)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11)`,
+		a.ID, a.EmployeeID, a.BankName, a.BankCode, a.BranchCode, a.AccountNumber, a.AccountName,
+		a.Status, a.RequestedBy, a.CreatedAt, a.UpdatedAt,
+	)
+	return err
+}
+
+func (r *BankAccountRepository) GetByID(id uuid.UUID) (*models.EmployeeBankAccount, error) {
+	return r.scanOne(r.db.QueryRow(bankAccountSelect+` WHERE ba.id=$1`, id))
+}
+
+// GetByEmployeeAndStatus returns an employee's approved or pending account.
+func (r *BankAccountRepository) GetByEmployeeAndStatus(employeeID uuid.UUID, status models.BankAccountStatus) (*models.EmployeeBankAccount, error) {
+	return r.scanOne(r.db.QueryRow(bankAccountSelect+` WHERE ba.employee_id=$1 AND ba.status=$2`, employeeID, status))
+}
+
+// ListByEmployee returns an employee's account history, most recent first.
+func (r *BankAccountRepository) ListByEmployee(employeeID uuid.UUID) ([]models.EmployeeBankAccount, error) {
+	rows, err := r.db.Query(bankAccountSelect+` WHERE ba.employee_id=$1 ORDER BY ba.created_at DESC`, employeeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return r.scanRows(rows)
+}
+
+func (r *BankAccountRepository) ListByStatus(status models.BankAccountStatus) ([]models.EmployeeBankAccount, error) {
+	rows, err := r.db.Query(bankAccountSelect+` WHERE ba.status=$1 ORDER BY ba.created_at`, status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return r.scanRows(rows)
+}
+
+// Approve makes a pending account the employee's active account, superseding the previous one.
+func (r *BankAccountRepository) Approve(id, reviewedBy uuid.UUID) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		UPDATE employee_bank_accounts SET status='superseded', updated_at=NOW()
+		WHERE status='approved' AND employee_id=(SELECT employee_id FROM employee_bank_accounts WHERE id=$1)`, id)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`
+		UPDATE employee_bank_accounts SET status='approved', reviewed_by=$2, reviewed_at=NOW(), updated_at=NOW()
+		WHERE id=$1 AND status='pending'`, id, reviewedBy)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *BankAccountRepository) Reject(id, reviewedBy uuid.UUID, reason string) error {
+	_, err := r.db.Exec(`
+		UPDATE employee_bank_accounts SET status='rejected', reviewed_by=$2, reviewed_at=NOW(), rejection_reason=$3, updated_at=NOW()
+		WHERE id=$1 AND status='pending'`, id, reviewedBy, reason)
+	return err
+}
+
+func (r *BankAccountRepository) scanRows(rows *sql.Rows) ([]models.EmployeeBankAccount, error) {
+	var accounts []models.EmployeeBankAccount
+	for rows.Next() {
+		a, err := r.scanRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, *a)
+	}
+	return accounts, rows.Err()
+}
+
+func (r *BankAccountRepository) scanOne(row *sql.Row) (*models.EmployeeBankAccount, error) {
+	return r.scanRow(row)
+}
+
+func (r *BankAccountRepository) scanRow(row rowScanner) (*models.EmployeeBankAccount, error) {
+	var a models.EmployeeBankAccount
+	err := row.Scan(&a.ID, &a.EmployeeID, &a.BankName, &a.BankCode, &a.BranchCode, &a.AccountNumber,
+		&a.AccountName, &a.Status, &a.RequestedBy, &a.ReviewedBy, &a.ReviewedAt,
+		&a.RejectionReason, &a.CreatedAt, &a.UpdatedAt, &a.EmployeeName)
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"hr-system/internal/database"
+	"hr-system/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type CalendarFeedRepository struct {
+	db *sql.DB
+}
+
+func NewCalendarFeedRepository() *CalendarFeedRepository {
+	return &CalendarFeedRepository{db: database.DB}
+}
+
+// Save stores the user's token, replacing any earlier one
+func (r *CalendarFeedRepository) Save(t *models.CalendarFeedToken) error {
+	t.ID = uuid.New()
+	t.CreatedAt = time.Now()
+	t.LastUsedAt = nil
+	_, err := r.db.Exec(`
+		INSERT INTO calendar_feed_tokens (id, user_id, token, created_at)
+		VALUES ($1,$2,$3,$4)
+		ON CONFLICT (user_id) DO UPDATE SET
+		  id=EXCLUDED.id, token=EXCLUDED.token, last_used_at=NULL, created_at=EXCLUDED.created_at`,
+		t.ID, t.UserID, t.Token, t.CreatedAt,
+	)
+	return err
+}
+
+func (r *CalendarFeedRepository) GetByUserID(userID uuid.UUID) (*models.CalendarFeedToken, error) {
+	return r.scanOne(r.db.QueryRow(`
+		SELECT id, user_id, token, last_used_at, created_at
+		FROM calendar_feed_tokens WHERE user_id=$1`, userID))
+}
+
+func (r *CalendarFeedRepository) GetByToken(token string) (*models.CalendarFeedToken, error) {
+	return r.scanOne(r.db.QueryRow(`
+		SELECT id, user_id, token, last_used_at, created_at
+		FROM calendar_feed_tokens WHERE token=$1`, token))
+}
+
+func (r *CalendarFeedRepository) MarkUsed(id uuid.UUID) error {
+	_, err := r.db.Exec(`UPDATE calendar_feed_tokens SET last_used_at=NOW() WHERE id=$1`, id)
+	return err
+}
+
+func (r *CalendarFeedRepository) scanOne(row rowScanner) (*models.CalendarFeedToken, error) {
+	var t models.CalendarFeedToken
+	var lastUsed sql.NullTime
+	if err := row.Scan(&t.ID, &t.UserID, &t.Token, &lastUsed, &t.CreatedAt); err != nil {
+		return nil, err
+	}
+	if lastUsed.Valid {
+		t.LastUsedAt = &lastUsed.Time
+	}
+	return &t, nil
+}
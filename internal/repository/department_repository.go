@@ -27,10 +27,10 @@ func (r *DepartmentRepository) Create(dept *models.Department) error {
 	dept.CreatedAt = now
 	dept.UpdatedAt = now
 	_, err := r.db.Exec(`
-		INSERT INTO departments (id, name, code, description, parent_department_id, manager_id, is_active, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		INSERT INTO departments (id, name, code, description, parent_department_id, manager_id, min_staff_on_duty, is_active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
 		dept.ID, dept.Name, dept.Code, dept.Description,
-		dept.ParentDepartmentID, dept.ManagerID, dept.IsActive, dept.CreatedAt, dept.UpdatedAt,
+		dept.ParentDepartmentID, dept.ManagerID, dept.MinStaffOnDuty, dept.IsActive, dept.CreatedAt, dept.UpdatedAt,
 	)
 	return err
 }
@@ -39,9 +39,9 @@ func (r *DepartmentRepository) GetByID(id uuid.UUID) (*models.Department, error)
 	var d models.Department
 	var parentID, managerID sql.NullString
 	err := r.db.QueryRow(`
-		SELECT id, name, code, description, parent_department_id, manager_id, is_active, created_at, updated_at, deleted_at
+		SELECT id, name, code, description, parent_department_id, manager_id, min_staff_on_duty, is_active, created_at, updated_at, deleted_at
 		FROM departments WHERE id = $1 AND deleted_at IS NULL`, id,
-	).Scan(&d.ID, &d.Name, &d.Code, &d.Description, &parentID, &managerID,
+	).Scan(&d.ID, &d.Name, &d.Code, &d.Description, &parentID, &managerID, &d.MinStaffOnDuty,
 		&d.IsActive, &d.CreatedAt, &d.UpdatedAt, &d.DeletedAt)
 	if err != nil {
 		return nil, err
@@ -83,7 +83,7 @@ func (r *DepartmentRepository) List(filter interfaces.DepartmentFilter, page, pa
 
 	args = append(args, pageSize, (page-1)*pageSize)
 	rows, err := r.db.Query(fmt.Sprintf(`
-		SELECT d.id, d.name, d.code, d.description, d.parent_department_id, d.manager_id, d.is_active, d.created_at, d.updated_at, d.deleted_at,
+		SELECT d.id, d.name, d.code, d.description, d.parent_department_id, d.manager_id, d.min_staff_on_duty, d.is_active, d.created_at, d.updated_at, d.deleted_at,
 		       COALESCE(pd.name, '') AS parent_department_name,
 		       COALESCE(e.first_name || ' ' || e.last_name, '') AS manager_name
 		FROM departments d
@@ -99,7 +99,7 @@ func (r *DepartmentRepository) List(filter interfaces.DepartmentFilter, page, pa
 	for rows.Next() {
 		var d models.Department
 		var parentID, managerID sql.NullString
-		if err := rows.Scan(&d.ID, &d.Name, &d.Code, &d.Description, &parentID, &managerID,
+		if err := rows.Scan(&d.ID, &d.Name, &d.Code, &d.Description, &parentID, &managerID, &d.MinStaffOnDuty,
 			&d.IsActive, &d.CreatedAt, &d.UpdatedAt, &d.DeletedAt,
 			&d.ParentDepartmentName, &d.ManagerName); err != nil {
 			return nil, 0, err
@@ -121,9 +121,9 @@ func (r *DepartmentRepository) Update(dept *models.Department) error {
 	dept.UpdatedAt = time.Now()
 	_, err := r.db.Exec(`
 		UPDATE departments SET name=$1, code=$2, description=$3, parent_department_id=$4,
-		manager_id=$5, is_active=$6, updated_at=$7 WHERE id=$8 AND deleted_at IS NULL`,
+		manager_id=$5, min_staff_on_duty=$6, is_active=$7, updated_at=$8 WHERE id=$9 AND deleted_at IS NULL`,
 		dept.Name, dept.Code, dept.Description, dept.ParentDepartmentID,
-		dept.ManagerID, dept.IsActive, dept.UpdatedAt, dept.ID,
+		dept.ManagerID, dept.MinStaffOnDuty, dept.IsActive, dept.UpdatedAt, dept.ID,
 	)
 	return err
 }
@@ -135,7 +135,7 @@ func (r *DepartmentRepository) SoftDelete(id uuid.UUID) error {
 
 func (r *DepartmentRepository) GetAll() ([]models.Department, error) {
 	rows, err := r.db.Query(`
-		SELECT id, name, code, description, parent_department_id, manager_id, is_active, created_at, updated_at, deleted_at
+		SELECT id, name, code, description, parent_department_id, manager_id, min_staff_on_duty, is_active, created_at, updated_at, deleted_at
 		FROM departments WHERE deleted_at IS NULL ORDER BY name`)
 	if err != nil {
 		return nil, err
@@ -146,7 +146,7 @@ func (r *DepartmentRepository) GetAll() ([]models.Department, error) {
 	for rows.Next() {
 		var d models.Department
 		var parentID, managerID sql.NullString
-		if err := rows.Scan(&d.ID, &d.Name, &d.Code, &d.Description, &parentID, &managerID,
+		if err := rows.Scan(&d.ID, &d.Name, &d.Code, &d.Description, &parentID, &managerID, &d.MinStaffOnDuty,
 			&d.IsActive, &d.CreatedAt, &d.UpdatedAt, &d.DeletedAt); err != nil {
 			return nil, err
 		}
@@ -26,7 +26,7 @@ const employeeSelectCols = `
 	e.phone, e.date_of_birth, e.gender, e.national_id, e.marital_status, e.address, e.city, e.state,
 	e.country, e.department_id, e.position_id, e.manager_id, e.hire_date, e.probation_end_date,
 	e.employment_type, e.employment_status, e.termination_date, e.termination_reason,
-	e.profile_photo_url, e.created_at, e.updated_at, e.deleted_at`
+	e.profile_photo_url, e.pay_group_id, e.created_at, e.updated_at, e.deleted_at`
 
 func (r *EmployeeRepository) Create(emp *models.Employee) error {
 	emp.ID = uuid.New()
@@ -100,6 +100,14 @@ func (r *EmployeeRepository) List(filter interfaces.EmployeeFilter, page, pageSi
 		args = append(args, filter.EmploymentType)
 		i++
 	}
+	if filter.PayGroupID != nil {
+		// Employees without a pay group belong to the default group
+		where = append(where, fmt.Sprintf(
+			"(e.pay_group_id=$%d OR (e.pay_group_id IS NULL AND EXISTS (SELECT 1 FROM pay_groups pg WHERE pg.id=$%d AND pg.is_default)))",
+			i, i))
+		args = append(args, *filter.PayGroupID)
+		i++
+	}
 
 	whereStr := "1=1"
 	if len(where) > 0 {
@@ -181,6 +189,38 @@ func (r *EmployeeRepository) GetDirectReports(managerID uuid.UUID) ([]models.Emp
 	return emps, rows.Err()
 }
 
+// CountTeam counts the active employees reporting to the manager, directly or indirectly
+func (r *EmployeeRepository) CountTeam(managerID uuid.UUID) (int, error) {
+	var count int
+	err := r.db.QueryRow(teamCTE+`
+		SELECT COUNT(*) FROM employees e JOIN team t ON e.id=t.id WHERE e.employment_status='active'`,
+		managerID).Scan(&count)
+	return count, err
+}
+
+// InTeam reports whether the employee reports to the manager, directly or through other
+// managers
+func (r *EmployeeRepository) InTeam(managerID, employeeID uuid.UUID) (bool, error) {
+	var in bool
+	err := r.db.QueryRow(teamCTE+`
+		SELECT EXISTS (SELECT 1 FROM team WHERE id=$2)`,
+		managerID, employeeID).Scan(&in)
+	return in, err
+}
+
+// DepartmentInTeam reports whether everyone in the department is the manager or reports
+// to them, directly or through other managers
+func (r *EmployeeRepository) DepartmentInTeam(managerID, departmentID uuid.UUID) (bool, error) {
+	var in bool
+	err := r.db.QueryRow(teamCTE+`
+		SELECT NOT EXISTS (
+			SELECT 1 FROM employees
+			WHERE department_id=$2 AND deleted_at IS NULL AND id<>$1 AND id NOT IN (SELECT id FROM team)
+		)`,
+		managerID, departmentID).Scan(&in)
+	return in, err
+}
+
 func (r *EmployeeRepository) CountByDatePrefix(prefix string) (int, error) {
 	var count int
 	err := r.db.QueryRow(`SELECT COUNT(*) FROM employees WHERE employee_number LIKE $1`, "EMP-"+prefix+"%").Scan(&count)
@@ -250,7 +290,7 @@ func (r *EmployeeRepository) scanEmployee(row rowScanner) (*models.Employee, err
 		&e.Phone, &dob, &e.Gender, &e.NationalID, &e.MaritalStatus, &e.Address, &e.City, &e.State,
 		&e.Country, &e.DepartmentID, &e.PositionID, &managerID, &e.HireDate, &probEnd,
 		&e.EmploymentType, &e.EmploymentStatus, &termDate, &e.TerminationReason,
-		&e.ProfilePhotoURL, &e.CreatedAt, &e.UpdatedAt, &e.DeletedAt,
+		&e.ProfilePhotoURL, &e.PayGroupID, &e.CreatedAt, &e.UpdatedAt, &e.DeletedAt,
 	)
 	if err != nil {
 		return nil, err
@@ -270,7 +310,7 @@ func (r *EmployeeRepository) scanEmployeeWithNames(row rowScanner) (*models.Empl
 		&e.Phone, &dob, &e.Gender, &e.NationalID, &e.MaritalStatus, &e.Address, &e.City, &e.State,
 		&e.Country, &e.DepartmentID, &e.PositionID, &managerID, &e.HireDate, &probEnd,
 		&e.EmploymentType, &e.EmploymentStatus, &termDate, &e.TerminationReason,
-		&e.ProfilePhotoURL, &e.CreatedAt, &e.UpdatedAt, &e.DeletedAt,
+		&e.ProfilePhotoURL, &e.PayGroupID, &e.CreatedAt, &e.UpdatedAt, &e.DeletedAt,
 		&e.DepartmentName, &e.PositionName, &e.ManagerName,
 	)
 	if err != nil {
@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"hr-system/internal/database"
+	"hr-system/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type FinalSettlementRepository struct {
+	db *sql.DB
+}
+
+func NewFinalSettlementRepository() *FinalSettlementRepository {
+	return &FinalSettlementRepository{db: database.DB}
+}
+
+const finalSettlementSelect = `
+	SELECT s.id, s.employee_id, s.termination_date, s.separation_type, s.period_start, s.period_end,
+	       s.days_worked, s.period_days, s.prorated_salary, s.other_earnings, s.leave_days, s.leave_encashment,
+	       s.notice_days, s.notice_pay, s.service_years, s.gratuity, s.arrears, s.gross_pay, s.income_tax,
+	       s.other_deductions, s.loan_recovery, s.loan_outstanding, s.net_pay, s.notes, s.status, s.payslip_id,
+	       s.created_by, s.approved_by, s.approved_at, s.created_at, s.updated_at,
+	       CONCAT(e.first_name, ' ', e.last_name) AS employee_name, e.employee_number
+	FROM final_settlements s
+	JOIN employees e ON s.employee_id = e.id`
+
+func (r *FinalSettlementRepository) Create(st *models.FinalSettlement) error {
+	st.ID = uuid.New()
+	now := time.Now()
+	st.CreatedAt = now
+	st.UpdatedAt = now
+	if st.Status == "" {
+		st.Status = models.FinalSettlementPending
+	}
+	_, err := r.db.Exec(`
+		INSERT INTO final_settlements (id, employee_id, termination_date, separation_type, period_start, period_end, days_worked, period_days, prorated_salary, other_earnings, leave_days, leave_encashment, notice_days, notice_pay, service_years, gratuity, arrears, gross_pay, income_tax, other_deductions, loan_recovery, loan_outstanding, net_pay, notes, status, created_by, created_at, updated_at)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19,$20,$21,$22,$23,$24,$25,$26,$27,$28)`,
+		st.ID, st.EmployeeID, st.TerminationDate, st.SeparationType, st.PeriodStart, st.PeriodEnd, st.DaysWorked,
+		st.PeriodDays, st.ProratedSalary, st.OtherEarnings, st.LeaveDays, st.LeaveEncashment, st.NoticeDays,
+		st.NoticePay, st.ServiceYears, st.Gratuity, st.Arrears, st.GrossPay, st.IncomeTax, st.OtherDeductions,
+		st.LoanRecovery, st.LoanOutstanding, st.NetPay, st.Notes, st.Status, st.CreatedBy, st.CreatedAt, st.UpdatedAt,
+	)
+	return err
+}
+
+// Update saves a settlement that is still pending, including its move to cancelled.
+func (r *FinalSettlementRepository) Update(st *models.FinalSettlement) error {
+	return updateSettlement(r.db, st)
+}
+
+// Approve saves a settlement moving to approved and debits the leave it encashed from the
+// employee's balances in the same transaction, recording each debit in the leave ledger.
+func (r *FinalSettlementRepository) Approve(st *models.FinalSettlement, encashed []models.LeaveBalance) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := updateSettlement(tx, st); err != nil {
+		return err
+	}
+	for _, b := range encashed {
+		if _, err := tx.Exec(`
+			UPDATE leave_balances SET used=used+$1, updated_at=NOW() WHERE id=$2`,
+			b.Balance, b.ID); err != nil {
+			return fmt.Errorf("failed to debit encashed leave: %w", err)
+		}
+		entry := &models.LeaveLedgerEntry{
+			EmployeeID:  b.EmployeeID,
+			LeaveTypeID: b.LeaveTypeID,
+			Year:        b.Year,
+			EntryType:   models.LeaveLedgerEncashment,
+			Days:        -b.Balance,
+			ReferenceID: &st.ID,
+			Notes:       "Encashed in final settlement",
+			CreatedBy:   st.ApprovedBy,
+		}
+		if err := insertLeaveLedgerEntry(tx, entry); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func updateSettlement(db sqlExecer, st *models.FinalSettlement) error {
+	st.UpdatedAt = time.Now()
+	res, err := db.Exec(`
+		UPDATE final_settlements
+		SET termination_date=$2, separation_type=$3, period_start=$4, period_end=$5, days_worked=$6, period_days=$7,
+		    prorated_salary=$8, other_earnings=$9, leave_days=$10, leave_encashment=$11, notice_days=$12,
+		    notice_pay=$13, service_years=$14, gratuity=$15, arrears=$16, gross_pay=$17, income_tax=$18,
+		    other_deductions=$19, loan_recovery=$20, loan_outstanding=$21, net_pay=$22, notes=$23, status=$24,
+		    payslip_id=$25, approved_by=$26, approved_at=$27, updated_at=$28
+		WHERE id=$1 AND status='pending'`,
+		st.ID, st.TerminationDate, st.SeparationType, st.PeriodStart, st.PeriodEnd, st.DaysWorked, st.PeriodDays,
+		st.ProratedSalary, st.OtherEarnings, st.LeaveDays, st.LeaveEncashment, st.NoticeDays, st.NoticePay,
+		st.ServiceYears, st.Gratuity, st.Arrears, st.GrossPay, st.IncomeTax, st.OtherDeductions, st.LoanRecovery,
+		st.LoanOutstanding, st.NetPay, st.Notes, st.Status, st.PayslipID, st.ApprovedBy, st.ApprovedAt, st.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("settlement is no longer pending")
+	}
+	return nil
+}
+
+func (r *FinalSettlementRepository) GetByID(id uuid.UUID) (*models.FinalSettlement, error) {
+	return r.scanRow(r.db.QueryRow(finalSettlementSelect+` WHERE s.id=$1`, id))
+}
+
+// GetActiveForEmployee returns an employee's pending or approved settlement
+func (r *FinalSettlementRepository) GetActiveForEmployee(employeeID uuid.UUID) (*models.FinalSettlement, error) {
+	return r.scanRow(r.db.QueryRow(finalSettlementSelect+` WHERE s.employee_id=$1 AND s.status<>'cancelled'`, employeeID))
+}
+
+// GetByPayslipID returns the settlement paid on a payslip
+func (r *FinalSettlementRepository) GetByPayslipID(payslipID uuid.UUID) (*models.FinalSettlement, error) {
+	return r.scanRow(r.db.QueryRow(finalSettlementSelect+` WHERE s.payslip_id=$1 AND s.status<>'cancelled'`, payslipID))
+}
+
+// List returns settlements, newest first, optionally filtered by status
+func (r *FinalSettlementRepository) List(status models.FinalSettlementStatus) ([]models.FinalSettlement, error) {
+	query := finalSettlementSelect
+	var args []interface{}
+	if status != "" {
+		query += ` WHERE s.status=$1`
+		args = append(args, status)
+	}
+	rows, err := r.db.Query(query+` ORDER BY s.created_at DESC`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var settlements []models.FinalSettlement
+	for rows.Next() {
+		st, err := r.scanRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		settlements = append(settlements, *st)
+	}
+	return settlements, rows.Err()
+}
+
+func (r *FinalSettlementRepository) scanRow(row rowScanner) (*models.FinalSettlement, error) {
+	var st models.FinalSettlement
+	err := row.Scan(&st.ID, &st.EmployeeID, &st.TerminationDate, &st.SeparationType, &st.PeriodStart, &st.PeriodEnd,
+		&st.DaysWorked, &st.PeriodDays, &st.ProratedSalary, &st.OtherEarnings, &st.LeaveDays, &st.LeaveEncashment,
+		&st.NoticeDays, &st.NoticePay, &st.ServiceYears, &st.Gratuity, &st.Arrears, &st.GrossPay, &st.IncomeTax,
+		&st.OtherDeductions, &st.LoanRecovery, &st.LoanOutstanding, &st.NetPay, &st.Notes, &st.Status, &st.PayslipID,
+		&st.CreatedBy, &st.ApprovedBy, &st.ApprovedAt, &st.CreatedAt, &st.UpdatedAt,
+		&st.EmployeeName, &st.EmployeeNumber)
+	if err != nil {
+		return nil, err
+	}
+	return &st, nil
+}
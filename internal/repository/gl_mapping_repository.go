@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"hr-system/internal/database"
+	"hr-system/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type GLMappingRepository struct {
+	db *sql.DB
+}
+
+func NewGLMappingRepository() *GLMappingRepository {
+	return &GLMappingRepository{db: database.DB}
+}
+
+const glMappingSelect = `
+	SELECT m.id, m.component, m.department_id, m.account_code, m.account_name, m.cost_centre,
+	       m.created_at, m.updated_at, COALESCE(d.name, '') AS department_name
+	FROM gl_account_mappings m
+	LEFT JOIN departments d ON m.department_id = d.id`
+
+func (r *GLMappingRepository) Create(m *models.GLAccountMapping) error {
+	m.ID = uuid.New()
+	now := time.Now()
+	m.CreatedAt = now
+	m.UpdatedAt = now
+	_, err := r.db.Exec(`
+		INSERT INTO gl_account_mappings (id, component, department_id, account_code, account_name, cost_centre, created_at, updated_at)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8)`,
+		m.ID, m.Component, m.DepartmentID, m.AccountCode, m.AccountName, m.CostCentre, m.CreatedAt, m.UpdatedAt,
+	)
+	return err
+}
+
+func (r *GLMappingRepository) Update(m *models.GLAccountMapping) error {
+	m.UpdatedAt = time.Now()
+	_, err := r.db.Exec(`
+		UPDATE gl_account_mappings SET account_code=$2, account_name=$3, cost_centre=$4, updated_at=$5
+		WHERE id=$1`,
+		m.ID, m.AccountCode, m.AccountName, m.CostCentre, m.UpdatedAt,
+	)
+	return err
+}
+
+func (r *GLMappingRepository) GetByID(id uuid.UUID) (*models.GLAccountMapping, error) {
+	return r.scanRow(r.db.QueryRow(glMappingSelect+` WHERE m.id=$1`, id))
+}
+
+// Find returns the mapping for a component, either the department override or
+// (with a nil departmentID) the company-wide default.
+func (r *GLMappingRepository) Find(component models.GLComponent, departmentID *uuid.UUID) (*models.GLAccountMapping, error) {
+	if departmentID == nil {
+		return r.scanRow(r.db.QueryRow(glMappingSelect+` WHERE m.component=$1 AND m.department_id IS NULL`, component))
+	}
+	return r.scanRow(r.db.QueryRow(glMappingSelect+` WHERE m.component=$1 AND m.department_id=$2`, component, *departmentID))
+}
+
+// List returns all mappings, defaults first, then by department and component.
+func (r *GLMappingRepository) List() ([]models.GLAccountMapping, error) {
+	rows, err := r.db.Query(glMappingSelect + ` ORDER BY m.department_id IS NOT NULL, d.name, m.component`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []models.GLAccountMapping
+	for rows.Next() {
+		m, err := r.scanRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, *m)
+	}
+	return list, rows.Err()
+}
+
+func (r *GLMappingRepository) Delete(id uuid.UUID) error {
+	result, err := r.db.Exec(`DELETE FROM gl_account_mappings WHERE id=$1`, id)
+	if err != nil {
+		return err
+	}
+	n, _ := result.RowsAffected()
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *GLMappingRepository) scanRow(row rowScanner) (*models.GLAccountMapping, error) {
+	var m models.GLAccountMapping
+	err := row.Scan(&m.ID, &m.Component, &m.DepartmentID, &m.AccountCode, &m.AccountName, &m.CostCentre,
+		&m.CreatedAt, &m.UpdatedAt, &m.DepartmentName)
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
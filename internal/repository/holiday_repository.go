@@ -127,6 +127,29 @@ func (r *HolidayRepository) GetHolidaysInRange(from, to time.Time, location stri
 	return holidays, rows.Err()
 }
 
+// ListInRange returns the active holidays dated within [from, to] for the given location
+func (r *HolidayRepository) ListInRange(from, to time.Time, location string) ([]models.Holiday, error) {
+	rows, err := r.db.Query(`
+		SELECT id, name, date, description, is_recurring, location, is_active, created_at, updated_at
+		FROM holidays
+		WHERE date>=$1 AND date<=$2 AND is_active=TRUE AND (location='' OR location=$3)
+		ORDER BY date`, from, to, location)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []models.Holiday{}
+	for rows.Next() {
+		h, err := r.scanOne(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *h)
+	}
+	return out, rows.Err()
+}
+
 func (r *HolidayRepository) scanOne(row rowScanner) (*models.Holiday, error) {
 	var h models.Holiday
 	err := row.Scan(&h.ID, &h.Name, &h.Date, &h.Description, &h.IsRecurring, &h.Location, &h.IsActive, &h.CreatedAt, &h.UpdatedAt)
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"database/sql"
+	"errors"
 	"time"
 
 	"hr-system/internal/database"
@@ -21,7 +22,8 @@ func NewLeaveBalanceRepository() *LeaveBalanceRepository {
 func (r *LeaveBalanceRepository) GetByEmployeeAndYear(employeeID uuid.UUID, year int) ([]models.LeaveBalance, error) {
 	rows, err := r.db.Query(`
 		SELECT lb.id, lb.employee_id, lb.leave_type_id, lb.year, lb.total_entitled, lb.used, lb.pending,
-		       lb.carried_forward, lb.earned_leave_days, lb.created_at, lb.updated_at,
+		       lb.carried_forward, lb.earned_leave_days, lb.carried_forward_expires_on, lb.forfeited,
+		       lb.carry_forward_reminder_days, lb.created_at, lb.updated_at,
 		       lt.id, lt.name, lt.code
 		FROM leave_balances lb
 		JOIN leave_types lt ON lb.leave_type_id = lt.id
@@ -37,7 +39,8 @@ func (r *LeaveBalanceRepository) GetByEmployeeAndYear(employeeID uuid.UUID, year
 func (r *LeaveBalanceRepository) GetByEmployeeTypeYear(employeeID, leaveTypeID uuid.UUID, year int) (*models.LeaveBalance, error) {
 	row := r.db.QueryRow(`
 		SELECT lb.id, lb.employee_id, lb.leave_type_id, lb.year, lb.total_entitled, lb.used, lb.pending,
-		       lb.carried_forward, lb.earned_leave_days, lb.created_at, lb.updated_at,
+		       lb.carried_forward, lb.earned_leave_days, lb.carried_forward_expires_on, lb.forfeited,
+		       lb.carry_forward_reminder_days, lb.created_at, lb.updated_at,
 		       lt.id, lt.name, lt.code
 		FROM leave_balances lb
 		JOIN leave_types lt ON lb.leave_type_id = lt.id
@@ -51,7 +54,8 @@ func (r *LeaveBalanceRepository) GetByEmployeeTypeYear(employeeID, leaveTypeID u
 func (r *LeaveBalanceRepository) GetAllByYear(year int) ([]models.LeaveBalance, error) {
 	rows, err := r.db.Query(`
 		SELECT lb.id, lb.employee_id, lb.leave_type_id, lb.year, lb.total_entitled, lb.used, lb.pending,
-		       lb.carried_forward, lb.earned_leave_days, lb.created_at, lb.updated_at,
+		       lb.carried_forward, lb.earned_leave_days, lb.carried_forward_expires_on, lb.forfeited,
+		       lb.carry_forward_reminder_days, lb.created_at, lb.updated_at,
 		       lt.id, lt.name, lt.code
 		FROM leave_balances lb
 		JOIN leave_types lt ON lb.leave_type_id = lt.id
@@ -64,13 +68,84 @@ func (r *LeaveBalanceRepository) GetAllByYear(year int) ([]models.LeaveBalance,
 	return r.scanRows(rows)
 }
 
-func (r *LeaveBalanceRepository) SetCarriedForward(id uuid.UUID, days int) error {
+// SetCarriedForward writes the days carried into a balance and the last day they can be
+// used on (nil if they never expire), starting their expiry afresh
+func (r *LeaveBalanceRepository) SetCarriedForward(id uuid.UUID, days float64, expiresOn *time.Time) error {
 	_, err := r.db.Exec(`
-		UPDATE leave_balances SET carried_forward=$1, updated_at=NOW() WHERE id=$2`,
-		days, id)
+		UPDATE leave_balances
+		SET carried_forward=$1, carried_forward_expires_on=$2, forfeited=0,
+		    carry_forward_settled_at=NULL, carry_forward_reminder_days=NULL, updated_at=NOW()
+		WHERE id=$3`,
+		days, expiresOn, id)
 	return err
 }
 
+// ListExpiringCarryForwards returns the balances with carried forward days that expire on
+// or before the given date and have not been settled yet, soonest expiry first
+func (r *LeaveBalanceRepository) ListExpiringCarryForwards(through time.Time) ([]models.LeaveBalance, error) {
+	rows, err := r.db.Query(`
+		SELECT lb.id, lb.employee_id, lb.leave_type_id, lb.year, lb.total_entitled, lb.used, lb.pending,
+		       lb.carried_forward, lb.earned_leave_days, lb.carried_forward_expires_on, lb.forfeited,
+		       lb.carry_forward_reminder_days, lb.created_at, lb.updated_at,
+		       lt.id, lt.name, lt.code
+		FROM leave_balances lb
+		JOIN leave_types lt ON lb.leave_type_id = lt.id
+		WHERE lb.carried_forward > 0 AND lb.carry_forward_settled_at IS NULL
+		  AND lb.carried_forward_expires_on <= $1
+		ORDER BY lb.carried_forward_expires_on, lb.employee_id`, through)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return r.scanRows(rows)
+}
+
+// MarkCarryForwardReminded records the reminder sent the given number of days before the
+// carried forward days expire
+func (r *LeaveBalanceRepository) MarkCarryForwardReminded(id uuid.UUID, daysBefore int) error {
+	_, err := r.db.Exec(`
+		UPDATE leave_balances SET carry_forward_reminder_days=$1, updated_at=NOW() WHERE id=$2`,
+		daysBefore, id)
+	return err
+}
+
+// ForfeitCarryForward settles expired carried forward days, forfeiting the given number of
+// them and recording the debit in the leave ledger. A balance is only settled once.
+func (r *LeaveBalanceRepository) ForfeitCarryForward(lb *models.LeaveBalance, days float64, notes string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`
+		UPDATE leave_balances
+		SET forfeited=forfeited+$1, carry_forward_settled_at=NOW(), updated_at=NOW()
+		WHERE id=$2 AND carry_forward_settled_at IS NULL`,
+		days, lb.ID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return errors.New("carried forward days are already settled")
+	}
+	if days > 0 {
+		entry := &models.LeaveLedgerEntry{
+			EmployeeID:  lb.EmployeeID,
+			LeaveTypeID: lb.LeaveTypeID,
+			Year:        lb.Year,
+			EntryType:   models.LeaveLedgerForfeiture,
+			Days:        -days,
+			ReferenceID: &lb.ID,
+			Notes:       notes,
+		}
+		if err := insertLeaveLedgerEntry(tx, entry); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
 func (r *LeaveBalanceRepository) Upsert(lb *models.LeaveBalance) error {
 	if lb.ID == uuid.Nil {
 		lb.ID = uuid.New()
@@ -91,7 +166,7 @@ func (r *LeaveBalanceRepository) Upsert(lb *models.LeaveBalance) error {
 	return err
 }
 
-func (r *LeaveBalanceRepository) IncrementPending(employeeID, leaveTypeID uuid.UUID, year, days int) error {
+func (r *LeaveBalanceRepository) IncrementPending(employeeID, leaveTypeID uuid.UUID, year int, days float64) error {
 	_, err := r.db.Exec(`
 		UPDATE leave_balances SET pending=pending+$1, updated_at=NOW()
 		WHERE employee_id=$2 AND leave_type_id=$3 AND year=$4`,
@@ -99,7 +174,7 @@ func (r *LeaveBalanceRepository) IncrementPending(employeeID, leaveTypeID uuid.U
 	return err
 }
 
-func (r *LeaveBalanceRepository) DecrementPending(employeeID, leaveTypeID uuid.UUID, year, days int) error {
+func (r *LeaveBalanceRepository) DecrementPending(employeeID, leaveTypeID uuid.UUID, year int, days float64) error {
 	_, err := r.db.Exec(`
 		UPDATE leave_balances SET pending=GREATEST(0, pending-$1), updated_at=NOW()
 		WHERE employee_id=$2 AND leave_type_id=$3 AND year=$4`,
@@ -108,7 +183,7 @@ func (r *LeaveBalanceRepository) DecrementPending(employeeID, leaveTypeID uuid.U
 }
 
 // ApproveLeave moves days from pending → used
-func (r *LeaveBalanceRepository) ApproveLeave(employeeID, leaveTypeID uuid.UUID, year, days int) error {
+func (r *LeaveBalanceRepository) ApproveLeave(employeeID, leaveTypeID uuid.UUID, year int, days float64) error {
 	_, err := r.db.Exec(`
 		UPDATE leave_balances
 		SET pending=GREATEST(0, pending-$1), used=used+$1, updated_at=NOW()
@@ -117,13 +192,72 @@ func (r *LeaveBalanceRepository) ApproveLeave(employeeID, leaveTypeID uuid.UUID,
 	return err
 }
 
-func (r *LeaveBalanceRepository) Adjust(id uuid.UUID, delta int) error {
+// AddUsed charges days of approved leave to a balance
+func (r *LeaveBalanceRepository) AddUsed(employeeID, leaveTypeID uuid.UUID, year int, days float64) error {
+	_, err := r.db.Exec(`
+		UPDATE leave_balances SET used=used+$1, updated_at=NOW()
+		WHERE employee_id=$2 AND leave_type_id=$3 AND year=$4`,
+		days, employeeID, leaveTypeID, year)
+	return err
+}
+
+// ReturnUsed gives back days taken by approved leave that has been cancelled
+func (r *LeaveBalanceRepository) ReturnUsed(employeeID, leaveTypeID uuid.UUID, year int, days float64) error {
+	_, err := r.db.Exec(`
+		UPDATE leave_balances SET used=GREATEST(0, used-$1), updated_at=NOW()
+		WHERE employee_id=$2 AND leave_type_id=$3 AND year=$4`,
+		days, employeeID, leaveTypeID, year)
+	return err
+}
+
+func (r *LeaveBalanceRepository) Adjust(id uuid.UUID, delta float64) error {
 	_, err := r.db.Exec(`
 		UPDATE leave_balances SET earned_leave_days=earned_leave_days+$1, updated_at=NOW() WHERE id=$2`,
 		delta, id)
 	return err
 }
 
+// ListLedger returns the ledger entries against an employee's balances for a leave year,
+// oldest first
+func (r *LeaveBalanceRepository) ListLedger(employeeID uuid.UUID, year int) ([]models.LeaveLedgerEntry, error) {
+	rows, err := r.db.Query(`
+		SELECT le.id, le.employee_id, le.leave_type_id, le.year, le.entry_type, le.days, le.reference_id,
+		       le.notes, le.created_by, le.created_at, lt.name
+		FROM leave_ledger_entries le
+		JOIN leave_types lt ON le.leave_type_id = lt.id
+		WHERE le.employee_id=$1 AND le.year=$2
+		ORDER BY le.created_at`, employeeID, year)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []models.LeaveLedgerEntry
+	for rows.Next() {
+		var e models.LeaveLedgerEntry
+		if err := rows.Scan(&e.ID, &e.EmployeeID, &e.LeaveTypeID, &e.Year, &e.EntryType, &e.Days, &e.ReferenceID,
+			&e.Notes, &e.CreatedBy, &e.CreatedAt, &e.LeaveTypeName); err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+// insertLeaveLedgerEntry records a ledger entry; callers change the balance itself in the
+// same transaction
+func insertLeaveLedgerEntry(db sqlExecer, e *models.LeaveLedgerEntry) error {
+	e.ID = uuid.New()
+	e.CreatedAt = time.Now()
+	_, err := db.Exec(`
+		INSERT INTO leave_ledger_entries
+		(id, employee_id, leave_type_id, year, entry_type, days, reference_id, notes, created_by, created_at)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10)`,
+		e.ID, e.EmployeeID, e.LeaveTypeID, e.Year, e.EntryType, e.Days, e.ReferenceID, e.Notes,
+		e.CreatedBy, e.CreatedAt,
+	)
+	return err
+}
+
 func (r *LeaveBalanceRepository) scanRows(rows *sql.Rows) ([]models.LeaveBalance, error) {
 	var out []models.LeaveBalance
 	for rows.Next() {
@@ -146,13 +280,14 @@ func (r *LeaveBalanceRepository) scanRow(row rowScanner) (*models.LeaveBalance,
 	var ltName, ltCode string
 	err := row.Scan(
 		&lb.ID, &lb.EmployeeID, &lb.LeaveTypeID, &lb.Year, &lb.TotalEntitled, &lb.Used, &lb.Pending,
-		&lb.CarriedForward, &lb.EarnedLeaveDays, &lb.CreatedAt, &lb.UpdatedAt,
+		&lb.CarriedForward, &lb.EarnedLeaveDays, &lb.CarriedForwardExpiresOn, &lb.Forfeited,
+		&lb.CarryForwardReminderDays, &lb.CreatedAt, &lb.UpdatedAt,
 		&ltID, &ltName, &ltCode,
 	)
 	if err != nil {
 		return nil, err
 	}
-	lb.Balance = lb.TotalEntitled + lb.CarriedForward + lb.EarnedLeaveDays - lb.Used - lb.Pending
+	lb.Balance = models.RoundLeaveDays(lb.TotalEntitled + lb.CarriedForward + lb.EarnedLeaveDays - lb.Used - lb.Pending - lb.Forfeited)
 	lb.LeaveType = &models.LeaveType{ID: ltID, Name: ltName, Code: ltCode}
 	return &lb, nil
 }
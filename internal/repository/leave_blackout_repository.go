@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"hr-system/internal/database"
+	"hr-system/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type LeaveBlackoutRepository struct {
+	db *sql.DB
+}
+
+func NewLeaveBlackoutRepository() *LeaveBlackoutRepository {
+	return &LeaveBlackoutRepository{db: database.DB}
+}
+
+func (r *LeaveBlackoutRepository) Create(b *models.LeaveBlackout) error {
+	b.ID = uuid.New()
+	now := time.Now()
+	b.CreatedAt = now
+	b.UpdatedAt = now
+	_, err := r.db.Exec(`
+		INSERT INTO leave_blackouts (id, name, description, department_id, start_date, end_date, is_active, created_at, updated_at)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9)`,
+		b.ID, b.Name, b.Description, b.DepartmentID, b.StartDate, b.EndDate, b.IsActive, b.CreatedAt, b.UpdatedAt,
+	)
+	return err
+}
+
+func (r *LeaveBlackoutRepository) GetByID(id uuid.UUID) (*models.LeaveBlackout, error) {
+	return r.scanOne(r.db.QueryRow(`
+		SELECT id, name, description, department_id, start_date, end_date, is_active, created_at, updated_at
+		FROM leave_blackouts WHERE id=$1`, id))
+}
+
+// List returns blackouts ending on or after from, or all of them when from is nil
+func (r *LeaveBlackoutRepository) List(from *time.Time) ([]models.LeaveBlackout, error) {
+	q := `SELECT id, name, description, department_id, start_date, end_date, is_active, created_at, updated_at
+		  FROM leave_blackouts`
+	args := []interface{}{}
+	if from != nil {
+		q += " WHERE end_date >= $1"
+		args = append(args, *from)
+	}
+	q += " ORDER BY start_date"
+	return r.query(q, args...)
+}
+
+// ListActiveInRange returns the active blackouts overlapping [start, end] that apply to the
+// department: company-wide ones and the department's own
+func (r *LeaveBlackoutRepository) ListActiveInRange(departmentID uuid.UUID, start, end time.Time) ([]models.LeaveBlackout, error) {
+	return r.query(`
+		SELECT id, name, description, department_id, start_date, end_date, is_active, created_at, updated_at
+		FROM leave_blackouts
+		WHERE is_active=TRUE AND start_date <= $1 AND end_date >= $2
+		  AND (department_id IS NULL OR department_id=$3)
+		ORDER BY start_date`, end, start, departmentID)
+}
+
+func (r *LeaveBlackoutRepository) Update(b *models.LeaveBlackout) error {
+	b.UpdatedAt = time.Now()
+	_, err := r.db.Exec(`
+		UPDATE leave_blackouts SET name=$1, description=$2, department_id=$3, start_date=$4, end_date=$5,
+		is_active=$6, updated_at=$7
+		WHERE id=$8`,
+		b.Name, b.Description, b.DepartmentID, b.StartDate, b.EndDate, b.IsActive, b.UpdatedAt, b.ID,
+	)
+	return err
+}
+
+func (r *LeaveBlackoutRepository) Delete(id uuid.UUID) error {
+	_, err := r.db.Exec(`DELETE FROM leave_blackouts WHERE id=$1`, id)
+	return err
+}
+
+func (r *LeaveBlackoutRepository) query(q string, args ...interface{}) ([]models.LeaveBlackout, error) {
+	rows, err := r.db.Query(q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []models.LeaveBlackout
+	for rows.Next() {
+		b, err := r.scanOne(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *b)
+	}
+	return out, rows.Err()
+}
+
+func (r *LeaveBlackoutRepository) scanOne(row rowScanner) (*models.LeaveBlackout, error) {
+	var b models.LeaveBlackout
+	var departmentID sql.NullString
+	err := row.Scan(&b.ID, &b.Name, &b.Description, &departmentID, &b.StartDate, &b.EndDate, &b.IsActive, &b.CreatedAt, &b.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if departmentID.Valid {
+		id, _ := uuid.Parse(departmentID.String)
+		b.DepartmentID = &id
+	}
+	return &b, nil
+}
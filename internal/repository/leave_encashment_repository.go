@@ -0,0 +1,241 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"hr-system/internal/database"
+	"hr-system/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type LeaveEncashmentRepository struct {
+	db *sql.DB
+}
+
+func NewLeaveEncashmentRepository() *LeaveEncashmentRepository {
+	return &LeaveEncashmentRepository{db: database.DB}
+}
+
+// An encashment counts as paid while it is on a payslip that has not been voided.
+const leaveEncashmentSelect = `
+	SELECT le.id, le.employee_id, le.leave_type_id, le.year, le.days, le.daily_rate, le.amount, le.reason,
+	       le.status, le.payslip_id, le.requested_by, le.reviewed_by, le.reviewed_at, le.created_at, le.updated_at,
+	       COALESCE(p.status <> 'void', false) AS paid,
+	       CONCAT(e.first_name, ' ', e.last_name) AS employee_name, lt.name AS leave_type_name
+	FROM leave_encashments le
+	JOIN employees e ON le.employee_id = e.id
+	JOIN leave_types lt ON le.leave_type_id = lt.id
+	LEFT JOIN payslips p ON le.payslip_id = p.id`
+
+// Create stores a pending encashment and holds its days as pending on the balance it
+// comes from.
+func (r *LeaveEncashmentRepository) Create(e *models.LeaveEncashment) error {
+	e.ID = uuid.New()
+	now := time.Now()
+	e.CreatedAt = now
+	e.UpdatedAt = now
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		INSERT INTO leave_encashments (id, employee_id, leave_type_id, year, days, daily_rate, amount, reason, status, requested_by, created_at, updated_at)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12)`,
+		e.ID, e.EmployeeID, e.LeaveTypeID, e.Year, e.Days, e.DailyRate, e.Amount, e.Reason, e.Status,
+		e.RequestedBy, e.CreatedAt, e.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	res, err := tx.Exec(`
+		UPDATE leave_balances SET pending=pending+$1, updated_at=NOW()
+		WHERE employee_id=$2 AND leave_type_id=$3 AND year=$4`,
+		e.Days, e.EmployeeID, e.LeaveTypeID, e.Year)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("no leave balance for %d", e.Year)
+	}
+	return tx.Commit()
+}
+
+// Approve approves a pending encashment, moving its days from pending to used and
+// recording the debit in the leave ledger.
+func (r *LeaveEncashmentRepository) Approve(id, reviewedBy uuid.UUID, approvedBy *uuid.UUID) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	e, err := lockPendingEncashment(tx, id)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`
+		UPDATE leave_encashments
+		SET status='approved', reviewed_by=$2, reviewed_at=NOW(), updated_at=NOW()
+		WHERE id=$1`, id, reviewedBy); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`
+		UPDATE leave_balances
+		SET pending=GREATEST(0, pending-$1), used=used+$1, updated_at=NOW()
+		WHERE employee_id=$2 AND leave_type_id=$3 AND year=$4`,
+		e.Days, e.EmployeeID, e.LeaveTypeID, e.Year); err != nil {
+		return err
+	}
+	entry := &models.LeaveLedgerEntry{
+		EmployeeID:  e.EmployeeID,
+		LeaveTypeID: e.LeaveTypeID,
+		Year:        e.Year,
+		EntryType:   models.LeaveLedgerEncashment,
+		Days:        -e.Days,
+		ReferenceID: &e.ID,
+		Notes:       fmt.Sprintf("Encashed for %s", e.Amount.Format()),
+		CreatedBy:   approvedBy,
+	}
+	if err := insertLeaveLedgerEntry(tx, entry); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Close rejects or cancels a pending encashment and releases the days it held.
+func (r *LeaveEncashmentRepository) Close(id uuid.UUID, status models.LeaveEncashmentStatus, reviewedBy *uuid.UUID) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	e, err := lockPendingEncashment(tx, id)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`
+		UPDATE leave_encashments
+		SET status=$2, reviewed_by=$3, reviewed_at=NOW(), updated_at=NOW()
+		WHERE id=$1`, id, status, reviewedBy); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`
+		UPDATE leave_balances SET pending=GREATEST(0, pending-$1), updated_at=NOW()
+		WHERE employee_id=$2 AND leave_type_id=$3 AND year=$4`,
+		e.Days, e.EmployeeID, e.LeaveTypeID, e.Year); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// lockPendingEncashment loads the days an encashment holds, locking it so it is approved
+// or closed only once
+func lockPendingEncashment(tx *sql.Tx, id uuid.UUID) (*models.LeaveEncashment, error) {
+	e := &models.LeaveEncashment{ID: id}
+	err := tx.QueryRow(`
+		SELECT employee_id, leave_type_id, year, days, amount
+		FROM leave_encashments WHERE id=$1 AND status='pending'
+		FOR UPDATE`, id).Scan(&e.EmployeeID, &e.LeaveTypeID, &e.Year, &e.Days, &e.Amount)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("leave encashment is no longer pending")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (r *LeaveEncashmentRepository) GetByID(id uuid.UUID) (*models.LeaveEncashment, error) {
+	return r.scanRow(r.db.QueryRow(leaveEncashmentSelect+` WHERE le.id=$1`, id))
+}
+
+// ListByEmployee returns an employee's encashments, most recent first.
+func (r *LeaveEncashmentRepository) ListByEmployee(employeeID uuid.UUID) ([]models.LeaveEncashment, error) {
+	rows, err := r.db.Query(leaveEncashmentSelect+` WHERE le.employee_id=$1 ORDER BY le.created_at DESC`, employeeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return r.scanRows(rows)
+}
+
+// List returns encashments in the given status, or all of them when status is empty.
+func (r *LeaveEncashmentRepository) List(status models.LeaveEncashmentStatus) ([]models.LeaveEncashment, error) {
+	where := ""
+	args := []interface{}{}
+	if status != "" {
+		where = ` WHERE le.status=$1`
+		args = append(args, status)
+	}
+	rows, err := r.db.Query(leaveEncashmentSelect+where+` ORDER BY le.created_at DESC`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return r.scanRows(rows)
+}
+
+// EncashedDays totals the days of a balance that are encashed or waiting to be
+func (r *LeaveEncashmentRepository) EncashedDays(employeeID, leaveTypeID uuid.UUID, year int) (float64, error) {
+	var days float64
+	err := r.db.QueryRow(`
+		SELECT COALESCE(SUM(days), 0) FROM leave_encashments
+		WHERE employee_id=$1 AND leave_type_id=$2 AND year=$3 AND status IN ('pending', 'approved')`,
+		employeeID, leaveTypeID, year).Scan(&days)
+	return days, err
+}
+
+// ListPayable returns an employee's approved encashments still to be paid, together with
+// those already paid on a payslip for the given period so it can be recalculated.
+func (r *LeaveEncashmentRepository) ListPayable(employeeID uuid.UUID, start, end time.Time) ([]models.LeaveEncashment, error) {
+	rows, err := r.db.Query(leaveEncashmentSelect+`
+		WHERE le.employee_id=$1 AND le.status='approved'
+		  AND (p.id IS NULL OR p.status = 'void' OR (p.period_start <= $3 AND p.period_end >= $2))
+		ORDER BY le.reviewed_at, le.created_at`, employeeID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return r.scanRows(rows)
+}
+
+// ListByPayslip returns the encashments paid on a payslip
+func (r *LeaveEncashmentRepository) ListByPayslip(payslipID uuid.UUID) ([]models.LeaveEncashment, error) {
+	rows, err := r.db.Query(leaveEncashmentSelect+` WHERE le.payslip_id=$1 ORDER BY le.reviewed_at, le.created_at`, payslipID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return r.scanRows(rows)
+}
+
+func (r *LeaveEncashmentRepository) scanRows(rows *sql.Rows) ([]models.LeaveEncashment, error) {
+	var out []models.LeaveEncashment
+	for rows.Next() {
+		e, err := r.scanRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *e)
+	}
+	return out, rows.Err()
+}
+
+func (r *LeaveEncashmentRepository) scanRow(row rowScanner) (*models.LeaveEncashment, error) {
+	var e models.LeaveEncashment
+	err := row.Scan(&e.ID, &e.EmployeeID, &e.LeaveTypeID, &e.Year, &e.Days, &e.DailyRate, &e.Amount, &e.Reason,
+		&e.Status, &e.PayslipID, &e.RequestedBy, &e.ReviewedBy, &e.ReviewedAt, &e.CreatedAt, &e.UpdatedAt,
+		&e.Paid, &e.EmployeeName, &e.LeaveTypeName)
+	if err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -21,33 +22,178 @@ func NewLeaveRequestRepository() *LeaveRequestRepository {
 	return &LeaveRequestRepository{db: database.DB}
 }
 
+// Create stores a request with its per-leave-year portions.
 func (r *LeaveRequestRepository) Create(req *models.LeaveRequest) error {
 	req.ID = uuid.New()
 	now := time.Now()
 	req.CreatedAt = now
 	req.UpdatedAt = now
 	req.Status = models.LeaveStatusPending
-	_, err := r.db.Exec(`
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
 		INSERT INTO leave_requests
-		(id, employee_id, leave_type_id, start_date, end_date, total_days, reason, status,
-		 attachment_url, created_at, updated_at)
-		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11)`,
-		req.ID, req.EmployeeID, req.LeaveTypeID, req.StartDate, req.EndDate, req.TotalDays,
-		req.Reason, req.Status, req.AttachmentURL, req.CreatedAt, req.UpdatedAt,
+		(id, employee_id, leave_type_id, start_date, end_date, start_part, end_part, hours, total_days,
+		 reason, status, attachment_url, rule_override_reason, rule_overridden_by, document_status,
+		 document_due_date, created_at, updated_at)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18)`,
+		req.ID, req.EmployeeID, req.LeaveTypeID, req.StartDate, req.EndDate, req.StartPart, req.EndPart,
+		req.Hours, req.TotalDays, req.Reason, req.Status, req.AttachmentURL, req.RuleOverrideReason,
+		req.RuleOverriddenBy, req.DocumentStatus, req.DocumentDueDate, req.CreatedAt, req.UpdatedAt,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+	for _, p := range req.Portions {
+		_, err := tx.Exec(`
+			INSERT INTO leave_request_portions (id, leave_request_id, year, start_date, end_date, days)
+			VALUES ($1,$2,$3,$4,$5,$6)`,
+			uuid.New(), req.ID, p.Year, p.StartDate, p.EndDate, p.Days,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
 }
 
 func (r *LeaveRequestRepository) GetByID(id uuid.UUID) (*models.LeaveRequest, error) {
 	row := r.db.QueryRow(`
-		SELECT lr.id, lr.employee_id, lr.leave_type_id, lr.start_date, lr.end_date, lr.total_days,
+		SELECT lr.id, lr.employee_id, lr.leave_type_id, lr.start_date, lr.end_date, lr.start_part,
+		       lr.end_part, lr.hours, lr.total_days,
 		       lr.reason, lr.status, lr.reviewed_by, lr.reviewed_at, lr.review_comment,
-		       lr.attachment_url, lr.created_at, lr.updated_at,
+		       lr.attachment_url, lr.rule_override_reason, lr.rule_overridden_by, lr.document_status,
+		       lr.document_due_date, lr.document_reminded_at, lr.created_at, lr.updated_at,
 		       lt.id, lt.name, lt.code
 		FROM leave_requests lr
 		JOIN leave_types lt ON lr.leave_type_id=lt.id
 		WHERE lr.id=$1`, id)
-	return r.scanOne(row)
+	req, err := r.scanOne(row)
+	if err != nil {
+		return nil, err
+	}
+	if req.Portions, err = r.ListPortions(id); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// ListLive returns the pending and approved requests that end on or after from, with
+// their portions
+func (r *LeaveRequestRepository) ListLive(from time.Time) ([]models.LeaveRequest, error) {
+	rows, err := r.db.Query(`
+		SELECT lr.id, lr.employee_id, lr.leave_type_id, lr.start_date, lr.end_date, lr.start_part,
+		       lr.end_part, lr.hours, lr.total_days,
+		       lr.reason, lr.status, lr.reviewed_by, lr.reviewed_at, lr.review_comment,
+		       lr.attachment_url, lr.rule_override_reason, lr.rule_overridden_by, lr.document_status,
+		       lr.document_due_date, lr.document_reminded_at, lr.created_at, lr.updated_at,
+		       lt.id, lt.name, lt.code
+		FROM leave_requests lr
+		JOIN leave_types lt ON lr.leave_type_id=lt.id
+		WHERE lr.status IN ('pending','approved') AND lr.end_date >= $1
+		ORDER BY lr.start_date`, from)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []models.LeaveRequest
+	for rows.Next() {
+		req, err := r.scanOne(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *req)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	for i := range out {
+		if out[i].Portions, err = r.ListPortions(out[i].ID); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// MovePortions recharges a live request to new leave-year portions in one transaction:
+// the days of its current portions are released from their balances, the new portions are
+// charged to theirs and replace the old ones. The balances for the new portions must exist.
+func (r *LeaveRequestRepository) MovePortions(req *models.LeaveRequest, portions []models.LeaveRequestPortion) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// The status decides whether pending or used days move, so it must not change meanwhile
+	var status models.LeaveRequestStatus
+	if err := tx.QueryRow(`SELECT status FROM leave_requests WHERE id=$1 FOR UPDATE`, req.ID).Scan(&status); err != nil {
+		return err
+	}
+	if status != req.Status {
+		return fmt.Errorf("leave request is now %s", status)
+	}
+
+	for _, p := range req.Portions {
+		if err := chargeLeave(tx, req, p, -p.Days); err != nil {
+			return err
+		}
+	}
+	for _, p := range portions {
+		if err := chargeLeave(tx, req, p, p.Days); err != nil {
+			return err
+		}
+	}
+	if err := replacePortions(tx, req.ID, portions); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func replacePortions(db sqlExecer, requestID uuid.UUID, portions []models.LeaveRequestPortion) error {
+	if _, err := db.Exec(`DELETE FROM leave_request_portions WHERE leave_request_id=$1`, requestID); err != nil {
+		return err
+	}
+	for _, p := range portions {
+		_, err := db.Exec(`
+			INSERT INTO leave_request_portions (id, leave_request_id, year, start_date, end_date, days)
+			VALUES ($1,$2,$3,$4,$5,$6)`,
+			uuid.New(), requestID, p.Year, p.StartDate, p.EndDate, p.Days,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListPortions returns the request's portions in leave-year order
+func (r *LeaveRequestRepository) ListPortions(requestID uuid.UUID) ([]models.LeaveRequestPortion, error) {
+	rows, err := r.db.Query(`
+		SELECT year, start_date, end_date, days
+		FROM leave_request_portions
+		WHERE leave_request_id=$1
+		ORDER BY year`, requestID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []models.LeaveRequestPortion
+	for rows.Next() {
+		var p models.LeaveRequestPortion
+		if err := rows.Scan(&p.Year, &p.StartDate, &p.EndDate, &p.Days); err != nil {
+			return nil, err
+		}
+		out = append(out, p)
+	}
+	return out, rows.Err()
 }
 
 func (r *LeaveRequestRepository) List(filter interfaces.LeaveRequestFilter, page, pageSize int) ([]models.LeaveRequest, int, error) {
@@ -65,6 +211,11 @@ func (r *LeaveRequestRepository) List(filter interfaces.LeaveRequestFilter, page
 		args = append(args, filter.Status)
 		i++
 	}
+	if filter.DocumentStatus != "" {
+		where = append(where, fmt.Sprintf("lr.document_status=$%d", i))
+		args = append(args, filter.DocumentStatus)
+		i++
+	}
 	if filter.StartDateGTE != nil {
 		where = append(where, fmt.Sprintf("lr.start_date>=$%d", i))
 		args = append(args, *filter.StartDateGTE)
@@ -89,9 +240,11 @@ func (r *LeaveRequestRepository) List(filter interfaces.LeaveRequestFilter, page
 
 	args = append(args, pageSize, (page-1)*pageSize)
 	rows, err := r.db.Query(fmt.Sprintf(`
-		SELECT lr.id, lr.employee_id, lr.leave_type_id, lr.start_date, lr.end_date, lr.total_days,
+		SELECT lr.id, lr.employee_id, lr.leave_type_id, lr.start_date, lr.end_date, lr.start_part,
+		       lr.end_part, lr.hours, lr.total_days,
 		       lr.reason, lr.status, lr.reviewed_by, lr.reviewed_at, lr.review_comment,
-		       lr.attachment_url, lr.created_at, lr.updated_at,
+		       lr.attachment_url, lr.rule_override_reason, lr.rule_overridden_by, lr.document_status,
+		       lr.document_due_date, lr.document_reminded_at, lr.created_at, lr.updated_at,
 		       lt.id, lt.name, lt.code
 		FROM leave_requests lr
 		JOIN leave_types lt ON lr.leave_type_id=lt.id
@@ -112,6 +265,328 @@ func (r *LeaveRequestRepository) List(filter interfaces.LeaveRequestFilter, page
 	return out, total, rows.Err()
 }
 
+// Amend saves the amended request in one transaction: the previous version's days are
+// released from its balances, the new days are charged to the balances of the leave years
+// they fall in, and the amendment is recorded with the replaced values.
+func (r *LeaveRequestRepository) Amend(req, previous *models.LeaveRequest, a *models.LeaveRequestAmendment) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, p := range previous.Portions {
+		if err := chargeLeave(tx, previous, p, -p.Days); err != nil {
+			return err
+		}
+	}
+	for _, p := range req.Portions {
+		if err := chargeLeave(tx, req, p, p.Days); err != nil {
+			return err
+		}
+	}
+
+	req.UpdatedAt = time.Now()
+	_, err = tx.Exec(`
+		UPDATE leave_requests SET leave_type_id=$1, start_date=$2, end_date=$3, start_part=$4, end_part=$5,
+		       hours=$6, total_days=$7, reason=$8, status=$9, reviewed_by=$10, reviewed_at=$11,
+		       review_comment=$12, document_status=$13, document_due_date=$14, rule_override_reason=$15,
+		       rule_overridden_by=$16, updated_at=$17
+		WHERE id=$18`,
+		req.LeaveTypeID, req.StartDate, req.EndDate, req.StartPart, req.EndPart, req.Hours, req.TotalDays,
+		req.Reason, req.Status, req.ReviewedBy, req.ReviewedAt, req.ReviewComment, req.DocumentStatus,
+		req.DocumentDueDate, req.RuleOverrideReason, req.RuleOverriddenBy, req.UpdatedAt, req.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := replacePortions(tx, req.ID, req.Portions); err != nil {
+		return err
+	}
+
+	a.ID = uuid.New()
+	a.LeaveRequestID = req.ID
+	a.CreatedAt = req.UpdatedAt
+	_, err = tx.Exec(`
+		INSERT INTO leave_request_amendments
+		(id, leave_request_id, previous_leave_type_id, previous_start_date, previous_end_date,
+		 previous_start_part, previous_end_part, previous_hours, previous_total_days, previous_reason,
+		 previous_status, previous_reviewed_by, previous_reviewed_at, auto_approved, amended_by, created_at)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16)`,
+		a.ID, a.LeaveRequestID, a.PreviousLeaveTypeID, a.PreviousStartDate, a.PreviousEndDate,
+		a.PreviousStartPart, a.PreviousEndPart, a.PreviousHours, a.PreviousTotalDays, a.PreviousReason,
+		a.PreviousStatus, a.PreviousReviewedBy, a.PreviousReviewedAt, a.AutoApproved, a.AmendedBy, a.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// chargeLeave adds days (negative to release them) to a leave year's balance: to used for
+// approved requests and to pending otherwise.
+func chargeLeave(db sqlExecer, req *models.LeaveRequest, p models.LeaveRequestPortion, days float64) error {
+	column := "pending"
+	if req.Status == models.LeaveStatusApproved {
+		column = "used"
+	}
+	_, err := db.Exec(fmt.Sprintf(`
+		UPDATE leave_balances SET %[1]s=GREATEST(0, %[1]s+$1), updated_at=NOW()
+		WHERE employee_id=$2 AND leave_type_id=$3 AND year=$4`, column),
+		days, req.EmployeeID, req.LeaveTypeID, p.Year)
+	return err
+}
+
+// ListAmendments returns the request's amendments, oldest first
+func (r *LeaveRequestRepository) ListAmendments(requestID uuid.UUID) ([]models.LeaveRequestAmendment, error) {
+	rows, err := r.db.Query(`
+		SELECT id, leave_request_id, previous_leave_type_id, previous_start_date, previous_end_date,
+		       previous_start_part, previous_end_part, previous_hours, previous_total_days, previous_reason,
+		       previous_status, previous_reviewed_by, previous_reviewed_at, auto_approved, amended_by, created_at
+		FROM leave_request_amendments
+		WHERE leave_request_id=$1
+		ORDER BY created_at`, requestID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []models.LeaveRequestAmendment
+	for rows.Next() {
+		var a models.LeaveRequestAmendment
+		var reviewedBy sql.NullString
+		var reviewedAt sql.NullTime
+		err := rows.Scan(
+			&a.ID, &a.LeaveRequestID, &a.PreviousLeaveTypeID, &a.PreviousStartDate, &a.PreviousEndDate,
+			&a.PreviousStartPart, &a.PreviousEndPart, &a.PreviousHours, &a.PreviousTotalDays, &a.PreviousReason,
+			&a.PreviousStatus, &reviewedBy, &reviewedAt, &a.AutoApproved, &a.AmendedBy, &a.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if reviewedBy.Valid {
+			id, _ := uuid.Parse(reviewedBy.String)
+			a.PreviousReviewedBy = &id
+		}
+		if reviewedAt.Valid {
+			a.PreviousReviewedAt = &reviewedAt.Time
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
+// AddAttachment records a file uploaded to a request. A document the request was waiting
+// for, or that HR is chasing, counts as received.
+func (r *LeaveRequestRepository) AddAttachment(a *models.LeaveRequestAttachment) error {
+	a.ID = uuid.New()
+	a.CreatedAt = time.Now()
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		INSERT INTO leave_request_attachments
+		(id, leave_request_id, file_name, content_type, file_size, storage_path, uploaded_by, created_at)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8)`,
+		a.ID, a.LeaveRequestID, a.FileName, a.ContentType, a.FileSize, a.StoragePath, a.UploadedBy, a.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`
+		UPDATE leave_requests SET document_status=$1, updated_at=$2
+		WHERE id=$3 AND document_status IN ($4,$5)`,
+		models.LeaveDocumentReceived, a.CreatedAt, a.LeaveRequestID,
+		models.LeaveDocumentAwaited, models.LeaveDocumentEscalated,
+	)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *LeaveRequestRepository) GetAttachment(id uuid.UUID) (*models.LeaveRequestAttachment, error) {
+	return r.scanAttachment(r.db.QueryRow(`
+		SELECT id, leave_request_id, file_name, content_type, file_size, storage_path, uploaded_by, created_at
+		FROM leave_request_attachments WHERE id=$1`, id))
+}
+
+// ListAttachments returns the files uploaded to a request, oldest first
+func (r *LeaveRequestRepository) ListAttachments(requestID uuid.UUID) ([]models.LeaveRequestAttachment, error) {
+	rows, err := r.db.Query(`
+		SELECT id, leave_request_id, file_name, content_type, file_size, storage_path, uploaded_by, created_at
+		FROM leave_request_attachments
+		WHERE leave_request_id=$1
+		ORDER BY created_at`, requestID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []models.LeaveRequestAttachment
+	for rows.Next() {
+		a, err := r.scanAttachment(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *a)
+	}
+	return out, rows.Err()
+}
+
+func (r *LeaveRequestRepository) scanAttachment(row rowScanner) (*models.LeaveRequestAttachment, error) {
+	var a models.LeaveRequestAttachment
+	var uploadedBy sql.NullString
+	err := row.Scan(&a.ID, &a.LeaveRequestID, &a.FileName, &a.ContentType, &a.FileSize, &a.StoragePath,
+		&uploadedBy, &a.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if uploadedBy.Valid {
+		id, _ := uuid.Parse(uploadedBy.String)
+		a.UploadedBy = &id
+	}
+	return &a, nil
+}
+
+// ListAwaitingDocument returns the pending and approved requests still waiting for a
+// required document, soonest due first
+func (r *LeaveRequestRepository) ListAwaitingDocument() ([]models.LeaveRequest, error) {
+	rows, err := r.db.Query(`
+		SELECT lr.id, lr.employee_id, lr.leave_type_id, lr.start_date, lr.end_date, lr.start_part,
+		       lr.end_part, lr.hours, lr.total_days,
+		       lr.reason, lr.status, lr.reviewed_by, lr.reviewed_at, lr.review_comment,
+		       lr.attachment_url, lr.rule_override_reason, lr.rule_overridden_by, lr.document_status,
+		       lr.document_due_date, lr.document_reminded_at, lr.created_at, lr.updated_at,
+		       lt.id, lt.name, lt.code
+		FROM leave_requests lr
+		JOIN leave_types lt ON lr.leave_type_id=lt.id
+		WHERE lr.status IN ('pending','approved') AND lr.document_status=$1
+		ORDER BY lr.document_due_date`, models.LeaveDocumentAwaited)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []models.LeaveRequest
+	for rows.Next() {
+		req, err := r.scanOne(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *req)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	for i := range out {
+		if out[i].Portions, err = r.ListPortions(out[i].ID); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// ListChargedThrough returns the employee's pending and approved requests of a leave type
+// with days charged to the given leave year on or before a date, earliest first. Only
+// their portions for that year are loaded.
+func (r *LeaveRequestRepository) ListChargedThrough(employeeID, leaveTypeID uuid.UUID, year int, through time.Time) ([]models.LeaveRequest, error) {
+	rows, err := r.db.Query(`
+		SELECT lr.id, lr.employee_id, lr.leave_type_id, lr.start_date, lr.end_date, lr.start_part,
+		       lr.end_part, lr.hours, lr.total_days,
+		       lr.reason, lr.status, lr.reviewed_by, lr.reviewed_at, lr.review_comment,
+		       lr.attachment_url, lr.rule_override_reason, lr.rule_overridden_by, lr.document_status,
+		       lr.document_due_date, lr.document_reminded_at, lr.created_at, lr.updated_at,
+		       lt.id, lt.name, lt.code
+		FROM leave_requests lr
+		JOIN leave_types lt ON lr.leave_type_id=lt.id
+		WHERE lr.employee_id=$1 AND lr.leave_type_id=$2 AND lr.status IN ('pending','approved')
+		  AND EXISTS (
+		      SELECT 1 FROM leave_request_portions p
+		      WHERE p.leave_request_id=lr.id AND p.year=$3 AND p.start_date <= $4)
+		ORDER BY lr.start_date`, employeeID, leaveTypeID, year, through)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []models.LeaveRequest
+	for rows.Next() {
+		req, err := r.scanOne(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *req)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	for i := range out {
+		portions, err := r.ListPortions(out[i].ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range portions {
+			if p.Year == year {
+				out[i].Portions = append(out[i].Portions, p)
+			}
+		}
+	}
+	return out, nil
+}
+
+// SetDocumentStatus moves a request's document to a new status; received, escalated and
+// converted documents are no longer due
+func (r *LeaveRequestRepository) SetDocumentStatus(id uuid.UUID, status models.LeaveDocumentStatus) error {
+	_, err := r.db.Exec(`
+		UPDATE leave_requests SET document_status=$1, updated_at=NOW()
+		WHERE id=$2`,
+		status, id)
+	return err
+}
+
+// MarkDocumentReminded records that the employee was reminded to hand in the document
+func (r *LeaveRequestRepository) MarkDocumentReminded(id uuid.UUID) error {
+	_, err := r.db.Exec(`UPDATE leave_requests SET document_reminded_at=NOW() WHERE id=$1`, id)
+	return err
+}
+
+// ConvertLeaveType moves a request to another leave type in one transaction, releasing its
+// days from the old type's balances and charging them to the new type's, and sets its
+// document status
+func (r *LeaveRequestRepository) ConvertLeaveType(req *models.LeaveRequest, leaveTypeID uuid.UUID, status models.LeaveDocumentStatus) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	converted := *req
+	converted.LeaveTypeID = leaveTypeID
+	for _, p := range req.Portions {
+		if err := chargeLeave(tx, req, p, -p.Days); err != nil {
+			return err
+		}
+		if err := chargeLeave(tx, &converted, p, p.Days); err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec(`
+		UPDATE leave_requests SET leave_type_id=$1, document_status=$2, updated_at=NOW()
+		WHERE id=$3`,
+		leaveTypeID, status, req.ID)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
 func (r *LeaveRequestRepository) UpdateStatus(id uuid.UUID, status models.LeaveRequestStatus, reviewedBy *uuid.UUID, comment string) error {
 	now := time.Now()
 	_, err := r.db.Exec(`
@@ -121,31 +596,145 @@ func (r *LeaveRequestRepository) UpdateStatus(id uuid.UUID, status models.LeaveR
 	return err
 }
 
-func (r *LeaveRequestRepository) HasOverlap(employeeID uuid.UUID, start, end time.Time, excludeID *uuid.UUID) (bool, error) {
-	q := `SELECT COUNT(1) FROM leave_requests
-		  WHERE employee_id=$1 AND status NOT IN ('rejected','cancelled')
-		  AND start_date <= $2 AND end_date >= $3`
-	args := []interface{}{employeeID, end, start}
+// teamCTE selects everyone reporting to the manager in $1, directly or through other managers
+const teamCTE = `
+	WITH RECURSIVE team AS (
+		SELECT id FROM employees WHERE manager_id=$1 AND deleted_at IS NULL
+		UNION
+		SELECT e.id FROM employees e JOIN team t ON e.manager_id=t.id WHERE e.deleted_at IS NULL
+	)`
+
+// calendarEntryQuery selects leave requests as calendar entries, with the employee's name
+const calendarEntryQuery = `
+	SELECT lr.id, lr.employee_id, e.first_name || ' ' || e.last_name, e.department_id, lt.name, lt.code,
+	       lr.start_date, lr.end_date, lr.start_part, lr.end_part, lr.hours, lr.total_days, lr.status,
+	       lr.updated_at
+	FROM leave_requests lr
+	JOIN employees e ON lr.employee_id=e.id
+	JOIN leave_types lt ON lr.leave_type_id=lt.id`
+
+// ListCalendar returns the pending and approved leave overlapping [from, to] of a
+// department's employees or, when managerID is set, of the manager's whole team
+func (r *LeaveRequestRepository) ListCalendar(departmentID, managerID *uuid.UUID, from, to time.Time) ([]models.LeaveCalendarEntry, error) {
+	q := calendarEntryQuery + `
+		WHERE lr.status IN ('pending','approved') AND lr.start_date <= $2 AND lr.end_date >= $3`
+	var scope uuid.UUID
+	switch {
+	case managerID != nil:
+		q = teamCTE + q + " AND lr.employee_id IN (SELECT id FROM team)"
+		scope = *managerID
+	case departmentID != nil:
+		q += " AND e.department_id=$1"
+		scope = *departmentID
+	default:
+		return nil, errors.New("a department or manager is required")
+	}
+	q += " ORDER BY lr.start_date, e.last_name, e.first_name"
+	return r.queryCalendar(q, scope, to, from)
+}
+
+// ListFeed returns the leave ending on or after from that calendar feeds show: the
+// employee's own or, when team is set, that of the employee's team. Cancelled and rejected
+// requests are included so calendars drop leave that was once approved.
+func (r *LeaveRequestRepository) ListFeed(employeeID uuid.UUID, team bool, from time.Time) ([]models.LeaveCalendarEntry, error) {
+	q := calendarEntryQuery + `
+		WHERE lr.status IN ('approved','cancelled','rejected') AND lr.end_date >= $2`
+	if team {
+		q = teamCTE + q + " AND lr.employee_id IN (SELECT id FROM team)"
+	} else {
+		q += " AND lr.employee_id=$1"
+	}
+	q += " ORDER BY lr.start_date"
+	return r.queryCalendar(q, employeeID, from)
+}
+
+func (r *LeaveRequestRepository) queryCalendar(q string, args ...interface{}) ([]models.LeaveCalendarEntry, error) {
+	rows, err := r.db.Query(q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []models.LeaveCalendarEntry{}
+	for rows.Next() {
+		var e models.LeaveCalendarEntry
+		err := rows.Scan(&e.LeaveRequestID, &e.EmployeeID, &e.EmployeeName, &e.DepartmentID, &e.LeaveTypeName,
+			&e.LeaveTypeCode, &e.StartDate, &e.EndDate, &e.StartPart, &e.EndPart, &e.Hours, &e.TotalDays, &e.Status,
+			&e.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+// CountStartingBetween counts the employee's pending and approved requests of a leave type
+// that start within [from, to]
+func (r *LeaveRequestRepository) CountStartingBetween(employeeID, leaveTypeID uuid.UUID, from, to time.Time, excludeID *uuid.UUID) (int, error) {
+	q := `
+		SELECT COUNT(*) FROM leave_requests
+		WHERE employee_id=$1 AND leave_type_id=$2 AND status IN ('pending','approved')
+		  AND start_date >= $3 AND start_date <= $4`
+	args := []interface{}{employeeID, leaveTypeID, from, to}
 	if excludeID != nil {
-		q += " AND id!=$4"
+		q += " AND id!=$5"
 		args = append(args, *excludeID)
 	}
 	var count int
 	err := r.db.QueryRow(q, args...).Scan(&count)
-	return count > 0, err
+	return count, err
+}
+
+// ListOverlapping returns the employee's live requests whose dates overlap start to end.
+// Requests on the same day may still fit together when they take different halves of it.
+func (r *LeaveRequestRepository) ListOverlapping(employeeID uuid.UUID, start, end time.Time, excludeID *uuid.UUID) ([]models.LeaveRequest, error) {
+	q := `
+		SELECT lr.id, lr.employee_id, lr.leave_type_id, lr.start_date, lr.end_date, lr.start_part,
+		       lr.end_part, lr.hours, lr.total_days,
+		       lr.reason, lr.status, lr.reviewed_by, lr.reviewed_at, lr.review_comment,
+		       lr.attachment_url, lr.rule_override_reason, lr.rule_overridden_by, lr.document_status,
+		       lr.document_due_date, lr.document_reminded_at, lr.created_at, lr.updated_at,
+		       lt.id, lt.name, lt.code
+		FROM leave_requests lr
+		JOIN leave_types lt ON lr.leave_type_id=lt.id
+		WHERE lr.employee_id=$1 AND lr.status NOT IN ('rejected','cancelled')
+		  AND lr.start_date <= $2 AND lr.end_date >= $3`
+	args := []interface{}{employeeID, end, start}
+	if excludeID != nil {
+		q += " AND lr.id!=$4"
+		args = append(args, *excludeID)
+	}
+	rows, err := r.db.Query(q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []models.LeaveRequest
+	for rows.Next() {
+		req, err := r.scanOne(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *req)
+	}
+	return out, rows.Err()
 }
 
 func (r *LeaveRequestRepository) scanOne(row rowScanner) (*models.LeaveRequest, error) {
 	var req models.LeaveRequest
-	var reviewedBy sql.NullString
-	var reviewedAt sql.NullTime
+	var reviewedBy, overriddenBy sql.NullString
+	var reviewedAt, documentDue, remindedAt sql.NullTime
 	var ltID uuid.UUID
 	var ltName, ltCode string
 
 	err := row.Scan(
-		&req.ID, &req.EmployeeID, &req.LeaveTypeID, &req.StartDate, &req.EndDate, &req.TotalDays,
+		&req.ID, &req.EmployeeID, &req.LeaveTypeID, &req.StartDate, &req.EndDate, &req.StartPart,
+		&req.EndPart, &req.Hours, &req.TotalDays,
 		&req.Reason, &req.Status, &reviewedBy, &reviewedAt, &req.ReviewComment,
-		&req.AttachmentURL, &req.CreatedAt, &req.UpdatedAt,
+		&req.AttachmentURL, &req.RuleOverrideReason, &overriddenBy, &req.DocumentStatus,
+		&documentDue, &remindedAt, &req.CreatedAt, &req.UpdatedAt,
 		&ltID, &ltName, &ltCode,
 	)
 	if err != nil {
@@ -158,6 +747,16 @@ func (r *LeaveRequestRepository) scanOne(row rowScanner) (*models.LeaveRequest,
 	if reviewedAt.Valid {
 		req.ReviewedAt = &reviewedAt.Time
 	}
+	if overriddenBy.Valid {
+		id, _ := uuid.Parse(overriddenBy.String)
+		req.RuleOverriddenBy = &id
+	}
+	if documentDue.Valid {
+		req.DocumentDueDate = &documentDue.Time
+	}
+	if remindedAt.Valid {
+		req.DocumentRemindedAt = &remindedAt.Time
+	}
 	req.LeaveType = &models.LeaveType{ID: ltID, Name: ltName, Code: ltCode}
 	return &req, nil
 }
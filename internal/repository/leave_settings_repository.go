@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"hr-system/internal/database"
+)
+
+type LeaveSettingsRepository struct {
+	db *sql.DB
+}
+
+func NewLeaveSettingsRepository() *LeaveSettingsRepository {
+	return &LeaveSettingsRepository{db: database.DB}
+}
+
+// GetYearStartMonth returns the leave year start the stored balances and requests were
+// split under
+func (r *LeaveSettingsRepository) GetYearStartMonth() (time.Month, error) {
+	var month int
+	err := r.db.QueryRow(`SELECT year_start_month FROM leave_settings WHERE id`).Scan(&month)
+	return time.Month(month), err
+}
+
+// SetYearStartMonth records the leave year start the data has been moved onto
+func (r *LeaveSettingsRepository) SetYearStartMonth(month time.Month) error {
+	_, err := r.db.Exec(`
+		INSERT INTO leave_settings (id, year_start_month, updated_at) VALUES (TRUE, $1, NOW())
+		ON CONFLICT (id) DO UPDATE SET year_start_month=EXCLUDED.year_start_month, updated_at=EXCLUDED.updated_at`,
+		int(month))
+	return err
+}
@@ -26,10 +26,16 @@ func (r *LeaveTypeRepository) Create(lt *models.LeaveType) error {
 	_, err := r.db.Exec(`
 		INSERT INTO leave_types
 		(id, name, code, description, default_days_per_year, is_paid, is_carry_forward_allowed,
-		 max_carry_forward_days, requires_approval, requires_document, is_active, created_at, updated_at)
-		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13)`,
+		 max_carry_forward_days, requires_approval, requires_document, allow_hourly, hours_per_day,
+		 min_notice_days, max_consecutive_days, max_requests_per_year, allow_backdating, blackout_exempt,
+		 document_grace_days, missing_document_action, allow_encashment, max_encashment_days,
+		 encashment_min_balance, carry_forward_expiry_months, is_active, created_at, updated_at)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19,$20,$21,$22,$23,$24,$25,$26)`,
 		lt.ID, lt.Name, lt.Code, lt.Description, lt.DefaultDaysPerYear, lt.IsPaid,
 		lt.IsCarryForwardAllowed, lt.MaxCarryForwardDays, lt.RequiresApproval, lt.RequiresDocument,
+		lt.AllowHourly, lt.HoursPerDay, lt.MinNoticeDays, lt.MaxConsecutiveDays, lt.MaxRequestsPerYear,
+		lt.AllowBackdating, lt.BlackoutExempt, lt.DocumentGraceDays, lt.MissingDocumentAction,
+		lt.AllowEncashment, lt.MaxEncashmentDays, lt.EncashmentMinBalance, lt.CarryForwardExpiryMonths,
 		lt.IsActive, lt.CreatedAt, lt.UpdatedAt,
 	)
 	return err
@@ -38,20 +44,29 @@ func (r *LeaveTypeRepository) Create(lt *models.LeaveType) error {
 func (r *LeaveTypeRepository) GetByID(id uuid.UUID) (*models.LeaveType, error) {
 	return r.scan(r.db.QueryRow(`
 		SELECT id, name, code, description, default_days_per_year, is_paid, is_carry_forward_allowed,
-		       max_carry_forward_days, requires_approval, requires_document, is_active, created_at, updated_at
+		       max_carry_forward_days, requires_approval, requires_document, allow_hourly, hours_per_day,
+		       min_notice_days, max_consecutive_days, max_requests_per_year, allow_backdating, blackout_exempt,
+		       document_grace_days, missing_document_action, allow_encashment, max_encashment_days,
+		       encashment_min_balance, carry_forward_expiry_months, is_active, created_at, updated_at
 		FROM leave_types WHERE id=$1`, id))
 }
 
 func (r *LeaveTypeRepository) GetByCode(code string) (*models.LeaveType, error) {
 	return r.scan(r.db.QueryRow(`
 		SELECT id, name, code, description, default_days_per_year, is_paid, is_carry_forward_allowed,
-		       max_carry_forward_days, requires_approval, requires_document, is_active, created_at, updated_at
+		       max_carry_forward_days, requires_approval, requires_document, allow_hourly, hours_per_day,
+		       min_notice_days, max_consecutive_days, max_requests_per_year, allow_backdating, blackout_exempt,
+		       document_grace_days, missing_document_action, allow_encashment, max_encashment_days,
+		       encashment_min_balance, carry_forward_expiry_months, is_active, created_at, updated_at
 		FROM leave_types WHERE code=$1`, code))
 }
 
 func (r *LeaveTypeRepository) List(activeOnly bool) ([]models.LeaveType, error) {
 	q := `SELECT id, name, code, description, default_days_per_year, is_paid, is_carry_forward_allowed,
-		         max_carry_forward_days, requires_approval, requires_document, is_active, created_at, updated_at
+		         max_carry_forward_days, requires_approval, requires_document, allow_hourly, hours_per_day,
+		         min_notice_days, max_consecutive_days, max_requests_per_year, allow_backdating, blackout_exempt,
+		         document_grace_days, missing_document_action, allow_encashment, max_encashment_days,
+		         encashment_min_balance, carry_forward_expiry_months, is_active, created_at, updated_at
 		  FROM leave_types`
 	if activeOnly {
 		q += " WHERE is_active=TRUE"
@@ -78,10 +93,18 @@ func (r *LeaveTypeRepository) Update(lt *models.LeaveType) error {
 	_, err := r.db.Exec(`
 		UPDATE leave_types SET name=$1, code=$2, description=$3, default_days_per_year=$4, is_paid=$5,
 		is_carry_forward_allowed=$6, max_carry_forward_days=$7, requires_approval=$8,
-		requires_document=$9, is_active=$10, updated_at=$11 WHERE id=$12`,
+		requires_document=$9, allow_hourly=$10, hours_per_day=$11, min_notice_days=$12,
+		max_consecutive_days=$13, max_requests_per_year=$14, allow_backdating=$15, blackout_exempt=$16,
+		document_grace_days=$17, missing_document_action=$18, allow_encashment=$19,
+		max_encashment_days=$20, encashment_min_balance=$21, carry_forward_expiry_months=$22,
+		is_active=$23, updated_at=$24
+		WHERE id=$25`,
 		lt.Name, lt.Code, lt.Description, lt.DefaultDaysPerYear, lt.IsPaid,
 		lt.IsCarryForwardAllowed, lt.MaxCarryForwardDays, lt.RequiresApproval,
-		lt.RequiresDocument, lt.IsActive, lt.UpdatedAt, lt.ID,
+		lt.RequiresDocument, lt.AllowHourly, lt.HoursPerDay, lt.MinNoticeDays, lt.MaxConsecutiveDays,
+		lt.MaxRequestsPerYear, lt.AllowBackdating, lt.BlackoutExempt, lt.DocumentGraceDays,
+		lt.MissingDocumentAction, lt.AllowEncashment, lt.MaxEncashmentDays, lt.EncashmentMinBalance,
+		lt.CarryForwardExpiryMonths, lt.IsActive, lt.UpdatedAt, lt.ID,
 	)
 	return err
 }
@@ -100,6 +123,9 @@ func (r *LeaveTypeRepository) scan(row rowScanner) (*models.LeaveType, error) {
 	var lt models.LeaveType
 	err := row.Scan(&lt.ID, &lt.Name, &lt.Code, &lt.Description, &lt.DefaultDaysPerYear, &lt.IsPaid,
 		&lt.IsCarryForwardAllowed, &lt.MaxCarryForwardDays, &lt.RequiresApproval, &lt.RequiresDocument,
+		&lt.AllowHourly, &lt.HoursPerDay, &lt.MinNoticeDays, &lt.MaxConsecutiveDays, &lt.MaxRequestsPerYear,
+		&lt.AllowBackdating, &lt.BlackoutExempt, &lt.DocumentGraceDays, &lt.MissingDocumentAction,
+		&lt.AllowEncashment, &lt.MaxEncashmentDays, &lt.EncashmentMinBalance, &lt.CarryForwardExpiryMonths,
 		&lt.IsActive, &lt.CreatedAt, &lt.UpdatedAt)
 	if err != nil {
 		return nil, err
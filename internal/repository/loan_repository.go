@@ -0,0 +1,239 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"hr-system/internal/database"
+	"hr-system/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type LoanRepository struct {
+	db *sql.DB
+}
+
+func NewLoanRepository() *LoanRepository {
+	return &LoanRepository{db: database.DB}
+}
+
+// Repayments taken on a voided payslip no longer count towards a loan.
+const loanSelect = `
+	SELECT l.id, l.employee_id, l.loan_type, l.principal, l.interest_rate, l.interest_amount,
+	       l.total_repayable, l.instalments, l.instalment_amount, l.start_month, l.start_year,
+	       l.reason, l.status, l.requested_by, l.reviewed_by, l.reviewed_at, l.created_at, l.updated_at,
+	       COALESCE(rp.repaid, 0) AS amount_repaid,
+	       CONCAT(e.first_name, ' ', e.last_name) AS employee_name
+	FROM employee_loans l
+	JOIN employees e ON l.employee_id = e.id
+	LEFT JOIN LATERAL (
+		SELECT SUM(r.amount) AS repaid
+		FROM loan_repayments r
+		LEFT JOIN payslips p ON r.payslip_id = p.id
+		WHERE r.loan_id = l.id AND (p.id IS NULL OR p.status <> 'void')
+	) rp ON true`
+
+// sqlExecer is satisfied by both *sql.DB and *sql.Tx.
+type sqlExecer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+func (r *LoanRepository) Create(l *models.EmployeeLoan) error {
+	l.ID = uuid.New()
+	now := time.Now()
+	l.CreatedAt = now
+	l.UpdatedAt = now
+	_, err := r.db.Exec(`
+		INSERT INTO employee_loans (id, employee_id, loan_type, principal, interest_rate, interest_amount, total_repayable, instalments, instalment_amount, start_month, start_year, reason, status, requested_by, created_at, updated_at)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16)`,
+		l.ID, l.EmployeeID, l.LoanType, l.Principal, l.InterestRate, l.InterestAmount, l.TotalRepayable,
+		l.Instalments, l.InstalmentAmount, l.StartMonth, l.StartYear, l.Reason, l.Status, l.RequestedBy,
+		l.CreatedAt, l.UpdatedAt,
+	)
+	return err
+}
+
+// UpdateTerms changes the amount and repayment schedule of a loan that is still pending.
+func (r *LoanRepository) UpdateTerms(l *models.EmployeeLoan) error {
+	l.UpdatedAt = time.Now()
+	res, err := r.db.Exec(`
+		UPDATE employee_loans
+		SET principal=$2, interest_rate=$3, interest_amount=$4, total_repayable=$5, instalments=$6,
+		    instalment_amount=$7, start_month=$8, start_year=$9, updated_at=$10
+		WHERE id=$1 AND status='pending'`,
+		l.ID, l.Principal, l.InterestRate, l.InterestAmount, l.TotalRepayable, l.Instalments,
+		l.InstalmentAmount, l.StartMonth, l.StartYear, l.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("loan is no longer pending")
+	}
+	return nil
+}
+
+// UpdateStatus moves a pending loan to active, rejected or cancelled.
+func (r *LoanRepository) UpdateStatus(id uuid.UUID, status models.LoanStatus, reviewedBy *uuid.UUID) error {
+	res, err := r.db.Exec(`
+		UPDATE employee_loans
+		SET status=$2, reviewed_by=$3, reviewed_at=NOW(), updated_at=NOW()
+		WHERE id=$1 AND status='pending'`, id, status, reviewedBy)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("loan is no longer pending")
+	}
+	return nil
+}
+
+func (r *LoanRepository) GetByID(id uuid.UUID) (*models.EmployeeLoan, error) {
+	return r.scanRow(r.db.QueryRow(loanSelect+` WHERE l.id=$1`, id))
+}
+
+// ListByEmployee returns an employee's loans, most recent first.
+func (r *LoanRepository) ListByEmployee(employeeID uuid.UUID) ([]models.EmployeeLoan, error) {
+	rows, err := r.db.Query(loanSelect+` WHERE l.employee_id=$1 ORDER BY l.created_at DESC`, employeeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return r.scanRows(rows)
+}
+
+// List returns loans in the given status, or all loans when status is empty. Active and
+// settled are told apart by the outstanding balance.
+func (r *LoanRepository) List(status models.LoanStatus) ([]models.EmployeeLoan, error) {
+	where := ""
+	args := []interface{}{}
+	switch status {
+	case "":
+	case models.LoanStatusActive:
+		where = ` WHERE l.status='active' AND l.total_repayable > COALESCE(rp.repaid, 0)`
+	case models.LoanStatusSettled:
+		where = ` WHERE l.status='active' AND l.total_repayable <= COALESCE(rp.repaid, 0)`
+	default:
+		where = ` WHERE l.status=$1`
+		args = append(args, status)
+	}
+	rows, err := r.db.Query(loanSelect+where+` ORDER BY e.last_name, e.first_name, l.created_at`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return r.scanRows(rows)
+}
+
+// ListOutstanding returns an employee's active loans with a balance left to repay, oldest
+// schedule first, for payroll deductions.
+func (r *LoanRepository) ListOutstanding(employeeID uuid.UUID) ([]models.EmployeeLoan, error) {
+	rows, err := r.db.Query(loanSelect+`
+		WHERE l.employee_id=$1 AND l.status='active' AND l.total_repayable > COALESCE(rp.repaid, 0)
+		ORDER BY l.start_year, l.start_month, l.created_at`, employeeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return r.scanRows(rows)
+}
+
+// LoansDeductedInMonth returns the IDs of an employee's loans that already have a payroll
+// deduction for the given month on a payslip that has not been voided.
+func (r *LoanRepository) LoansDeductedInMonth(employeeID uuid.UUID, month, year int) (map[uuid.UUID]bool, error) {
+	rows, err := r.db.Query(`
+		SELECT DISTINCT r.loan_id
+		FROM loan_repayments r
+		JOIN employee_loans l ON r.loan_id = l.id
+		JOIN payslips p ON r.payslip_id = p.id
+		WHERE l.employee_id=$1 AND r.month=$2 AND r.year=$3
+		  AND r.repayment_type='payroll' AND p.status <> 'void'`, employeeID, month, year)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deducted := map[uuid.UUID]bool{}
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		deducted[id] = true
+	}
+	return deducted, rows.Err()
+}
+
+// CreateRepayment records an amount paid against a loan outside payroll.
+func (r *LoanRepository) CreateRepayment(rp *models.LoanRepayment) error {
+	return insertLoanRepayment(r.db, rp)
+}
+
+func insertLoanRepayment(db sqlExecer, rp *models.LoanRepayment) error {
+	rp.ID = uuid.New()
+	rp.CreatedAt = time.Now()
+	_, err := db.Exec(`
+		INSERT INTO loan_repayments (id, loan_id, payslip_id, repayment_type, amount, month, year, notes, recorded_by, created_at)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10)`,
+		rp.ID, rp.LoanID, rp.PayslipID, rp.RepaymentType, rp.Amount, rp.Month, rp.Year, rp.Notes,
+		rp.RecordedBy, rp.CreatedAt,
+	)
+	return err
+}
+
+// ListRepayments returns every repayment against a loan in the order they were taken,
+// flagging those whose payslip has since been voided.
+func (r *LoanRepository) ListRepayments(loanID uuid.UUID) ([]models.LoanRepayment, error) {
+	rows, err := r.db.Query(`
+		SELECT r.id, r.loan_id, r.payslip_id, r.repayment_type, r.amount, r.month, r.year, r.notes,
+		       r.recorded_by, r.created_at, COALESCE(p.status = 'void', false) AS voided
+		FROM loan_repayments r
+		LEFT JOIN payslips p ON r.payslip_id = p.id
+		WHERE r.loan_id=$1
+		ORDER BY r.year, r.month, r.created_at`, loanID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var repayments []models.LoanRepayment
+	for rows.Next() {
+		var rp models.LoanRepayment
+		if err := rows.Scan(&rp.ID, &rp.LoanID, &rp.PayslipID, &rp.RepaymentType, &rp.Amount, &rp.Month, &rp.Year,
+			&rp.Notes, &rp.RecordedBy, &rp.CreatedAt, &rp.Voided); err != nil {
+			return nil, err
+		}
+		repayments = append(repayments, rp)
+	}
+	return repayments, rows.Err()
+}
+
+func (r *LoanRepository) scanRows(rows *sql.Rows) ([]models.EmployeeLoan, error) {
+	var loans []models.EmployeeLoan
+	for rows.Next() {
+		l, err := r.scanRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		loans = append(loans, *l)
+	}
+	return loans, rows.Err()
+}
+
+func (r *LoanRepository) scanRow(row rowScanner) (*models.EmployeeLoan, error) {
+	var l models.EmployeeLoan
+	err := row.Scan(&l.ID, &l.EmployeeID, &l.LoanType, &l.Principal, &l.InterestRate, &l.InterestAmount,
+		&l.TotalRepayable, &l.Instalments, &l.InstalmentAmount, &l.StartMonth, &l.StartYear,
+		&l.Reason, &l.Status, &l.RequestedBy, &l.ReviewedBy, &l.ReviewedAt, &l.CreatedAt, &l.UpdatedAt,
+		&l.AmountRepaid, &l.EmployeeName)
+	if err != nil {
+		return nil, err
+	}
+	l.OutstandingBalance = (l.TotalRepayable - l.AmountRepaid).Max(0)
+	if l.Status == models.LoanStatusActive && l.OutstandingBalance == 0 {
+		l.Status = models.LoanStatusSettled
+	}
+	return &l, nil
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"hr-system/internal/database"
+	"hr-system/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type OvertimeRuleRepository struct {
+	db *sql.DB
+}
+
+func NewOvertimeRuleRepository() *OvertimeRuleRepository {
+	return &OvertimeRuleRepository{db: database.DB}
+}
+
+// Get retrieves the global overtime rules
+func (r *OvertimeRuleRepository) Get() (*models.OvertimeRule, error) {
+	var rule models.OvertimeRule
+	err := r.db.QueryRow(`
+		SELECT id, weekday_multiplier, weekend_multiplier, holiday_multiplier,
+		       standard_monthly_hours, max_monthly_hours, created_at, updated_at
+		FROM overtime_rules
+		LIMIT 1`,
+	).Scan(&rule.ID, &rule.WeekdayMultiplier, &rule.WeekendMultiplier, &rule.HolidayMultiplier,
+		&rule.StandardMonthlyHours, &rule.MaxMonthlyHours, &rule.CreatedAt, &rule.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("no overtime rules found")
+		}
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// Upsert creates or updates the global overtime rules
+func (r *OvertimeRuleRepository) Upsert(rule *models.OvertimeRule) error {
+	now := time.Now()
+	rule.UpdatedAt = now
+
+	existing, err := r.Get()
+	if err != nil {
+		rule.ID = uuid.New()
+		rule.CreatedAt = now
+		_, err := r.db.Exec(`
+			INSERT INTO overtime_rules (id, weekday_multiplier, weekend_multiplier, holiday_multiplier,
+			standard_monthly_hours, max_monthly_hours, created_at, updated_at)
+			VALUES ($1,$2,$3,$4,$5,$6,$7,$8)`,
+			rule.ID, rule.WeekdayMultiplier, rule.WeekendMultiplier, rule.HolidayMultiplier,
+			rule.StandardMonthlyHours, rule.MaxMonthlyHours, rule.CreatedAt, rule.UpdatedAt,
+		)
+		return err
+	}
+
+	rule.ID = existing.ID
+	rule.CreatedAt = existing.CreatedAt
+	_, err = r.db.Exec(`
+		UPDATE overtime_rules SET weekday_multiplier=$1, weekend_multiplier=$2, holiday_multiplier=$3,
+		standard_monthly_hours=$4, max_monthly_hours=$5, updated_at=$6
+		WHERE id=$7`,
+		rule.WeekdayMultiplier, rule.WeekendMultiplier, rule.HolidayMultiplier,
+		rule.StandardMonthlyHours, rule.MaxMonthlyHours, rule.UpdatedAt, rule.ID,
+	)
+	return err
+}
@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"hr-system/internal/database"
+	"hr-system/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type PayGroupRepository struct {
+	db *sql.DB
+}
+
+func NewPayGroupRepository() *PayGroupRepository {
+	return &PayGroupRepository{db: database.DB}
+}
+
+// Members are employees assigned to the group, plus unassigned employees for the default group
+const payGroupSelect = `
+	SELECT g.id, g.name, g.description, g.pay_frequency, g.calendar_start, g.pay_day_offset,
+	       g.is_default, g.is_active, g.created_at, g.updated_at,
+	       (SELECT COUNT(*) FROM employees e
+	        WHERE e.deleted_at IS NULL AND e.employment_status = 'active'
+	          AND (e.pay_group_id = g.id OR (e.pay_group_id IS NULL AND g.is_default))) AS member_count
+	FROM pay_groups g`
+
+func (r *PayGroupRepository) Create(g *models.PayGroup) error {
+	g.ID = uuid.New()
+	now := time.Now()
+	g.CreatedAt = now
+	g.UpdatedAt = now
+	_, err := r.db.Exec(`
+		INSERT INTO pay_groups (id, name, description, pay_frequency, calendar_start, pay_day_offset, is_default, is_active, created_at, updated_at)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10)`,
+		g.ID, g.Name, g.Description, g.PayFrequency, g.CalendarStart, g.PayDayOffset, g.IsDefault, g.IsActive, g.CreatedAt, g.UpdatedAt,
+	)
+	return err
+}
+
+// Update changes a group's details. Making a group the default moves the flag off the
+// previous default group.
+func (r *PayGroupRepository) Update(g *models.PayGroup) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if g.IsDefault {
+		if _, err := tx.Exec(`UPDATE pay_groups SET is_default=false, updated_at=NOW() WHERE is_default AND id<>$1`, g.ID); err != nil {
+			return err
+		}
+	}
+	g.UpdatedAt = time.Now()
+	_, err = tx.Exec(`
+		UPDATE pay_groups SET name=$1, description=$2, pay_frequency=$3, calendar_start=$4, pay_day_offset=$5,
+		is_default=$6, is_active=$7, updated_at=$8
+		WHERE id=$9`,
+		g.Name, g.Description, g.PayFrequency, g.CalendarStart, g.PayDayOffset, g.IsDefault, g.IsActive, g.UpdatedAt, g.ID,
+	)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *PayGroupRepository) GetByID(id uuid.UUID) (*models.PayGroup, error) {
+	return r.scanRow(r.db.QueryRow(payGroupSelect+` WHERE g.id=$1`, id))
+}
+
+// GetDefault returns the group that employees without a pay group are paid with
+func (r *PayGroupRepository) GetDefault() (*models.PayGroup, error) {
+	return r.scanRow(r.db.QueryRow(payGroupSelect + ` WHERE g.is_default`))
+}
+
+// GetForEmployee returns the group an employee is paid with
+func (r *PayGroupRepository) GetForEmployee(employeeID uuid.UUID) (*models.PayGroup, error) {
+	return r.scanRow(r.db.QueryRow(payGroupSelect+`
+		WHERE g.id = COALESCE((SELECT pay_group_id FROM employees WHERE id=$1), (SELECT id FROM pay_groups WHERE is_default))`,
+		employeeID))
+}
+
+func (r *PayGroupRepository) List(includeInactive bool) ([]models.PayGroup, error) {
+	query := payGroupSelect
+	if !includeInactive {
+		query += ` WHERE g.is_active`
+	}
+	rows, err := r.db.Query(query + ` ORDER BY g.is_default DESC, g.name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var groups []models.PayGroup
+	for rows.Next() {
+		g, err := r.scanRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, *g)
+	}
+	return groups, rows.Err()
+}
+
+// Delete removes a group that has never been used for a payroll run. Its members
+// fall back to the default group.
+func (r *PayGroupRepository) Delete(id uuid.UUID) error {
+	res, err := r.db.Exec(`
+		DELETE FROM pay_groups g
+		WHERE g.id=$1 AND NOT g.is_default
+		  AND NOT EXISTS (SELECT 1 FROM payrolls p WHERE p.pay_group_id = g.id)`, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("pay group not found, is the default group or has payroll runs")
+	}
+	return nil
+}
+
+// AssignEmployees moves employees into a group and returns how many were moved.
+func (r *PayGroupRepository) AssignEmployees(groupID uuid.UUID, employeeIDs []uuid.UUID) (int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	assigned := 0
+	for _, id := range employeeIDs {
+		res, err := tx.Exec(`UPDATE employees SET pay_group_id=$1, updated_at=NOW() WHERE id=$2 AND deleted_at IS NULL`, groupID, id)
+		if err != nil {
+			return 0, err
+		}
+		n, _ := res.RowsAffected()
+		if n == 0 {
+			return 0, fmt.Errorf("employee %s not found", id)
+		}
+		assigned++
+	}
+	return assigned, tx.Commit()
+}
+
+func (r *PayGroupRepository) scanRow(row rowScanner) (*models.PayGroup, error) {
+	var g models.PayGroup
+	err := row.Scan(&g.ID, &g.Name, &g.Description, &g.PayFrequency, &g.CalendarStart, &g.PayDayOffset,
+		&g.IsDefault, &g.IsActive, &g.CreatedAt, &g.UpdatedAt, &g.MemberCount)
+	if err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
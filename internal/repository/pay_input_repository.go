@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"hr-system/internal/database"
+	"hr-system/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type PayInputRepository struct {
+	db *sql.DB
+}
+
+func NewPayInputRepository() *PayInputRepository {
+	return &PayInputRepository{db: database.DB}
+}
+
+// An input counts as processed while it is on a payslip that has not been voided.
+const payInputSelect = `
+	SELECT pi.id, pi.employee_id, pi.month, pi.year, pi.input_type, pi.category, pi.description,
+	       pi.amount, pi.tax_treatment, pi.reference, pi.payslip_id, pi.created_by, pi.created_at, pi.updated_at,
+	       COALESCE(p.status <> 'void', false) AS processed,
+	       CONCAT(e.first_name, ' ', e.last_name) AS employee_name, e.employee_number
+	FROM pay_inputs pi
+	JOIN employees e ON pi.employee_id = e.id
+	LEFT JOIN payslips p ON pi.payslip_id = p.id`
+
+const payInputUnprocessed = `(pi.payslip_id IS NULL OR EXISTS (
+		SELECT 1 FROM payslips vp WHERE vp.id = pi.payslip_id AND vp.status = 'void'))`
+
+func (r *PayInputRepository) Create(in *models.PayInput) error {
+	return insertPayInput(r.db, in)
+}
+
+// CreateBatch stores a set of inputs, all or none.
+func (r *PayInputRepository) CreateBatch(inputs []models.PayInput) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for i := range inputs {
+		if err := insertPayInput(tx, &inputs[i]); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func insertPayInput(db sqlExecer, in *models.PayInput) error {
+	in.ID = uuid.New()
+	now := time.Now()
+	in.CreatedAt = now
+	in.UpdatedAt = now
+	_, err := db.Exec(`
+		INSERT INTO pay_inputs (id, employee_id, month, year, input_type, category, description, amount, tax_treatment, reference, created_by, created_at, updated_at)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13)`,
+		in.ID, in.EmployeeID, in.Month, in.Year, in.InputType, in.Category, in.Description, in.Amount,
+		in.TaxTreatment, in.Reference, in.CreatedBy, in.CreatedAt, in.UpdatedAt,
+	)
+	return err
+}
+
+// Update changes an input that has not been paid yet.
+func (r *PayInputRepository) Update(in *models.PayInput) error {
+	in.UpdatedAt = time.Now()
+	res, err := r.db.Exec(`
+		UPDATE pay_inputs pi
+		SET month=$2, year=$3, input_type=$4, category=$5, description=$6, amount=$7, tax_treatment=$8, updated_at=$9
+		WHERE pi.id=$1 AND `+payInputUnprocessed,
+		in.ID, in.Month, in.Year, in.InputType, in.Category, in.Description, in.Amount, in.TaxTreatment, in.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("pay input has already been processed")
+	}
+	return nil
+}
+
+// Delete removes an input that has not been paid yet.
+func (r *PayInputRepository) Delete(id uuid.UUID) error {
+	res, err := r.db.Exec(`DELETE FROM pay_inputs pi WHERE pi.id=$1 AND `+payInputUnprocessed, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("pay input not found or already processed")
+	}
+	return nil
+}
+
+func (r *PayInputRepository) GetByID(id uuid.UUID) (*models.PayInput, error) {
+	return r.scanRow(r.db.QueryRow(payInputSelect+` WHERE pi.id=$1`, id))
+}
+
+// List returns the inputs for a pay period, optionally for one employee.
+func (r *PayInputRepository) List(employeeID *uuid.UUID, month, year int) ([]models.PayInput, error) {
+	where := []string{"pi.month=$1", "pi.year=$2"}
+	args := []interface{}{month, year}
+	if employeeID != nil {
+		where = append(where, "pi.employee_id=$3")
+		args = append(args, *employeeID)
+	}
+	rows, err := r.db.Query(payInputSelect+` WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY e.last_name, e.first_name, pi.input_type, pi.created_at`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return r.scanRows(rows)
+}
+
+// ListByPayslip returns the inputs paid on a payslip.
+func (r *PayInputRepository) ListByPayslip(payslipID uuid.UUID) ([]models.PayInput, error) {
+	rows, err := r.db.Query(payInputSelect+` WHERE pi.payslip_id=$1 ORDER BY pi.input_type, pi.created_at`, payslipID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return r.scanRows(rows)
+}
+
+func (r *PayInputRepository) scanRows(rows *sql.Rows) ([]models.PayInput, error) {
+	var inputs []models.PayInput
+	for rows.Next() {
+		in, err := r.scanRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		inputs = append(inputs, *in)
+	}
+	return inputs, rows.Err()
+}
+
+func (r *PayInputRepository) scanRow(row rowScanner) (*models.PayInput, error) {
+	var in models.PayInput
+	err := row.Scan(&in.ID, &in.EmployeeID, &in.Month, &in.Year, &in.InputType, &in.Category, &in.Description,
+		&in.Amount, &in.TaxTreatment, &in.Reference, &in.PayslipID, &in.CreatedBy, &in.CreatedAt, &in.UpdatedAt,
+		&in.Processed, &in.EmployeeName, &in.EmployeeNumber)
+	if err != nil {
+		return nil, err
+	}
+	return &in, nil
+}
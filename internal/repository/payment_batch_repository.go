@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"hr-system/internal/database"
+	"hr-system/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type PaymentBatchRepository struct {
+	db *sql.DB
+}
+
+func NewPaymentBatchRepository() *PaymentBatchRepository {
+	return &PaymentBatchRepository{db: database.DB}
+}
+
+// Create stores a generated batch and marks its payslips as paid by it, atomically.
+func (r *PaymentBatchRepository) Create(b *models.PaymentBatch, payslipIDs []uuid.UUID) error {
+	b.ID = uuid.New()
+	b.CreatedAt = time.Now()
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		INSERT INTO payment_batches (id, payroll_id, batch_reference, format, record_count, control_total, file_name, content_type, content, generated_by, created_at)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11)`,
+		b.ID, b.PayrollID, b.BatchReference, b.Format, b.RecordCount, b.ControlTotal,
+		b.FileName, b.ContentType, b.Content, b.GeneratedBy, b.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	for _, id := range payslipIDs {
+		res, err := tx.Exec(`
+			UPDATE payslips SET payment_batch_id=$1, updated_at=NOW()
+			WHERE id=$2 AND status='final' AND payment_batch_id IS NULL`, b.ID, id)
+		if err != nil {
+			return err
+		}
+		// A payroll reversed or batched since the payslips were listed
+		if n, _ := res.RowsAffected(); n == 0 {
+			return fmt.Errorf("payslip %s is no longer awaiting payment", id)
+		}
+	}
+	return tx.Commit()
+}
+
+// GetByID returns a batch including its file content.
+func (r *PaymentBatchRepository) GetByID(id uuid.UUID) (*models.PaymentBatch, error) {
+	var b models.PaymentBatch
+	err := r.db.QueryRow(`
+		SELECT id, payroll_id, batch_reference, format, record_count, control_total, file_name, content_type, content, generated_by, created_at
+		FROM payment_batches WHERE id=$1`, id,
+	).Scan(&b.ID, &b.PayrollID, &b.BatchReference, &b.Format, &b.RecordCount, &b.ControlTotal,
+		&b.FileName, &b.ContentType, &b.Content, &b.GeneratedBy, &b.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// ListByPayroll returns a payroll's batches without their file content.
+func (r *PaymentBatchRepository) ListByPayroll(payrollID uuid.UUID) ([]models.PaymentBatch, error) {
+	rows, err := r.db.Query(`
+		SELECT id, payroll_id, batch_reference, format, record_count, control_total, file_name, content_type, generated_by, created_at
+		FROM payment_batches WHERE payroll_id=$1 ORDER BY created_at`, payrollID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var batches []models.PaymentBatch
+	for rows.Next() {
+		var b models.PaymentBatch
+		if err := rows.Scan(&b.ID, &b.PayrollID, &b.BatchReference, &b.Format, &b.RecordCount, &b.ControlTotal,
+			&b.FileName, &b.ContentType, &b.GeneratedBy, &b.CreatedAt); err != nil {
+			return nil, err
+		}
+		batches = append(batches, b)
+	}
+	return batches, rows.Err()
+}
@@ -20,36 +20,72 @@ func NewPayrollRepository() *PayrollRepository {
 	return &PayrollRepository{db: database.DB}
 }
 
+const payrollSelect = `
+	p.id, p.pay_group_id, p.start_date, p.end_date, p.pay_date, p.status, p.run_type, p.bonus_percent, p.currency, p.processed_by, p.processed_at,
+	p.approved_by, p.approved_at, p.total_employees, p.succeeded_count, p.failed_count,
+	p.created_at, p.updated_at,
+	COALESCE(u.email, '') AS processed_by_name,
+	COALESCE(au.email, '') AS approved_by_name,
+	COALESCE(g.name, '') AS pay_group_name,
+	COALESCE(g.pay_frequency, '') AS pay_frequency
+	FROM payrolls p
+	LEFT JOIN users u ON p.processed_by = u.user_id
+	LEFT JOIN users au ON p.approved_by = au.user_id
+	LEFT JOIN pay_groups g ON p.pay_group_id = g.id`
+
 func (r *PayrollRepository) Create(p *models.Payroll) error {
 	p.ID = uuid.New()
 	now := time.Now()
 	p.CreatedAt = now
 	p.UpdatedAt = now
 	_, err := r.db.Exec(`
-		INSERT INTO payrolls (id, start_date, end_date, status, processed_by, processed_at, created_at, updated_at)
-		VALUES ($1,$2,$3,$4,$5,$6,$7,$8)`,
-		p.ID, p.StartDate, p.EndDate, p.Status, p.ProcessedBy, p.ProcessedAt, p.CreatedAt, p.UpdatedAt,
+		INSERT INTO payrolls (id, pay_group_id, start_date, end_date, pay_date, status, run_type, bonus_percent, currency, processed_by, processed_at, created_at, updated_at)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13)`,
+		p.ID, p.PayGroupID, p.StartDate, p.EndDate, p.PayDate, p.Status, p.RunType, p.BonusPercent, p.Currency, p.ProcessedBy, p.ProcessedAt, p.CreatedAt, p.UpdatedAt,
 	)
 	return err
 }
 
 func (r *PayrollRepository) GetByID(id uuid.UUID) (*models.Payroll, error) {
+	row := r.db.QueryRow(fmt.Sprintf(`SELECT %s WHERE p.id=$1`, payrollSelect), id)
+	return r.scanRow(row)
+}
+
+// FindOverlappingRegular returns a non-cancelled regular payroll of the pay group whose
+// period overlaps the given dates.
+func (r *PayrollRepository) FindOverlappingRegular(payGroupID uuid.UUID, start, end time.Time) (*models.Payroll, error) {
+	row := r.db.QueryRow(fmt.Sprintf(`
+		SELECT %s
+		WHERE p.pay_group_id=$1 AND p.run_type='regular' AND p.status<>'CANCELLED'
+		  AND p.start_date<=$3 AND p.end_date>=$2
+		ORDER BY p.start_date
+		LIMIT 1`, payrollSelect), payGroupID, start, end)
+	return r.scanRow(row)
+}
+
+// FindPreviousRegular returns the pay group's latest non-cancelled regular payroll
+// ending before the given date.
+func (r *PayrollRepository) FindPreviousRegular(payGroupID uuid.UUID, before time.Time) (*models.Payroll, error) {
+	row := r.db.QueryRow(fmt.Sprintf(`
+		SELECT %s
+		WHERE p.pay_group_id=$1 AND p.run_type='regular' AND p.status<>'CANCELLED' AND p.end_date<$2
+		ORDER BY p.end_date DESC, p.created_at DESC
+		LIMIT 1`, payrollSelect), payGroupID, before)
+	return r.scanRow(row)
+}
+
+func (r *PayrollRepository) scanRow(row rowScanner) (*models.Payroll, error) {
 	var p models.Payroll
-	err := r.db.QueryRow(`
-		SELECT p.id, p.start_date, p.end_date, p.status, p.processed_by, p.processed_at, p.created_at, p.updated_at,
-		       COALESCE(u.email, '') AS processed_by_name
-		FROM payrolls p
-		LEFT JOIN users u ON p.processed_by = u.user_id
-		WHERE p.id=$1`, id,
-	).Scan(&p.ID, &p.StartDate, &p.EndDate, &p.Status, &p.ProcessedBy, &p.ProcessedAt,
-		&p.CreatedAt, &p.UpdatedAt, &p.ProcessedByName)
+	err := row.Scan(&p.ID, &p.PayGroupID, &p.StartDate, &p.EndDate, &p.PayDate, &p.Status, &p.RunType, &p.BonusPercent, &p.Currency, &p.ProcessedBy, &p.ProcessedAt,
+		&p.ApprovedBy, &p.ApprovedAt, &p.TotalEmployees, &p.SucceededCount, &p.FailedCount, &p.CreatedAt, &p.UpdatedAt,
+		&p.ProcessedByName, &p.ApprovedByName, &p.PayGroupName, &p.PayFrequency)
 	if err != nil {
 		return nil, err
 	}
 	return &p, nil
 }
 
-func (r *PayrollRepository) List(status string, page, pageSize int) ([]models.Payroll, int, error) {
+func (r *PayrollRepository) List(status string, payGroupID *uuid.UUID, page, pageSize int) ([]models.Payroll, int, error) {
 	args := []interface{}{}
 	where := []string{}
 	i := 1
@@ -59,6 +95,11 @@ func (r *PayrollRepository) List(status string, page, pageSize int) ([]models.Pa
 		args = append(args, status)
 		i++
 	}
+	if payGroupID != nil {
+		where = append(where, fmt.Sprintf("p.pay_group_id=$%d", i))
+		args = append(args, *payGroupID)
+		i++
+	}
 
 	whereStr := "1=1"
 	if len(where) > 0 {
@@ -73,13 +114,10 @@ func (r *PayrollRepository) List(status string, page, pageSize int) ([]models.Pa
 
 	args = append(args, pageSize, (page-1)*pageSize)
 	rows, err := r.db.Query(fmt.Sprintf(`
-		SELECT p.id, p.start_date, p.end_date, p.status, p.processed_by, p.processed_at, p.created_at, p.updated_at,
-		       COALESCE(u.email, '') AS processed_by_name
-		FROM payrolls p
-		LEFT JOIN users u ON p.processed_by = u.user_id
+		SELECT %s
 		WHERE %s
 		ORDER BY p.created_at DESC
-		LIMIT $%d OFFSET $%d`, whereStr, i, i+1), args...)
+		LIMIT $%d OFFSET $%d`, payrollSelect, whereStr, i, i+1), args...)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -87,26 +125,45 @@ func (r *PayrollRepository) List(status string, page, pageSize int) ([]models.Pa
 
 	var payrolls []models.Payroll
 	for rows.Next() {
-		var p models.Payroll
-		if err := rows.Scan(&p.ID, &p.StartDate, &p.EndDate, &p.Status, &p.ProcessedBy, &p.ProcessedAt,
-			&p.CreatedAt, &p.UpdatedAt, &p.ProcessedByName); err != nil {
+		p, err := r.scanRow(rows)
+		if err != nil {
 			return nil, 0, err
 		}
-		payrolls = append(payrolls, p)
+		payrolls = append(payrolls, *p)
 	}
 	return payrolls, total, rows.Err()
 }
 
 func (r *PayrollRepository) Update(p *models.Payroll) error {
+	return updatePayroll(r.db, p)
+}
+
+func updatePayroll(db sqlExecer, p *models.Payroll) error {
 	p.UpdatedAt = time.Now()
-	_, err := r.db.Exec(`
-		UPDATE payrolls SET status=$1, processed_by=$2, processed_at=$3, updated_at=$4
-		WHERE id=$5`,
-		p.Status, p.ProcessedBy, p.ProcessedAt, p.UpdatedAt, p.ID,
+	_, err := db.Exec(`
+		UPDATE payrolls SET status=$1, processed_by=$2, processed_at=$3, approved_by=$4, approved_at=$5, updated_at=$6
+		WHERE id=$7`,
+		p.Status, p.ProcessedBy, p.ProcessedAt, p.ApprovedBy, p.ApprovedAt, p.UpdatedAt, p.ID,
 	)
 	return err
 }
 
+// RefreshProgress recalculates a payroll's progress counters from its run items.
+func (r *PayrollRepository) RefreshProgress(id uuid.UUID) error {
+	return refreshProgress(r.db, id)
+}
+
+func refreshProgress(db sqlExecer, id uuid.UUID) error {
+	_, err := db.Exec(`
+		UPDATE payrolls SET
+			total_employees = (SELECT COUNT(*) FROM payroll_run_items WHERE payroll_id=$1),
+			succeeded_count = (SELECT COUNT(*) FROM payroll_run_items WHERE payroll_id=$1 AND status='succeeded'),
+			failed_count    = (SELECT COUNT(*) FROM payroll_run_items WHERE payroll_id=$1 AND status='failed'),
+			updated_at      = NOW()
+		WHERE id=$1`, id)
+	return err
+}
+
 func (r *PayrollRepository) Delete(id uuid.UUID) error {
 	result, err := r.db.Exec(`DELETE FROM payrolls WHERE id=$1 AND status='OPEN'`, id)
 	if err != nil {
@@ -121,3 +178,92 @@ func (r *PayrollRepository) Delete(id uuid.UUID) error {
 	}
 	return nil
 }
+
+// Reverse voids a COMPLETED payroll's final payslips, records the reversal, clears the
+// run and reopens the payroll, all in one transaction. It fails without changing anything
+// when any payslip has already been put in a payment batch.
+func (r *PayrollRepository) Reverse(p *models.Payroll, rev *models.PayrollReversal) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var status models.PayrollStatus
+	if err := tx.QueryRow(`SELECT status FROM payrolls WHERE id=$1 FOR UPDATE`, p.ID).Scan(&status); err != nil {
+		return err
+	}
+	if status != models.PayrollStatusCompleted {
+		return fmt.Errorf("can only reverse payrolls in COMPLETED status, current: %s", status)
+	}
+
+	var paid int
+	err = tx.QueryRow(`
+		WITH voided AS (
+			UPDATE payslips
+			SET status='void', void_reason=$2, voided_by=$3, voided_at=NOW(), updated_at=NOW()
+			WHERE payroll_id=$1 AND status='final'
+			RETURNING net_salary, payment_batch_id
+		)
+		SELECT COUNT(*), COALESCE(SUM(net_salary), 0), COUNT(payment_batch_id) FROM voided`,
+		p.ID, rev.Reason, rev.ReversedBy).Scan(&rev.PayslipsVoided, &rev.TotalNetVoided, &paid)
+	if err != nil {
+		return err
+	}
+	if paid > 0 {
+		return fmt.Errorf("%d payslips of this payroll are already in a payment batch and cannot be voided; "+
+			"issue supplementary payslips for the corrections instead", paid)
+	}
+
+	rev.ID = uuid.New()
+	rev.PayrollID = p.ID
+	rev.ReversedAt = time.Now()
+	if _, err := tx.Exec(`
+		INSERT INTO payroll_reversals (id, payroll_id, reason, payslips_voided, total_net_voided, reversed_by, reversed_at)
+		VALUES ($1,$2,$3,$4,$5,$6,$7)`,
+		rev.ID, rev.PayrollID, rev.Reason, rev.PayslipsVoided, rev.TotalNetVoided, rev.ReversedBy, rev.ReversedAt,
+	); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM payroll_run_items WHERE payroll_id=$1`, p.ID); err != nil {
+		return err
+	}
+	if err := refreshProgress(tx, p.ID); err != nil {
+		return err
+	}
+
+	p.Status = models.PayrollStatusOpen
+	p.ProcessedBy, p.ProcessedAt = nil, nil
+	p.ApprovedBy, p.ApprovedAt = nil, nil
+	if err := updatePayroll(tx, p); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ListReversals returns a payroll's reversal history, most recent first.
+func (r *PayrollRepository) ListReversals(payrollID uuid.UUID) ([]models.PayrollReversal, error) {
+	rows, err := r.db.Query(`
+		SELECT pr.id, pr.payroll_id, pr.reason, pr.payslips_voided, pr.total_net_voided,
+		       pr.reversed_by, pr.reversed_at, COALESCE(u.email, '') AS reversed_by_name
+		FROM payroll_reversals pr
+		LEFT JOIN users u ON pr.reversed_by = u.user_id
+		WHERE pr.payroll_id=$1
+		ORDER BY pr.reversed_at DESC`, payrollID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reversals []models.PayrollReversal
+	for rows.Next() {
+		var rev models.PayrollReversal
+		if err := rows.Scan(&rev.ID, &rev.PayrollID, &rev.Reason, &rev.PayslipsVoided, &rev.TotalNetVoided,
+			&rev.ReversedBy, &rev.ReversedAt, &rev.ReversedByName); err != nil {
+			return nil, err
+		}
+		reversals = append(reversals, rev)
+	}
+	return reversals, rows.Err()
+}
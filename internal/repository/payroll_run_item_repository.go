@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"hr-system/internal/database"
+	"hr-system/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type PayrollRunItemRepository struct {
+	db *sql.DB
+}
+
+func NewPayrollRunItemRepository() *PayrollRunItemRepository {
+	return &PayrollRunItemRepository{db: database.DB}
+}
+
+// CreatePending queues a pending item for each employee in a payroll run.
+func (r *PayrollRunItemRepository) CreatePending(payrollID uuid.UUID, employeeIDs []uuid.UUID) ([]models.PayrollRunItem, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	items := make([]models.PayrollRunItem, 0, len(employeeIDs))
+	for _, employeeID := range employeeIDs {
+		item := models.PayrollRunItem{
+			ID:         uuid.New(),
+			PayrollID:  payrollID,
+			EmployeeID: employeeID,
+			Status:     models.PayrollRunItemPending,
+			CreatedAt:  now,
+			UpdatedAt:  now,
+		}
+		_, err := tx.Exec(`
+			INSERT INTO payroll_run_items (id, payroll_id, employee_id, status, created_at, updated_at)
+			VALUES ($1,$2,$3,$4,$5,$6)`,
+			item.ID, item.PayrollID, item.EmployeeID, item.Status, item.CreatedAt, item.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// MarkSucceeded records the payslip generated for an item.
+func (r *PayrollRunItemRepository) MarkSucceeded(id, payslipID uuid.UUID) error {
+	_, err := r.db.Exec(`
+		UPDATE payroll_run_items
+		SET status='succeeded', error_reason='', payslip_id=$2, attempts=attempts+1, updated_at=NOW()
+		WHERE id=$1`, id, payslipID)
+	return err
+}
+
+// MarkFailed records why an item's payslip could not be generated.
+func (r *PayrollRunItemRepository) MarkFailed(id uuid.UUID, reason string) error {
+	_, err := r.db.Exec(`
+		UPDATE payroll_run_items
+		SET status='failed', error_reason=$2, attempts=attempts+1, updated_at=NOW()
+		WHERE id=$1`, id, reason)
+	return err
+}
+
+// ResetFailed moves a payroll's failed items back to pending and returns them.
+func (r *PayrollRunItemRepository) ResetFailed(payrollID uuid.UUID) ([]models.PayrollRunItem, error) {
+	rows, err := r.db.Query(`
+		UPDATE payroll_run_items
+		SET status='pending', updated_at=NOW()
+		WHERE payroll_id=$1 AND status='failed'
+		RETURNING id, payroll_id, employee_id, status, error_reason, payslip_id, attempts, created_at, updated_at`,
+		payrollID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []models.PayrollRunItem
+	for rows.Next() {
+		var item models.PayrollRunItem
+		if err := rows.Scan(&item.ID, &item.PayrollID, &item.EmployeeID, &item.Status, &item.ErrorReason,
+			&item.PayslipID, &item.Attempts, &item.CreatedAt, &item.UpdatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// ListByPayroll returns a payroll's run items, optionally restricted to one status.
+func (r *PayrollRunItemRepository) ListByPayroll(payrollID uuid.UUID, status string) ([]models.PayrollRunItem, error) {
+	q := `
+		SELECT i.id, i.payroll_id, i.employee_id, i.status, i.error_reason, i.payslip_id, i.attempts,
+		       i.created_at, i.updated_at, CONCAT(e.first_name, ' ', e.last_name) AS employee_name
+		FROM payroll_run_items i
+		JOIN employees e ON i.employee_id = e.id
+		WHERE i.payroll_id=$1`
+	args := []interface{}{payrollID}
+	if status != "" {
+		q += " AND i.status=$2"
+		args = append(args, status)
+	}
+	q += " ORDER BY e.last_name, e.first_name"
+
+	rows, err := r.db.Query(q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []models.PayrollRunItem
+	for rows.Next() {
+		var item models.PayrollRunItem
+		if err := rows.Scan(&item.ID, &item.PayrollID, &item.EmployeeID, &item.Status, &item.ErrorReason,
+			&item.PayslipID, &item.Attempts, &item.CreatedAt, &item.UpdatedAt, &item.EmployeeName); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// DeleteByPayroll clears a payroll's run items so the payroll can be processed afresh.
+func (r *PayrollRunItemRepository) DeleteByPayroll(payrollID uuid.UUID) error {
+	_, err := r.db.Exec(`DELETE FROM payroll_run_items WHERE payroll_id=$1`, payrollID)
+	return err
+}
@@ -8,6 +8,7 @@ import (
 
 	"hr-system/internal/database"
 	"hr-system/internal/models"
+	"hr-system/pkg/money"
 
 	"github.com/google/uuid"
 )
@@ -20,52 +21,166 @@ func NewPayslipRepository() *PayslipRepository {
 	return &PayslipRepository{db: database.DB}
 }
 
-func (r *PayslipRepository) Create(p *models.Payslip) error {
+const payslipSelectCols = `
+	p.id, p.payroll_id, p.employee_id, p.month, p.year, p.period_start, p.period_end, p.base_salary, p.housing_allowance,
+	p.transport_allowance, p.medical_allowance, p.overtime_hours, p.overtime_pay, p.bonus_pay, p.gross_salary, p.income_tax,
+	p.leave_days, p.additional_earnings, p.reimbursements, p.pre_tax_deductions, p.other_deductions,
+	p.arrears_pay, p.arrears_tax, p.encashed_leave, p.leave_encashment, p.notice_pay, p.gratuity, p.loan_deduction, p.net_salary, p.currency, p.status, p.payslip_type, p.original_payslip_id,
+	p.adjustment_reason, p.void_reason, p.voided_by, p.voided_at, p.payment_batch_id,
+	p.ytd_gross_salary, p.ytd_income_tax, p.ytd_overtime_pay, p.ytd_bonus_pay, p.ytd_net_salary,
+	p.created_at, p.updated_at,
+	CONCAT(e.first_name, ' ', e.last_name) AS employee_name,
+	COALESCE(pos.title, '') AS position_name`
+
+const payslipJoins = `
+	FROM payslips p
+	JOIN employees e ON p.employee_id = e.id
+	LEFT JOIN positions pos ON e.position_id = pos.id`
+
+// Create stores a payslip together with any loan repayments deducted on it, and marks
+// the unprocessed pay inputs in p.PayInputs, the retro pay arrears in p.Arrears and the
+// unpaid leave encashments in p.Encashments as paid on it.
+func (r *PayslipRepository) Create(p *models.Payslip, repayments ...models.LoanRepayment) error {
 	p.ID = uuid.New()
 	now := time.Now()
 	p.CreatedAt = now
 	p.UpdatedAt = now
-	_, err := r.db.Exec(`
-		INSERT INTO payslips (id, employee_id, month, year, base_salary, housing_allowance, transport_allowance, medical_allowance, gross_salary, income_tax, leave_days, net_salary, created_at, updated_at)
-		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14)`,
-		p.ID, p.EmployeeID, p.Month, p.Year, p.BaseSalary, p.HousingAllowance,
-		p.TransportAllowance, p.MedicalAllowance, p.GrossSalary, p.IncomeTax,
-		p.LeaveDays, p.NetSalary, p.CreatedAt, p.UpdatedAt,
+	if p.Status == "" {
+		p.Status = models.PayslipStatusFinal
+	}
+	if p.PayslipType == "" {
+		p.PayslipType = models.PayslipTypeRegular
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		INSERT INTO payslips (id, payroll_id, employee_id, month, year, period_start, period_end, base_salary, housing_allowance, transport_allowance, medical_allowance, overtime_hours, overtime_pay, bonus_pay, gross_salary, income_tax, leave_days, additional_earnings, reimbursements, pre_tax_deductions, other_deductions, arrears_pay, arrears_tax, encashed_leave, leave_encashment, notice_pay, gratuity, loan_deduction, net_salary, currency, status, payslip_type, original_payslip_id, adjustment_reason, created_at, updated_at)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19,$20,$21,$22,$23,$24,$25,$26,$27,$28,$29,$30,$31,$32,$33,$34,$35,$36)`,
+		p.ID, p.PayrollID, p.EmployeeID, p.Month, p.Year, p.PeriodStart, p.PeriodEnd, p.BaseSalary, p.HousingAllowance,
+		p.TransportAllowance, p.MedicalAllowance, p.OvertimeHours, p.OvertimePay, p.BonusPay, p.GrossSalary, p.IncomeTax,
+		p.LeaveDays, p.AdditionalEarnings, p.Reimbursements, p.PreTaxDeductions, p.OtherDeductions, p.ArrearsPay, p.ArrearsTax,
+		p.EncashedLeave, p.LeaveEncashment, p.NoticePay, p.Gratuity, p.LoanDeduction, p.NetSalary, p.Currency, p.Status, p.PayslipType, p.OriginalPayslipID, p.AdjustmentReason,
+		p.CreatedAt, p.UpdatedAt,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+	for i := range repayments {
+		repayments[i].PayslipID = &p.ID
+		if err := insertLoanRepayment(tx, &repayments[i]); err != nil {
+			return err
+		}
+	}
+	for _, in := range p.PayInputs {
+		if in.Processed {
+			continue
+		}
+		if _, err := tx.Exec(`UPDATE pay_inputs SET payslip_id=$1, updated_at=NOW() WHERE id=$2`, p.ID, in.ID); err != nil {
+			return err
+		}
+	}
+	marked := map[uuid.UUID]bool{}
+	for _, l := range p.Arrears {
+		if marked[l.AdjustmentID] {
+			continue
+		}
+		marked[l.AdjustmentID] = true
+		if _, err := tx.Exec(`UPDATE retro_pay_adjustments SET payslip_id=$1, updated_at=NOW() WHERE id=$2`, p.ID, l.AdjustmentID); err != nil {
+			return err
+		}
+	}
+	for _, e := range p.Encashments {
+		if e.Paid {
+			continue
+		}
+		if _, err := tx.Exec(`UPDATE leave_encashments SET payslip_id=$1, updated_at=NOW() WHERE id=$2`, p.ID, e.ID); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
 }
 
 func (r *PayslipRepository) GetByID(id uuid.UUID) (*models.Payslip, error) {
-	row := r.db.QueryRow(`
-		SELECT p.id, p.employee_id, p.month, p.year, p.base_salary, p.housing_allowance,
-		       p.transport_allowance, p.medical_allowance, p.gross_salary, p.income_tax,
-		       p.leave_days, p.net_salary, p.created_at, p.updated_at,
-		       CONCAT(e.first_name, ' ', e.last_name) AS employee_name,
-		       COALESCE(pos.title, '') AS position_name
-		FROM payslips p
-		JOIN employees e ON p.employee_id = e.id
-		LEFT JOIN positions pos ON e.position_id = pos.id
-		WHERE p.id=$1`, id)
+	row := r.db.QueryRow(fmt.Sprintf(`SELECT %s %s WHERE p.id=$1`, payslipSelectCols, payslipJoins), id)
 	return r.scanOne(row)
 }
 
+// GetByEmployeeAndPeriod returns the active (non-void) regular payslip for a period.
 func (r *PayslipRepository) GetByEmployeeAndPeriod(employeeID uuid.UUID, month, year int) (*models.Payslip, error) {
-	row := r.db.QueryRow(`
-		SELECT p.id, p.employee_id, p.month, p.year, p.base_salary, p.housing_allowance,
-		       p.transport_allowance, p.medical_allowance, p.gross_salary, p.income_tax,
-		       p.leave_days, p.net_salary, p.created_at, p.updated_at,
-		       CONCAT(e.first_name, ' ', e.last_name) AS employee_name,
-		       COALESCE(pos.title, '') AS position_name
-		FROM payslips p
-		JOIN employees e ON p.employee_id = e.id
-		LEFT JOIN positions pos ON e.position_id = pos.id
-		WHERE p.employee_id=$1 AND p.month=$2 AND p.year=$3`, employeeID, month, year)
+	row := r.db.QueryRow(fmt.Sprintf(`
+		SELECT %s %s
+		WHERE p.employee_id=$1 AND p.month=$2 AND p.year=$3
+		  AND p.payslip_type='regular' AND p.status<>'void'`, payslipSelectCols, payslipJoins),
+		employeeID, month, year)
 	return r.scanOne(row)
 }
 
+// FindOverlappingRegular returns an active (non-void) regular payslip whose pay period
+// overlaps the given dates, so an employee moved between pay groups is not paid twice.
+func (r *PayslipRepository) FindOverlappingRegular(employeeID uuid.UUID, start, end time.Time) (*models.Payslip, error) {
+	row := r.db.QueryRow(fmt.Sprintf(`
+		SELECT %s %s
+		WHERE p.employee_id=$1 AND p.period_start<=$3 AND p.period_end>=$2
+		  AND p.payslip_type='regular' AND p.status<>'void'
+		ORDER BY p.period_start
+		LIMIT 1`, payslipSelectCols, payslipJoins),
+		employeeID, start, end)
+	return r.scanOne(row)
+}
+
+// GetLatestVoided returns the most recently voided regular payslip for a pay period,
+// used to link a re-run payslip back to the one it replaces.
+func (r *PayslipRepository) GetLatestVoided(employeeID uuid.UUID, start, end time.Time) (*models.Payslip, error) {
+	row := r.db.QueryRow(fmt.Sprintf(`
+		SELECT %s %s
+		WHERE p.employee_id=$1 AND p.period_start=$2 AND p.period_end=$3
+		  AND p.payslip_type='regular' AND p.status='void'
+		ORDER BY p.voided_at DESC
+		LIMIT 1`, payslipSelectCols, payslipJoins),
+		employeeID, start, end)
+	return r.scanOne(row)
+}
+
+// ListActiveForEmployeePeriod returns the final regular and supplementary payslips
+// an employee has been paid for a pay period. Bonus payslips are not included.
+func (r *PayslipRepository) ListActiveForEmployeePeriod(employeeID uuid.UUID, start, end time.Time) ([]models.Payslip, error) {
+	rows, err := r.db.Query(fmt.Sprintf(`
+		SELECT %s %s
+		WHERE p.employee_id=$1 AND p.period_start=$2 AND p.period_end=$3 AND p.status='final'
+		  AND p.payslip_type IN ('regular', 'supplementary')
+		ORDER BY p.created_at`, payslipSelectCols, payslipJoins),
+		employeeID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return r.scanRows(rows)
+}
+
+// ListFinalRegularSince returns an employee's final regular payslips for pay periods
+// ending on or after the given date, oldest first.
+func (r *PayslipRepository) ListFinalRegularSince(employeeID uuid.UUID, from time.Time) ([]models.Payslip, error) {
+	rows, err := r.db.Query(fmt.Sprintf(`
+		SELECT %s %s
+		WHERE p.employee_id=$1 AND p.period_end>=$2 AND p.status='final' AND p.payslip_type='regular'
+		ORDER BY p.period_start`, payslipSelectCols, payslipJoins),
+		employeeID, from)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return r.scanRows(rows)
+}
+
+// List returns final payslips only; drafts are reachable through their payroll.
 func (r *PayslipRepository) List(employeeID *uuid.UUID, month *int, year *int, page, pageSize int) ([]models.Payslip, int, error) {
 	args := []interface{}{}
-	where := []string{}
+	where := []string{"p.status='final'"}
 	i := 1
 
 	if employeeID != nil {
@@ -84,10 +199,7 @@ func (r *PayslipRepository) List(employeeID *uuid.UUID, month *int, year *int, p
 		i++
 	}
 
-	whereStr := "1=1"
-	if len(where) > 0 {
-		whereStr = strings.Join(where, " AND ")
-	}
+	whereStr := strings.Join(where, " AND ")
 
 	var total int
 	err := r.db.QueryRow(fmt.Sprintf(`SELECT COUNT(*) FROM payslips p WHERE %s`, whereStr), args...).Scan(&total)
@@ -97,31 +209,243 @@ func (r *PayslipRepository) List(employeeID *uuid.UUID, month *int, year *int, p
 
 	args = append(args, pageSize, (page-1)*pageSize)
 	rows, err := r.db.Query(fmt.Sprintf(`
-		SELECT p.id, p.employee_id, p.month, p.year, p.base_salary, p.housing_allowance,
-		       p.transport_allowance, p.medical_allowance, p.gross_salary, p.income_tax,
-		       p.leave_days, p.net_salary, p.created_at, p.updated_at,
-		       CONCAT(e.first_name, ' ', e.last_name) AS employee_name,
-		       COALESCE(pos.title, '') AS position_name
-		FROM payslips p
-		JOIN employees e ON p.employee_id = e.id
-		LEFT JOIN positions pos ON e.position_id = pos.id
+		SELECT %s %s
 		WHERE %s
 		ORDER BY p.year DESC, p.month DESC, e.last_name
-		LIMIT $%d OFFSET $%d`, whereStr, i, i+1), args...)
+		LIMIT $%d OFFSET $%d`, payslipSelectCols, payslipJoins, whereStr, i, i+1), args...)
 	if err != nil {
 		return nil, 0, err
 	}
 	defer rows.Close()
 
-	var payslips []models.Payslip
+	payslips, err := r.scanRows(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+	return payslips, total, nil
+}
+
+// ListByPayroll returns the payslips produced by a payroll, optionally restricted to one
+// status. With no status, voided payslips are excluded.
+func (r *PayslipRepository) ListByPayroll(payrollID uuid.UUID, status models.PayslipStatus) ([]models.Payslip, error) {
+	q := fmt.Sprintf(`SELECT %s %s WHERE p.payroll_id=$1`, payslipSelectCols, payslipJoins)
+	args := []interface{}{payrollID}
+	if status != "" {
+		q += " AND p.status=$2"
+		args = append(args, status)
+	} else {
+		q += " AND p.status<>'void'"
+	}
+	q += " ORDER BY e.last_name, e.first_name, p.created_at"
+
+	rows, err := r.db.Query(q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return r.scanRows(rows)
+}
+
+// FinalizeDrafts locks a payroll's draft payslips, returning the number finalised.
+func (r *PayslipRepository) FinalizeDrafts(payrollID uuid.UUID) (int, error) {
+	result, err := r.db.Exec(`
+		UPDATE payslips SET status='final', updated_at=NOW()
+		WHERE payroll_id=$1 AND status='draft'`, payrollID)
+	if err != nil {
+		return 0, err
+	}
+	n, err := result.RowsAffected()
+	return int(n), err
+}
+
+// DeleteDrafts discards a payroll's draft payslips.
+func (r *PayslipRepository) DeleteDrafts(payrollID uuid.UUID) error {
+	_, err := r.db.Exec(`DELETE FROM payslips WHERE payroll_id=$1 AND status='draft'`, payrollID)
+	return err
+}
+
+// ListUnpaidByPayroll returns a payroll's final payslips not yet included in a payment batch.
+func (r *PayslipRepository) ListUnpaidByPayroll(payrollID uuid.UUID) ([]models.Payslip, error) {
+	rows, err := r.db.Query(fmt.Sprintf(`
+		SELECT %s %s
+		WHERE p.payroll_id=$1 AND p.status='final' AND p.payment_batch_id IS NULL
+		ORDER BY e.last_name, e.first_name, p.created_at`, payslipSelectCols, payslipJoins), payrollID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return r.scanRows(rows)
+}
+
+// YTDTotals sums an employee's final payslips for a year up to and including the given month.
+func (r *PayslipRepository) YTDTotals(employeeID uuid.UUID, year, month int) (*models.PayslipYTD, error) {
+	ytd := &models.PayslipYTD{Year: year}
+	err := r.db.QueryRow(`
+		SELECT COALESCE(SUM(gross_salary), 0), COALESCE(SUM(income_tax), 0),
+		       COALESCE(SUM(overtime_pay), 0), COALESCE(SUM(bonus_pay), 0), COALESCE(SUM(net_salary), 0)
+		FROM payslips
+		WHERE employee_id=$1 AND year=$2 AND month<=$3 AND status='final'`,
+		employeeID, year, month,
+	).Scan(&ytd.GrossSalary, &ytd.IncomeTax, &ytd.OvertimePay, &ytd.BonusPay, &ytd.NetSalary)
+	if err != nil {
+		return nil, err
+	}
+	return ytd, nil
+}
+
+// RefreshYTD rebuilds an employee's YTD accumulator for a tax year and stamps YTD
+// figures on any final payslips that do not have them yet.
+func (r *PayslipRepository) RefreshYTD(employeeID uuid.UUID, year int) error {
+	return r.refreshYTD(`SELECT $1::uuid AS employee_id, $2::int AS tax_year`, employeeID, year)
+}
+
+// RefreshYTDForPayroll rebuilds the YTD accumulators of every employee on a payroll.
+func (r *PayslipRepository) RefreshYTDForPayroll(payrollID uuid.UUID) error {
+	return r.refreshYTD(`SELECT DISTINCT employee_id, year AS tax_year FROM payslips WHERE payroll_id=$1`, payrollID)
+}
+
+// refreshYTD recomputes accumulators for the (employee_id, tax_year) pairs selected by
+// targets. Totals are rebuilt from final payslips so voided payslips drop out. Payslip
+// snapshots are only written once, so an issued payslip keeps the figures it showed.
+func (r *PayslipRepository) refreshYTD(targets string, args ...interface{}) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		WITH targets AS (`+targets+`)
+		UPDATE employee_ytd_totals y
+		SET gross_salary=0, income_tax=0, overtime_pay=0, bonus_pay=0, leave_pay=0, net_salary=0,
+		    payslip_count=0, updated_at=NOW()
+		FROM targets t
+		WHERE y.employee_id=t.employee_id AND y.tax_year=t.tax_year`, args...)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		WITH targets AS (`+targets+`)
+		INSERT INTO employee_ytd_totals (employee_id, tax_year, gross_salary, income_tax, overtime_pay, bonus_pay, leave_pay, net_salary, payslip_count, updated_at)
+		SELECT p.employee_id, p.year, SUM(p.gross_salary), SUM(p.income_tax), SUM(p.overtime_pay),
+		       SUM(p.bonus_pay), SUM(p.leave_days), SUM(p.net_salary), COUNT(*), NOW()
+		FROM payslips p
+		JOIN targets t ON p.employee_id=t.employee_id AND p.year=t.tax_year
+		WHERE p.status='final'
+		GROUP BY p.employee_id, p.year
+		ON CONFLICT (employee_id, tax_year) DO UPDATE SET
+			gross_salary=EXCLUDED.gross_salary, income_tax=EXCLUDED.income_tax,
+			overtime_pay=EXCLUDED.overtime_pay, bonus_pay=EXCLUDED.bonus_pay,
+			leave_pay=EXCLUDED.leave_pay, net_salary=EXCLUDED.net_salary,
+			payslip_count=EXCLUDED.payslip_count, updated_at=EXCLUDED.updated_at`, args...)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		WITH targets AS (`+targets+`)
+		UPDATE payslips p
+		SET ytd_gross_salary=s.gross_salary, ytd_income_tax=s.income_tax, ytd_overtime_pay=s.overtime_pay,
+		    ytd_bonus_pay=s.bonus_pay, ytd_net_salary=s.net_salary
+		FROM (
+			SELECT p1.id, SUM(p2.gross_salary) AS gross_salary, SUM(p2.income_tax) AS income_tax,
+			       SUM(p2.overtime_pay) AS overtime_pay, SUM(p2.bonus_pay) AS bonus_pay, SUM(p2.net_salary) AS net_salary
+			FROM payslips p1
+			JOIN targets t ON p1.employee_id=t.employee_id AND p1.year=t.tax_year
+			JOIN payslips p2 ON p2.employee_id=p1.employee_id AND p2.year=p1.year AND p2.status='final'
+				AND (p2.month < p1.month OR (p2.month = p1.month AND p2.created_at <= p1.created_at))
+			WHERE p1.status='final' AND p1.ytd_gross_salary IS NULL
+			GROUP BY p1.id
+		) s
+		WHERE p.id=s.id`, args...)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetYTD returns an employee's YTD accumulator for a tax year (zero if nothing paid yet).
+func (r *PayslipRepository) GetYTD(employeeID uuid.UUID, year int) (*models.PayslipYTD, error) {
+	ytd := &models.PayslipYTD{Year: year}
+	var updatedAt time.Time
+	err := r.db.QueryRow(`
+		SELECT gross_salary, income_tax, overtime_pay, bonus_pay, leave_pay, net_salary, payslip_count, updated_at
+		FROM employee_ytd_totals WHERE employee_id=$1 AND tax_year=$2`, employeeID, year,
+	).Scan(&ytd.GrossSalary, &ytd.IncomeTax, &ytd.OvertimePay, &ytd.BonusPay, &ytd.LeavePay,
+		&ytd.NetSalary, &ytd.PayslipCount, &updatedAt)
+	if err == sql.ErrNoRows {
+		return ytd, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	ytd.UpdatedAt = &updatedAt
+	return ytd, nil
+}
+
+// taxAmountsCols sums final payslips into models.TaxAmounts column order
+const taxAmountsCols = `
+	COALESCE(SUM(p.base_salary), 0),
+	COALESCE(SUM(p.housing_allowance + p.transport_allowance + p.medical_allowance), 0),
+	COALESCE(SUM(p.overtime_pay), 0), COALESCE(SUM(p.bonus_pay), 0), COALESCE(SUM(p.leave_days), 0),
+	COALESCE(SUM(p.gross_salary), 0), COALESCE(SUM(p.income_tax), 0), COALESCE(SUM(p.net_salary), 0)`
+
+// MonthlyTaxTotals returns an employee's final pay and tax for each month of a year
+// in which they were paid. Supplementary and bonus payslips count in their month.
+func (r *PayslipRepository) MonthlyTaxTotals(employeeID uuid.UUID, year int) ([]models.TaxCertificateMonth, error) {
+	rows, err := r.db.Query(`
+		SELECT p.month, `+taxAmountsCols+`
+		FROM payslips p
+		WHERE p.employee_id=$1 AND p.year=$2 AND p.status='final'
+		GROUP BY p.month
+		ORDER BY p.month`, employeeID, year)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var months []models.TaxCertificateMonth
 	for rows.Next() {
-		p, err := r.scanRow(rows)
-		if err != nil {
-			return nil, 0, err
+		var m models.TaxCertificateMonth
+		a := &m.TaxAmounts
+		if err := rows.Scan(&m.Month, &a.BasicSalary, &a.Allowances, &a.OvertimePay, &a.BonusPay,
+			&a.LeavePay, &a.GrossSalary, &a.IncomeTax, &a.NetSalary); err != nil {
+			return nil, err
 		}
-		payslips = append(payslips, *p)
+		months = append(months, m)
 	}
-	return payslips, total, rows.Err()
+	return months, rows.Err()
+}
+
+// AnnualTaxTotals returns each employee's final pay and tax for a year.
+func (r *PayslipRepository) AnnualTaxTotals(year int) ([]models.AnnualReturnLine, error) {
+	rows, err := r.db.Query(`
+		SELECT e.id, e.employee_number, CONCAT(e.first_name, ' ', e.last_name), COALESCE(e.national_id, ''),
+		       COUNT(DISTINCT p.month), `+taxAmountsCols+`
+		FROM payslips p
+		JOIN employees e ON p.employee_id = e.id
+		WHERE p.year=$1 AND p.status='final'
+		GROUP BY e.id, e.employee_number, e.first_name, e.last_name, e.national_id
+		ORDER BY e.last_name, e.first_name`, year)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lines []models.AnnualReturnLine
+	for rows.Next() {
+		var l models.AnnualReturnLine
+		a := &l.TaxAmounts
+		if err := rows.Scan(&l.EmployeeID, &l.EmployeeNumber, &l.EmployeeName, &l.NationalID, &l.MonthsPaid,
+			&a.BasicSalary, &a.Allowances, &a.OvertimePay, &a.BonusPay,
+			&a.LeavePay, &a.GrossSalary, &a.IncomeTax, &a.NetSalary); err != nil {
+			return nil, err
+		}
+		lines = append(lines, l)
+	}
+	return lines, rows.Err()
 }
 
 func (r *PayslipRepository) Delete(id uuid.UUID) error {
@@ -139,20 +463,67 @@ func (r *PayslipRepository) Delete(id uuid.UUID) error {
 	return nil
 }
 
+func (r *PayslipRepository) scanRows(rows *sql.Rows) ([]models.Payslip, error) {
+	var payslips []models.Payslip
+	for rows.Next() {
+		p, err := r.scanRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		payslips = append(payslips, *p)
+	}
+	return payslips, rows.Err()
+}
+
 func (r *PayslipRepository) scanOne(row *sql.Row) (*models.Payslip, error) {
 	return r.scanRow(row)
 }
 
 func (r *PayslipRepository) scanRow(row rowScanner) (*models.Payslip, error) {
 	var p models.Payslip
+	var payrollID, originalID, voidedBy, batchID sql.NullString
+	var voidedAt sql.NullTime
+	var ytdGross, ytdTax, ytdOvertime, ytdBonus, ytdNet sql.Null[money.Money]
 	err := row.Scan(
-		&p.ID, &p.EmployeeID, &p.Month, &p.Year, &p.BaseSalary, &p.HousingAllowance,
-		&p.TransportAllowance, &p.MedicalAllowance, &p.GrossSalary, &p.IncomeTax,
-		&p.LeaveDays, &p.NetSalary, &p.CreatedAt, &p.UpdatedAt,
+		&p.ID, &payrollID, &p.EmployeeID, &p.Month, &p.Year, &p.PeriodStart, &p.PeriodEnd, &p.BaseSalary, &p.HousingAllowance,
+		&p.TransportAllowance, &p.MedicalAllowance, &p.OvertimeHours, &p.OvertimePay, &p.BonusPay, &p.GrossSalary, &p.IncomeTax,
+		&p.LeaveDays, &p.AdditionalEarnings, &p.Reimbursements, &p.PreTaxDeductions, &p.OtherDeductions,
+		&p.ArrearsPay, &p.ArrearsTax, &p.EncashedLeave, &p.LeaveEncashment, &p.NoticePay, &p.Gratuity, &p.LoanDeduction, &p.NetSalary, &p.Currency, &p.Status, &p.PayslipType, &originalID,
+		&p.AdjustmentReason, &p.VoidReason, &voidedBy, &voidedAt, &batchID,
+		&ytdGross, &ytdTax, &ytdOvertime, &ytdBonus, &ytdNet, &p.CreatedAt, &p.UpdatedAt,
 		&p.EmployeeName, &p.PositionName,
 	)
 	if err != nil {
 		return nil, err
 	}
+	if payrollID.Valid {
+		id, _ := uuid.Parse(payrollID.String)
+		p.PayrollID = &id
+	}
+	if originalID.Valid {
+		id, _ := uuid.Parse(originalID.String)
+		p.OriginalPayslipID = &id
+	}
+	if voidedBy.Valid {
+		id, _ := uuid.Parse(voidedBy.String)
+		p.VoidedBy = &id
+	}
+	if voidedAt.Valid {
+		p.VoidedAt = &voidedAt.Time
+	}
+	if ytdGross.Valid {
+		p.YTD = &models.PayslipYTD{
+			Year:        p.Year,
+			GrossSalary: ytdGross.V,
+			IncomeTax:   ytdTax.V,
+			OvertimePay: ytdOvertime.V,
+			BonusPay:    ytdBonus.V,
+			NetSalary:   ytdNet.V,
+		}
+	}
+	if batchID.Valid {
+		id, _ := uuid.Parse(batchID.String)
+		p.PaymentBatchID = &id
+	}
 	return &p, nil
 }
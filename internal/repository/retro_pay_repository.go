@@ -0,0 +1,226 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"hr-system/internal/database"
+	"hr-system/internal/models"
+	"hr-system/pkg/money"
+
+	"github.com/google/uuid"
+)
+
+type RetroPayRepository struct {
+	db *sql.DB
+}
+
+func NewRetroPayRepository() *RetroPayRepository {
+	return &RetroPayRepository{db: database.DB}
+}
+
+// Arrears count as paid while they are on a payslip that has not been voided.
+const retroPaySelect = `
+	SELECT a.id, a.employee_id, a.effective_from, a.reason, a.status, a.gross_arrears, a.tax_arrears,
+	       a.net_arrears, a.payslip_id, a.created_by, a.created_at, a.updated_at,
+	       COALESCE(p.status <> 'void', false) AS paid,
+	       CONCAT(e.first_name, ' ', e.last_name) AS employee_name
+	FROM retro_pay_adjustments a
+	JOIN employees e ON a.employee_id = e.id
+	LEFT JOIN payslips p ON a.payslip_id = p.id`
+
+const retroPayUnpaid = `(a.payslip_id IS NULL OR EXISTS (
+		SELECT 1 FROM payslips vp WHERE vp.id = a.payslip_id AND vp.status = 'void'))`
+
+const retroPayLineCols = `
+	l.id, l.adjustment_id, l.payslip_id, l.month, l.year, l.period_start, l.period_end,
+	l.previous_gross, l.recalculated_gross, l.gross_difference, l.previous_tax, l.recalculated_tax,
+	l.tax_difference, l.net_difference`
+
+// Create stores an adjustment with its per-period lines.
+func (r *RetroPayRepository) Create(a *models.RetroPayAdjustment) error {
+	a.ID = uuid.New()
+	now := time.Now()
+	a.CreatedAt = now
+	a.UpdatedAt = now
+	if a.Status == "" {
+		a.Status = models.RetroPayStatusPending
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		INSERT INTO retro_pay_adjustments (id, employee_id, effective_from, reason, status, gross_arrears, tax_arrears, net_arrears, created_by, created_at, updated_at)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11)`,
+		a.ID, a.EmployeeID, a.EffectiveFrom, a.Reason, a.Status, a.GrossArrears, a.TaxArrears, a.NetArrears,
+		a.CreatedBy, a.CreatedAt, a.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	for i := range a.Lines {
+		l := &a.Lines[i]
+		l.ID = uuid.New()
+		l.AdjustmentID = a.ID
+		_, err := tx.Exec(`
+			INSERT INTO retro_pay_lines (id, adjustment_id, payslip_id, month, year, period_start, period_end, previous_gross, recalculated_gross, gross_difference, previous_tax, recalculated_tax, tax_difference, net_difference)
+			VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14)`,
+			l.ID, l.AdjustmentID, l.PayslipID, l.Month, l.Year, l.PeriodStart, l.PeriodEnd, l.PreviousGross,
+			l.RecalculatedGross, l.GrossDifference, l.PreviousTax, l.RecalculatedTax, l.TaxDifference, l.NetDifference,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetByID returns an adjustment with its lines
+func (r *RetroPayRepository) GetByID(id uuid.UUID) (*models.RetroPayAdjustment, error) {
+	a, err := r.scanRow(r.db.QueryRow(retroPaySelect+` WHERE a.id=$1`, id))
+	if err != nil {
+		return nil, err
+	}
+	a.Lines, err = r.listLines(`l.adjustment_id=$1`, id)
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// List returns adjustments, optionally for one employee and by state: pending (not yet
+// paid), paid or cancelled.
+func (r *RetroPayRepository) List(employeeID *uuid.UUID, state string) ([]models.RetroPayAdjustment, error) {
+	var where []string
+	var args []interface{}
+	if employeeID != nil {
+		args = append(args, *employeeID)
+		where = append(where, fmt.Sprintf("a.employee_id=$%d", len(args)))
+	}
+	switch state {
+	case "pending":
+		where = append(where, "a.status='pending' AND "+retroPayUnpaid)
+	case "paid":
+		where = append(where, "a.status='pending' AND NOT "+retroPayUnpaid)
+	case "cancelled":
+		where = append(where, "a.status='cancelled'")
+	}
+	query := retroPaySelect
+	if len(where) > 0 {
+		query += ` WHERE ` + strings.Join(where, " AND ")
+	}
+	rows, err := r.db.Query(query+` ORDER BY a.created_at DESC`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return r.scanRows(rows)
+}
+
+// ListPayable returns an employee's unpaid arrears whose recalculated periods all end
+// before the given date, so they can go on the payslip for a period starting then.
+func (r *RetroPayRepository) ListPayable(employeeID uuid.UUID, before time.Time) ([]models.RetroPayAdjustment, error) {
+	rows, err := r.db.Query(retroPaySelect+`
+		WHERE a.employee_id=$1 AND a.status='pending' AND `+retroPayUnpaid+`
+		  AND NOT EXISTS (SELECT 1 FROM retro_pay_lines l WHERE l.adjustment_id = a.id AND l.period_end >= $2)
+		ORDER BY a.created_at`, employeeID, before)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	adjustments, err := r.scanRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	for i := range adjustments {
+		adjustments[i].Lines, err = r.listLines(`l.adjustment_id=$1`, adjustments[i].ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return adjustments, nil
+}
+
+// ListLinesByPayslip returns the per-period arrears paid on a payslip
+func (r *RetroPayRepository) ListLinesByPayslip(payslipID uuid.UUID) ([]models.RetroPayLine, error) {
+	return r.listLines(`a.payslip_id=$1 AND a.status='pending'`, payslipID)
+}
+
+// PeriodArrears totals the gross and tax differences already owed or paid to an employee
+// for a pay period by earlier retro calculations that were not cancelled.
+func (r *RetroPayRepository) PeriodArrears(employeeID uuid.UUID, start, end time.Time) (gross, tax money.Money, err error) {
+	err = r.db.QueryRow(`
+		SELECT COALESCE(SUM(l.gross_difference), 0), COALESCE(SUM(l.tax_difference), 0)
+		FROM retro_pay_lines l
+		JOIN retro_pay_adjustments a ON l.adjustment_id = a.id
+		WHERE a.employee_id=$1 AND l.period_start=$2 AND l.period_end=$3 AND a.status='pending'`,
+		employeeID, start, end).Scan(&gross, &tax)
+	return gross, tax, err
+}
+
+// Cancel withdraws arrears that have not been paid yet.
+func (r *RetroPayRepository) Cancel(id uuid.UUID) error {
+	res, err := r.db.Exec(`
+		UPDATE retro_pay_adjustments a SET status='cancelled', updated_at=NOW()
+		WHERE a.id=$1 AND a.status='pending' AND `+retroPayUnpaid, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("retro pay adjustment not found, already paid or cancelled")
+	}
+	return nil
+}
+
+func (r *RetroPayRepository) listLines(where string, args ...interface{}) ([]models.RetroPayLine, error) {
+	rows, err := r.db.Query(`
+		SELECT `+retroPayLineCols+`
+		FROM retro_pay_lines l
+		JOIN retro_pay_adjustments a ON l.adjustment_id = a.id
+		WHERE `+where+`
+		ORDER BY l.period_start, a.created_at`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lines []models.RetroPayLine
+	for rows.Next() {
+		var l models.RetroPayLine
+		if err := rows.Scan(&l.ID, &l.AdjustmentID, &l.PayslipID, &l.Month, &l.Year, &l.PeriodStart, &l.PeriodEnd,
+			&l.PreviousGross, &l.RecalculatedGross, &l.GrossDifference, &l.PreviousTax, &l.RecalculatedTax,
+			&l.TaxDifference, &l.NetDifference); err != nil {
+			return nil, err
+		}
+		lines = append(lines, l)
+	}
+	return lines, rows.Err()
+}
+
+func (r *RetroPayRepository) scanRows(rows *sql.Rows) ([]models.RetroPayAdjustment, error) {
+	var adjustments []models.RetroPayAdjustment
+	for rows.Next() {
+		a, err := r.scanRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		adjustments = append(adjustments, *a)
+	}
+	return adjustments, rows.Err()
+}
+
+func (r *RetroPayRepository) scanRow(row rowScanner) (*models.RetroPayAdjustment, error) {
+	var a models.RetroPayAdjustment
+	err := row.Scan(&a.ID, &a.EmployeeID, &a.EffectiveFrom, &a.Reason, &a.Status, &a.GrossArrears, &a.TaxArrears,
+		&a.NetArrears, &a.PayslipID, &a.CreatedBy, &a.CreatedAt, &a.UpdatedAt, &a.Paid, &a.EmployeeName)
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"net/http"
+
+	"hr-system/internal/handlers"
+)
+
+func RegisterCalendarFeedRoutes(h *handlers.CalendarFeedHandler) {
+	// My calendar subscription URLs - any authenticated employee
+	http.HandleFunc("GET /api/v1/hr/calendar-feeds/me",
+		withAuth(h.GetMyFeeds))
+
+	// Issue new subscription URLs, revoking the old ones
+	http.HandleFunc("POST /api/v1/hr/calendar-feeds/me/reset",
+		withAuth(h.ResetMyFeeds))
+
+	// iCalendar feeds for calendar apps, authenticated by the token in the URL
+	http.HandleFunc("GET /api/v1/calendar/{token}/leave.ics",
+		withPublic(h.MyLeave))
+
+	http.HandleFunc("GET /api/v1/calendar/{token}/team-leave.ics",
+		withPublic(h.TeamLeave))
+
+	http.HandleFunc("GET /api/v1/calendar/{token}/holidays.ics",
+		withPublic(h.Holidays))
+}
@@ -0,0 +1,34 @@
+package routes
+
+import (
+	"net/http"
+
+	"hr-system/internal/handlers"
+	"hr-system/internal/models"
+)
+
+func RegisterFinalSettlementRoutes(h *handlers.FinalSettlementHandler) {
+	// List final settlements - requires SuperAdmin or HRManager
+	http.HandleFunc("GET /api/v1/hr/final-settlements",
+		withAuthAndRole(h.List, models.RoleSuperAdmin, models.RoleHRManager))
+
+	// Get settlement statement - requires SuperAdmin or HRManager
+	http.HandleFunc("GET /api/v1/hr/final-settlements/{id}",
+		withAuthAndRole(h.GetByID, models.RoleSuperAdmin, models.RoleHRManager))
+
+	// Approve settlement and issue the final payslip - requires SuperAdmin or HRManager
+	http.HandleFunc("POST /api/v1/hr/final-settlements/{id}/approve",
+		withAuthAndRole(h.Approve, models.RoleSuperAdmin, models.RoleHRManager))
+
+	// Cancel pending settlement - requires SuperAdmin or HRManager
+	http.HandleFunc("POST /api/v1/hr/final-settlements/{id}/cancel",
+		withAuthAndRole(h.Cancel, models.RoleSuperAdmin, models.RoleHRManager))
+
+	// Get an employee's settlement - requires SuperAdmin or HRManager
+	http.HandleFunc("GET /api/v1/hr/employees/{id}/final-settlement",
+		withAuthAndRole(h.GetForEmployee, models.RoleSuperAdmin, models.RoleHRManager))
+
+	// Prepare or recalculate an employee's settlement - requires SuperAdmin or HRManager
+	http.HandleFunc("POST /api/v1/hr/employees/{id}/final-settlement",
+		withAuthAndRole(h.Prepare, models.RoleSuperAdmin, models.RoleHRManager))
+}
@@ -0,0 +1,30 @@
+package routes
+
+import (
+	"net/http"
+
+	"hr-system/internal/handlers"
+	"hr-system/internal/models"
+)
+
+func RegisterGLRoutes(h *handlers.GLJournalHandler) {
+	// List mappable payroll components - requires SuperAdmin or HRManager
+	http.HandleFunc("GET /api/v1/hr/gl-components",
+		withAuthAndRole(h.Components, models.RoleSuperAdmin, models.RoleHRManager))
+
+	// List GL account mappings - requires SuperAdmin or HRManager
+	http.HandleFunc("GET /api/v1/hr/gl-mappings",
+		withAuthAndRole(h.ListMappings, models.RoleSuperAdmin, models.RoleHRManager))
+
+	// Create or replace GL account mapping - requires SuperAdmin or HRManager
+	http.HandleFunc("PUT /api/v1/hr/gl-mappings",
+		withAuthAndRole(h.SaveMapping, models.RoleSuperAdmin, models.RoleHRManager))
+
+	// Delete GL account mapping - requires SuperAdmin or HRManager
+	http.HandleFunc("DELETE /api/v1/hr/gl-mappings/{id}",
+		withAuthAndRole(h.DeleteMapping, models.RoleSuperAdmin, models.RoleHRManager))
+
+	// GL journal for a completed payroll (JSON, or ?format=csv) - requires SuperAdmin or HRManager
+	http.HandleFunc("GET /api/v1/hr/payrolls/{id}/journal",
+		withAuthAndRole(h.Journal, models.RoleSuperAdmin, models.RoleHRManager))
+}
@@ -29,12 +29,21 @@ func RegisterLeaveRoutes(
 	http.HandleFunc("GET /api/v1/hr/leave-balances/me",
 		withAuth(lbH.GetMyBalances))
 
+	http.HandleFunc("GET /api/v1/hr/leave-balances/leave-year",
+		withAuth(lbH.LeaveYear))
+
+	http.HandleFunc("POST /api/v1/hr/leave-balances/rebase",
+		withAuthAndRole(lrH.RebaseLeaveYears, models.RoleSuperAdmin, models.RoleHRManager))
+
 	http.HandleFunc("POST /api/v1/hr/leave-balances/initialize/{year}",
 		withAuthAndRole(lbH.Initialize, models.RoleSuperAdmin, models.RoleHRManager))
 
 	http.HandleFunc("GET /api/v1/hr/leave-balances/employee/{id}",
 		withAuthAndRole(lbH.GetByEmployee, models.RoleSuperAdmin, models.RoleHRManager, models.RoleManager))
 
+	http.HandleFunc("GET /api/v1/hr/leave-balances/employee/{id}/ledger",
+		withAuthAndRole(lbH.GetLedger, models.RoleSuperAdmin, models.RoleHRManager, models.RoleManager))
+
 	http.HandleFunc("POST /api/v1/hr/leave-balances/adjust/{id}",
 		withAuthAndRole(lbH.Adjust, models.RoleSuperAdmin, models.RoleHRManager))
 
@@ -48,9 +57,19 @@ func RegisterLeaveRoutes(
 	http.HandleFunc("POST /api/v1/hr/leave-requests",
 		withAuth(lrH.Create))
 
+	http.HandleFunc("POST /api/v1/hr/employees/{id}/leave-requests",
+		withAuthAndRole(lrH.CreateForEmployee, models.RoleSuperAdmin, models.RoleHRManager))
+
+	// HR amends an employee's leave, overriding leave rules with a reason
+	http.HandleFunc("PUT /api/v1/hr/employees/{id}/leave-requests/{rid}",
+		withAuthAndRole(lrH.AmendForEmployee, models.RoleSuperAdmin, models.RoleHRManager))
+
 	http.HandleFunc("GET /api/v1/hr/leave-requests/{id}",
 		withAuth(lrH.GetByID))
 
+	http.HandleFunc("PUT /api/v1/hr/leave-requests/{id}",
+		withAuth(lrH.Amend))
+
 	http.HandleFunc("POST /api/v1/hr/leave-requests/{id}/cancel",
 		withAuth(lrH.Cancel))
 
@@ -59,6 +78,10 @@ func RegisterLeaveRoutes(
 
 	http.HandleFunc("POST /api/v1/hr/leave-requests/{id}/reject",
 		withAuthAndRole(lrH.Reject, models.RoleSuperAdmin, models.RoleHRManager, models.RoleManager))
+
+	// Leave Calendar - managers see their own reporting line only
+	http.HandleFunc("GET /api/v1/leave/calendar",
+		withAuthAndRole(lrH.Calendar, models.RoleSuperAdmin, models.RoleHRManager, models.RoleManager))
 }
 
 func RegisterAttendanceRoutes(h *handlers.AttendanceHandler) {
@@ -85,6 +108,10 @@ func RegisterAttendanceRoutes(h *handlers.AttendanceHandler) {
 
 	http.HandleFunc("PUT /api/v1/hr/attendance/{id}",
 		withAuthAndRole(h.Update, models.RoleSuperAdmin, models.RoleHRManager))
+
+	// Managers can approve overtime for their own reports only
+	http.HandleFunc("POST /api/v1/hr/attendance/{id}/approve-overtime",
+		withAuthAndRole(h.ApproveOvertime, models.RoleSuperAdmin, models.RoleHRManager, models.RoleManager))
 }
 
 func RegisterHolidayRoutes(h *handlers.HolidayHandler) {
@@ -100,3 +127,54 @@ func RegisterHolidayRoutes(h *handlers.HolidayHandler) {
 	http.HandleFunc("DELETE /api/v1/hr/holidays/{id}",
 		withAuthAndRole(h.Delete, models.RoleSuperAdmin, models.RoleHRManager))
 }
+
+func RegisterLeaveBlackoutRoutes(h *handlers.LeaveBlackoutHandler) {
+	http.HandleFunc("GET /api/v1/hr/leave-blackouts",
+		withAuth(h.List))
+
+	http.HandleFunc("POST /api/v1/hr/leave-blackouts",
+		withAuthAndRole(h.Create, models.RoleSuperAdmin, models.RoleHRManager))
+
+	http.HandleFunc("PUT /api/v1/hr/leave-blackouts/{id}",
+		withAuthAndRole(h.Update, models.RoleSuperAdmin, models.RoleHRManager))
+
+	http.HandleFunc("DELETE /api/v1/hr/leave-blackouts/{id}",
+		withAuthAndRole(h.Delete, models.RoleSuperAdmin, models.RoleHRManager))
+}
+
+func RegisterLeaveDocumentRoutes(h *handlers.LeaveDocumentHandler) {
+	http.HandleFunc("POST /api/v1/hr/leave-requests/{id}/attachments",
+		withAuth(h.Upload))
+
+	http.HandleFunc("GET /api/v1/hr/leave-requests/{id}/attachments/{aid}",
+		withAuth(h.Download))
+
+	http.HandleFunc("POST /api/v1/hr/leave-requests/{id}/document-received",
+		withAuthAndRole(h.MarkReceived, models.RoleSuperAdmin, models.RoleHRManager))
+}
+
+func RegisterLeaveEncashmentRoutes(h *handlers.LeaveEncashmentHandler) {
+	// Get my leave encashments - any authenticated employee
+	http.HandleFunc("GET /api/v1/hr/leave-encashments/me",
+		withAuth(h.GetMine))
+
+	// Request a leave encashment - any authenticated employee
+	http.HandleFunc("POST /api/v1/hr/leave-encashments/me",
+		withAuth(h.RequestMine))
+
+	// List leave encashments, optionally by status - requires SuperAdmin or HRManager
+	http.HandleFunc("GET /api/v1/hr/leave-encashments",
+		withAuthAndRole(h.List, models.RoleSuperAdmin, models.RoleHRManager))
+
+	// Get a leave encashment - own encashments, or any for SuperAdmin/HRManager
+	http.HandleFunc("GET /api/v1/hr/leave-encashments/{id}",
+		withAuth(h.GetByID))
+
+	// Cancel a pending leave encashment - own encashments, or any for SuperAdmin/HRManager
+	http.HandleFunc("POST /api/v1/hr/leave-encashments/{id}/cancel",
+		withAuth(h.Cancel))
+
+	// Employee leave encashments - requires SuperAdmin or HRManager
+	http.HandleFunc("GET /api/v1/hr/employees/{id}/leave-encashments",
+		withAuthAndRole(h.ListForEmployee, models.RoleSuperAdmin, models.RoleHRManager))
+}
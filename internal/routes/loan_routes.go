@@ -0,0 +1,46 @@
+package routes
+
+import (
+	"net/http"
+
+	"hr-system/internal/handlers"
+	"hr-system/internal/models"
+)
+
+func RegisterLoanRoutes(h *handlers.LoanHandler) {
+	// Get my loans - any authenticated employee
+	http.HandleFunc("GET /api/v1/hr/loans/me",
+		withAuth(h.GetMine))
+
+	// Request a loan or salary advance - any authenticated employee
+	http.HandleFunc("POST /api/v1/hr/loans/me",
+		withAuth(h.RequestMine))
+
+	// List loans, optionally by status - requires SuperAdmin or HRManager
+	http.HandleFunc("GET /api/v1/hr/loans",
+		withAuthAndRole(h.List, models.RoleSuperAdmin, models.RoleHRManager))
+
+	// Get loan with repayments - own loans, or any for SuperAdmin/HRManager
+	http.HandleFunc("GET /api/v1/hr/loans/{id}",
+		withAuth(h.GetByID))
+
+	// Change terms of a pending loan - requires SuperAdmin or HRManager
+	http.HandleFunc("PUT /api/v1/hr/loans/{id}",
+		withAuthAndRole(h.UpdateTerms, models.RoleSuperAdmin, models.RoleHRManager))
+
+	// Cancel a pending loan - own loans, or any for SuperAdmin/HRManager
+	http.HandleFunc("POST /api/v1/hr/loans/{id}/cancel",
+		withAuth(h.Cancel))
+
+	// Record an early settlement - requires SuperAdmin or HRManager
+	http.HandleFunc("POST /api/v1/hr/loans/{id}/settle",
+		withAuthAndRole(h.Settle, models.RoleSuperAdmin, models.RoleHRManager))
+
+	// Employee loans and balances - requires SuperAdmin or HRManager
+	http.HandleFunc("GET /api/v1/hr/employees/{id}/loans",
+		withAuthAndRole(h.ListForEmployee, models.RoleSuperAdmin, models.RoleHRManager))
+
+	// Record a loan for an employee - requires SuperAdmin or HRManager
+	http.HandleFunc("POST /api/v1/hr/employees/{id}/loans",
+		withAuthAndRole(h.CreateForEmployee, models.RoleSuperAdmin, models.RoleHRManager))
+}
@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"net/http"
+
+	"hr-system/internal/handlers"
+	"hr-system/internal/models"
+)
+
+func RegisterOvertimeRoutes(h *handlers.OvertimeHandler) {
+	// Get overtime rules - requires SuperAdmin or HRManager
+	http.HandleFunc("GET /api/v1/hr/overtime-rules",
+		withAuthAndRole(h.GetRules, models.RoleSuperAdmin, models.RoleHRManager))
+
+	// Update overtime rules - requires SuperAdmin or HRManager
+	http.HandleFunc("PUT /api/v1/hr/overtime-rules",
+		withAuthAndRole(h.UpdateRules, models.RoleSuperAdmin, models.RoleHRManager))
+}
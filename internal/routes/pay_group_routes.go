@@ -0,0 +1,42 @@
+package routes
+
+import (
+	"net/http"
+
+	"hr-system/internal/handlers"
+	"hr-system/internal/models"
+)
+
+func RegisterPayGroupRoutes(h *handlers.PayGroupHandler) {
+	// List pay groups - requires SuperAdmin or HRManager
+	http.HandleFunc("GET /api/v1/hr/pay-groups",
+		withAuthAndRole(h.List, models.RoleSuperAdmin, models.RoleHRManager))
+
+	// Create pay group - requires SuperAdmin or HRManager
+	http.HandleFunc("POST /api/v1/hr/pay-groups",
+		withAuthAndRole(h.Create, models.RoleSuperAdmin, models.RoleHRManager))
+
+	// Get pay group - requires SuperAdmin or HRManager
+	http.HandleFunc("GET /api/v1/hr/pay-groups/{id}",
+		withAuthAndRole(h.GetByID, models.RoleSuperAdmin, models.RoleHRManager))
+
+	// Update pay group - requires SuperAdmin or HRManager
+	http.HandleFunc("PUT /api/v1/hr/pay-groups/{id}",
+		withAuthAndRole(h.Update, models.RoleSuperAdmin, models.RoleHRManager))
+
+	// Delete unused pay group - requires SuperAdmin or HRManager
+	http.HandleFunc("DELETE /api/v1/hr/pay-groups/{id}",
+		withAuthAndRole(h.Delete, models.RoleSuperAdmin, models.RoleHRManager))
+
+	// List members - requires SuperAdmin or HRManager
+	http.HandleFunc("GET /api/v1/hr/pay-groups/{id}/members",
+		withAuthAndRole(h.Members, models.RoleSuperAdmin, models.RoleHRManager))
+
+	// Assign employees to the group - requires SuperAdmin or HRManager
+	http.HandleFunc("POST /api/v1/hr/pay-groups/{id}/members",
+		withAuthAndRole(h.AssignMembers, models.RoleSuperAdmin, models.RoleHRManager))
+
+	// Pay calendar for a year - requires SuperAdmin or HRManager
+	http.HandleFunc("GET /api/v1/hr/pay-groups/{id}/calendar",
+		withAuthAndRole(h.Calendar, models.RoleSuperAdmin, models.RoleHRManager))
+}
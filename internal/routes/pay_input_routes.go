@@ -0,0 +1,38 @@
+package routes
+
+import (
+	"net/http"
+
+	"hr-system/internal/handlers"
+	"hr-system/internal/models"
+)
+
+func RegisterPayInputRoutes(h *handlers.PayInputHandler) {
+	// List earning and deduction categories - requires SuperAdmin or HRManager
+	http.HandleFunc("GET /api/v1/hr/pay-input-categories",
+		withAuthAndRole(h.Categories, models.RoleSuperAdmin, models.RoleHRManager))
+
+	// List inputs for a pay period - requires SuperAdmin or HRManager
+	http.HandleFunc("GET /api/v1/hr/pay-inputs",
+		withAuthAndRole(h.List, models.RoleSuperAdmin, models.RoleHRManager))
+
+	// Record a one-off earning or deduction - requires SuperAdmin or HRManager
+	http.HandleFunc("POST /api/v1/hr/pay-inputs",
+		withAuthAndRole(h.Create, models.RoleSuperAdmin, models.RoleHRManager))
+
+	// Upload a CSV of inputs for a pay period - requires SuperAdmin or HRManager
+	http.HandleFunc("POST /api/v1/hr/pay-inputs/upload",
+		withAuthAndRole(h.Upload, models.RoleSuperAdmin, models.RoleHRManager))
+
+	// Pre-processing validation report for a pay period - requires SuperAdmin or HRManager
+	http.HandleFunc("GET /api/v1/hr/pay-inputs/validation",
+		withAuthAndRole(h.ValidatePeriod, models.RoleSuperAdmin, models.RoleHRManager))
+
+	// Change an unprocessed input - requires SuperAdmin or HRManager
+	http.HandleFunc("PUT /api/v1/hr/pay-inputs/{id}",
+		withAuthAndRole(h.Update, models.RoleSuperAdmin, models.RoleHRManager))
+
+	// Delete an unprocessed input - requires SuperAdmin or HRManager
+	http.HandleFunc("DELETE /api/v1/hr/pay-inputs/{id}",
+		withAuthAndRole(h.Delete, models.RoleSuperAdmin, models.RoleHRManager))
+}
@@ -0,0 +1,54 @@
+package routes
+
+import (
+	"net/http"
+
+	"hr-system/internal/handlers"
+	"hr-system/internal/models"
+)
+
+func RegisterPaymentRoutes(bh *handlers.BankAccountHandler, ph *handlers.PaymentBatchHandler) {
+	// Get my bank accounts - any authenticated employee
+	http.HandleFunc("GET /api/v1/hr/bank-accounts/me",
+		withAuth(bh.GetMine))
+
+	// Request a change to my bank details - any authenticated employee
+	http.HandleFunc("POST /api/v1/hr/bank-accounts/me",
+		withAuth(bh.SubmitMine))
+
+	// List bank account changes awaiting approval - requires SuperAdmin or HRManager
+	http.HandleFunc("GET /api/v1/hr/bank-accounts/pending",
+		withAuthAndRole(bh.ListPending, models.RoleSuperAdmin, models.RoleHRManager))
+
+	// Approve bank account change - requires SuperAdmin or HRManager
+	http.HandleFunc("POST /api/v1/hr/bank-accounts/{id}/approve",
+		withAuthAndRole(bh.Approve, models.RoleSuperAdmin, models.RoleHRManager))
+
+	// Reject bank account change - requires SuperAdmin or HRManager
+	http.HandleFunc("POST /api/v1/hr/bank-accounts/{id}/reject",
+		withAuthAndRole(bh.Reject, models.RoleSuperAdmin, models.RoleHRManager))
+
+	// Employee bank account history - requires SuperAdmin or HRManager
+	http.HandleFunc("GET /api/v1/hr/employees/{id}/bank-accounts",
+		withAuthAndRole(bh.ListForEmployee, models.RoleSuperAdmin, models.RoleHRManager))
+
+	// Submit bank details for an employee - requires SuperAdmin or HRManager
+	http.HandleFunc("POST /api/v1/hr/employees/{id}/bank-accounts",
+		withAuthAndRole(bh.SubmitForEmployee, models.RoleSuperAdmin, models.RoleHRManager))
+
+	// Supported bank file formats - requires SuperAdmin or HRManager
+	http.HandleFunc("GET /api/v1/hr/payment-formats",
+		withAuthAndRole(ph.Formats, models.RoleSuperAdmin, models.RoleHRManager))
+
+	// Generate bank transfer file for a completed payroll - requires SuperAdmin or HRManager
+	http.HandleFunc("POST /api/v1/hr/payrolls/{id}/payment-batches",
+		withAuthAndRole(ph.Generate, models.RoleSuperAdmin, models.RoleHRManager))
+
+	// List payment batches for a payroll - requires SuperAdmin or HRManager
+	http.HandleFunc("GET /api/v1/hr/payrolls/{id}/payment-batches",
+		withAuthAndRole(ph.ListByPayroll, models.RoleSuperAdmin, models.RoleHRManager))
+
+	// Download payment file - requires SuperAdmin or HRManager
+	http.HandleFunc("GET /api/v1/hr/payment-batches/{id}/download",
+		withAuthAndRole(ph.Download, models.RoleSuperAdmin, models.RoleHRManager))
+}
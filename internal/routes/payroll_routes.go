@@ -20,10 +20,38 @@ func RegisterPayrollRoutes(h *handlers.PayrollHandler) {
 	http.HandleFunc("GET /api/v1/hr/payrolls/{id}",
 		withAuthAndRole(h.GetByID, models.RoleSuperAdmin, models.RoleHRManager))
 
-	// Process payroll (generate all payslips) - requires SuperAdmin or HRManager
+	// Process payroll (generate draft payslips) - requires SuperAdmin or HRManager
 	http.HandleFunc("POST /api/v1/hr/payrolls/{id}/process",
 		withAuthAndRole(h.Process, models.RoleSuperAdmin, models.RoleHRManager))
 
+	// Per-employee run status and failure reasons - requires SuperAdmin or HRManager
+	http.HandleFunc("GET /api/v1/hr/payrolls/{id}/items",
+		withAuthAndRole(h.RunItems, models.RoleSuperAdmin, models.RoleHRManager))
+
+	// Retry employees that failed during processing - requires SuperAdmin or HRManager
+	http.HandleFunc("POST /api/v1/hr/payrolls/{id}/retry-failed",
+		withAuthAndRole(h.RetryFailed, models.RoleSuperAdmin, models.RoleHRManager))
+
+	// Variance report against the previous period - requires SuperAdmin or HRManager
+	http.HandleFunc("GET /api/v1/hr/payrolls/{id}/variance",
+		withAuthAndRole(h.Variance, models.RoleSuperAdmin, models.RoleHRManager))
+
+	// Approve draft payroll (finalise payslips) - requires SuperAdmin or HRManager
+	http.HandleFunc("POST /api/v1/hr/payrolls/{id}/approve",
+		withAuthAndRole(h.Approve, models.RoleSuperAdmin, models.RoleHRManager))
+
+	// Reject draft payroll (discard payslips) - requires SuperAdmin or HRManager
+	http.HandleFunc("POST /api/v1/hr/payrolls/{id}/reject",
+		withAuthAndRole(h.Reject, models.RoleSuperAdmin, models.RoleHRManager))
+
+	// Reverse completed, unpaid payroll (void payslips, reopen for re-run) - requires SuperAdmin or HRManager
+	http.HandleFunc("POST /api/v1/hr/payrolls/{id}/reverse",
+		withAuthAndRole(h.Reverse, models.RoleSuperAdmin, models.RoleHRManager))
+
+	// Issue supplementary off-cycle payslip - requires SuperAdmin or HRManager
+	http.HandleFunc("POST /api/v1/hr/payrolls/{id}/supplementary",
+		withAuthAndRole(h.Supplementary, models.RoleSuperAdmin, models.RoleHRManager))
+
 	// Cancel payroll - requires SuperAdmin or HRManager
 	http.HandleFunc("POST /api/v1/hr/payrolls/{id}/cancel",
 		withAuthAndRole(h.Cancel, models.RoleSuperAdmin, models.RoleHRManager))
@@ -24,6 +24,10 @@ func RegisterPayslipRoutes(h *handlers.PayslipHandler) {
 	http.HandleFunc("GET /api/v1/hr/payslips/{id}",
 		withAuth(h.GetByID))
 
+	// Download payslip as PDF - own payslips, or any for SuperAdmin/HRManager
+	http.HandleFunc("GET /api/v1/hr/payslips/{id}/pdf",
+		withAuth(h.DownloadPDF))
+
 	// Delete payslip - requires SuperAdmin
 	http.HandleFunc("DELETE /api/v1/hr/payslips/{id}",
 		withAuthAndRole(h.Delete, models.RoleSuperAdmin))
@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"net/http"
+
+	"hr-system/internal/handlers"
+	"hr-system/internal/models"
+)
+
+func RegisterRetroPayRoutes(h *handlers.RetroPayHandler) {
+	// Calculate or preview retro pay arrears - requires SuperAdmin or HRManager
+	http.HandleFunc("POST /api/v1/hr/retro-pay",
+		withAuthAndRole(h.Calculate, models.RoleSuperAdmin, models.RoleHRManager))
+
+	// List retro pay arrears - requires SuperAdmin or HRManager
+	http.HandleFunc("GET /api/v1/hr/retro-pay",
+		withAuthAndRole(h.List, models.RoleSuperAdmin, models.RoleHRManager))
+
+	// Get retro pay arrears with per-period breakdown - requires SuperAdmin or HRManager
+	http.HandleFunc("GET /api/v1/hr/retro-pay/{id}",
+		withAuthAndRole(h.GetByID, models.RoleSuperAdmin, models.RoleHRManager))
+
+	// Cancel unpaid arrears - requires SuperAdmin or HRManager
+	http.HandleFunc("POST /api/v1/hr/retro-pay/{id}/cancel",
+		withAuthAndRole(h.Cancel, models.RoleSuperAdmin, models.RoleHRManager))
+}
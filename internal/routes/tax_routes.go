@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"net/http"
+
+	"hr-system/internal/handlers"
+	"hr-system/internal/models"
+)
+
+func RegisterTaxRoutes(h *handlers.TaxCertificateHandler) {
+	// Get my annual tax certificate (JSON or ?format=pdf) - any authenticated employee
+	http.HandleFunc("GET /api/v1/hr/tax-certificates/me",
+		withAuth(h.MyCertificate))
+
+	// Get an employee's annual tax certificate - requires SuperAdmin or HRManager
+	http.HandleFunc("GET /api/v1/hr/employees/{id}/tax-certificate",
+		withAuthAndRole(h.EmployeeCertificate, models.RoleSuperAdmin, models.RoleHRManager))
+
+	// Get an employee's year-to-date totals - requires SuperAdmin or HRManager
+	http.HandleFunc("GET /api/v1/hr/employees/{id}/ytd",
+		withAuthAndRole(h.EmployeeYTD, models.RoleSuperAdmin, models.RoleHRManager))
+
+	// Employer annual return (JSON or ?format=csv) - requires SuperAdmin or HRManager
+	http.HandleFunc("GET /api/v1/hr/tax/annual-return",
+		withAuthAndRole(h.AnnualReturn, models.RoleSuperAdmin, models.RoleHRManager))
+}
@@ -10,6 +10,10 @@ import (
 	"github.com/google/uuid"
 )
 
+// ErrOvertimeApprovalScope is returned when a manager tries to approve overtime for someone
+// who does not report to them
+var ErrOvertimeApprovalScope = errors.New("you can only approve overtime for your own reports")
+
 type AttendanceService struct {
 	repo        *repository.AttendanceRepository
 	holidayRepo *repository.HolidayRepository
@@ -98,9 +102,48 @@ func (s *AttendanceService) Update(a *models.Attendance) error {
 	a.EmployeeID = existing.EmployeeID
 	a.Date = existing.Date
 	a.Source = models.AttendanceSourceManual
+
+	// Keep an existing approval only if the overtime hours are unchanged
+	if existing.OvertimeApproved && existing.OvertimeHours == a.OvertimeHours {
+		a.OvertimeApproved = true
+		a.OvertimeApprovedBy = existing.OvertimeApprovedBy
+		a.OvertimeApprovedAt = existing.OvertimeApprovedAt
+	} else {
+		a.OvertimeApproved = false
+		a.OvertimeApprovedBy = nil
+		a.OvertimeApprovedAt = nil
+	}
 	return s.repo.Update(a)
 }
 
+// ApproveOvertime approves the overtime recorded on an attendance record so it is paid in payroll.
+// Unless canApproveAny (HR and admins), only the employee's line manager can approve it.
+func (s *AttendanceService) ApproveOvertime(id, approverEmployeeID uuid.UUID, canApproveAny bool) (*models.Attendance, error) {
+	existing, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("attendance record not found")
+	}
+	if existing.EmployeeID == approverEmployeeID {
+		return nil, errors.New("you cannot approve your own overtime")
+	}
+	if !canApproveAny {
+		emp, err := s.empRepo.GetByID(existing.EmployeeID)
+		if err != nil {
+			return nil, errors.New("employee not found")
+		}
+		if emp.ManagerID == nil || *emp.ManagerID != approverEmployeeID {
+			return nil, ErrOvertimeApprovalScope
+		}
+	}
+	if existing.OvertimeHours <= 0 {
+		return nil, errors.New("attendance record has no overtime to approve")
+	}
+	if err := s.repo.ApproveOvertime(id, approverEmployeeID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(id)
+}
+
 func (s *AttendanceService) GetMonthlySummary(employeeID uuid.UUID, month, year int) (map[string]interface{}, error) {
 	return s.repo.GetMonthlySummary(employeeID, month, year)
 }
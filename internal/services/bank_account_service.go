@@ -0,0 +1,129 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"hr-system/internal/models"
+	"hr-system/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+var (
+	accountNumberPattern = regexp.MustCompile(`^[0-9]{6,20}$`)
+	bankCodePattern      = regexp.MustCompile(`^[A-Za-z0-9]{2,10}$`)
+)
+
+type BankAccountService struct {
+	repo    *repository.BankAccountRepository
+	empRepo *repository.EmployeeRepository
+}
+
+func NewBankAccountService(repo *repository.BankAccountRepository, empRepo *repository.EmployeeRepository) *BankAccountService {
+	return &BankAccountService{repo: repo, empRepo: empRepo}
+}
+
+// RequestChange submits new bank details for an employee. The details are held as
+// pending until HR approves them; the current approved account stays in use until then.
+func (s *BankAccountService) RequestChange(a *models.EmployeeBankAccount, requestedBy uuid.UUID) error {
+	if _, err := s.empRepo.GetByID(a.EmployeeID); err != nil {
+		return errors.New("employee not found")
+	}
+
+	a.BankName = strings.TrimSpace(a.BankName)
+	a.BankCode = strings.ToUpper(strings.TrimSpace(a.BankCode))
+	a.BranchCode = strings.TrimSpace(a.BranchCode)
+	a.AccountNumber = strings.ReplaceAll(strings.TrimSpace(a.AccountNumber), " ", "")
+	a.AccountName = strings.TrimSpace(a.AccountName)
+
+	switch {
+	case a.BankName == "" || a.AccountName == "":
+		return errors.New("bank_name and account_name are required")
+	case !bankCodePattern.MatchString(a.BankCode):
+		return errors.New("bank_code must be 2-10 letters or digits")
+	case a.BranchCode != "" && !bankCodePattern.MatchString(a.BranchCode):
+		return errors.New("branch_code must be 2-10 letters or digits")
+	case !accountNumberPattern.MatchString(a.AccountNumber):
+		return errors.New("account_number must be 6-20 digits")
+	}
+
+	if _, err := s.repo.GetByEmployeeAndStatus(a.EmployeeID, models.BankAccountPending); err == nil {
+		return errors.New("a bank account change is already awaiting approval")
+	}
+	if current, err := s.repo.GetByEmployeeAndStatus(a.EmployeeID, models.BankAccountApproved); err == nil &&
+		current.BankCode == a.BankCode && current.AccountNumber == a.AccountNumber && current.BranchCode == a.BranchCode {
+		return errors.New("these bank details are already on record")
+	}
+
+	a.Status = models.BankAccountPending
+	a.RequestedBy = &requestedBy
+	if err := s.repo.Create(a); err != nil {
+		return fmt.Errorf("failed to submit bank account: %w", err)
+	}
+	return nil
+}
+
+// Approve activates a pending account. Employees cannot approve changes to their own account.
+func (s *BankAccountService) Approve(id, reviewedBy uuid.UUID) (*models.EmployeeBankAccount, error) {
+	account, err := s.pendingForReview(id, reviewedBy)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.Approve(account.ID, reviewedBy); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(id)
+}
+
+func (s *BankAccountService) Reject(id, reviewedBy uuid.UUID, reason string) (*models.EmployeeBankAccount, error) {
+	if reason == "" {
+		return nil, errors.New("reason is required")
+	}
+	account, err := s.pendingForReview(id, reviewedBy)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.Reject(account.ID, reviewedBy, reason); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(id)
+}
+
+func (s *BankAccountService) pendingForReview(id, reviewedBy uuid.UUID) (*models.EmployeeBankAccount, error) {
+	account, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("bank account not found")
+	}
+	if account.Status != models.BankAccountPending {
+		return nil, fmt.Errorf("bank account is already %s", account.Status)
+	}
+	emp, err := s.empRepo.GetByID(account.EmployeeID)
+	if err != nil {
+		return nil, errors.New("employee not found")
+	}
+	if emp.UserID != nil && *emp.UserID == reviewedBy {
+		return nil, errors.New("you cannot review changes to your own bank account")
+	}
+	return account, nil
+}
+
+// GetApproved returns the account an employee is currently paid into.
+func (s *BankAccountService) GetApproved(employeeID uuid.UUID) (*models.EmployeeBankAccount, error) {
+	return s.repo.GetByEmployeeAndStatus(employeeID, models.BankAccountApproved)
+}
+
+func (s *BankAccountService) ListByEmployee(employeeID uuid.UUID) ([]models.EmployeeBankAccount, error) {
+	return s.repo.ListByEmployee(employeeID)
+}
+
+func (s *BankAccountService) ListPending() ([]models.EmployeeBankAccount, error) {
+	return s.repo.ListByStatus(models.BankAccountPending)
+}
+
+// GetEmployeeByUserID returns the employee record linked to the given user ID
+func (s *BankAccountService) GetEmployeeByUserID(userID uuid.UUID) (*models.Employee, error) {
+	return s.empRepo.GetByUserID(userID)
+}
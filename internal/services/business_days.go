@@ -2,6 +2,8 @@ package services
 
 import (
 	"time"
+
+	"hr-system/internal/models"
 )
 
 // CountBusinessDays counts working days between start and end (inclusive),
@@ -24,23 +26,46 @@ func CountBusinessDays(start, end time.Time, holidays map[string]bool) int {
 	return count
 }
 
+// CountLeaveDays counts the working days a leave request covers between start and end
+// (inclusive). A morning or afternoon on the start or end date counts as half a day.
+func CountLeaveDays(start, end time.Time, startPart, endPart models.LeaveDayPart, holidays map[string]bool) float64 {
+	days := float64(CountBusinessDays(start, end, holidays))
+	if days == 0 {
+		return 0
+	}
+	isWorkingDay := func(d time.Time) bool {
+		return !IsWeekend(d) && !holidays[d.Format("2006-01-02")]
+	}
+	if startPart != models.LeaveDayFull && isWorkingDay(start) {
+		days -= 0.5
+	}
+	if !end.Equal(start) && endPart != models.LeaveDayFull && isWorkingDay(end) {
+		days -= 0.5
+	}
+	return days
+}
+
 // IsWeekend returns true for Saturday and Sunday.
 func IsWeekend(d time.Time) bool {
 	wd := d.Weekday()
 	return wd == time.Saturday || wd == time.Sunday
 }
 
-// ProrateEntitlement computes the prorated entitled days for an employee hired mid-year.
-// It returns: floor(defaultDays * remainingMonths / 12)
-func ProrateEntitlement(defaultDays int, hireDate time.Time, year int) int {
-	yearEnd := time.Date(year, 12, 31, 0, 0, 0, 0, time.UTC)
+// ProrateEntitlement computes the prorated entitled days for an employee hired during a leave year.
+// It returns: defaultDays * remainingMonths / 12, rounded to two decimal places, where the
+// remaining months run from the hire month to the end of the leave year.
+func ProrateEntitlement(defaultDays int, hireDate time.Time, leaveYear models.LeaveYear, year int) float64 {
+	yearEnd := leaveYear.End(year)
 	if hireDate.After(yearEnd) {
 		return 0
 	}
+	if hireDate.Before(leaveYear.Start(year)) {
+		return float64(defaultDays)
+	}
 	// Remaining months including the hire month
-	remainingMonths := int(yearEnd.Month()) - int(hireDate.Month()) + 1
+	remainingMonths := (yearEnd.Year()-hireDate.Year())*12 + int(yearEnd.Month()) - int(hireDate.Month()) + 1
 	if remainingMonths <= 0 {
 		return 0
 	}
-	return defaultDays * remainingMonths / 12
+	return models.RoundLeaveDays(float64(defaultDays*remainingMonths) / 12)
 }
@@ -0,0 +1,245 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"hr-system/internal/config"
+	"hr-system/internal/models"
+	"hr-system/internal/repository"
+	"hr-system/internal/utils/ical"
+	"hr-system/pkg/utils"
+
+	"github.com/google/uuid"
+)
+
+// ErrFeedNotFound is returned for an unknown or revoked calendar feed token
+var ErrFeedNotFound = errors.New("calendar feed not found")
+
+// feedUIDDomain ends the UID of every feed event; with the leave request or holiday ID in
+// front it stays the same across feed refreshes, so calendar apps update events in place
+const feedUIDDomain = "hr-system"
+
+// feedEpoch is where event sequence numbers start counting minutes from
+var feedEpoch = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// CalendarFeedService publishes iCalendar subscription feeds of approved leave and public
+// holidays, authenticated by a per-user token in the URL
+type CalendarFeedService struct {
+	repo        *repository.CalendarFeedRepository
+	lrRepo      *repository.LeaveRequestRepository
+	holidayRepo *repository.HolidayRepository
+	empRepo     *repository.EmployeeRepository
+	company     config.CompanyConfig
+}
+
+func NewCalendarFeedService(
+	repo *repository.CalendarFeedRepository,
+	lrRepo *repository.LeaveRequestRepository,
+	holidayRepo *repository.HolidayRepository,
+	empRepo *repository.EmployeeRepository,
+	company config.CompanyConfig,
+) *CalendarFeedService {
+	return &CalendarFeedService{
+		repo:        repo,
+		lrRepo:      lrRepo,
+		holidayRepo: holidayRepo,
+		empRepo:     empRepo,
+		company:     company,
+	}
+}
+
+// Feeds returns the user's subscription URLs under baseURL, creating the token on first use
+func (s *CalendarFeedService) Feeds(userID uuid.UUID, baseURL string) (*models.CalendarFeeds, error) {
+	emp, err := s.empRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, errors.New("no employee record linked to your account")
+	}
+	t, err := s.repo.GetByUserID(userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		t, err = s.newToken(userID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.feedURLs(emp, t, baseURL)
+}
+
+// Reset replaces the user's token, so URLs handed out before stop working
+func (s *CalendarFeedService) Reset(userID uuid.UUID, baseURL string) (*models.CalendarFeeds, error) {
+	emp, err := s.empRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, errors.New("no employee record linked to your account")
+	}
+	t, err := s.newToken(userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.feedURLs(emp, t, baseURL)
+}
+
+func (s *CalendarFeedService) newToken(userID uuid.UUID) (*models.CalendarFeedToken, error) {
+	t := &models.CalendarFeedToken{UserID: userID, Token: utils.GenerateSessionToken(24)}
+	if err := s.repo.Save(t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (s *CalendarFeedService) feedURLs(emp *models.Employee, t *models.CalendarFeedToken, baseURL string) (*models.CalendarFeeds, error) {
+	prefix := baseURL + "/api/v1/calendar/" + t.Token
+	feeds := &models.CalendarFeeds{
+		MyLeave:    prefix + "/leave.ics",
+		Holidays:   prefix + "/holidays.ics",
+		LastUsedAt: t.LastUsedAt,
+		CreatedAt:  t.CreatedAt,
+	}
+	team, err := s.empRepo.CountTeam(emp.ID)
+	if err != nil {
+		return nil, err
+	}
+	if team > 0 {
+		feeds.TeamLeave = prefix + "/team-leave.ics"
+	}
+	return feeds, nil
+}
+
+// MyLeave renders the feed of the token owner's own leave
+func (s *CalendarFeedService) MyLeave(token string) ([]byte, error) {
+	emp, err := s.employeeForToken(token)
+	if err != nil {
+		return nil, err
+	}
+	leave, err := s.lrRepo.ListFeed(emp.ID, false, time.Now().AddDate(-1, 0, 0))
+	if err != nil {
+		return nil, err
+	}
+	cal := ical.Calendar{ProductID: s.productID(), Name: "My leave"}
+	for i := range leave {
+		cal.Events = append(cal.Events, leaveEvent(&leave[i], false))
+	}
+	return ical.Render(cal), nil
+}
+
+// TeamLeave renders the feed of leave taken by everyone reporting to the token owner
+func (s *CalendarFeedService) TeamLeave(token string) ([]byte, error) {
+	emp, err := s.employeeForToken(token)
+	if err != nil {
+		return nil, err
+	}
+	leave, err := s.lrRepo.ListFeed(emp.ID, true, time.Now().AddDate(-1, 0, 0))
+	if err != nil {
+		return nil, err
+	}
+	cal := ical.Calendar{ProductID: s.productID(), Name: "Team leave"}
+	for i := range leave {
+		cal.Events = append(cal.Events, leaveEvent(&leave[i], true))
+	}
+	return ical.Render(cal), nil
+}
+
+// Holidays renders the company-wide holidays and those for the token owner's city
+func (s *CalendarFeedService) Holidays(token string) ([]byte, error) {
+	emp, err := s.employeeForToken(token)
+	if err != nil {
+		return nil, err
+	}
+	holidays, err := s.holidayRepo.List(0, emp.City)
+	if err != nil {
+		return nil, err
+	}
+	since := time.Now().AddDate(-1, 0, 0)
+	cal := ical.Calendar{ProductID: s.productID(), Name: s.company.Name + " holidays"}
+	for _, h := range holidays {
+		if h.Location != "" && h.Location != emp.City {
+			continue
+		}
+		if !h.IsRecurring && h.Date.Before(since) {
+			continue
+		}
+		cal.Events = append(cal.Events, ical.Event{
+			UID:          fmt.Sprintf("holiday-%s@%s", h.ID, feedUIDDomain),
+			Summary:      h.Name,
+			Description:  h.Description,
+			Start:        h.Date,
+			End:          h.Date,
+			Status:       ical.StatusConfirmed,
+			Sequence:     feedSequence(h.UpdatedAt),
+			LastModified: h.UpdatedAt,
+			Yearly:       h.IsRecurring,
+		})
+	}
+	return ical.Render(cal), nil
+}
+
+func (s *CalendarFeedService) employeeForToken(token string) (*models.Employee, error) {
+	t, err := s.repo.GetByToken(token)
+	if err != nil {
+		return nil, ErrFeedNotFound
+	}
+	emp, err := s.empRepo.GetByUserID(t.UserID)
+	if err != nil {
+		return nil, ErrFeedNotFound
+	}
+	if err := s.repo.MarkUsed(t.ID); err != nil {
+		fmt.Printf("Warning: Failed to record calendar feed use %s: %v\n", t.ID, err)
+	}
+	return emp, nil
+}
+
+func (s *CalendarFeedService) productID() string {
+	return "-//" + s.company.Name + "//HR System//EN"
+}
+
+// leaveEvent shows a leave request as an all-day event. Leave that is no longer approved
+// is sent as cancelled, which removes it from calendars that showed it.
+func leaveEvent(e *models.LeaveCalendarEntry, withName bool) ical.Event {
+	summary := e.LeaveTypeName
+	if withName {
+		summary = e.EmployeeName + " - " + summary
+	}
+	switch {
+	case e.Hours > 0:
+		summary += fmt.Sprintf(" (%gh)", e.Hours)
+	case e.StartDate.Equal(e.EndDate) && e.StartPart == models.LeaveDayAM:
+		summary += " (morning)"
+	case e.StartDate.Equal(e.EndDate) && e.StartPart == models.LeaveDayPM:
+		summary += " (afternoon)"
+	}
+
+	description := fmt.Sprintf("%g day(s) of %s", e.TotalDays, e.LeaveTypeName)
+	if !e.StartDate.Equal(e.EndDate) {
+		if e.StartPart == models.LeaveDayPM {
+			description += ", starting in the afternoon"
+		}
+		if e.EndPart == models.LeaveDayAM {
+			description += ", ending at midday"
+		}
+	}
+
+	status := ical.StatusConfirmed
+	if e.Status != models.LeaveStatusApproved {
+		status = ical.StatusCancelled
+	}
+	return ical.Event{
+		UID:          fmt.Sprintf("leave-%s@%s", e.LeaveRequestID, feedUIDDomain),
+		Summary:      summary,
+		Description:  description,
+		Start:        e.StartDate,
+		End:          e.EndDate,
+		Status:       status,
+		Sequence:     feedSequence(e.UpdatedAt),
+		LastModified: e.UpdatedAt,
+	}
+}
+
+// feedSequence numbers an event's revisions by the minutes between feedEpoch and its last
+// change, so every saved change gives a higher sequence
+func feedSequence(updatedAt time.Time) int {
+	if updatedAt.Before(feedEpoch) {
+		return 0
+	}
+	return int(updatedAt.Sub(feedEpoch) / time.Minute)
+}
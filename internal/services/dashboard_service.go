@@ -19,6 +19,7 @@ type DashboardService struct {
 	lbRepo         *repository.LeaveBalanceRepository
 	lrRepo         *repository.LeaveRequestRepository
 	adminDashRepo  *repository.AdminDashboardRepository
+	leaveYear      models.LeaveYear
 }
 
 func NewDashboardService(
@@ -28,6 +29,7 @@ func NewDashboardService(
 	lbRepo *repository.LeaveBalanceRepository,
 	lrRepo *repository.LeaveRequestRepository,
 	adminDashRepo *repository.AdminDashboardRepository,
+	leaveYear models.LeaveYear,
 ) *DashboardService {
 	return &DashboardService{
 		empRepo:       empRepo,
@@ -36,6 +38,7 @@ func NewDashboardService(
 		lbRepo:        lbRepo,
 		lrRepo:        lrRepo,
 		adminDashRepo: adminDashRepo,
+		leaveYear:     leaveYear,
 	}
 }
 
@@ -63,7 +66,7 @@ func (s *DashboardService) GetEmployeeDashboard(userID uuid.UUID) (*models.Emplo
 	// }
 
 	now := time.Now()
-	year := now.Year()
+	year := s.leaveYear.Of(now)
 
 	// 2. Resolve position.
 	posTitle := ""
@@ -104,19 +107,19 @@ func (s *DashboardService) GetEmployeeDashboard(userID uuid.UUID) (*models.Emplo
 	}
 
 	// 7. Leave days earned this month = earned_leave_days for the AL balance
-	//    divided by the number of months elapsed so far this year.
-	leaveDaysThisMonth := 0
+	//    divided by the number of months elapsed so far this leave year.
+	leaveDaysThisMonth := 0.0
 	alBalance, err := s.lbRepo.GetByEmployeeAndYear(emp.ID, year)
-	yearlyEntitlement := 0
+	yearlyEntitlement := 0.0
 	if err == nil {
 		for _, lb := range alBalance {
 			if lb.LeaveType != nil && lb.LeaveType.Code == "AL" {
 				// Yearly entitlement = base days from leave type (e.g., 24 for AL)
 				yearlyEntitlement = lb.TotalEntitled
 				// earned_leave_days accumulates +2 per month; divide by months elapsed
-				elapsed := int(now.Month())
+				elapsed := float64(s.leaveYear.MonthsElapsed(now))
 				if elapsed > 0 {
-					leaveDaysThisMonth = lb.EarnedLeaveDays / elapsed
+					leaveDaysThisMonth = models.RoundLeaveDays(lb.EarnedLeaveDays / elapsed)
 				}
 				break
 			}
@@ -148,6 +151,7 @@ func (s *DashboardService) GetEmployeeDashboard(userID uuid.UUID) (*models.Emplo
 		},
 		LeaveDaysThisMonth: leaveDaysThisMonth,
 		YearlyEntitlement:  yearlyEntitlement,
+		LeaveYear:          s.leaveYear.Label(year),
 		LeaveRequests:      leaveRequestCount,
 	}, nil
 }
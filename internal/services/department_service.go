@@ -27,6 +27,9 @@ func (s *DepartmentService) Create(dept *models.Department) error {
 	if dept.Name == "" {
 		return errors.New("department name is required")
 	}
+	if dept.MinStaffOnDuty < 0 {
+		return errors.New("min_staff_on_duty cannot be negative")
+	}
 
 	exists, err := s.repo.CodeExists(dept.Code, nil)
 	if err != nil {
@@ -55,6 +58,9 @@ func (s *DepartmentService) Update(dept *models.Department) error {
 	if err != nil {
 		return errors.New("department not found")
 	}
+	if dept.MinStaffOnDuty < 0 {
+		return errors.New("min_staff_on_duty cannot be negative")
+	}
 
 	if dept.Code != existing.Code {
 		exists, err := s.repo.CodeExists(dept.Code, &dept.ID)
@@ -3,6 +3,7 @@ package services
 import (
 	"errors"
 	"fmt"
+	"log"
 	"time"
 
 	"hr-system/internal/interfaces"
@@ -19,6 +20,8 @@ type EmployeeService struct {
 	posRepo      *repository.PositionRepository
 	userService  *UserService
 	emailService *email.EmailService
+
+	settlementService *FinalSettlementService
 }
 
 func NewEmployeeService(
@@ -140,20 +143,28 @@ func (s *EmployeeService) List(filter interfaces.EmployeeFilter, page, pageSize
 	return s.repo.List(filter, page, pageSize)
 }
 
-func (s *EmployeeService) Update(emp *models.Employee) error {
+// SetSettlementService sets the final settlement service (called after initialization)
+func (s *EmployeeService) SetSettlementService(settlementService *FinalSettlementService) {
+	s.settlementService = settlementService
+}
+
+// Update saves an employee. When the change terminates the employee or records their
+// resignation, a final settlement is prepared for HR approval. If that fails the employee
+// is still saved and the returned warning tells HR to prepare the settlement by hand.
+func (s *EmployeeService) Update(emp *models.Employee) (string, error) {
 	existing, err := s.repo.GetByID(emp.ID)
 	if err != nil {
-		return errors.New("employee not found")
+		return "", errors.New("employee not found")
 	}
 
 	// Validate termination fields
 	if emp.EmploymentStatus == models.EmploymentStatusTerminated ||
 		emp.EmploymentStatus == models.EmploymentStatusResigned {
 		if emp.TerminationDate == nil {
-			return errors.New("termination_date is required when status is terminated or resigned")
+			return "", errors.New("termination_date is required when status is terminated or resigned")
 		}
 		if emp.TerminationReason == "" {
-			return errors.New("termination_reason is required when status is terminated or resigned")
+			return "", errors.New("termination_reason is required when status is terminated or resigned")
 		}
 	}
 
@@ -161,10 +172,25 @@ func (s *EmployeeService) Update(emp *models.Employee) error {
 	emp.EmployeeNumber = existing.EmployeeNumber
 
 	if err := s.validateEmployee(emp, &emp.ID); err != nil {
-		return err
+		return "", err
 	}
 
-	return s.repo.Update(emp)
+	if err := s.repo.Update(emp); err != nil {
+		return "", err
+	}
+	if s.settlementService != nil && isLeaver(emp.EmploymentStatus) && !isLeaver(existing.EmploymentStatus) {
+		// The employee record is saved; HR can prepare the settlement again by hand
+		if _, err := s.settlementService.Prepare(emp.ID, SettlementOptions{}, nil); err != nil {
+			log.Printf("employee %s: failed to prepare final settlement: %v", emp.ID, err)
+			return fmt.Sprintf("Employee saved, but the final settlement could not be prepared: %v. "+
+				"Prepare it with POST /api/v1/hr/employees/%s/final-settlement.", err, emp.ID), nil
+		}
+	}
+	return "", nil
+}
+
+func isLeaver(status models.EmploymentStatus) bool {
+	return status == models.EmploymentStatusTerminated || status == models.EmploymentStatusResigned
 }
 
 func (s *EmployeeService) SoftDelete(id uuid.UUID) error {
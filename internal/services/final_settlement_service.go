@@ -0,0 +1,311 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"hr-system/internal/config"
+	"hr-system/internal/models"
+	"hr-system/internal/repository"
+	"hr-system/pkg/money"
+	"hr-system/pkg/utils"
+
+	"github.com/google/uuid"
+)
+
+type FinalSettlementService struct {
+	repo           *repository.FinalSettlementRepository
+	empRepo        *repository.EmployeeRepository
+	posRepo        *repository.PositionRepository
+	lbRepo         *repository.LeaveBalanceRepository
+	ltRepo         *repository.LeaveTypeRepository
+	loanRepo       *repository.LoanRepository
+	payGroupRepo   *repository.PayGroupRepository
+	payslipRepo    *repository.PayslipRepository
+	payslipService *PayslipService
+	cfg            config.PayrollConfig
+	leaveYear      models.LeaveYear
+}
+
+func NewFinalSettlementService(
+	repo *repository.FinalSettlementRepository,
+	empRepo *repository.EmployeeRepository,
+	posRepo *repository.PositionRepository,
+	lbRepo *repository.LeaveBalanceRepository,
+	ltRepo *repository.LeaveTypeRepository,
+	loanRepo *repository.LoanRepository,
+	payGroupRepo *repository.PayGroupRepository,
+	payslipRepo *repository.PayslipRepository,
+	payslipService *PayslipService,
+	cfg config.PayrollConfig,
+	leaveYear models.LeaveYear,
+) *FinalSettlementService {
+	return &FinalSettlementService{
+		repo:           repo,
+		empRepo:        empRepo,
+		posRepo:        posRepo,
+		lbRepo:         lbRepo,
+		ltRepo:         ltRepo,
+		loanRepo:       loanRepo,
+		payGroupRepo:   payGroupRepo,
+		payslipRepo:    payslipRepo,
+		payslipService: payslipService,
+		cfg:            cfg,
+		leaveYear:      leaveYear,
+	}
+}
+
+// SettlementOptions are HR's choices when preparing a settlement
+type SettlementOptions struct {
+	NoticeDays *int // working days paid in lieu of notice; nil uses the configured default
+	Notes      string
+}
+
+// Prepare works out the final settlement for an employee who has been terminated or has
+// resigned and saves it for HR approval. A pending settlement is recalculated in place.
+//
+// Salary is paid for the days of the final pay period up to the termination date, or not
+// at all if that period has already been paid, in which case the settlement goes on the
+// next period. Unused annual leave is encashed at the daily basic rate, notice pay is
+// paid at the daily gross rate (by default only when the company ended the employment),
+// and gratuity is paid per completed year of service. Outstanding loans are recovered
+// from what is left after tax.
+func (s *FinalSettlementService) Prepare(employeeID uuid.UUID, opts SettlementOptions, createdBy *uuid.UUID) (*models.FinalSettlement, error) {
+	emp, err := s.empRepo.GetByID(employeeID)
+	if err != nil {
+		return nil, errors.New("employee not found")
+	}
+	if emp.EmploymentStatus != models.EmploymentStatusTerminated && emp.EmploymentStatus != models.EmploymentStatusResigned {
+		return nil, errors.New("employee has not been terminated or resigned")
+	}
+	if emp.TerminationDate == nil {
+		return nil, errors.New("employee has no termination date")
+	}
+	if opts.NoticeDays != nil && (*opts.NoticeDays < 0 || *opts.NoticeDays > 260) {
+		return nil, errors.New("notice_days must be between 0 and 260")
+	}
+
+	st, err := s.repo.GetActiveForEmployee(employeeID)
+	if err == nil && st.Status == models.FinalSettlementApproved {
+		return nil, errors.New("employee's final settlement has already been approved")
+	}
+	if err != nil {
+		st = &models.FinalSettlement{EmployeeID: employeeID, CreatedBy: createdBy}
+	}
+	if err := s.calculate(st, emp, opts); err != nil {
+		return nil, err
+	}
+
+	if st.ID == uuid.Nil {
+		err = s.repo.Create(st)
+	} else {
+		err = s.repo.Update(st)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to save settlement: %w", err)
+	}
+	return s.repo.GetByID(st.ID)
+}
+
+// calculate fills in a settlement's figures from the employee's current records
+func (s *FinalSettlementService) calculate(st *models.FinalSettlement, emp *models.Employee, opts SettlementOptions) error {
+	terminationDate := dateOnly(*emp.TerminationDate)
+	st.TerminationDate = terminationDate
+	st.SeparationType = emp.EmploymentStatus
+	st.Notes = strings.TrimSpace(opts.Notes)
+
+	pos, err := s.posRepo.GetByID(emp.PositionID)
+	if err != nil {
+		return errors.New("employee position not found")
+	}
+	group, err := s.payGroupRepo.GetForEmployee(emp.ID)
+	if err != nil {
+		return errors.New("employee pay group not found")
+	}
+
+	// Final pay period and the salary days still owed in it
+	period := group.PeriodContaining(terminationDate)
+	st.DaysWorked = 0
+	if paid, err := s.payslipRepo.FindOverlappingRegular(emp.ID, period.Start, period.End); err == nil && paid != nil {
+		period = group.NextPeriod(period)
+	} else {
+		from := period.Start
+		if hire := dateOnly(emp.HireDate); hire.After(from) {
+			from = hire
+		}
+		if !from.After(terminationDate) {
+			st.DaysWorked = daysBetween(from, terminationDate)
+		}
+	}
+	st.PeriodStart, st.PeriodEnd = period.Start, period.End
+	st.PeriodDays = daysBetween(period.Start, period.End)
+
+	basicDaily := utils.DailyRate(pos.BaseSalary)
+	grossDaily := utils.DailyRate(utils.CalculateSalaryBreakdown(pos.BaseSalary).GrossSalary)
+
+	// Unused annual leave
+	encashable, err := s.encashableBalances(emp.ID, s.leaveYear.Of(terminationDate))
+	if err != nil {
+		return err
+	}
+	st.LeaveDays = 0
+	for _, b := range encashable {
+		st.LeaveDays += b.Balance
+	}
+	st.LeaveDays = models.RoundLeaveDays(st.LeaveDays)
+	st.LeaveEncashment = basicDaily.Mul(st.LeaveDays)
+
+	// Pay in lieu of notice
+	switch {
+	case opts.NoticeDays != nil:
+		st.NoticeDays = *opts.NoticeDays
+	case emp.EmploymentStatus == models.EmploymentStatusTerminated:
+		st.NoticeDays = s.cfg.NoticePeriodDays
+	default:
+		st.NoticeDays = 0
+	}
+	st.NoticePay = grossDaily.Mul(float64(st.NoticeDays))
+
+	// Gratuity by completed years of service
+	st.ServiceYears = completedYears(emp.HireDate, terminationDate)
+	st.Gratuity = 0
+	if st.ServiceYears >= s.cfg.GratuityMinYears {
+		st.Gratuity = basicDaily.Mul(s.cfg.GratuityDaysPerYear * float64(st.ServiceYears))
+	}
+
+	payslip, _, err := s.payslipService.finalPay(st)
+	if err != nil {
+		return err
+	}
+	st.ProratedSalary = money.Sum(payslip.BaseSalary, payslip.HousingAllowance, payslip.TransportAllowance, payslip.MedicalAllowance)
+	st.OtherEarnings = payslip.OvertimePay + payslip.AdditionalEarnings + payslip.Reimbursements + payslip.EncashedLeave
+	st.Arrears = payslip.ArrearsPay
+	st.GrossPay = payslip.GrossSalary + payslip.Reimbursements
+	st.IncomeTax = payslip.IncomeTax
+	st.OtherDeductions = payslip.PreTaxDeductions + payslip.OtherDeductions
+	st.LoanRecovery = payslip.LoanDeduction
+	st.NetPay = payslip.NetSalary
+
+	loans, err := s.loanRepo.ListOutstanding(emp.ID)
+	if err != nil {
+		return fmt.Errorf("failed to load loans: %w", err)
+	}
+	var outstanding money.Money
+	for _, l := range loans {
+		outstanding += l.OutstandingBalance
+	}
+	st.LoanOutstanding = (outstanding - st.LoanRecovery).Max(0)
+	return nil
+}
+
+// encashableBalances returns the employee's leave balances with days left to encash.
+// Only paid leave that can be carried forward (annual leave) is encashed.
+func (s *FinalSettlementService) encashableBalances(employeeID uuid.UUID, year int) ([]models.LeaveBalance, error) {
+	types, err := s.ltRepo.List(false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load leave types: %w", err)
+	}
+	encashable := map[uuid.UUID]bool{}
+	for _, lt := range types {
+		encashable[lt.ID] = lt.IsPaid && lt.IsCarryForwardAllowed
+	}
+
+	balances, err := s.lbRepo.GetByEmployeeAndYear(employeeID, year)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load leave balances: %w", err)
+	}
+	var out []models.LeaveBalance
+	for _, b := range balances {
+		if encashable[b.LeaveTypeID] && b.Balance > 0 {
+			out = append(out, b)
+		}
+	}
+	return out, nil
+}
+
+// Approve recalculates a pending settlement, issues the final payslip and debits the
+// encashed leave from the employee's balances.
+func (s *FinalSettlementService) Approve(id uuid.UUID, approvedBy uuid.UUID) (*models.FinalSettlement, error) {
+	st, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("settlement not found")
+	}
+	if st.Status != models.FinalSettlementPending {
+		return nil, fmt.Errorf("settlement is already %s", st.Status)
+	}
+	emp, err := s.empRepo.GetByID(st.EmployeeID)
+	if err != nil {
+		return nil, errors.New("employee not found")
+	}
+
+	// Pick up anything that changed since the statement was prepared
+	noticeDays := st.NoticeDays
+	if err := s.calculate(st, emp, SettlementOptions{NoticeDays: &noticeDays, Notes: st.Notes}); err != nil {
+		return nil, err
+	}
+	encashed, err := s.encashableBalances(st.EmployeeID, s.leaveYear.Of(st.TerminationDate))
+	if err != nil {
+		return nil, err
+	}
+
+	payslip, err := s.payslipService.GenerateFinalSettlement(st)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	st.Status = models.FinalSettlementApproved
+	st.PayslipID = &payslip.ID
+	st.ApprovedBy = &approvedBy
+	st.ApprovedAt = &now
+	if err := s.repo.Approve(st, encashed); err != nil {
+		return nil, fmt.Errorf("final payslip %s issued but settlement not approved: %w", payslip.ID, err)
+	}
+	return s.repo.GetByID(id)
+}
+
+// Cancel withdraws a settlement that has not been approved
+func (s *FinalSettlementService) Cancel(id uuid.UUID) error {
+	st, err := s.repo.GetByID(id)
+	if err != nil {
+		return errors.New("settlement not found")
+	}
+	if st.Status != models.FinalSettlementPending {
+		return fmt.Errorf("settlement is already %s", st.Status)
+	}
+	st.Status = models.FinalSettlementCancelled
+	return s.repo.Update(st)
+}
+
+func (s *FinalSettlementService) GetByID(id uuid.UUID) (*models.FinalSettlement, error) {
+	return s.repo.GetByID(id)
+}
+
+func (s *FinalSettlementService) GetForEmployee(employeeID uuid.UUID) (*models.FinalSettlement, error) {
+	return s.repo.GetActiveForEmployee(employeeID)
+}
+
+func (s *FinalSettlementService) List(status models.FinalSettlementStatus) ([]models.FinalSettlement, error) {
+	return s.repo.List(status)
+}
+
+// daysBetween counts the days from start to end inclusive
+func daysBetween(start, end time.Time) int {
+	return int(end.Sub(start).Hours()/24) + 1
+}
+
+// completedYears returns the whole years from hire to termination
+func completedYears(hire, termination time.Time) int {
+	hire, termination = dateOnly(hire), dateOnly(termination)
+	years := termination.Year() - hire.Year()
+	if termination.Before(hire.AddDate(years, 0, 0)) {
+		years--
+	}
+	return max(years, 0)
+}
+
+func dateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,307 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"hr-system/internal/config"
+	"hr-system/internal/models"
+	"hr-system/internal/repository"
+	"hr-system/pkg/money"
+
+	"github.com/google/uuid"
+)
+
+// GLJournalService maps payroll components to general-ledger accounts and builds
+// the journal that posts a completed payroll's costs by cost centre.
+type GLJournalService struct {
+	mappingRepo *repository.GLMappingRepository
+	payrollRepo *repository.PayrollRepository
+	payslipRepo *repository.PayslipRepository
+	empRepo     *repository.EmployeeRepository
+	deptRepo    *repository.DepartmentRepository
+	cfg         config.PayrollConfig
+}
+
+func NewGLJournalService(
+	mappingRepo *repository.GLMappingRepository,
+	payrollRepo *repository.PayrollRepository,
+	payslipRepo *repository.PayslipRepository,
+	empRepo *repository.EmployeeRepository,
+	deptRepo *repository.DepartmentRepository,
+	cfg config.PayrollConfig,
+) *GLJournalService {
+	return &GLJournalService{
+		mappingRepo: mappingRepo,
+		payrollRepo: payrollRepo,
+		payslipRepo: payslipRepo,
+		empRepo:     empRepo,
+		deptRepo:    deptRepo,
+		cfg:         cfg,
+	}
+}
+
+func (s *GLJournalService) ListMappings() ([]models.GLAccountMapping, error) {
+	return s.mappingRepo.List()
+}
+
+// SaveMapping creates or replaces the mapping for a component (and department, if given).
+func (s *GLJournalService) SaveMapping(m *models.GLAccountMapping) (*models.GLAccountMapping, error) {
+	if !m.Component.IsValid() {
+		return nil, fmt.Errorf("unknown component: %s", m.Component)
+	}
+	m.AccountCode = strings.TrimSpace(m.AccountCode)
+	if m.AccountCode == "" {
+		return nil, errors.New("account_code is required")
+	}
+	m.AccountName = strings.TrimSpace(m.AccountName)
+	m.CostCentre = strings.TrimSpace(m.CostCentre)
+	if m.DepartmentID != nil {
+		if _, err := s.deptRepo.GetByID(*m.DepartmentID); err != nil {
+			return nil, errors.New("department not found")
+		}
+	}
+
+	if existing, err := s.mappingRepo.Find(m.Component, m.DepartmentID); err == nil {
+		m.ID = existing.ID
+		if err := s.mappingRepo.Update(m); err != nil {
+			return nil, fmt.Errorf("failed to update mapping: %w", err)
+		}
+	} else if err := s.mappingRepo.Create(m); err != nil {
+		return nil, fmt.Errorf("failed to create mapping: %w", err)
+	}
+	return s.mappingRepo.GetByID(m.ID)
+}
+
+func (s *GLJournalService) DeleteMapping(id uuid.UUID) error {
+	if err := s.mappingRepo.Delete(id); err != nil {
+		return errors.New("mapping not found")
+	}
+	return nil
+}
+
+// journalKey groups postings to the same account, cost centre and component
+type journalKey struct {
+	account    string
+	costCentre string
+	component  models.GLComponent
+}
+
+// Journal builds the GL journal for a COMPLETED payroll. Every final payslip is split
+// into its pay components, each posted to the account mapped for the employee's
+// department (falling back to the company-wide default). Generation fails if any
+// component is unmapped or if debits and credits do not balance to the cent.
+func (s *GLJournalService) Journal(payrollID uuid.UUID) (*models.PayrollJournal, error) {
+	payroll, err := s.payrollRepo.GetByID(payrollID)
+	if err != nil {
+		return nil, errors.New("payroll not found")
+	}
+	if payroll.Status != models.PayrollStatusCompleted {
+		return nil, fmt.Errorf("journals can only be generated for COMPLETED payrolls, current: %s", payroll.Status)
+	}
+
+	payslips, err := s.payslipRepo.ListByPayroll(payrollID, models.PayslipStatusFinal)
+	if err != nil {
+		return nil, err
+	}
+	if len(payslips) == 0 {
+		return nil, errors.New("payroll has no final payslips")
+	}
+
+	mappings, err := s.mappingRepo.List()
+	if err != nil {
+		return nil, err
+	}
+	defaults := map[models.GLComponent]models.GLAccountMapping{}
+	overrides := map[uuid.UUID]map[models.GLComponent]models.GLAccountMapping{}
+	for _, m := range mappings {
+		if m.DepartmentID == nil {
+			defaults[m.Component] = m
+			continue
+		}
+		if overrides[*m.DepartmentID] == nil {
+			overrides[*m.DepartmentID] = map[models.GLComponent]models.GLAccountMapping{}
+		}
+		overrides[*m.DepartmentID][m.Component] = m
+	}
+
+	departments := map[uuid.UUID]*models.Department{}
+	totals := map[journalKey]money.Money{}
+	deptNames := map[journalKey]string{}
+	accountNames := map[string]string{}
+	missing := map[string]bool{}
+	var unbalanced []string
+
+	for _, p := range payslips {
+		emp, err := s.empRepo.GetByID(p.EmployeeID)
+		if err != nil {
+			return nil, fmt.Errorf("employee %s on payslip %s not found", p.EmployeeID, p.ID)
+		}
+		dept, seen := departments[emp.DepartmentID]
+		if !seen {
+			dept, _ = s.deptRepo.GetByID(emp.DepartmentID)
+			departments[emp.DepartmentID] = dept
+		}
+		deptName, deptCode := "Unassigned", ""
+		if dept != nil {
+			deptName, deptCode = dept.Name, dept.Code
+		}
+
+		amounts := s.componentAmounts(&p)
+		var debits, credits money.Money
+		for component, amount := range amounts {
+			if amount == 0 {
+				continue
+			}
+			if component.IsDebit() {
+				debits += amount
+			} else {
+				credits += amount
+			}
+
+			m, ok := overrides[emp.DepartmentID][component]
+			if !ok {
+				m, ok = defaults[component]
+			}
+			if !ok {
+				missing[fmt.Sprintf("%s (%s)", component, deptName)] = true
+				continue
+			}
+			costCentre := m.CostCentre
+			if costCentre == "" {
+				costCentre = deptCode
+			}
+			key := journalKey{account: m.AccountCode, costCentre: costCentre, component: component}
+			totals[key] += amount
+			deptNames[key] = deptName
+			accountNames[m.AccountCode] = m.AccountName
+		}
+		if debits != credits {
+			unbalanced = append(unbalanced, fmt.Sprintf("%s (%s)", p.ID, p.EmployeeName))
+		}
+	}
+
+	if len(missing) > 0 {
+		list := make([]string, 0, len(missing))
+		for k := range missing {
+			list = append(list, k)
+		}
+		sort.Strings(list)
+		return nil, fmt.Errorf("no GL account mapped for: %s", strings.Join(list, ", "))
+	}
+	if len(unbalanced) > 0 {
+		log.Printf("GL journal for payroll %s: unbalanced payslips %v", payrollID, unbalanced)
+		return nil, fmt.Errorf("journal does not balance: earnings and deductions differ on payslips %s", strings.Join(unbalanced, ", "))
+	}
+
+	period := payrollPeriod(payroll)
+	journal := &models.PayrollJournal{
+		PayrollID:   payrollID,
+		Reference:   fmt.Sprintf("PAYJ%d%02d-%s", payroll.EndDate.Year(), int(payroll.EndDate.Month()), strings.ToUpper(payrollID.String()[:8])),
+		PostingDate: payroll.EndDate,
+		Period:      period,
+		Lines:       []models.JournalLine{},
+	}
+	var totalDebit, totalCredit money.Money
+	for key, amount := range totals {
+		if amount == 0 {
+			continue
+		}
+		line := models.JournalLine{
+			AccountCode:    key.account,
+			AccountName:    accountNames[key.account],
+			CostCentre:     key.costCentre,
+			DepartmentName: deptNames[key],
+			Component:      key.component,
+			Description:    fmt.Sprintf("%s - %s", componentLabel(key.component), period),
+		}
+		// Net corrections (e.g. supplementary payslips) can flip a line to the other side
+		debit := key.component.IsDebit()
+		if amount < 0 {
+			debit, amount = !debit, -amount
+		}
+		if debit {
+			line.Debit = amount
+			totalDebit += amount
+		} else {
+			line.Credit = amount
+			totalCredit += amount
+		}
+		journal.Lines = append(journal.Lines, line)
+	}
+	if totalDebit != totalCredit {
+		log.Printf("GL journal for payroll %s does not balance: debits %s, credits %s", payrollID, totalDebit, totalCredit)
+		return nil, fmt.Errorf("journal does not balance: debits %s, credits %s", totalDebit, totalCredit)
+	}
+	journal.TotalDebit = totalDebit
+	journal.TotalCredit = totalCredit
+
+	order := map[models.GLComponent]int{}
+	for i, c := range append(append([]models.GLComponent{}, models.GLDebitComponents...), models.GLCreditComponents...) {
+		order[c] = i
+	}
+	sort.Slice(journal.Lines, func(i, j int) bool {
+		a, b := journal.Lines[i], journal.Lines[j]
+		if order[a.Component] != order[b.Component] {
+			return order[a.Component] < order[b.Component]
+		}
+		if a.CostCentre != b.CostCentre {
+			return a.CostCentre < b.CostCentre
+		}
+		return a.AccountCode < b.AccountCode
+	})
+	return journal, nil
+}
+
+// componentAmounts splits a payslip into GL components.
+func (s *GLJournalService) componentAmounts(p *models.Payslip) map[models.GLComponent]money.Money {
+	pension := p.GrossSalary.Percent(s.cfg.EmployerPensionRate)
+	return map[models.GLComponent]money.Money{
+		models.GLBasicSalary:        p.BaseSalary,
+		models.GLHousingAllowance:   p.HousingAllowance,
+		models.GLTransportAllowance: p.TransportAllowance,
+		models.GLMedicalAllowance:   p.MedicalAllowance,
+		models.GLOvertime:           p.OvertimePay,
+		models.GLBonus:              p.BonusPay,
+		models.GLLeavePay:           p.LeaveDays + p.EncashedLeave,
+		models.GLEmployerPension:    pension,
+		models.GLOtherEarnings:      p.AdditionalEarnings,
+		models.GLReimbursements:     p.Reimbursements,
+		models.GLArrears:            p.ArrearsPay,
+		models.GLTermination:        p.LeaveEncashment + p.NoticePay + p.Gratuity,
+		models.GLNetPay:             p.NetSalary,
+		models.GLIncomeTax:          p.IncomeTax,
+		models.GLPensionPayable:     pension,
+		models.GLLoanRecovery:       p.LoanDeduction,
+		models.GLOtherDeductions:    p.PreTaxDeductions + p.OtherDeductions,
+	}
+}
+
+// ExportCSV renders a journal as CSV with a closing totals row.
+func (s *GLJournalService) ExportCSV(j *models.PayrollJournal) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"reference", "posting_date", "account_code", "account_name", "cost_centre", "department", "component", "description", "debit", "credit"})
+	date := j.PostingDate.Format("2006-01-02")
+	for _, l := range j.Lines {
+		w.Write([]string{
+			j.Reference, date, l.AccountCode, l.AccountName, l.CostCentre, l.DepartmentName,
+			string(l.Component), l.Description,
+			l.Debit.String(), l.Credit.String(),
+		})
+	}
+	w.Write([]string{j.Reference, date, "", "", "", "", "", "TOTAL",
+		j.TotalDebit.String(), j.TotalCredit.String()})
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+func componentLabel(c models.GLComponent) string {
+	s := strings.ReplaceAll(string(c), "_", " ")
+	return strings.ToUpper(s[:1]) + s[1:]
+}
@@ -15,14 +15,26 @@ type LeaveBalanceService struct {
 	repo         *repository.LeaveBalanceRepository
 	leaveTypeRepo *repository.LeaveTypeRepository
 	empRepo      *repository.EmployeeRepository
+	leaveYear    models.LeaveYear
 }
 
 func NewLeaveBalanceService(
 	repo *repository.LeaveBalanceRepository,
 	ltRepo *repository.LeaveTypeRepository,
 	empRepo *repository.EmployeeRepository,
+	leaveYear models.LeaveYear,
 ) *LeaveBalanceService {
-	return &LeaveBalanceService{repo: repo, leaveTypeRepo: ltRepo, empRepo: empRepo}
+	return &LeaveBalanceService{repo: repo, leaveTypeRepo: ltRepo, empRepo: empRepo, leaveYear: leaveYear}
+}
+
+// LeaveYear returns the organisation's leave calendar
+func (s *LeaveBalanceService) LeaveYear() models.LeaveYear {
+	return s.leaveYear
+}
+
+// CurrentYear returns the leave year that is running today
+func (s *LeaveBalanceService) CurrentYear() int {
+	return s.leaveYear.Of(time.Now())
 }
 
 func (s *LeaveBalanceService) GetByEmployeeAndYear(employeeID uuid.UUID, year int) ([]models.LeaveBalance, error) {
@@ -33,8 +45,14 @@ func (s *LeaveBalanceService) GetByEmployeeTypeYear(employeeID, leaveTypeID uuid
 	return s.repo.GetByEmployeeTypeYear(employeeID, leaveTypeID, year)
 }
 
-// InitializeForEmployee creates leave balance records for all active leave types for a given year.
-// It prorates entitlement for new employees hired mid-year.
+// Ledger returns the changes made to an employee's balances for a leave year outside leave
+// requests, such as encashments
+func (s *LeaveBalanceService) Ledger(employeeID uuid.UUID, year int) ([]models.LeaveLedgerEntry, error) {
+	return s.repo.ListLedger(employeeID, year)
+}
+
+// InitializeForEmployee creates leave balance records for all active leave types for a given leave year.
+// It prorates entitlement for new employees hired during that leave year.
 func (s *LeaveBalanceService) InitializeForEmployee(employeeID uuid.UUID, year int) error {
 	emp, err := s.empRepo.GetByID(employeeID)
 	if err != nil {
@@ -47,10 +65,10 @@ func (s *LeaveBalanceService) InitializeForEmployee(employeeID uuid.UUID, year i
 	}
 
 	for _, lt := range leaveTypes {
-		entitled := lt.DefaultDaysPerYear
-		// Prorate if hired in the current year
-		if emp.HireDate.Year() == year {
-			entitled = ProrateEntitlement(lt.DefaultDaysPerYear, emp.HireDate, year)
+		entitled := float64(lt.DefaultDaysPerYear)
+		// Prorate if hired during the leave year
+		if s.leaveYear.Of(emp.HireDate) == year {
+			entitled = ProrateEntitlement(lt.DefaultDaysPerYear, emp.HireDate, s.leaveYear, year)
 		}
 
 		lb := &models.LeaveBalance{
@@ -73,24 +91,73 @@ func (s *LeaveBalanceService) Adjust(input interfaces.AdjustBalanceInput) error
 	return s.repo.Adjust(input.LeaveBalanceID, input.Delta)
 }
 
-func (s *LeaveBalanceService) IncrementPending(employeeID, leaveTypeID uuid.UUID, year, days int) error {
+func (s *LeaveBalanceService) IncrementPending(employeeID, leaveTypeID uuid.UUID, year int, days float64) error {
 	return s.repo.IncrementPending(employeeID, leaveTypeID, year, days)
 }
 
-func (s *LeaveBalanceService) DecrementPending(employeeID, leaveTypeID uuid.UUID, year, days int) error {
+func (s *LeaveBalanceService) DecrementPending(employeeID, leaveTypeID uuid.UUID, year int, days float64) error {
 	return s.repo.DecrementPending(employeeID, leaveTypeID, year, days)
 }
 
-func (s *LeaveBalanceService) ApproveLeave(employeeID, leaveTypeID uuid.UUID, year, days int) error {
+func (s *LeaveBalanceService) ApproveLeave(employeeID, leaveTypeID uuid.UUID, year int, days float64) error {
 	return s.repo.ApproveLeave(employeeID, leaveTypeID, year, days)
 }
 
+// HoldRequest reserves each portion of a new request as pending on its leave year's balance.
+func (s *LeaveBalanceService) HoldRequest(req *models.LeaveRequest) error {
+	for _, p := range req.Portions {
+		if err := s.repo.IncrementPending(req.EmployeeID, req.LeaveTypeID, p.Year, p.Days); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReleaseRequest returns the pending days of a request that was rejected or withdrawn.
+func (s *LeaveBalanceService) ReleaseRequest(req *models.LeaveRequest) error {
+	for _, p := range req.Portions {
+		if err := s.repo.DecrementPending(req.EmployeeID, req.LeaveTypeID, p.Year, p.Days); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ApproveRequest moves each portion of an approved request from pending to used.
+func (s *LeaveBalanceService) ApproveRequest(req *models.LeaveRequest) error {
+	for _, p := range req.Portions {
+		if err := s.repo.ApproveLeave(req.EmployeeID, req.LeaveTypeID, p.Year, p.Days); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RefundRequest gives back the used days of an approved request that was cancelled.
+func (s *LeaveBalanceService) RefundRequest(req *models.LeaveRequest) error {
+	for _, p := range req.Portions {
+		if err := s.repo.ReturnUsed(req.EmployeeID, req.LeaveTypeID, p.Year, p.Days); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EnsureBalance creates the employee's balances for a leave year when the leave type has
+// none yet
+func (s *LeaveBalanceService) EnsureBalance(employeeID, leaveTypeID uuid.UUID, year int) error {
+	if _, err := s.repo.GetByEmployeeTypeYear(employeeID, leaveTypeID, year); err == nil {
+		return nil
+	}
+	return s.InitializeForEmployee(employeeID, year)
+}
+
 // HasSufficientBalance checks if the employee has enough balance for the given days.
-func (s *LeaveBalanceService) HasSufficientBalance(employeeID, leaveTypeID uuid.UUID, year, days int) (bool, error) {
+func (s *LeaveBalanceService) HasSufficientBalance(employeeID, leaveTypeID uuid.UUID, year int, days float64) (bool, error) {
 	lb, err := s.repo.GetByEmployeeTypeYear(employeeID, leaveTypeID, year)
 	if err != nil {
 		// Auto-initialize if missing
-		if initErr := s.InitializeForEmployee(employeeID, time.Now().Year()); initErr != nil {
+		if initErr := s.InitializeForEmployee(employeeID, year); initErr != nil {
 			return false, initErr
 		}
 		lb, err = s.repo.GetByEmployeeTypeYear(employeeID, leaveTypeID, year)
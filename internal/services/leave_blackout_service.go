@@ -0,0 +1,73 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"hr-system/internal/models"
+	"hr-system/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+type LeaveBlackoutService struct {
+	repo     *repository.LeaveBlackoutRepository
+	deptRepo *repository.DepartmentRepository
+}
+
+func NewLeaveBlackoutService(repo *repository.LeaveBlackoutRepository, deptRepo *repository.DepartmentRepository) *LeaveBlackoutService {
+	return &LeaveBlackoutService{repo: repo, deptRepo: deptRepo}
+}
+
+func (s *LeaveBlackoutService) Create(b *models.LeaveBlackout) error {
+	if err := s.validate(b); err != nil {
+		return err
+	}
+	b.IsActive = true
+	return s.repo.Create(b)
+}
+
+func (s *LeaveBlackoutService) GetByID(id uuid.UUID) (*models.LeaveBlackout, error) {
+	return s.repo.GetByID(id)
+}
+
+func (s *LeaveBlackoutService) List(from *time.Time) ([]models.LeaveBlackout, error) {
+	return s.repo.List(from)
+}
+
+func (s *LeaveBlackoutService) Update(b *models.LeaveBlackout) error {
+	if _, err := s.repo.GetByID(b.ID); err != nil {
+		return errors.New("blackout not found")
+	}
+	if err := s.validate(b); err != nil {
+		return err
+	}
+	return s.repo.Update(b)
+}
+
+func (s *LeaveBlackoutService) Delete(id uuid.UUID) error {
+	if _, err := s.repo.GetByID(id); err != nil {
+		return errors.New("blackout not found")
+	}
+	return s.repo.Delete(id)
+}
+
+func (s *LeaveBlackoutService) validate(b *models.LeaveBlackout) error {
+	b.Name = strings.TrimSpace(b.Name)
+	if b.Name == "" {
+		return errors.New("blackout name is required")
+	}
+	if b.StartDate.IsZero() || b.EndDate.IsZero() {
+		return errors.New("start_date and end_date are required")
+	}
+	if b.EndDate.Before(b.StartDate) {
+		return errors.New("end_date must be >= start_date")
+	}
+	if b.DepartmentID != nil {
+		if _, err := s.deptRepo.GetByID(*b.DepartmentID); err != nil {
+			return errors.New("department not found")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,230 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"hr-system/internal/config"
+	"hr-system/internal/models"
+	"hr-system/internal/repository"
+	"hr-system/internal/utils/email"
+)
+
+// LeaveCarryForwardService expires carried-forward leave. Carried days are used before the
+// year's own entitlement, so when they expire only the carried days not covered by leave
+// taken or encashed by then are forfeited. Employees with carried days at risk are reminded
+// beforehand.
+type LeaveCarryForwardService struct {
+	balanceRepo  *repository.LeaveBalanceRepository
+	requestRepo  *repository.LeaveRequestRepository
+	holidayRepo  *repository.HolidayRepository
+	empRepo      *repository.EmployeeRepository
+	emailService *email.EmailService
+	reminderDays []int // days before expiry to remind on, largest first
+}
+
+func NewLeaveCarryForwardService(
+	balanceRepo *repository.LeaveBalanceRepository,
+	requestRepo *repository.LeaveRequestRepository,
+	holidayRepo *repository.HolidayRepository,
+	empRepo *repository.EmployeeRepository,
+	emailSvc *email.EmailService,
+	cfg config.LeaveConfig,
+) *LeaveCarryForwardService {
+	var reminderDays []int
+	for _, d := range cfg.CarryForwardReminderDays {
+		if d > 0 {
+			reminderDays = append(reminderDays, d)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(reminderDays)))
+
+	return &LeaveCarryForwardService{
+		balanceRepo:  balanceRepo,
+		requestRepo:  requestRepo,
+		holidayRepo:  holidayRepo,
+		empRepo:      empRepo,
+		emailService: emailSvc,
+		reminderDays: reminderDays,
+	}
+}
+
+// CarryForwardExpiryResult counts what one run of ExpireCarryForwards did
+type CarryForwardExpiryResult struct {
+	Reminded  int
+	Forfeited int // balances that lost carried days
+	Used      int // balances whose carried days were all used in time
+	Failed    int
+}
+
+// ExpireCarryForwards settles the carried forward days that expired before today and
+// reminds employees whose carried days expire within the reminder days. A balance that
+// fails is logged and tried again on the next run.
+func (s *LeaveCarryForwardService) ExpireCarryForwards(today time.Time) (*CarryForwardExpiryResult, error) {
+	horizon := today
+	if len(s.reminderDays) > 0 {
+		horizon = today.AddDate(0, 0, s.reminderDays[0])
+	}
+	balances, err := s.balanceRepo.ListExpiringCarryForwards(horizon)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &CarryForwardExpiryResult{}
+	for i := range balances {
+		b := &balances[i]
+
+		var err error
+		if b.CarriedForwardExpiresOn.Before(today) {
+			var forfeited float64
+			if forfeited, err = s.forfeit(b); err == nil {
+				if forfeited > 0 {
+					result.Forfeited++
+				} else {
+					result.Used++
+				}
+			}
+		} else {
+			var reminded bool
+			if reminded, err = s.remind(b, today); err == nil && reminded {
+				result.Reminded++
+			}
+		}
+		if err != nil {
+			fmt.Printf("Warning: Failed to expire carried forward days on leave balance %s: %v\n", b.ID, err)
+			result.Failed++
+		}
+	}
+	return result, nil
+}
+
+// forfeit removes the carried days left unused at expiry from the balance, recording them
+// in the leave ledger, and tells the employee. It returns the days forfeited.
+func (s *LeaveCarryForwardService) forfeit(b *models.LeaveBalance) (float64, error) {
+	days, err := s.unusedCarriedDays(b)
+	if err != nil {
+		return 0, err
+	}
+	expiredOn := b.CarriedForwardExpiresOn.Format("2006-01-02")
+	notes := fmt.Sprintf("Carried forward days unused on %s", expiredOn)
+	if err := s.balanceRepo.ForfeitCarryForward(b, days, notes); err != nil {
+		return 0, err
+	}
+	if days <= 0 {
+		return 0, nil
+	}
+
+	emp, err := s.empRepo.GetByID(b.EmployeeID)
+	if err != nil {
+		return days, nil
+	}
+	if s.emailService != nil {
+		body := email.CarryForwardForfeitedTemplate(emp.FirstName, b.LeaveType.Name, days, expiredOn)
+		subject := fmt.Sprintf("Your carried-forward %s has expired", b.LeaveType.Name)
+		if err := s.emailService.SendEmail([]string{emp.Email}, subject, body); err != nil {
+			fmt.Printf("Warning: Failed to send forfeiture email for leave balance %s: %v\n", b.ID, err)
+		}
+	}
+	return days, nil
+}
+
+// remind emails the employee when a reminder falls due and some carried days would still
+// be lost, once for each of the reminder days. It reports whether an email was sent.
+func (s *LeaveCarryForwardService) remind(b *models.LeaveBalance, today time.Time) (bool, error) {
+	daysLeft := int(b.CarriedForwardExpiresOn.Sub(today).Hours() / 24)
+	due := 0
+	for _, d := range s.reminderDays {
+		if daysLeft <= d {
+			due = d
+		}
+	}
+	if due == 0 || (b.CarryForwardReminderDays != nil && *b.CarryForwardReminderDays <= due) {
+		return false, nil
+	}
+
+	days, err := s.unusedCarriedDays(b)
+	if err != nil {
+		return false, err
+	}
+	if days <= 0 {
+		return false, s.balanceRepo.MarkCarryForwardReminded(b.ID, due)
+	}
+
+	emp, err := s.empRepo.GetByID(b.EmployeeID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get employee: %w", err)
+	}
+	if s.emailService != nil {
+		body := email.CarryForwardExpiryReminderTemplate(emp.FirstName, b.LeaveType.Name, days,
+			b.CarriedForwardExpiresOn.Format("2006-01-02"))
+		subject := fmt.Sprintf("Reminder: use your carried-forward %s by %s", b.LeaveType.Name,
+			b.CarriedForwardExpiresOn.Format("2 January"))
+		if err := s.emailService.SendEmail([]string{emp.Email}, subject, body); err != nil {
+			return false, fmt.Errorf("failed to send reminder: %w", err)
+		}
+	}
+	return true, s.balanceRepo.MarkCarryForwardReminded(b.ID, due)
+}
+
+// unusedCarriedDays works out how many carried forward days will be left when they expire
+func (s *LeaveCarryForwardService) unusedCarriedDays(b *models.LeaveBalance) (float64, error) {
+	expiresOn := *b.CarriedForwardExpiresOn
+	reqs, err := s.requestRepo.ListChargedThrough(b.EmployeeID, b.LeaveTypeID, b.Year, expiresOn)
+	if err != nil {
+		return 0, err
+	}
+	// Expiry is at most a year into the leave year, so this covers any leave running past it
+	holidays, err := s.holidayRepo.GetHolidaysInRange(expiresOn.AddDate(-1, 0, 0), expiresOn, "")
+	if err != nil {
+		return 0, err
+	}
+	ledger, err := s.balanceRepo.ListLedger(b.EmployeeID, b.Year)
+	if err != nil {
+		return 0, err
+	}
+	return carriedDaysLeft(b, reqs, ledger, holidays), nil
+}
+
+// carriedDaysLeft returns the balance's carried forward days left at expiry. Carried days
+// are used first: by leave charged to the year up to the expiry date, counting requests
+// still pending so slow approvals don't cost the employee, and by encashments approved by
+// then. No more than the remaining balance is lost, so leave already booked after the
+// expiry date keeps the days it was booked with.
+func carriedDaysLeft(b *models.LeaveBalance, reqs []models.LeaveRequest, ledger []models.LeaveLedgerEntry, holidays map[string]bool) float64 {
+	expiresOn := *b.CarriedForwardExpiresOn
+	used := 0.0
+	for _, req := range reqs {
+		for _, p := range req.Portions {
+			if p.StartDate.After(expiresOn) {
+				continue
+			}
+			if !p.EndDate.After(expiresOn) {
+				used += p.Days
+				continue
+			}
+			// Leave running past the expiry date only uses carried days up to it
+			startPart := models.LeaveDayFull
+			if p.StartDate.Equal(req.StartDate) {
+				startPart = req.StartPart
+			}
+			used += CountLeaveDays(p.StartDate, expiresOn, startPart, models.LeaveDayFull, holidays)
+		}
+	}
+
+	for _, e := range ledger {
+		if e.LeaveTypeID == b.LeaveTypeID && e.EntryType == models.LeaveLedgerEncashment &&
+			e.CreatedAt.Before(expiresOn.AddDate(0, 0, 1)) {
+			used -= e.Days
+		}
+	}
+
+	unused := models.RoundLeaveDays(b.CarriedForward - b.Forfeited - used)
+	if unused > b.Balance {
+		unused = b.Balance
+	}
+	if unused < 0 {
+		return 0
+	}
+	return unused
+}
@@ -0,0 +1,143 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"hr-system/internal/models"
+
+	"github.com/google/uuid"
+)
+
+var annualLeaveID = uuid.New()
+
+func day(s string) time.Time {
+	d, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// carriedBalance has 21 days of entitlement plus 5 carried days expiring on Tuesday 31 March
+func carriedBalance() *models.LeaveBalance {
+	expiresOn := day("2026-03-31")
+	return &models.LeaveBalance{
+		LeaveTypeID:             annualLeaveID,
+		Year:                    2026,
+		TotalEntitled:           21,
+		CarriedForward:          5,
+		Balance:                 26,
+		CarriedForwardExpiresOn: &expiresOn,
+	}
+}
+
+func leaveRequest(start, end string, startPart models.LeaveDayPart, days float64) models.LeaveRequest {
+	return models.LeaveRequest{
+		LeaveTypeID: annualLeaveID,
+		StartDate:   day(start),
+		EndDate:     day(end),
+		StartPart:   startPart,
+		EndPart:     models.LeaveDayFull,
+		TotalDays:   days,
+		Portions:    []models.LeaveRequestPortion{{Year: 2026, StartDate: day(start), EndDate: day(end), Days: days}},
+	}
+}
+
+func TestCarriedDaysLeftUsesCarriedDaysFirst(t *testing.T) {
+	tests := []struct {
+		name string
+		reqs []models.LeaveRequest
+		want float64
+	}{
+		{"no leave taken", nil, 5},
+		{"leave before expiry", []models.LeaveRequest{
+			leaveRequest("2026-02-02", "2026-02-04", models.LeaveDayFull, 3),
+		}, 2},
+		{"several requests", []models.LeaveRequest{
+			leaveRequest("2026-01-12", "2026-01-12", models.LeaveDayAM, 0.5),
+			leaveRequest("2026-02-02", "2026-02-03", models.LeaveDayFull, 2),
+		}, 2.5},
+		{"more leave than carried", []models.LeaveRequest{
+			leaveRequest("2026-02-02", "2026-02-13", models.LeaveDayFull, 10),
+		}, 0},
+		{"leave ending on the expiry date", []models.LeaveRequest{
+			leaveRequest("2026-03-30", "2026-03-31", models.LeaveDayFull, 2),
+		}, 3},
+		{"leave after expiry", []models.LeaveRequest{
+			leaveRequest("2026-04-06", "2026-04-10", models.LeaveDayFull, 5),
+		}, 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := carriedDaysLeft(carriedBalance(), tt.reqs, nil, nil); got != tt.want {
+				t.Errorf("carriedDaysLeft = %g, want %g", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCarriedDaysLeftSplitsLeaveRunningPastExpiry(t *testing.T) {
+	tests := []struct {
+		name      string
+		startPart models.LeaveDayPart
+		holidays  map[string]bool
+		want      float64
+	}{
+		// Monday 30 March to Friday 3 April uses Monday and Tuesday of the carried days
+		{"full days", models.LeaveDayFull, nil, 3},
+		{"starting in the afternoon", models.LeaveDayPM, nil, 3.5},
+		{"holiday on the expiry date", models.LeaveDayFull, map[string]bool{"2026-03-31": true}, 4},
+		{"holiday after the expiry date", models.LeaveDayFull, map[string]bool{"2026-04-01": true}, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reqs := []models.LeaveRequest{leaveRequest("2026-03-30", "2026-04-03", tt.startPart, 5)}
+			if got := carriedDaysLeft(carriedBalance(), reqs, nil, tt.holidays); got != tt.want {
+				t.Errorf("carriedDaysLeft = %g, want %g", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCarriedDaysLeftIgnoresStartPartOfEarlierPortion(t *testing.T) {
+	// The request started in the previous leave year; its afternoon start belongs to that
+	// year's portion, so this year's portion uses whole days
+	req := leaveRequest("2026-03-30", "2026-04-01", models.LeaveDayPM, 3)
+	req.StartDate = day("2025-12-31")
+	if got, want := carriedDaysLeft(carriedBalance(), []models.LeaveRequest{req}, nil, nil), 3.0; got != want {
+		t.Errorf("carriedDaysLeft = %g, want %g", got, want)
+	}
+}
+
+func TestCarriedDaysLeftCountsEncashmentsBeforeExpiry(t *testing.T) {
+	ledger := []models.LeaveLedgerEntry{
+		{LeaveTypeID: annualLeaveID, EntryType: models.LeaveLedgerEncashment, Days: -2, CreatedAt: day("2026-02-10")},
+		// Approved during the expiry date itself
+		{LeaveTypeID: annualLeaveID, EntryType: models.LeaveLedgerEncashment, Days: -1, CreatedAt: day("2026-03-31").Add(15 * time.Hour)},
+		// Too late to use the carried days
+		{LeaveTypeID: annualLeaveID, EntryType: models.LeaveLedgerEncashment, Days: -1, CreatedAt: day("2026-04-01")},
+		// Another leave type
+		{LeaveTypeID: uuid.New(), EntryType: models.LeaveLedgerEncashment, Days: -1, CreatedAt: day("2026-02-10")},
+	}
+	reqs := []models.LeaveRequest{leaveRequest("2026-03-02", "2026-03-02", models.LeaveDayFull, 1)}
+	if got, want := carriedDaysLeft(carriedBalance(), reqs, ledger, nil), 1.0; got != want {
+		t.Errorf("carriedDaysLeft = %g, want %g", got, want)
+	}
+}
+
+func TestCarriedDaysLeftIsLimitedByBalance(t *testing.T) {
+	b := carriedBalance()
+	b.Balance = 3 // leave booked after the expiry date keeps its days
+	if got, want := carriedDaysLeft(b, nil, nil, nil), 3.0; got != want {
+		t.Errorf("carriedDaysLeft = %g, want %g", got, want)
+	}
+
+	b = carriedBalance()
+	b.Forfeited = 1
+	b.Balance = 25
+	reqs := []models.LeaveRequest{leaveRequest("2026-02-02", "2026-02-03", models.LeaveDayFull, 2)}
+	if got, want := carriedDaysLeft(b, reqs, nil, nil), 2.0; got != want {
+		t.Errorf("carriedDaysLeft with days already forfeited = %g, want %g", got, want)
+	}
+}
@@ -0,0 +1,305 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"hr-system/internal/config"
+	"hr-system/internal/models"
+	"hr-system/internal/repository"
+	"hr-system/internal/utils/email"
+
+	"github.com/google/uuid"
+)
+
+// unpaidLeaveCode is the leave type overdue leave is converted to when its leave type says so
+const unpaidLeaveCode = "UL"
+
+var (
+	ErrAttachmentNotFound = errors.New("attachment not found")
+	ErrLeaveDocumentScope = errors.New("you can only access documents on your own leave requests")
+)
+
+// LeaveDocumentService keeps the files attached to leave requests and chases the documents
+// leave types require: employees are reminded once their leave ends, and requests still
+// without one after the grace days are escalated to HR or converted to unpaid leave.
+type LeaveDocumentService struct {
+	repo           *repository.LeaveRequestRepository
+	leaveTypeRepo  *repository.LeaveTypeRepository
+	balanceService *LeaveBalanceService
+	empRepo        *repository.EmployeeRepository
+	userRepo       *repository.UserRepository
+	emailService   *email.EmailService
+	dir            string
+}
+
+func NewLeaveDocumentService(
+	repo *repository.LeaveRequestRepository,
+	ltRepo *repository.LeaveTypeRepository,
+	balanceSvc *LeaveBalanceService,
+	empRepo *repository.EmployeeRepository,
+	userRepo *repository.UserRepository,
+	emailSvc *email.EmailService,
+	cfg config.LeaveConfig,
+) *LeaveDocumentService {
+	return &LeaveDocumentService{
+		repo:           repo,
+		leaveTypeRepo:  ltRepo,
+		balanceService: balanceSvc,
+		empRepo:        empRepo,
+		userRepo:       userRepo,
+		emailService:   emailSvc,
+		dir:            cfg.AttachmentDir,
+	}
+}
+
+// DocumentEnforcementResult counts what one run of EnforceDocuments did
+type DocumentEnforcementResult struct {
+	Reminded  int
+	Escalated int
+	Converted int
+	Failed    int
+}
+
+// Upload stores a file for a leave request. Employees can attach files to their own
+// requests and HR to anyone's. A document the request was waiting for counts as received.
+func (s *LeaveDocumentService) Upload(requestID uuid.UUID, user *models.User, a *models.LeaveRequestAttachment, content io.Reader) error {
+	req, err := s.repo.GetByID(requestID)
+	if err != nil {
+		return errors.New("leave request not found")
+	}
+	if !hasRole(user, models.RoleSuperAdmin, models.RoleHRManager) && !s.ownsRequest(user, req) {
+		return ErrLeaveDocumentScope
+	}
+	if req.Status != models.LeaveStatusPending && req.Status != models.LeaveStatusApproved {
+		return errors.New("documents can only be added to pending or approved requests")
+	}
+	if !allowedMimeTypes[a.ContentType] {
+		return errors.New("file type not allowed; use PDF, JPEG, PNG, or DOCX")
+	}
+
+	a.LeaveRequestID = requestID
+	a.UploadedBy = &user.UserID
+	a.FileName = filepath.Base(a.FileName)
+	if a.FileName == "." || a.FileName == string(filepath.Separator) {
+		a.FileName = "document"
+	}
+	a.StoragePath = filepath.Join(requestID.String(), uuid.New().String()+strings.ToLower(filepath.Ext(a.FileName)))
+
+	path := filepath.Join(s.dir, a.StoragePath)
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("failed to store attachment: %w", err)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to store attachment: %w", err)
+	}
+	a.FileSize, err = io.Copy(f, io.LimitReader(content, maxFileSize+1))
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	switch {
+	case err != nil:
+		err = fmt.Errorf("failed to store attachment: %w", err)
+	case a.FileSize == 0:
+		err = errors.New("file is empty")
+	case a.FileSize > maxFileSize:
+		err = errors.New("file size exceeds 10MB limit")
+	default:
+		err = s.repo.AddAttachment(a)
+	}
+	if err != nil {
+		os.Remove(path)
+		return err
+	}
+	return nil
+}
+
+// Open returns an attachment and its file for download; the caller closes the file.
+// Employees can only open files on their own requests unless canViewAll is set.
+func (s *LeaveDocumentService) Open(requestID, attachmentID uuid.UUID, user *models.User, canViewAll bool) (*models.LeaveRequestAttachment, *os.File, error) {
+	a, err := s.repo.GetAttachment(attachmentID)
+	if err != nil || a.LeaveRequestID != requestID {
+		return nil, nil, ErrAttachmentNotFound
+	}
+	if !canViewAll {
+		req, err := s.repo.GetByID(requestID)
+		if err != nil {
+			return nil, nil, ErrAttachmentNotFound
+		}
+		if !s.ownsRequest(user, req) {
+			return nil, nil, ErrLeaveDocumentScope
+		}
+	}
+	f, err := os.Open(filepath.Join(s.dir, a.StoragePath))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open attachment: %w", err)
+	}
+	return a, f, nil
+}
+
+// MarkReceived lets HR settle a request's required document without an upload, e.g. a
+// certificate handed in on paper. Escalated requests are settled the same way.
+func (s *LeaveDocumentService) MarkReceived(requestID uuid.UUID) error {
+	req, err := s.repo.GetByID(requestID)
+	if err != nil {
+		return errors.New("leave request not found")
+	}
+	if req.DocumentStatus != models.LeaveDocumentAwaited && req.DocumentStatus != models.LeaveDocumentEscalated {
+		return errors.New("this leave request is not waiting for a document")
+	}
+	return s.repo.SetDocumentStatus(requestID, models.LeaveDocumentReceived)
+}
+
+// EnforceDocuments chases the documents live requests are waiting for as of today. Once
+// the leave has ended the employee is reminded, once; after the due date the request is
+// escalated to HR or converted to unpaid leave, as its leave type says. A request that
+// fails is logged and tried again on the next run.
+func (s *LeaveDocumentService) EnforceDocuments(today time.Time) (*DocumentEnforcementResult, error) {
+	reqs, err := s.repo.ListAwaitingDocument()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &DocumentEnforcementResult{}
+	leaveTypes := map[uuid.UUID]*models.LeaveType{}
+	for i := range reqs {
+		req := &reqs[i]
+		if req.DocumentDueDate == nil {
+			continue
+		}
+
+		var err error
+		switch {
+		case req.DocumentDueDate.Before(today):
+			lt, ok := leaveTypes[req.LeaveTypeID]
+			if !ok {
+				if lt, err = s.leaveTypeRepo.GetByID(req.LeaveTypeID); err != nil {
+					break
+				}
+				leaveTypes[req.LeaveTypeID] = lt
+			}
+			if lt.MissingDocumentAction == models.MissingDocumentConvertUnpaid {
+				if err = s.convertToUnpaid(req); err == nil {
+					result.Converted++
+				}
+			} else if err = s.escalate(req); err == nil {
+				result.Escalated++
+			}
+		case req.EndDate.Before(today) && req.DocumentRemindedAt == nil:
+			if err = s.remind(req); err == nil {
+				result.Reminded++
+			}
+		default:
+			continue
+		}
+		if err != nil {
+			fmt.Printf("Warning: Failed to chase the document for leave request %s: %v\n", req.ID, err)
+			result.Failed++
+		}
+	}
+	return result, nil
+}
+
+// remind asks the employee to hand in the document before it is due
+func (s *LeaveDocumentService) remind(req *models.LeaveRequest) error {
+	emp, err := s.empRepo.GetByID(req.EmployeeID)
+	if err != nil {
+		return fmt.Errorf("failed to get employee: %w", err)
+	}
+	if s.emailService != nil {
+		body := email.LeaveDocumentReminderTemplate(emp.FirstName, req.LeaveType.Name,
+			req.StartDate.Format("2006-01-02"), req.EndDate.Format("2006-01-02"), req.DocumentDueDate.Format("2006-01-02"))
+		subject := fmt.Sprintf("Reminder: document needed for your %s", req.LeaveType.Name)
+		if err := s.emailService.SendEmail([]string{emp.Email}, subject, body); err != nil {
+			return fmt.Errorf("failed to send reminder: %w", err)
+		}
+	}
+	return s.repo.MarkDocumentReminded(req.ID)
+}
+
+// escalate hands an overdue document to the HR manager with the fewest open tasks. The
+// request is marked escalated even when no one can be emailed, so HR can still find it.
+func (s *LeaveDocumentService) escalate(req *models.LeaveRequest) error {
+	emp, err := s.empRepo.GetByID(req.EmployeeID)
+	if err != nil {
+		return fmt.Errorf("failed to get employee: %w", err)
+	}
+	if err := s.repo.SetDocumentStatus(req.ID, models.LeaveDocumentEscalated); err != nil {
+		return err
+	}
+
+	hr, err := s.userRepo.GetUserWithFewestTasksByRole(models.RoleHRManager)
+	if err != nil {
+		fmt.Printf("Warning: No HR manager to escalate leave request %s to: %v\n", req.ID, err)
+		return nil
+	}
+	if s.emailService != nil {
+		name := fmt.Sprintf("%s %s", emp.FirstName, emp.LastName)
+		body := email.LeaveDocumentOverdueTemplate(name, req.LeaveType.Name,
+			req.StartDate.Format("2006-01-02"), req.EndDate.Format("2006-01-02"), req.DocumentDueDate.Format("2006-01-02"))
+		subject := fmt.Sprintf("Overdue leave document: %s", name)
+		if err := s.emailService.SendEmail([]string{hr.Email}, subject, body); err != nil {
+			fmt.Printf("Warning: Failed to send escalation email for leave request %s: %v\n", req.ID, err)
+		}
+	}
+	return nil
+}
+
+// convertToUnpaid moves an overdue request to unpaid leave, giving its days back to the
+// original leave type's balances, and tells the employee. Without an unpaid leave type the
+// request is escalated instead.
+func (s *LeaveDocumentService) convertToUnpaid(req *models.LeaveRequest) error {
+	ul, err := s.leaveTypeRepo.GetByCode(unpaidLeaveCode)
+	if err != nil {
+		fmt.Printf("Warning: No unpaid leave type to convert leave request %s to, escalating it\n", req.ID)
+		return s.escalate(req)
+	}
+	for _, p := range req.Portions {
+		if _, err := s.balanceService.GetByEmployeeTypeYear(req.EmployeeID, ul.ID, p.Year); err != nil {
+			if err := s.balanceService.InitializeForEmployee(req.EmployeeID, p.Year); err != nil {
+				return err
+			}
+		}
+	}
+	if err := s.repo.ConvertLeaveType(req, ul.ID, models.LeaveDocumentConverted); err != nil {
+		return err
+	}
+
+	emp, err := s.empRepo.GetByID(req.EmployeeID)
+	if err != nil {
+		return nil
+	}
+	if s.emailService != nil {
+		body := email.LeaveConvertedToUnpaidTemplate(emp.FirstName, req.LeaveType.Name, req.TotalDays,
+			req.StartDate.Format("2006-01-02"), req.EndDate.Format("2006-01-02"))
+		subject := fmt.Sprintf("Your %s has been changed to %s", req.LeaveType.Name, ul.Name)
+		if err := s.emailService.SendEmail([]string{emp.Email}, subject, body); err != nil {
+			fmt.Printf("Warning: Failed to send conversion email for leave request %s: %v\n", req.ID, err)
+		}
+	}
+	return nil
+}
+
+func (s *LeaveDocumentService) ownsRequest(user *models.User, req *models.LeaveRequest) bool {
+	emp, err := s.empRepo.GetByUserID(user.UserID)
+	return err == nil && emp.ID == req.EmployeeID
+}
+
+// hasRole reports whether the user has one of the roles
+func hasRole(user *models.User, roles ...string) bool {
+	if user.Role == nil {
+		return false
+	}
+	for _, role := range roles {
+		if user.Role.Name == role {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,196 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"hr-system/internal/models"
+	"hr-system/internal/repository"
+	"hr-system/pkg/utils"
+
+	"github.com/google/uuid"
+)
+
+// LeaveEncashmentService pays employees for unused leave on request. The days come from
+// the current leave year's balance within the leave type's limits, are valued at the daily
+// rate of the employee's base salary and go through the leave encashment workflow; the
+// workflow debits the balance and the payout follows on the next payslip.
+type LeaveEncashmentService struct {
+	repo            *repository.LeaveEncashmentRepository
+	leaveTypeRepo   *repository.LeaveTypeRepository
+	balanceService  *LeaveBalanceService
+	empRepo         *repository.EmployeeRepository
+	posRepo         *repository.PositionRepository
+	workflowService *WorkflowService
+}
+
+func NewLeaveEncashmentService(
+	repo *repository.LeaveEncashmentRepository,
+	ltRepo *repository.LeaveTypeRepository,
+	balanceSvc *LeaveBalanceService,
+	empRepo *repository.EmployeeRepository,
+	posRepo *repository.PositionRepository,
+	workflowService *WorkflowService,
+) *LeaveEncashmentService {
+	return &LeaveEncashmentService{
+		repo:            repo,
+		leaveTypeRepo:   ltRepo,
+		balanceService:  balanceSvc,
+		empRepo:         empRepo,
+		posRepo:         posRepo,
+		workflowService: workflowService,
+	}
+}
+
+// Request records an encashment as pending, holding its days on the balance, and sends it
+// through the leave encashment workflow.
+func (s *LeaveEncashmentService) Request(e *models.LeaveEncashment, requestedBy uuid.UUID) error {
+	emp, err := s.empRepo.GetByID(e.EmployeeID)
+	if err != nil {
+		return errors.New("employee not found")
+	}
+	if emp.EmploymentStatus != models.EmploymentStatusActive {
+		return errors.New("employee is not active")
+	}
+	lt, err := s.leaveTypeRepo.GetByID(e.LeaveTypeID)
+	if err != nil {
+		return errors.New("leave type not found")
+	}
+	if !lt.AllowEncashment {
+		return fmt.Errorf("%s cannot be encashed", lt.Name)
+	}
+
+	e.Days = models.RoundLeaveDays(e.Days)
+	if e.Days <= 0 {
+		return errors.New("days must be greater than zero")
+	}
+	e.Year = s.balanceService.CurrentYear()
+	if err := s.checkLimits(e, lt); err != nil {
+		return err
+	}
+
+	pos, err := s.posRepo.GetByID(emp.PositionID)
+	if err != nil {
+		return errors.New("employee position not found")
+	}
+	e.DailyRate = utils.DailyRate(pos.BaseSalary)
+	e.Amount = e.DailyRate.Mul(e.Days)
+	e.Reason = strings.TrimSpace(e.Reason)
+	e.Status = models.LeaveEncashmentPending
+	e.RequestedBy = &requestedBy
+	if err := s.repo.Create(e); err != nil {
+		return fmt.Errorf("failed to create leave encashment: %w", err)
+	}
+	e.EmployeeName = fmt.Sprintf("%s %s", emp.FirstName, emp.LastName)
+	e.LeaveTypeName = lt.Name
+
+	if s.workflowService != nil {
+		if err := s.initiateEncashmentWorkflow(e, emp, lt, requestedBy); err != nil {
+			// The encashment stays pending and keeps its days held until it is cancelled
+			log.Printf("leave encashment %s: failed to initiate workflow: %v", e.ID, err)
+		}
+	}
+	return nil
+}
+
+// checkLimits makes sure the employee keeps the leave type's minimum balance and does not
+// encash more than its yearly limit, counting encashments still pending
+func (s *LeaveEncashmentService) checkLimits(e *models.LeaveEncashment, lt *models.LeaveType) error {
+	balance, err := s.balanceService.GetByEmployeeTypeYear(e.EmployeeID, e.LeaveTypeID, e.Year)
+	if err != nil {
+		return fmt.Errorf("no %s balance for the current leave year", lt.Name)
+	}
+	if left := models.RoundLeaveDays(balance.Balance - e.Days); left < lt.EncashmentMinBalance {
+		available := models.RoundLeaveDays(balance.Balance - lt.EncashmentMinBalance)
+		if available <= 0 {
+			return fmt.Errorf("no %s days can be encashed; at least %g must be kept", lt.Name, lt.EncashmentMinBalance)
+		}
+		return fmt.Errorf("only %g %s days can be encashed; at least %g must be kept", available, lt.Name, lt.EncashmentMinBalance)
+	}
+
+	if lt.MaxEncashmentDays > 0 {
+		encashed, err := s.repo.EncashedDays(e.EmployeeID, e.LeaveTypeID, e.Year)
+		if err != nil {
+			return err
+		}
+		if models.RoundLeaveDays(encashed+e.Days) > lt.MaxEncashmentDays {
+			return fmt.Errorf("at most %g %s days can be encashed per leave year; %g already encashed or pending",
+				lt.MaxEncashmentDays, lt.Name, encashed)
+		}
+	}
+	return nil
+}
+
+// Cancel withdraws an encashment that has not been approved yet, releasing its days
+func (s *LeaveEncashmentService) Cancel(id uuid.UUID) (*models.LeaveEncashment, error) {
+	e, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("leave encashment not found")
+	}
+	if e.Status != models.LeaveEncashmentPending {
+		return nil, errors.New("only pending encashments can be cancelled")
+	}
+	if err := s.repo.Close(id, models.LeaveEncashmentCancelled, nil); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(id)
+}
+
+func (s *LeaveEncashmentService) GetByID(id uuid.UUID) (*models.LeaveEncashment, error) {
+	e, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("leave encashment not found")
+	}
+	return e, nil
+}
+
+func (s *LeaveEncashmentService) ListByEmployee(employeeID uuid.UUID) ([]models.LeaveEncashment, error) {
+	return s.repo.ListByEmployee(employeeID)
+}
+
+func (s *LeaveEncashmentService) List(status models.LeaveEncashmentStatus) ([]models.LeaveEncashment, error) {
+	return s.repo.List(status)
+}
+
+// GetEmployeeByUserID returns the employee record linked to the given user ID
+func (s *LeaveEncashmentService) GetEmployeeByUserID(userID uuid.UUID) (*models.Employee, error) {
+	return s.empRepo.GetByUserID(userID)
+}
+
+// initiateEncashmentWorkflow creates a workflow instance for the encashment request
+func (s *LeaveEncashmentService) initiateEncashmentWorkflow(e *models.LeaveEncashment, emp *models.Employee, lt *models.LeaveType, requestedBy uuid.UUID) error {
+	positionName := ""
+	if emp.Position != nil {
+		positionName = emp.Position.Title
+	}
+	departmentName := ""
+	if emp.Department != nil {
+		departmentName = emp.Department.Name
+	}
+
+	taskDetails := models.TaskDetails{
+		TaskID:   e.ID.String(),
+		TaskType: "leave_encashment",
+		TaskDescription: fmt.Sprintf("%s %s has requested to encash %g days of %s for %s (%s a day)",
+			emp.FirstName, emp.LastName, e.Days, lt.Name, e.Amount.Format(), e.DailyRate.Format()),
+		SenderDetails: models.SenderDetails{
+			SenderID:   requestedBy.String(),
+			SenderName: fmt.Sprintf("%s %s", emp.FirstName, emp.LastName),
+			Position:   positionName,
+			Department: departmentName,
+		},
+	}
+
+	dueDate := time.Now().Add(72 * time.Hour)
+	_, err := s.workflowService.InitiateWorkflow(
+		models.WorkflowTypeLeaveEncashment,
+		taskDetails,
+		requestedBy.String(),
+		"medium",
+		&dueDate,
+	)
+	return err
+}
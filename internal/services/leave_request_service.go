@@ -3,8 +3,10 @@ package services
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
+	"hr-system/internal/config"
 	"hr-system/internal/interfaces"
 	"hr-system/internal/models"
 	"hr-system/internal/repository"
@@ -17,8 +19,12 @@ type LeaveRequestService struct {
 	balanceService  *LeaveBalanceService
 	leaveTypeRepo   *repository.LeaveTypeRepository
 	holidayRepo     *repository.HolidayRepository
+	blackoutRepo    *repository.LeaveBlackoutRepository
+	deptRepo        *repository.DepartmentRepository
 	empRepo         *repository.EmployeeRepository
+	settingsRepo    *repository.LeaveSettingsRepository
 	workflowService *WorkflowService
+	cfg             config.LeaveConfig
 }
 
 func NewLeaveRequestService(
@@ -26,31 +32,52 @@ func NewLeaveRequestService(
 	balanceSvc *LeaveBalanceService,
 	ltRepo *repository.LeaveTypeRepository,
 	holidayRepo *repository.HolidayRepository,
+	blackoutRepo *repository.LeaveBlackoutRepository,
+	deptRepo *repository.DepartmentRepository,
 	empRepo *repository.EmployeeRepository,
+	settingsRepo *repository.LeaveSettingsRepository,
 	workflowSvc *WorkflowService,
+	cfg config.LeaveConfig,
 ) *LeaveRequestService {
 	return &LeaveRequestService{
 		repo:            repo,
 		balanceService:  balanceSvc,
 		leaveTypeRepo:   ltRepo,
 		holidayRepo:     holidayRepo,
+		blackoutRepo:    blackoutRepo,
+		deptRepo:        deptRepo,
 		empRepo:         empRepo,
+		settingsRepo:    settingsRepo,
 		workflowService: workflowSvc,
+		cfg:             cfg,
 	}
 }
 
+// LeaveRuleError lists the leave rules a request breaks. HR can file the request anyway
+// by giving a reason to override them.
+type LeaveRuleError struct {
+	Violations []models.LeaveRuleViolation
+}
+
+func (e *LeaveRuleError) Error() string {
+	msgs := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		msgs[i] = v.Message
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Create files a leave request. Requests breaking leave rules fail with a *LeaveRuleError
+// unless RuleOverriddenBy and RuleOverrideReason are set by HR.
 func (s *LeaveRequestService) Create(req *models.LeaveRequest) error {
 	// Validate dates
-	today := time.Now().Truncate(24 * time.Hour)
-	if req.StartDate.Before(today) {
-		return errors.New("start_date must be today or in the future")
-	}
 	if req.EndDate.Before(req.StartDate) {
 		return errors.New("end_date must be >= start_date")
 	}
 
 	// Verify employee exists
-	if _, err := s.empRepo.GetByID(req.EmployeeID); err != nil {
+	emp, err := s.empRepo.GetByID(req.EmployeeID)
+	if err != nil {
 		return errors.New("employee not found")
 	}
 
@@ -68,43 +95,67 @@ func (s *LeaveRequestService) Create(req *models.LeaveRequest) error {
 	if err != nil {
 		return err
 	}
-	req.TotalDays = CountBusinessDays(req.StartDate, req.EndDate, holidays)
+	req.TotalDays, err = leaveDays(req, lt, holidays)
+	if err != nil {
+		return err
+	}
 	if req.TotalDays == 0 {
 		return errors.New("leave period contains no working days")
 	}
 
-	// Check balance
-	year := req.StartDate.Year()
-	ok, err := s.balanceService.HasSufficientBalance(req.EmployeeID, req.LeaveTypeID, year, req.TotalDays)
+	// Check leave rules; HR may override them with a reason
+	violations, err := s.checkRules(req, lt, emp, true)
 	if err != nil {
 		return err
 	}
-	if !ok {
-		return errors.New("insufficient leave balance")
+	req.RuleOverrideReason = strings.TrimSpace(req.RuleOverrideReason)
+	if len(violations) == 0 {
+		req.RuleOverrideReason, req.RuleOverriddenBy = "", nil
+	} else if req.RuleOverriddenBy == nil || req.RuleOverrideReason == "" {
+		return &LeaveRuleError{Violations: violations}
+	}
+
+	// Check balance for each leave year the request falls in
+	req.Portions = splitByLeaveYear(req, s.balanceService.LeaveYear(), holidays)
+	for _, p := range req.Portions {
+		ok, err := s.balanceService.HasSufficientBalance(req.EmployeeID, req.LeaveTypeID, p.Year, p.Days)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			if len(req.Portions) > 1 {
+				return fmt.Errorf("insufficient leave balance for %d", p.Year)
+			}
+			return errors.New("insufficient leave balance")
+		}
 	}
 
 	// Check overlap
-	overlap, err := s.repo.HasOverlap(req.EmployeeID, req.StartDate, req.EndDate, nil)
+	existing, err := s.repo.ListOverlapping(req.EmployeeID, req.StartDate, req.EndDate, nil)
 	if err != nil {
 		return err
 	}
-	if overlap {
-		return errors.New("overlapping leave request already exists")
+	for i := range existing {
+		if clashes(req, &existing[i]) {
+			return errors.New("overlapping leave request already exists")
+		}
 	}
 
-	// Warn if document required (don't block)
-	if lt.RequiresDocument && req.AttachmentURL == "" {
-		// just continue — caller may log/warn
-	}
+	// A required document may follow after the leave; it is chased once the grace days pass
+	req.DocumentStatus, req.DocumentDueDate, req.DocumentRemindedAt = "", nil, nil
+	setDocumentDue(req, lt)
 
 	// Create and increment pending balance
 	if err := s.repo.Create(req); err != nil {
 		return err
 	}
-	if err := s.balanceService.IncrementPending(req.EmployeeID, req.LeaveTypeID, year, req.TotalDays); err != nil {
+	if err := s.balanceService.HoldRequest(req); err != nil {
 		return err
 	}
 
+	// Warn about days the department would be short-staffed; this never blocks the request
+	s.setStaffingConflicts(req, emp.DepartmentID)
+
 	// Trigger workflow for leave request approval
 	if s.workflowService != nil {
 		if err := s.initiateLeaveRequestWorkflow(req); err != nil {
@@ -117,6 +168,270 @@ func (s *LeaveRequestService) Create(req *models.LeaveRequest) error {
 	return nil
 }
 
+// checkRules returns the leave type's rules and the blackouts the request breaks. Notice
+// and backdating are only checked when checkStart is set, so an amendment that keeps the
+// start date is not held to them again.
+func (s *LeaveRequestService) checkRules(req *models.LeaveRequest, lt *models.LeaveType, emp *models.Employee, checkStart bool) ([]models.LeaveRuleViolation, error) {
+	var violations []models.LeaveRuleViolation
+	add := func(rule, format string, args ...interface{}) {
+		violations = append(violations, models.LeaveRuleViolation{Rule: rule, Message: fmt.Sprintf(format, args...)})
+	}
+
+	today := time.Now().Truncate(24 * time.Hour)
+	if checkStart {
+		if req.StartDate.Before(today) {
+			if !lt.AllowBackdating {
+				add(models.LeaveRuleBackdating, "%s cannot be requested for past dates", lt.Name)
+			}
+		} else if lt.MinNoticeDays > 0 && req.StartDate.Before(today.AddDate(0, 0, lt.MinNoticeDays)) {
+			add(models.LeaveRuleMinNotice, "%s must be requested at least %d days in advance", lt.Name, lt.MinNoticeDays)
+		}
+	}
+
+	if lt.MaxConsecutiveDays > 0 && req.TotalDays > lt.MaxConsecutiveDays {
+		add(models.LeaveRuleMaxConsecutiveDays, "%s can be taken for at most %g days at a time", lt.Name, lt.MaxConsecutiveDays)
+	}
+
+	if lt.MaxRequestsPerYear > 0 {
+		var excludeID *uuid.UUID
+		if req.ID != uuid.Nil {
+			excludeID = &req.ID
+		}
+		leaveYear := s.balanceService.LeaveYear()
+		year := leaveYear.Of(req.StartDate)
+		count, err := s.repo.CountStartingBetween(req.EmployeeID, lt.ID, leaveYear.Start(year), leaveYear.End(year), excludeID)
+		if err != nil {
+			return nil, err
+		}
+		if count >= lt.MaxRequestsPerYear {
+			add(models.LeaveRuleMaxRequestsPerYear, "%s can be requested at most %d times in leave year %s",
+				lt.Name, lt.MaxRequestsPerYear, leaveYear.Label(year))
+		}
+	}
+
+	if !lt.BlackoutExempt {
+		blackouts, err := s.blackoutRepo.ListActiveInRange(emp.DepartmentID, req.StartDate, req.EndDate)
+		if err != nil {
+			return nil, err
+		}
+		for i := range blackouts {
+			b := &blackouts[i]
+			violations = append(violations, models.LeaveRuleViolation{
+				Rule: models.LeaveRuleBlackout,
+				Message: fmt.Sprintf("leave cannot be taken during %s (%s to %s)", b.Name,
+					b.StartDate.Format("2006-01-02"), b.EndDate.Format("2006-01-02")),
+				BlackoutID: &b.ID,
+			})
+		}
+	}
+	return violations, nil
+}
+
+// leaveDays validates the request's half-day and hourly selections and returns the
+// number of leave days it takes. Hourly leave is a share of the leave type's working day.
+func leaveDays(req *models.LeaveRequest, lt *models.LeaveType, holidays map[string]bool) (float64, error) {
+	if req.StartPart == "" {
+		req.StartPart = models.LeaveDayFull
+	}
+	if req.EndPart == "" {
+		req.EndPart = models.LeaveDayFull
+	}
+	if !req.StartPart.Valid() || !req.EndPart.Valid() {
+		return 0, errors.New("start_part and end_part must be full, am or pm")
+	}
+
+	singleDay := req.EndDate.Equal(req.StartDate)
+	if req.Hours != 0 {
+		if !lt.AllowHourly {
+			return 0, errors.New("this leave type cannot be taken in hours")
+		}
+		if !singleDay {
+			return 0, errors.New("hourly leave must start and end on the same day")
+		}
+		if req.StartPart != models.LeaveDayFull || req.EndPart != models.LeaveDayFull {
+			return 0, errors.New("hourly leave cannot also be a half day")
+		}
+		if req.Hours < 0 || req.Hours > lt.HoursPerDay {
+			return 0, fmt.Errorf("hours must be between 0 and %g", lt.HoursPerDay)
+		}
+		if CountBusinessDays(req.StartDate, req.EndDate, holidays) == 0 {
+			return 0, nil
+		}
+		return models.RoundLeaveDays(req.Hours / lt.HoursPerDay), nil
+	}
+
+	if singleDay {
+		// One half of one day: either part may carry the selection, but not both differently
+		if req.StartPart == models.LeaveDayFull {
+			req.StartPart = req.EndPart
+		} else if req.EndPart != models.LeaveDayFull && req.EndPart != req.StartPart {
+			return 0, errors.New("a single-day request can only take the morning or the afternoon")
+		}
+		req.EndPart = req.StartPart
+	} else {
+		if req.StartPart == models.LeaveDayAM {
+			return 0, errors.New("a request spanning several days can only start in the afternoon (pm)")
+		}
+		if req.EndPart == models.LeaveDayPM {
+			return 0, errors.New("a request spanning several days can only end in the morning (am)")
+		}
+	}
+	return CountLeaveDays(req.StartDate, req.EndDate, req.StartPart, req.EndPart, holidays), nil
+}
+
+// splitByLeaveYear divides a request into the parts that fall in each leave year, with
+// the request's half-day selections applied to its first and last day. Portions with no
+// working days are left out.
+func splitByLeaveYear(req *models.LeaveRequest, leaveYear models.LeaveYear, holidays map[string]bool) []models.LeaveRequestPortion {
+	if req.Hours > 0 {
+		return []models.LeaveRequestPortion{{
+			Year: leaveYear.Of(req.StartDate), StartDate: req.StartDate, EndDate: req.EndDate, Days: req.TotalDays,
+		}}
+	}
+
+	var portions []models.LeaveRequestPortion
+	for start := req.StartDate; !start.After(req.EndDate); {
+		year := leaveYear.Of(start)
+		end := leaveYear.End(year)
+		if end.After(req.EndDate) {
+			end = req.EndDate
+		}
+
+		startPart, endPart := models.LeaveDayFull, models.LeaveDayFull
+		if start.Equal(req.StartDate) {
+			startPart = req.StartPart
+		}
+		if end.Equal(req.EndDate) {
+			endPart = req.EndPart
+		}
+		if start.Equal(end) && startPart == models.LeaveDayFull {
+			startPart = endPart
+		}
+
+		if days := CountLeaveDays(start, end, startPart, endPart, holidays); days > 0 {
+			portions = append(portions, models.LeaveRequestPortion{Year: year, StartDate: start, EndDate: end, Days: days})
+		}
+		start = end.AddDate(0, 0, 1)
+	}
+	return portions
+}
+
+// SyncLeaveYear moves live leave requests onto the configured leave calendar when it is not
+// the one stored with the leave data, then stores the configured one. It runs at startup
+// before the leave jobs, so balances are never read under a calendar they were not split
+// under. It returns nil when the calendar has not changed.
+func (s *LeaveRequestService) SyncLeaveYear() (*models.LeaveYearRebase, error) {
+	stored, err := s.settingsRepo.GetYearStartMonth()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load stored leave year start: %w", err)
+	}
+	configured := s.balanceService.LeaveYear().FirstMonth()
+	if stored == configured {
+		return nil, nil
+	}
+
+	report, err := s.RebaseLeaveYears(nil)
+	if err != nil {
+		return nil, err
+	}
+	report.PreviousStartMonth = stored
+	if err := s.settingsRepo.SetYearStartMonth(configured); err != nil {
+		return nil, fmt.Errorf("leave requests moved but leave year start not stored: %w", err)
+	}
+	return report, nil
+}
+
+// RebaseLeaveYears is the migration path after the leave year start changes. Every pending
+// or approved request ending on or after from (default: the start of the current leave
+// year) is split again under the configured leave calendar, and requests whose portions
+// change are recharged to the matching balances. Entitlements are left for HR to review.
+func (s *LeaveRequestService) RebaseLeaveYears(from *time.Time) (*models.LeaveYearRebase, error) {
+	leaveYear := s.balanceService.LeaveYear()
+	since := leaveYear.Start(s.balanceService.CurrentYear())
+	if from != nil {
+		since = *from
+	}
+	reqs, err := s.repo.ListLive(since)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.LeaveYearRebase{
+		StartMonth: leaveYear.FirstMonth(),
+		Checked:    len(reqs),
+		Moved:      []models.LeaveYearMove{},
+		Failed:     []models.LeaveYearError{},
+	}
+	for i := range reqs {
+		req := &reqs[i]
+		holidays, err := s.holidayRepo.GetHolidaysInRange(req.StartDate, req.EndDate, "")
+		if err != nil {
+			return nil, err
+		}
+		portions := splitByLeaveYear(req, leaveYear, holidays)
+		if samePortions(req.Portions, portions) {
+			continue
+		}
+
+		if err := s.moveRequest(req, portions); err != nil {
+			report.Failed = append(report.Failed, models.LeaveYearError{LeaveRequestID: req.ID, Error: err.Error()})
+			continue
+		}
+		report.Moved = append(report.Moved, models.LeaveYearMove{
+			LeaveRequestID: req.ID,
+			EmployeeID:     req.EmployeeID,
+			From:           req.Portions,
+			To:             portions,
+		})
+	}
+	return report, nil
+}
+
+// moveRequest charges a request to new portions, creating the balances of leave years the
+// employee has none for yet. The balances and portions move together or not at all.
+func (s *LeaveRequestService) moveRequest(req *models.LeaveRequest, portions []models.LeaveRequestPortion) error {
+	for _, p := range portions {
+		if err := s.balanceService.EnsureBalance(req.EmployeeID, req.LeaveTypeID, p.Year); err != nil {
+			return err
+		}
+	}
+	return s.repo.MovePortions(req, portions)
+}
+
+func samePortions(a, b []models.LeaveRequestPortion) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Year != b[i].Year || a[i].Days != b[i].Days {
+			return false
+		}
+	}
+	return true
+}
+
+// clashes reports whether two requests need the same time off: they share a day and
+// neither takes only the half of it the other leaves free
+func clashes(a, b *models.LeaveRequest) bool {
+	start, end := a.StartDate, a.EndDate
+	if b.StartDate.After(start) {
+		start = b.StartDate
+	}
+	if b.EndDate.Before(end) {
+		end = b.EndDate
+	}
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		pa, pb := a.PartOn(d), b.PartOn(d)
+		if pa == "" || pb == "" {
+			continue
+		}
+		if pa == models.LeaveDayFull || pb == models.LeaveDayFull || pa == pb {
+			return true
+		}
+	}
+	return false
+}
+
 // initiateLeaveRequestWorkflow creates a workflow instance for the leave request
 func (s *LeaveRequestService) initiateLeaveRequestWorkflow(req *models.LeaveRequest) error {
 	// Get employee details for the task
@@ -140,7 +455,7 @@ func (s *LeaveRequestService) initiateLeaveRequestWorkflow(req *models.LeaveRequ
 	taskDetails := models.TaskDetails{
 		TaskID:          req.ID.String(),
 		TaskType:        "leave_request",
-		TaskDescription: fmt.Sprintf("%s %s has requested %d days of leave from %s to %s",
+		TaskDescription: fmt.Sprintf("%s %s has requested %g days of leave from %s to %s",
 			employee.FirstName, employee.LastName,
 			req.TotalDays,
 			req.StartDate.Format("2006-01-02"),
@@ -153,6 +468,17 @@ func (s *LeaveRequestService) initiateLeaveRequestWorkflow(req *models.LeaveRequ
 		},
 	}
 
+	// Flag days the approval would leave the department short-staffed
+	if len(req.StaffingConflicts) > 0 {
+		dates := make([]string, len(req.StaffingConflicts))
+		for i, c := range req.StaffingConflicts {
+			dates[i] = c.Date.Format("2006-01-02")
+		}
+		c := req.StaffingConflicts[0]
+		taskDetails.TaskDescription += fmt.Sprintf(". Staffing conflict: %s would have fewer than %d people at work on %s",
+			c.DepartmentName, c.MinStaffOnDuty, strings.Join(dates, ", "))
+	}
+
 	// Calculate due date (e.g., 3 days from now for approval)
 	dueDate := time.Now().Add(72 * time.Hour)
 
@@ -169,7 +495,195 @@ func (s *LeaveRequestService) initiateLeaveRequestWorkflow(req *models.LeaveRequ
 }
 
 func (s *LeaveRequestService) GetByID(id uuid.UUID) (*models.LeaveRequest, error) {
-	return s.repo.GetByID(id)
+	req, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	req.Amendments, err = s.repo.ListAmendments(id)
+	if err != nil {
+		return nil, err
+	}
+	req.Attachments, err = s.repo.ListAttachments(id)
+	if err != nil {
+		return nil, err
+	}
+	if req.Status == models.LeaveStatusPending {
+		if emp, err := s.empRepo.GetByID(req.EmployeeID); err == nil {
+			s.setStaffingConflicts(req, emp.DepartmentID)
+		}
+	}
+	return req, nil
+}
+
+// setStaffingConflicts records on the request the days its approval would take the
+// department below its minimum staffing. Failures are logged, never returned.
+func (s *LeaveRequestService) setStaffingConflicts(req *models.LeaveRequest, departmentID uuid.UUID) {
+	conflicts, err := s.staffingConflicts(req, departmentID)
+	if err != nil {
+		fmt.Printf("Warning: Failed to check staffing for leave request %s: %v\n", req.ID, err)
+		return
+	}
+	req.StaffingConflicts = conflicts
+}
+
+// staffingConflicts returns the working days of the request on which the department would
+// have fewer people at work than its minimum if the request were approved. Other approved
+// and pending leave counts as away, as does leave for part of a day.
+func (s *LeaveRequestService) staffingConflicts(req *models.LeaveRequest, departmentID uuid.UUID) ([]models.StaffingConflict, error) {
+	dept, err := s.deptRepo.GetByID(departmentID)
+	if err != nil {
+		return nil, err
+	}
+	if dept.MinStaffOnDuty == 0 {
+		return nil, nil
+	}
+	headcount, err := s.deptRepo.GetEmployeeCount(dept.ID)
+	if err != nil {
+		return nil, err
+	}
+	leave, err := s.repo.ListCalendar(&dept.ID, nil, req.StartDate, req.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	holidays, err := s.holidayRepo.GetHolidaysInRange(req.StartDate, req.EndDate, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var conflicts []models.StaffingConflict
+	for d := req.StartDate; !d.After(req.EndDate); d = d.AddDate(0, 0, 1) {
+		if IsWeekend(d) || holidays[d.Format("2006-01-02")] {
+			continue
+		}
+		away := map[uuid.UUID]bool{req.EmployeeID: true}
+		for i := range leave {
+			if leave[i].On(d) {
+				away[leave[i].EmployeeID] = true
+			}
+		}
+		if headcount-len(away) < dept.MinStaffOnDuty {
+			conflicts = append(conflicts, models.StaffingConflict{
+				DepartmentID:   dept.ID,
+				DepartmentName: dept.Name,
+				Date:           d,
+				Headcount:      headcount,
+				Away:           len(away),
+				MinStaffOnDuty: dept.MinStaffOnDuty,
+			})
+		}
+	}
+	return conflicts, nil
+}
+
+// ErrLeaveCalendarScope is returned when a manager asks for the calendar of people outside
+// their reporting line
+var ErrLeaveCalendarScope = errors.New("you can only view the leave calendar of your own reporting line")
+
+// Calendar returns the approved and pending leave of a department or, when managerID is
+// set, of everyone reporting to the manager, over [from, to]. Each day counts the people
+// away and, for a department, whether that leaves it below its minimum staffing. Unless
+// canViewAny, the viewer may only see their own team, a manager within it, or a department
+// made up of their team.
+func (s *LeaveRequestService) Calendar(departmentID, managerID *uuid.UUID, from, to time.Time, viewerID uuid.UUID, canViewAny bool) (*models.LeaveCalendar, error) {
+	if to.Before(from) {
+		return nil, errors.New("to must be >= from")
+	}
+	if to.After(from.AddDate(1, 0, 0)) {
+		return nil, errors.New("the calendar can cover at most one year")
+	}
+	if !canViewAny {
+		if err := s.checkCalendarScope(departmentID, managerID, viewerID); err != nil {
+			return nil, err
+		}
+	}
+
+	cal := &models.LeaveCalendar{From: from, To: to, Days: []models.LeaveCalendarDay{}}
+	switch {
+	case managerID != nil:
+		if _, err := s.empRepo.GetByID(*managerID); err != nil {
+			return nil, errors.New("manager not found")
+		}
+		count, err := s.empRepo.CountTeam(*managerID)
+		if err != nil {
+			return nil, err
+		}
+		cal.ManagerID, cal.Headcount = managerID, count
+	case departmentID != nil:
+		dept, err := s.deptRepo.GetByID(*departmentID)
+		if err != nil {
+			return nil, errors.New("department not found")
+		}
+		count, err := s.deptRepo.GetEmployeeCount(dept.ID)
+		if err != nil {
+			return nil, err
+		}
+		cal.DepartmentID, cal.Headcount, cal.MinStaffOnDuty = departmentID, count, dept.MinStaffOnDuty
+	default:
+		return nil, errors.New("department_id or manager_id is required")
+	}
+
+	var err error
+	if cal.Leave, err = s.repo.ListCalendar(cal.DepartmentID, cal.ManagerID, from, to); err != nil {
+		return nil, err
+	}
+	if cal.Holidays, err = s.holidayRepo.ListInRange(from, to, ""); err != nil {
+		return nil, err
+	}
+	holidays := map[string]string{}
+	for _, h := range cal.Holidays {
+		holidays[h.Date.Format("2006-01-02")] = h.Name
+	}
+
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		day := models.LeaveCalendarDay{Date: d, Holiday: holidays[d.Format("2006-01-02")]}
+		day.WorkingDay = !IsWeekend(d) && day.Holiday == ""
+		approved, pending := map[uuid.UUID]bool{}, map[uuid.UUID]bool{}
+		for i := range cal.Leave {
+			e := &cal.Leave[i]
+			if !e.On(d) {
+				continue
+			}
+			if e.Status == models.LeaveStatusApproved {
+				approved[e.EmployeeID] = true
+			} else {
+				pending[e.EmployeeID] = true
+			}
+		}
+		for id := range pending {
+			if approved[id] {
+				delete(pending, id)
+			}
+		}
+		day.Approved, day.Pending = len(approved), len(pending)
+		day.Understaffed = day.WorkingDay && cal.MinStaffOnDuty > 0 &&
+			cal.Headcount-day.Approved-day.Pending < cal.MinStaffOnDuty
+		cal.Days = append(cal.Days, day)
+	}
+	return cal, nil
+}
+
+// checkCalendarScope allows a manager's calendar for the viewer or someone in their team,
+// and a department's when everyone in it is the viewer or in their team
+func (s *LeaveRequestService) checkCalendarScope(departmentID, managerID *uuid.UUID, viewerID uuid.UUID) error {
+	if managerID != nil && *managerID != viewerID {
+		in, err := s.empRepo.InTeam(viewerID, *managerID)
+		if err != nil {
+			return err
+		}
+		if !in {
+			return ErrLeaveCalendarScope
+		}
+	}
+	if managerID == nil && departmentID != nil {
+		in, err := s.empRepo.DepartmentInTeam(viewerID, *departmentID)
+		if err != nil {
+			return err
+		}
+		if !in {
+			return ErrLeaveCalendarScope
+		}
+	}
+	return nil
 }
 
 func (s *LeaveRequestService) List(filter interfaces.LeaveRequestFilter, page, pageSize int) ([]models.LeaveRequest, int, error) {
@@ -195,11 +709,229 @@ func (s *LeaveRequestService) Cancel(id, requestorID uuid.UUID) error {
 		return errors.New("only pending or approved requests can be cancelled")
 	}
 
-	year := req.StartDate.Year()
 	if err := s.repo.UpdateStatus(id, models.LeaveStatusCancelled, nil, ""); err != nil {
 		return err
 	}
-	return s.balanceService.DecrementPending(req.EmployeeID, req.LeaveTypeID, year, req.TotalDays)
+	if req.Status == models.LeaveStatusApproved {
+		return s.balanceService.RefundRequest(req)
+	}
+	return s.balanceService.ReleaseRequest(req)
+}
+
+// Amend changes the leave type, dates or reason of the employee's pending or approved
+// request in place. The days are counted and checked again, and the balances move with the
+// request in one transaction. Approved leave that only gets shorter keeps its approval when
+// LEAVE_AUTO_APPROVE_SHORTENING is on; any other change goes back through approval.
+// Amendments breaking leave rules fail with a *LeaveRuleError unless HR sets
+// RuleOverriddenBy and RuleOverrideReason, or the request already had its rules overridden
+// and the amendment only shortens it.
+func (s *LeaveRequestService) Amend(id, requestorID uuid.UUID, input interfaces.AmendLeaveInput) (*models.LeaveRequest, error) {
+	previous, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("leave request not found")
+	}
+	if previous.EmployeeID != requestorID && input.RuleOverriddenBy == nil {
+		return nil, errors.New("you can only amend your own leave requests")
+	}
+	if previous.Status != models.LeaveStatusPending && previous.Status != models.LeaveStatusApproved {
+		return nil, errors.New("only pending or approved requests can be amended")
+	}
+
+	req := *previous
+	req.LeaveType, req.Portions, req.Amendments = nil, nil, nil
+	if input.LeaveTypeID != nil {
+		req.LeaveTypeID = *input.LeaveTypeID
+	}
+	if input.StartDate != nil {
+		req.StartDate = *input.StartDate
+	}
+	if input.EndDate != nil {
+		req.EndDate = *input.EndDate
+	}
+	if input.StartPart != nil {
+		req.StartPart = *input.StartPart
+	}
+	if input.EndPart != nil {
+		req.EndPart = *input.EndPart
+	}
+	if input.Hours != nil {
+		req.Hours = *input.Hours
+	}
+	if input.Reason != nil {
+		req.Reason = *input.Reason
+	}
+
+	// Validate dates: leave already under way can only be cut short
+	today := time.Now().Truncate(24 * time.Hour)
+	if previous.Status == models.LeaveStatusApproved && !previous.StartDate.After(time.Now()) {
+		if req.LeaveTypeID != previous.LeaveTypeID || !req.StartDate.Equal(previous.StartDate) || req.StartPart != previous.StartPart {
+			return nil, errors.New("leave that has already started can only be shortened")
+		}
+		if req.EndDate.Before(today) {
+			return nil, errors.New("end_date must be today or in the future")
+		}
+	}
+	if req.EndDate.Before(req.StartDate) {
+		return nil, errors.New("end_date must be >= start_date")
+	}
+
+	// Verify leave type
+	lt, err := s.leaveTypeRepo.GetByID(req.LeaveTypeID)
+	if err != nil {
+		return nil, errors.New("leave type not found")
+	}
+	if !lt.IsActive && req.LeaveTypeID != previous.LeaveTypeID {
+		return nil, errors.New("leave type is inactive")
+	}
+
+	// Count business days
+	holidays, err := s.holidayRepo.GetHolidaysInRange(req.StartDate, req.EndDate, "")
+	if err != nil {
+		return nil, err
+	}
+	req.TotalDays, err = leaveDays(&req, lt, holidays)
+	if err != nil {
+		return nil, err
+	}
+	if req.TotalDays == 0 {
+		return nil, errors.New("leave period contains no working days")
+	}
+
+	// Check leave rules against the amended request; HR may override them with a reason.
+	// A request whose rules were overridden keeps the override while it only gets shorter.
+	emp, err := s.empRepo.GetByID(req.EmployeeID)
+	if err != nil {
+		return nil, errors.New("employee not found")
+	}
+	violations, err := s.checkRules(&req, lt, emp, !req.StartDate.Equal(previous.StartDate))
+	if err != nil {
+		return nil, err
+	}
+	shortened := req.LeaveTypeID == previous.LeaveTypeID && covers(previous, &req)
+	overrideReason := strings.TrimSpace(input.RuleOverrideReason)
+	switch {
+	case len(violations) == 0:
+		req.RuleOverrideReason, req.RuleOverriddenBy = "", nil
+	case input.RuleOverriddenBy != nil && overrideReason != "":
+		req.RuleOverrideReason, req.RuleOverriddenBy = overrideReason, input.RuleOverriddenBy
+	case shortened && previous.RuleOverriddenBy != nil:
+		// keeps the original override
+	default:
+		return nil, &LeaveRuleError{Violations: violations}
+	}
+
+	// Check balance for the days the amendment adds in each leave year; days the request
+	// already holds of the same leave type are given back when it is saved
+	held := map[int]float64{}
+	if req.LeaveTypeID == previous.LeaveTypeID {
+		for _, p := range previous.Portions {
+			held[p.Year] += p.Days
+		}
+	}
+	req.Portions = splitByLeaveYear(&req, s.balanceService.LeaveYear(), holidays)
+	for _, p := range req.Portions {
+		extra := p.Days - held[p.Year]
+		if extra <= 0 {
+			continue
+		}
+		ok, err := s.balanceService.HasSufficientBalance(req.EmployeeID, req.LeaveTypeID, p.Year, extra)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			if len(req.Portions) > 1 {
+				return nil, fmt.Errorf("insufficient leave balance for %d", p.Year)
+			}
+			return nil, errors.New("insufficient leave balance")
+		}
+	}
+
+	// Check overlap with the employee's other requests
+	existing, err := s.repo.ListOverlapping(req.EmployeeID, req.StartDate, req.EndDate, &req.ID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range existing {
+		if clashes(&req, &existing[i]) {
+			return nil, errors.New("overlapping leave request already exists")
+		}
+	}
+
+	setDocumentDue(&req, lt)
+
+	autoApproved := s.cfg.AutoApproveShortening && previous.Status == models.LeaveStatusApproved &&
+		shortened && req.TotalDays <= previous.TotalDays
+	if !autoApproved {
+		req.Status = models.LeaveStatusPending
+		req.ReviewedBy, req.ReviewedAt, req.ReviewComment = nil, nil, ""
+	}
+
+	amendment := &models.LeaveRequestAmendment{
+		PreviousLeaveTypeID: previous.LeaveTypeID,
+		PreviousStartDate:   previous.StartDate,
+		PreviousEndDate:     previous.EndDate,
+		PreviousStartPart:   previous.StartPart,
+		PreviousEndPart:     previous.EndPart,
+		PreviousHours:       previous.Hours,
+		PreviousTotalDays:   previous.TotalDays,
+		PreviousReason:      previous.Reason,
+		PreviousStatus:      previous.Status,
+		PreviousReviewedBy:  previous.ReviewedBy,
+		PreviousReviewedAt:  previous.ReviewedAt,
+		AutoApproved:        autoApproved,
+		AmendedBy:           requestorID,
+	}
+	if err := s.repo.Amend(&req, previous, amendment); err != nil {
+		return nil, err
+	}
+
+	// Send the amended request back through approval
+	if !autoApproved {
+		s.setStaffingConflicts(&req, emp.DepartmentID)
+	}
+	if !autoApproved && s.workflowService != nil {
+		if err := s.workflowService.WithdrawWorkflow(req.ID.String(), emp.UserID.String(), "Leave request amended"); err != nil {
+			fmt.Printf("Warning: Failed to withdraw workflow for leave request %s: %v\n", req.ID, err)
+		}
+		if err := s.initiateLeaveRequestWorkflow(&req); err != nil {
+			fmt.Printf("Warning: Failed to initiate workflow for leave request %s: %v\n", req.ID, err)
+		}
+	}
+
+	return s.GetByID(req.ID)
+}
+
+// setDocumentDue works out whether the request waits for the document its leave type
+// requires and, if so, when it is due: the leave type's grace days after the leave ends.
+// Documents already received or overdue are left as they are.
+func setDocumentDue(req *models.LeaveRequest, lt *models.LeaveType) {
+	switch req.DocumentStatus {
+	case models.LeaveDocumentReceived, models.LeaveDocumentEscalated, models.LeaveDocumentConverted:
+		return
+	}
+	switch {
+	case !lt.RequiresDocument:
+		req.DocumentStatus, req.DocumentDueDate = models.LeaveDocumentNotRequired, nil
+	case req.AttachmentURL != "":
+		req.DocumentStatus, req.DocumentDueDate = models.LeaveDocumentReceived, nil
+	default:
+		due := req.EndDate.AddDate(0, 0, lt.DocumentGraceDays)
+		req.DocumentStatus, req.DocumentDueDate = models.LeaveDocumentAwaited, &due
+	}
+}
+
+// covers reports whether every part of a day b takes off is already taken by a
+func covers(a, b *models.LeaveRequest) bool {
+	for d := b.StartDate; !d.After(b.EndDate); d = d.AddDate(0, 0, 1) {
+		pa, pb := a.PartOn(d), b.PartOn(d)
+		if pa == "" {
+			return false
+		}
+		if pa != models.LeaveDayFull && pa != pb {
+			return false
+		}
+	}
+	return true
 }
 
 func (s *LeaveRequestService) Approve(id, reviewerEmployeeID uuid.UUID, comment string) error {
@@ -211,11 +943,10 @@ func (s *LeaveRequestService) Approve(id, reviewerEmployeeID uuid.UUID, comment
 		return errors.New("only pending requests can be approved")
 	}
 
-	year := req.StartDate.Year()
 	if err := s.repo.UpdateStatus(id, models.LeaveStatusApproved, &reviewerEmployeeID, comment); err != nil {
 		return err
 	}
-	return s.balanceService.ApproveLeave(req.EmployeeID, req.LeaveTypeID, year, req.TotalDays)
+	return s.balanceService.ApproveRequest(req)
 }
 
 func (s *LeaveRequestService) Reject(id, reviewerEmployeeID uuid.UUID, comment string) error {
@@ -227,9 +958,8 @@ func (s *LeaveRequestService) Reject(id, reviewerEmployeeID uuid.UUID, comment s
 		return errors.New("only pending requests can be rejected")
 	}
 
-	year := req.StartDate.Year()
 	if err := s.repo.UpdateStatus(id, models.LeaveStatusRejected, &reviewerEmployeeID, comment); err != nil {
 		return err
 	}
-	return s.balanceService.DecrementPending(req.EmployeeID, req.LeaveTypeID, year, req.TotalDays)
+	return s.balanceService.ReleaseRequest(req)
 }
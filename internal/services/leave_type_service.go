@@ -23,6 +23,9 @@ func (s *LeaveTypeService) SeedDefaults() error {
 		_, err := s.repo.GetByCode(lt.Code)
 		if err != nil {
 			lt.IsActive = true
+			lt.HoursPerDay = models.DefaultHoursPerDay
+			lt.DocumentGraceDays = models.DefaultDocumentGraceDays
+			lt.MissingDocumentAction = models.MissingDocumentEscalate
 			if err := s.repo.Create(&lt); err != nil {
 				return err
 			}
@@ -43,6 +46,21 @@ func (s *LeaveTypeService) Create(lt *models.LeaveType) error {
 	if exists {
 		return errors.New("leave type code already exists")
 	}
+	if err := setHoursPerDay(lt); err != nil {
+		return err
+	}
+	if lt.MinNoticeDays < 0 || lt.MaxConsecutiveDays < 0 || lt.MaxRequestsPerYear < 0 {
+		return errors.New("min_notice_days, max_consecutive_days and max_requests_per_year cannot be negative")
+	}
+	if err := setMissingDocumentRule(lt); err != nil {
+		return err
+	}
+	if lt.MaxEncashmentDays < 0 || lt.EncashmentMinBalance < 0 {
+		return errors.New("max_encashment_days and encashment_min_balance cannot be negative")
+	}
+	if lt.CarryForwardExpiryMonths < 0 || lt.CarryForwardExpiryMonths > 12 {
+		return errors.New("carry_forward_expiry_months must be between 0 and 12")
+	}
 	lt.IsActive = true
 	return s.repo.Create(lt)
 }
@@ -70,5 +88,47 @@ func (s *LeaveTypeService) Update(lt *models.LeaveType) error {
 			return errors.New("leave type code already exists")
 		}
 	}
+	if err := setHoursPerDay(lt); err != nil {
+		return err
+	}
+	if lt.MinNoticeDays < 0 || lt.MaxConsecutiveDays < 0 || lt.MaxRequestsPerYear < 0 {
+		return errors.New("min_notice_days, max_consecutive_days and max_requests_per_year cannot be negative")
+	}
+	if err := setMissingDocumentRule(lt); err != nil {
+		return err
+	}
+	if lt.MaxEncashmentDays < 0 || lt.EncashmentMinBalance < 0 {
+		return errors.New("max_encashment_days and encashment_min_balance cannot be negative")
+	}
+	if lt.CarryForwardExpiryMonths < 0 || lt.CarryForwardExpiryMonths > 12 {
+		return errors.New("carry_forward_expiry_months must be between 0 and 12")
+	}
 	return s.repo.Update(lt)
 }
+
+// setHoursPerDay defaults the length of a leave day to a standard working day
+func setHoursPerDay(lt *models.LeaveType) error {
+	if lt.HoursPerDay < 0 || lt.HoursPerDay > 24 {
+		return errors.New("hours_per_day must be between 0 and 24")
+	}
+	if lt.HoursPerDay == 0 {
+		lt.HoursPerDay = models.DefaultHoursPerDay
+	}
+	return nil
+}
+
+// setMissingDocumentRule checks what happens when a required document is not handed in,
+// escalating to HR by default
+func setMissingDocumentRule(lt *models.LeaveType) error {
+	if lt.DocumentGraceDays < 0 {
+		return errors.New("document_grace_days cannot be negative")
+	}
+	switch lt.MissingDocumentAction {
+	case "":
+		lt.MissingDocumentAction = models.MissingDocumentEscalate
+	case models.MissingDocumentEscalate, models.MissingDocumentConvertUnpaid:
+	default:
+		return errors.New("missing_document_action must be escalate or convert_unpaid")
+	}
+	return nil
+}
@@ -0,0 +1,260 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"hr-system/internal/models"
+	"hr-system/internal/repository"
+	"hr-system/pkg/money"
+
+	"github.com/google/uuid"
+)
+
+const (
+	maxLoanInstalments    = 60
+	maxAdvanceInstalments = 3
+)
+
+type LoanService struct {
+	repo            *repository.LoanRepository
+	empRepo         *repository.EmployeeRepository
+	posRepo         *repository.PositionRepository
+	workflowService *WorkflowService
+}
+
+func NewLoanService(
+	repo *repository.LoanRepository,
+	empRepo *repository.EmployeeRepository,
+	posRepo *repository.PositionRepository,
+	workflowService *WorkflowService,
+) *LoanService {
+	return &LoanService{
+		repo:            repo,
+		empRepo:         empRepo,
+		posRepo:         posRepo,
+		workflowService: workflowService,
+	}
+}
+
+// Request records a loan or salary advance as pending and sends it through the loan
+// approval workflow. Repayments are only deducted once the workflow approves it.
+func (s *LoanService) Request(l *models.EmployeeLoan, requestedBy uuid.UUID) error {
+	emp, err := s.empRepo.GetByID(l.EmployeeID)
+	if err != nil {
+		return errors.New("employee not found")
+	}
+	if emp.EmploymentStatus != models.EmploymentStatusActive {
+		return errors.New("employee is not active")
+	}
+	if l.LoanType == "" {
+		l.LoanType = models.LoanTypeLoan
+	}
+	if err := s.validateTerms(l, emp); err != nil {
+		return err
+	}
+
+	l.Reason = strings.TrimSpace(l.Reason)
+	l.Status = models.LoanStatusPending
+	l.RequestedBy = &requestedBy
+	if err := s.repo.Create(l); err != nil {
+		return fmt.Errorf("failed to create loan: %w", err)
+	}
+
+	if s.workflowService != nil {
+		if err := s.initiateLoanWorkflow(l, emp, requestedBy); err != nil {
+			// The loan stays pending; the workflow can be started again by HR
+			log.Printf("loan %s: failed to initiate workflow: %v", l.ID, err)
+		}
+	}
+	return nil
+}
+
+// UpdateTerms lets HR adjust the amount, interest and schedule of a loan before it is approved.
+func (s *LoanService) UpdateTerms(id uuid.UUID, terms *models.EmployeeLoan) (*models.EmployeeLoan, error) {
+	l, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("loan not found")
+	}
+	if l.Status != models.LoanStatusPending {
+		return nil, errors.New("only pending loans can be changed")
+	}
+	emp, err := s.empRepo.GetByID(l.EmployeeID)
+	if err != nil {
+		return nil, errors.New("employee not found")
+	}
+
+	l.Principal = terms.Principal
+	l.InterestRate = terms.InterestRate
+	l.Instalments = terms.Instalments
+	l.StartMonth = terms.StartMonth
+	l.StartYear = terms.StartYear
+	if err := s.validateTerms(l, emp); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateTerms(l); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(id)
+}
+
+// Cancel withdraws a loan request that has not been approved yet.
+func (s *LoanService) Cancel(id uuid.UUID) (*models.EmployeeLoan, error) {
+	l, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("loan not found")
+	}
+	if l.Status != models.LoanStatusPending {
+		return nil, errors.New("only pending loans can be cancelled")
+	}
+	if err := s.repo.UpdateStatus(id, models.LoanStatusCancelled, nil); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(id)
+}
+
+// Settle records a direct repayment against an active loan. A zero amount settles the
+// whole outstanding balance; a smaller amount is an early part-payment that shortens the
+// schedule, since instalments carry on at the same amount until the balance is cleared.
+func (s *LoanService) Settle(id uuid.UUID, amount money.Money, notes string, recordedBy uuid.UUID) (*models.EmployeeLoan, error) {
+	l, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("loan not found")
+	}
+	if l.Status != models.LoanStatusActive {
+		return nil, fmt.Errorf("cannot settle a %s loan", l.Status)
+	}
+	if amount == 0 {
+		amount = l.OutstandingBalance
+	}
+	if amount < 0 {
+		return nil, errors.New("amount must be positive")
+	}
+	if amount > l.OutstandingBalance {
+		return nil, fmt.Errorf("amount exceeds the outstanding balance of %s", l.OutstandingBalance.Format())
+	}
+
+	now := time.Now()
+	repayment := &models.LoanRepayment{
+		LoanID:        id,
+		RepaymentType: models.LoanRepaymentEarlySettlement,
+		Amount:        amount,
+		Month:         int(now.Month()),
+		Year:          now.Year(),
+		Notes:         strings.TrimSpace(notes),
+		RecordedBy:    &recordedBy,
+	}
+	if err := s.repo.CreateRepayment(repayment); err != nil {
+		return nil, fmt.Errorf("failed to record repayment: %w", err)
+	}
+	return s.GetByID(id)
+}
+
+// GetByID returns a loan with its repayment history.
+func (s *LoanService) GetByID(id uuid.UUID) (*models.EmployeeLoan, error) {
+	l, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("loan not found")
+	}
+	l.Repayments, err = s.repo.ListRepayments(id)
+	if err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (s *LoanService) ListByEmployee(employeeID uuid.UUID) ([]models.EmployeeLoan, error) {
+	return s.repo.ListByEmployee(employeeID)
+}
+
+func (s *LoanService) List(status models.LoanStatus) ([]models.EmployeeLoan, error) {
+	return s.repo.List(status)
+}
+
+// GetEmployeeByUserID returns the employee record linked to the given user ID
+func (s *LoanService) GetEmployeeByUserID(userID uuid.UUID) (*models.Employee, error) {
+	return s.empRepo.GetByUserID(userID)
+}
+
+// validateTerms checks a loan's amount and schedule and works out the repayments. The
+// first deduction defaults to next month and cannot be in a month already past.
+func (s *LoanService) validateTerms(l *models.EmployeeLoan, emp *models.Employee) error {
+	now := time.Now()
+	if l.StartMonth == 0 && l.StartYear == 0 {
+		next := time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+		l.StartMonth, l.StartYear = int(next.Month()), next.Year()
+	}
+
+	switch {
+	case l.LoanType != models.LoanTypeLoan && l.LoanType != models.LoanTypeAdvance:
+		return fmt.Errorf("invalid loan_type: %s", l.LoanType)
+	case l.Principal <= 0:
+		return errors.New("principal must be greater than zero")
+	case l.InterestRate < 0 || l.InterestRate > 100:
+		return errors.New("interest_rate must be between 0 and 100")
+	case l.Instalments < 1 || l.Instalments > maxLoanInstalments:
+		return fmt.Errorf("instalments must be between 1 and %d", maxLoanInstalments)
+	case l.StartMonth < 1 || l.StartMonth > 12:
+		return errors.New("start_month must be between 1 and 12")
+	case l.StartYear*12+l.StartMonth < now.Year()*12+int(now.Month()):
+		return errors.New("repayments cannot start in a past month")
+	}
+
+	if l.LoanType == models.LoanTypeAdvance {
+		if l.InterestRate != 0 {
+			return errors.New("salary advances do not carry interest")
+		}
+		if l.Instalments > maxAdvanceInstalments {
+			return fmt.Errorf("salary advances must be repaid within %d months", maxAdvanceInstalments)
+		}
+		if pos, err := s.posRepo.GetByID(emp.PositionID); err == nil && l.Principal > pos.NetSalary() {
+			return fmt.Errorf("a salary advance cannot exceed one month's net salary (%s)", pos.NetSalary().Format())
+		}
+	}
+
+	l.ApplyTerms()
+	return nil
+}
+
+// initiateLoanWorkflow creates a workflow instance for the loan request
+func (s *LoanService) initiateLoanWorkflow(l *models.EmployeeLoan, emp *models.Employee, requestedBy uuid.UUID) error {
+	positionName := ""
+	if emp.Position != nil {
+		positionName = emp.Position.Title
+	}
+	departmentName := ""
+	if emp.Department != nil {
+		departmentName = emp.Department.Name
+	}
+	kind := "loan"
+	if l.LoanType == models.LoanTypeAdvance {
+		kind = "salary advance"
+	}
+
+	taskDetails := models.TaskDetails{
+		TaskID:   l.ID.String(),
+		TaskType: "loan_request",
+		TaskDescription: fmt.Sprintf("%s %s has requested a %s of %s, repayable in %d instalments of %s from %s %d",
+			emp.FirstName, emp.LastName, kind, l.Principal.Format(),
+			l.Instalments, l.InstalmentAmount.Format(), time.Month(l.StartMonth), l.StartYear),
+		SenderDetails: models.SenderDetails{
+			SenderID:   requestedBy.String(),
+			SenderName: fmt.Sprintf("%s %s", emp.FirstName, emp.LastName),
+			Position:   positionName,
+			Department: departmentName,
+		},
+	}
+
+	dueDate := time.Now().Add(72 * time.Hour)
+	_, err := s.workflowService.InitiateWorkflow(
+		models.WorkflowTypeLoanRequest,
+		taskDetails,
+		requestedBy.String(),
+		"medium",
+		&dueDate,
+	)
+	return err
+}
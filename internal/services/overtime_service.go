@@ -0,0 +1,116 @@
+package services
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"hr-system/internal/models"
+	"hr-system/internal/repository"
+	"hr-system/pkg/money"
+	"hr-system/pkg/utils"
+
+	"github.com/google/uuid"
+)
+
+type OvertimeService struct {
+	ruleRepo    *repository.OvertimeRuleRepository
+	attRepo     *repository.AttendanceRepository
+	holidayRepo *repository.HolidayRepository
+}
+
+func NewOvertimeService(
+	ruleRepo *repository.OvertimeRuleRepository,
+	attRepo *repository.AttendanceRepository,
+	holidayRepo *repository.HolidayRepository,
+) *OvertimeService {
+	return &OvertimeService{ruleRepo: ruleRepo, attRepo: attRepo, holidayRepo: holidayRepo}
+}
+
+// GetRules returns the configured overtime rules, falling back to the defaults.
+func (s *OvertimeService) GetRules() *models.OvertimeRule {
+	rule, err := s.ruleRepo.Get()
+	if err != nil {
+		return models.DefaultOvertimeRule()
+	}
+	return rule
+}
+
+func (s *OvertimeService) UpdateRules(rule *models.OvertimeRule) error {
+	if rule.WeekdayMultiplier < 1 || rule.WeekendMultiplier < 1 || rule.HolidayMultiplier < 1 {
+		return errors.New("overtime multipliers must be at least 1.0")
+	}
+	if rule.StandardMonthlyHours <= 0 {
+		return errors.New("standard_monthly_hours must be greater than 0")
+	}
+	if rule.MaxMonthlyHours < 0 {
+		return errors.New("max_monthly_hours cannot be negative")
+	}
+	return s.ruleRepo.Upsert(rule)
+}
+
+// CalculateForPeriod aggregates an employee's manager-approved overtime between from and to
+// (inclusive) and prices it against the hourly rate derived from baseSalary.
+// Holiday hours take precedence over weekend hours. The monthly cap applies per calendar
+// month across pay periods: it is consumed in date order from the first of the month, so
+// approved hours earlier in the month, paid in an earlier weekly or bi-weekly period,
+// count against it before this period's hours do.
+func (s *OvertimeService) CalculateForPeriod(employeeID uuid.UUID, baseSalary money.Money, from, to time.Time) (*models.OvertimeSummary, error) {
+	rule := s.GetRules()
+	summary := &models.OvertimeSummary{
+		HourlyRate: utils.OvertimeHourlyRate(baseSalary, rule.StandardMonthlyHours),
+	}
+
+	monthStart := time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, from.Location())
+	records, err := s.attRepo.ListApprovedOvertime(employeeID, monthStart, to)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return summary, nil
+	}
+
+	holidays, err := s.holidayRepo.GetHolidaysInRange(from, to, "")
+	if err != nil {
+		return nil, err
+	}
+
+	weightedHours := addOvertime(summary, rule, records, holidays, from)
+	summary.Amount = summary.HourlyRate.Mul(weightedHours)
+	return summary, nil
+}
+
+// addOvertime adds the hours of the approved overtime records dated on or after from to the
+// summary, records being in date order, and returns them weighted by their multipliers so
+// they are priced, and rounded, once. Earlier records only use up the monthly cap.
+func addOvertime(summary *models.OvertimeSummary, rule *models.OvertimeRule, records []models.Attendance, holidays map[string]bool, from time.Time) float64 {
+	used := map[string]float64{} // hours counted against the cap, by month
+	weightedHours := 0.0
+	for _, a := range records {
+		hours := a.OvertimeHours
+		if rule.MaxMonthlyHours > 0 {
+			month := a.Date.Format("2006-01")
+			hours = math.Max(0, math.Min(hours, rule.MaxMonthlyHours-used[month]))
+			used[month] += hours
+		}
+		if hours == 0 || a.Date.Before(from) {
+			continue
+		}
+
+		multiplier := rule.WeekdayMultiplier
+		switch {
+		case holidays[a.Date.Format("2006-01-02")]:
+			multiplier = rule.HolidayMultiplier
+			summary.HolidayHours += hours
+		case IsWeekend(a.Date):
+			multiplier = rule.WeekendMultiplier
+			summary.WeekendHours += hours
+		default:
+			summary.WeekdayHours += hours
+		}
+
+		summary.PaidHours += hours
+		weightedHours += hours * multiplier
+	}
+	return weightedHours
+}
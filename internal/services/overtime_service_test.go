@@ -0,0 +1,98 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"hr-system/internal/models"
+)
+
+func overtimeOn(date string, hours float64) models.Attendance {
+	d, _ := time.Parse("2006-01-02", date)
+	return models.Attendance{Date: d, OvertimeHours: hours}
+}
+
+func TestAddOvertimeCapsMonthAcrossWeeklyPeriods(t *testing.T) {
+	rule := &models.OvertimeRule{WeekdayMultiplier: 1.5, WeekendMultiplier: 2, HolidayMultiplier: 2.5, MaxMonthlyHours: 10}
+	// Approved overtime on Mondays in March 2026; the weekly periods start on those Mondays
+	records := []models.Attendance{
+		overtimeOn("2026-03-02", 6),
+		overtimeOn("2026-03-09", 6),
+		overtimeOn("2026-03-16", 3),
+	}
+
+	tests := []struct {
+		from string
+		to   string
+		want float64
+	}{
+		{"2026-03-02", "2026-03-08", 6},
+		{"2026-03-09", "2026-03-15", 4}, // 6 already paid in the first week
+		{"2026-03-16", "2026-03-22", 0}, // the month's cap is used up
+	}
+	for _, tt := range tests {
+		from, _ := time.Parse("2006-01-02", tt.from)
+		to, _ := time.Parse("2006-01-02", tt.to)
+		var inPeriod []models.Attendance
+		for _, a := range records {
+			if !a.Date.After(to) {
+				inPeriod = append(inPeriod, a)
+			}
+		}
+
+		summary := &models.OvertimeSummary{}
+		weighted := addOvertime(summary, rule, inPeriod, nil, from)
+		if summary.PaidHours != tt.want {
+			t.Errorf("period from %s: paid %g hours, want %g", tt.from, summary.PaidHours, tt.want)
+		}
+		if weighted != tt.want*rule.WeekdayMultiplier {
+			t.Errorf("period from %s: weighted hours %g, want %g", tt.from, weighted, tt.want*rule.WeekdayMultiplier)
+		}
+	}
+}
+
+func TestAddOvertimeCapsEachMonthOfAPeriodSeparately(t *testing.T) {
+	rule := &models.OvertimeRule{WeekdayMultiplier: 1.5, WeekendMultiplier: 2, HolidayMultiplier: 2.5, MaxMonthlyHours: 10}
+	// A bi-weekly period running from 23 March to 5 April 2026
+	records := []models.Attendance{
+		overtimeOn("2026-03-10", 8), // paid in an earlier period
+		overtimeOn("2026-03-24", 8),
+		overtimeOn("2026-04-01", 8),
+		overtimeOn("2026-04-04", 4), // a Saturday
+	}
+	from, _ := time.Parse("2006-01-02", "2026-03-23")
+
+	summary := &models.OvertimeSummary{}
+	weighted := addOvertime(summary, rule, records, nil, from)
+
+	// March has 2 hours of cap left; April's cap covers 8 weekday and 2 weekend hours
+	if summary.PaidHours != 12 {
+		t.Errorf("paid %g hours, want 12", summary.PaidHours)
+	}
+	if summary.WeekdayHours != 10 || summary.WeekendHours != 2 {
+		t.Errorf("weekday %g, weekend %g hours, want 10 and 2", summary.WeekdayHours, summary.WeekendHours)
+	}
+	if want := 10*1.5 + 2*2.0; weighted != want {
+		t.Errorf("weighted hours %g, want %g", weighted, want)
+	}
+}
+
+func TestAddOvertimeWithoutCap(t *testing.T) {
+	rule := &models.OvertimeRule{WeekdayMultiplier: 1.5, WeekendMultiplier: 2, HolidayMultiplier: 2.5}
+	records := []models.Attendance{
+		overtimeOn("2026-03-02", 30),
+		overtimeOn("2026-03-03", 30),
+	}
+	from, _ := time.Parse("2006-01-02", "2026-03-03")
+	holidays := map[string]bool{"2026-03-03": true}
+
+	summary := &models.OvertimeSummary{}
+	weighted := addOvertime(summary, rule, records, holidays, from)
+
+	if summary.PaidHours != 30 || summary.HolidayHours != 30 {
+		t.Errorf("paid %g, holiday %g hours, want 30 and 30", summary.PaidHours, summary.HolidayHours)
+	}
+	if weighted != 75 {
+		t.Errorf("weighted hours %g, want 75", weighted)
+	}
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"hr-system/internal/interfaces"
+	"hr-system/internal/models"
+	"hr-system/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+type PayGroupService struct {
+	repo    *repository.PayGroupRepository
+	empRepo *repository.EmployeeRepository
+}
+
+func NewPayGroupService(repo *repository.PayGroupRepository, empRepo *repository.EmployeeRepository) *PayGroupService {
+	return &PayGroupService{repo: repo, empRepo: empRepo}
+}
+
+func (s *PayGroupService) Create(g *models.PayGroup) error {
+	if g.IsDefault {
+		return errors.New("a new pay group cannot be the default; update it once it is set up")
+	}
+	if err := s.validate(g); err != nil {
+		return err
+	}
+	g.IsActive = true
+	if err := s.repo.Create(g); err != nil {
+		return fmt.Errorf("failed to create pay group: %w", err)
+	}
+	return nil
+}
+
+// Update changes a group's details. Payroll runs already opened keep the dates they
+// were created with. There is always exactly one default group, so the default flag
+// can only be moved onto another group, not cleared.
+func (s *PayGroupService) Update(id uuid.UUID, g *models.PayGroup) (*models.PayGroup, error) {
+	existing, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("pay group not found")
+	}
+	g.ID = id
+	if err := s.validate(g); err != nil {
+		return nil, err
+	}
+	if existing.IsDefault && !g.IsDefault {
+		return nil, errors.New("make another group the default instead")
+	}
+	if g.IsDefault && !g.IsActive {
+		return nil, errors.New("the default pay group cannot be inactive")
+	}
+	if err := s.repo.Update(g); err != nil {
+		return nil, fmt.Errorf("failed to update pay group: %w", err)
+	}
+	return s.repo.GetByID(id)
+}
+
+func (s *PayGroupService) Delete(id uuid.UUID) error {
+	return s.repo.Delete(id)
+}
+
+func (s *PayGroupService) GetByID(id uuid.UUID) (*models.PayGroup, error) {
+	return s.repo.GetByID(id)
+}
+
+func (s *PayGroupService) List(includeInactive bool) ([]models.PayGroup, error) {
+	return s.repo.List(includeInactive)
+}
+
+// Members returns the active employees paid with the group
+func (s *PayGroupService) Members(id uuid.UUID) ([]models.Employee, error) {
+	if _, err := s.repo.GetByID(id); err != nil {
+		return nil, errors.New("pay group not found")
+	}
+	filter := interfaces.EmployeeFilter{EmploymentStatus: "active", PayGroupID: &id}
+	employees, _, err := s.empRepo.List(filter, 1, 10000)
+	return employees, err
+}
+
+// AssignMembers moves employees into the group. They are paid with it from its next
+// payroll run; periods already paid with their old group are not paid again.
+func (s *PayGroupService) AssignMembers(id uuid.UUID, employeeIDs []uuid.UUID) (int, error) {
+	g, err := s.repo.GetByID(id)
+	if err != nil {
+		return 0, errors.New("pay group not found")
+	}
+	if !g.IsActive {
+		return 0, errors.New("cannot assign employees to an inactive pay group")
+	}
+	if len(employeeIDs) == 0 {
+		return 0, errors.New("employee_ids is required")
+	}
+	return s.repo.AssignEmployees(id, employeeIDs)
+}
+
+// Calendar returns the group's pay periods ending in the given year
+func (s *PayGroupService) Calendar(id uuid.UUID, year int) ([]models.PayPeriod, error) {
+	g, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("pay group not found")
+	}
+	if year < 2000 {
+		return nil, errors.New("invalid year")
+	}
+	return g.Calendar(year), nil
+}
+
+func (s *PayGroupService) validate(g *models.PayGroup) error {
+	g.Name = strings.TrimSpace(g.Name)
+	g.Description = strings.TrimSpace(g.Description)
+	switch {
+	case g.Name == "":
+		return errors.New("name is required")
+	case len(g.Name) > 100:
+		return errors.New("name must be at most 100 characters")
+	case g.PayFrequency.PeriodsPerYear() == 0:
+		return fmt.Errorf("invalid pay_frequency: %s", g.PayFrequency)
+	case g.CalendarStart.IsZero():
+		return errors.New("calendar_start is required")
+	case g.PayFrequency == models.PayFrequencyMonthly && g.CalendarStart.Day() > 28:
+		return errors.New("monthly pay periods must start on day 1 to 28")
+	case g.PayDayOffset < 0 || g.PayDayOffset > 31:
+		return errors.New("pay_day_offset must be between 0 and 31")
+	}
+	return nil
+}
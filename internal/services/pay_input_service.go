@@ -0,0 +1,345 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"hr-system/internal/models"
+	"hr-system/internal/repository"
+	"hr-system/pkg/money"
+
+	"github.com/google/uuid"
+)
+
+// Columns of a pay input upload file; employee_number, category and amount are required
+var payInputColumns = []string{"employee_number", "category", "amount", "description", "tax_treatment", "input_type"}
+
+type PayInputService struct {
+	repo        *repository.PayInputRepository
+	empRepo     *repository.EmployeeRepository
+	posRepo     *repository.PositionRepository
+	payslipRepo *repository.PayslipRepository
+}
+
+func NewPayInputService(
+	repo *repository.PayInputRepository,
+	empRepo *repository.EmployeeRepository,
+	posRepo *repository.PositionRepository,
+	payslipRepo *repository.PayslipRepository,
+) *PayInputService {
+	return &PayInputService{
+		repo:        repo,
+		empRepo:     empRepo,
+		posRepo:     posRepo,
+		payslipRepo: payslipRepo,
+	}
+}
+
+func (s *PayInputService) Categories() []models.PayInputCategory {
+	return models.PayInputCategories
+}
+
+// Create records a one-off earning or deduction for an employee's pay period.
+func (s *PayInputService) Create(in *models.PayInput, createdBy uuid.UUID) error {
+	if errs := s.validate(in); len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	in.CreatedBy = &createdBy
+	if err := s.repo.Create(in); err != nil {
+		return fmt.Errorf("failed to create pay input: %w", err)
+	}
+	return nil
+}
+
+// Update changes an input that has not been paid yet.
+func (s *PayInputService) Update(id uuid.UUID, in *models.PayInput) (*models.PayInput, error) {
+	existing, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("pay input not found")
+	}
+	if existing.Processed {
+		return nil, errors.New("pay input has already been processed")
+	}
+	in.ID = id
+	in.EmployeeID = existing.EmployeeID
+	if errs := s.validate(in); len(errs) > 0 {
+		return nil, errors.New(strings.Join(errs, "; "))
+	}
+	if err := s.repo.Update(in); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(id)
+}
+
+func (s *PayInputService) Delete(id uuid.UUID) error {
+	return s.repo.Delete(id)
+}
+
+func (s *PayInputService) GetByID(id uuid.UUID) (*models.PayInput, error) {
+	return s.repo.GetByID(id)
+}
+
+func (s *PayInputService) List(employeeID *uuid.UUID, month, year int) ([]models.PayInput, error) {
+	return s.repo.List(employeeID, month, year)
+}
+
+// Upload reads a CSV of inputs for a pay period and validates every row. The inputs
+// are only saved when the whole file is valid and validateOnly is false; the report
+// says which rows failed and why.
+func (s *PayInputService) Upload(r io.Reader, month, year int, reference string, validateOnly bool, createdBy uuid.UUID) (*models.PayInputUploadReport, error) {
+	if month < 1 || month > 12 {
+		return nil, errors.New("month must be between 1 and 12")
+	}
+	if year < 2000 {
+		return nil, errors.New("invalid year")
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+	if len(records) < 2 {
+		return nil, errors.New("file has no rows")
+	}
+
+	columns := map[string]int{}
+	for i, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range payInputColumns[:3] {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("missing column: %s", required)
+		}
+	}
+	field := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	report := &models.PayInputUploadReport{Month: month, Year: year, Rows: []models.PayInputRowResult{}}
+	var inputs []models.PayInput
+	seen := map[string]int{}
+	for n, record := range records[1:] {
+		rowNum := n + 2 // 1-based, after the header
+		result := models.PayInputRowResult{Row: rowNum, EmployeeNumber: field(record, "employee_number")}
+
+		in := &models.PayInput{
+			Month:        month,
+			Year:         year,
+			Category:     strings.ToLower(field(record, "category")),
+			Description:  field(record, "description"),
+			TaxTreatment: models.TaxTreatment(strings.ToLower(field(record, "tax_treatment"))),
+			InputType:    models.PayInputType(strings.ToLower(field(record, "input_type"))),
+			Reference:    reference,
+			CreatedBy:    &createdBy,
+		}
+		amount, err := money.Parse(field(record, "amount"))
+		if err != nil {
+			result.Errors = append(result.Errors, "amount is not a number")
+		}
+		in.Amount = amount
+
+		if result.EmployeeNumber == "" {
+			result.Errors = append(result.Errors, "employee_number is required")
+		} else if emp, err := s.empRepo.GetByEmployeeNumber(result.EmployeeNumber); err != nil {
+			result.Errors = append(result.Errors, "employee not found")
+		} else {
+			in.EmployeeID = emp.ID
+			in.EmployeeName = emp.FirstName + " " + emp.LastName
+			in.EmployeeNumber = emp.EmployeeNumber
+			result.Errors = append(result.Errors, s.validate(in)...)
+		}
+
+		key := strings.Join([]string{result.EmployeeNumber, in.Category, in.Amount.String(), strings.ToLower(in.Description)}, "|")
+		if first, dup := seen[key]; dup {
+			result.Errors = append(result.Errors, fmt.Sprintf("duplicate of row %d", first))
+		} else {
+			seen[key] = rowNum
+		}
+
+		result.Valid = len(result.Errors) == 0
+		if result.Valid {
+			report.ValidRows++
+			if in.InputType == models.PayInputEarning {
+				report.TotalEarnings += in.Amount
+			} else {
+				report.TotalDeductions += in.Amount
+			}
+			inputs = append(inputs, *in)
+			result.Input = &inputs[len(inputs)-1]
+		} else {
+			report.ErrorRows++
+		}
+		report.Rows = append(report.Rows, result)
+	}
+	report.TotalRows = len(report.Rows)
+
+	if report.ErrorRows > 0 || validateOnly {
+		return report, nil
+	}
+	if err := s.repo.CreateBatch(inputs); err != nil {
+		return nil, fmt.Errorf("failed to save pay inputs: %w", err)
+	}
+	// Point the report rows at the saved inputs, which now carry their IDs
+	i := 0
+	for r := range report.Rows {
+		if report.Rows[r].Valid {
+			report.Rows[r].Input = &inputs[i]
+			i++
+		}
+	}
+	report.Saved = true
+	return report, nil
+}
+
+// ValidatePeriod checks a period's inputs before payroll is processed: employees who
+// will not be paid, inputs that will land on a supplementary payslip because the
+// period has already been paid, likely duplicates, and deductions larger than pay.
+func (s *PayInputService) ValidatePeriod(month, year int) (*models.PayInputPeriodReport, error) {
+	inputs, err := s.repo.List(nil, month, year)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.PayInputPeriodReport{Month: month, Year: year, InputCount: len(inputs), Issues: []models.PayInputIssue{}}
+	byEmployee := map[uuid.UUID][]models.PayInput{}
+	var order []uuid.UUID
+	for _, in := range inputs {
+		if _, ok := byEmployee[in.EmployeeID]; !ok {
+			order = append(order, in.EmployeeID)
+		}
+		byEmployee[in.EmployeeID] = append(byEmployee[in.EmployeeID], in)
+		if in.InputType == models.PayInputEarning {
+			report.TotalEarnings += in.Amount
+		} else {
+			report.TotalDeductions += in.Amount
+		}
+	}
+	report.EmployeeCount = len(order)
+
+	for _, empID := range order {
+		empInputs := byEmployee[empID]
+		name := empInputs[0].EmployeeName
+		issue := func(severity string, in *models.PayInput, format string, args ...interface{}) {
+			i := models.PayInputIssue{Severity: severity, EmployeeID: empID, EmployeeName: name, Message: fmt.Sprintf(format, args...)}
+			if in != nil {
+				i.PayInputID = &in.ID
+			}
+			report.Issues = append(report.Issues, i)
+		}
+
+		emp, err := s.empRepo.GetByID(empID)
+		if err != nil {
+			issue("error", nil, "employee record not found")
+			continue
+		}
+		if emp.EmploymentStatus != models.EmploymentStatusActive && !isFinalPeriod(emp, month, year) {
+			issue("error", nil, "employee is %s and will not be paid for %s %d", emp.EmploymentStatus, time.Month(month), year)
+		}
+
+		var pending []models.PayInput
+		for _, in := range empInputs {
+			if !in.Processed {
+				pending = append(pending, in)
+			}
+		}
+		if len(pending) > 0 {
+			if _, err := s.payslipRepo.GetByEmployeeAndPeriod(empID, month, year); err == nil {
+				issue("warning", nil, "%d input(s) added after the payslip was generated will only be paid on a supplementary payslip", len(pending))
+			}
+		}
+
+		seen := map[string]bool{}
+		var earnings, deductions money.Money
+		for i, in := range empInputs {
+			key := in.Category + "|" + in.Amount.String()
+			if seen[key] {
+				issue("warning", &empInputs[i], "possible duplicate: another %s of %s for this period", in.Category, in.Amount.Format())
+			}
+			seen[key] = true
+			if in.InputType == models.PayInputEarning {
+				earnings += in.Amount
+			} else {
+				deductions += in.Amount
+			}
+		}
+
+		if pos, err := s.posRepo.GetByID(emp.PositionID); err == nil {
+			if available := pos.NetSalary() + earnings; deductions > available {
+				issue("error", nil, "deductions of %s exceed estimated net pay of %s", deductions.Format(), available.Format())
+			}
+		}
+	}
+	return report, nil
+}
+
+// validate normalises an input and returns every problem with it. Type and tax
+// treatment default from the category.
+func (s *PayInputService) validate(in *models.PayInput) []string {
+	var errs []string
+	in.Category = strings.TrimSpace(in.Category)
+	in.Description = strings.TrimSpace(in.Description)
+
+	if in.Month < 1 || in.Month > 12 {
+		errs = append(errs, "month must be between 1 and 12")
+	}
+	if in.Year < 2000 {
+		errs = append(errs, "invalid year")
+	}
+	if in.Amount <= 0 {
+		errs = append(errs, "amount must be greater than zero")
+	}
+	if len(in.Description) > 255 {
+		errs = append(errs, "description must be at most 255 characters")
+	}
+
+	category, ok := models.FindPayInputCategory(in.Category)
+	if !ok {
+		return append(errs, fmt.Sprintf("unknown category: %q", in.Category))
+	}
+	if in.InputType == "" {
+		in.InputType = category.InputType
+	} else if in.InputType != category.InputType {
+		errs = append(errs, fmt.Sprintf("category %s is a %s", category.Code, category.InputType))
+	}
+	if in.TaxTreatment == "" {
+		in.TaxTreatment = category.DefaultTreatment
+	}
+	switch in.InputType {
+	case models.PayInputEarning:
+		if in.TaxTreatment != models.TaxTreatmentTaxable && in.TaxTreatment != models.TaxTreatmentNonTaxable {
+			errs = append(errs, "tax_treatment for an earning must be taxable or non_taxable")
+		}
+	case models.PayInputDeduction:
+		if in.TaxTreatment != models.TaxTreatmentPreTax && in.TaxTreatment != models.TaxTreatmentPostTax {
+			errs = append(errs, "tax_treatment for a deduction must be pre_tax or post_tax")
+		}
+	}
+
+	if in.EmployeeID != uuid.Nil {
+		emp, err := s.empRepo.GetByID(in.EmployeeID)
+		if err != nil {
+			errs = append(errs, "employee not found")
+		} else if emp.EmploymentStatus != models.EmploymentStatusActive && !isFinalPeriod(emp, in.Month, in.Year) {
+			errs = append(errs, "employee is not active")
+		}
+	} else {
+		errs = append(errs, "employee_id is required")
+	}
+	return errs
+}
@@ -0,0 +1,184 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"hr-system/internal/config"
+	"hr-system/internal/models"
+	"hr-system/internal/repository"
+	"hr-system/internal/utils/bankfile"
+	"hr-system/pkg/money"
+
+	"github.com/google/uuid"
+)
+
+type PaymentBatchService struct {
+	repo        *repository.PaymentBatchRepository
+	payrollRepo *repository.PayrollRepository
+	payslipRepo *repository.PayslipRepository
+	bankRepo    *repository.BankAccountRepository
+	empRepo     *repository.EmployeeRepository
+	company     config.CompanyConfig
+}
+
+func NewPaymentBatchService(
+	repo *repository.PaymentBatchRepository,
+	payrollRepo *repository.PayrollRepository,
+	payslipRepo *repository.PayslipRepository,
+	bankRepo *repository.BankAccountRepository,
+	empRepo *repository.EmployeeRepository,
+	company config.CompanyConfig,
+) *PaymentBatchService {
+	return &PaymentBatchService{
+		repo:        repo,
+		payrollRepo: payrollRepo,
+		payslipRepo: payslipRepo,
+		bankRepo:    bankRepo,
+		empRepo:     empRepo,
+		company:     company,
+	}
+}
+
+// Generate builds a bank transfer file for every payslip of a COMPLETED payroll that
+// has not been paid in an earlier batch. Amounts are summed per employee and paid into
+// their approved bank account. Generation fails if any employee has no approved
+// account, a negative balance, or details the chosen format cannot represent.
+func (s *PaymentBatchService) Generate(payrollID uuid.UUID, formatCode string, valueDate time.Time, generatedBy uuid.UUID) (*models.PaymentBatch, error) {
+	if formatCode == "" {
+		formatCode = "csv"
+	}
+	format, ok := bankfile.Get(formatCode)
+	if !ok {
+		return nil, fmt.Errorf("unknown payment file format: %s", formatCode)
+	}
+
+	payroll, err := s.payrollRepo.GetByID(payrollID)
+	if err != nil {
+		return nil, errors.New("payroll not found")
+	}
+	if payroll.Status != models.PayrollStatusCompleted {
+		return nil, fmt.Errorf("payment files can only be generated for COMPLETED payrolls, current: %s", payroll.Status)
+	}
+
+	payslips, err := s.payslipRepo.ListUnpaidByPayroll(payrollID)
+	if err != nil {
+		return nil, err
+	}
+	if len(payslips) == 0 {
+		return nil, errors.New("every payslip on this payroll is already in a payment batch")
+	}
+
+	// One transfer per employee, in payslip order
+	amounts := map[uuid.UUID]money.Money{}
+	var order []uuid.UUID
+	payslipIDs := make([]uuid.UUID, 0, len(payslips))
+	for _, p := range payslips {
+		if _, seen := amounts[p.EmployeeID]; !seen {
+			order = append(order, p.EmployeeID)
+		}
+		amounts[p.EmployeeID] += p.NetSalary
+		payslipIDs = append(payslipIDs, p.ID)
+	}
+
+	narrative := fmt.Sprintf("SALARY %s %d", strings.ToUpper(payroll.EndDate.Month().String()[:3]), payroll.EndDate.Year())
+	var lines []models.PaymentLine
+	var problems []string
+	var controlTotal money.Money
+	for _, employeeID := range order {
+		emp, err := s.empRepo.GetByID(employeeID)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: employee not found", employeeID))
+			continue
+		}
+		amount := amounts[employeeID]
+		if amount <= 0 {
+			if amount < 0 {
+				problems = append(problems, fmt.Sprintf("%s: net amount %s is negative", emp.FullName(), amount))
+			}
+			continue
+		}
+		account, err := s.bankRepo.GetByEmployeeAndStatus(employeeID, models.BankAccountApproved)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: no approved bank account", emp.FullName()))
+			continue
+		}
+
+		line := models.PaymentLine{
+			EmployeeID:     employeeID,
+			EmployeeNumber: emp.EmployeeNumber,
+			BankCode:       account.BankCode,
+			BranchCode:     account.BranchCode,
+			AccountNumber:  account.AccountNumber,
+			AccountName:    account.AccountName,
+			Amount:         amount,
+			Narrative:      narrative,
+		}
+		if err := format.Validate(line); err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		lines = append(lines, line)
+		controlTotal += line.Amount
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("cannot generate payment file: %s", strings.Join(problems, "; "))
+	}
+	if len(lines) == 0 {
+		return nil, errors.New("no positive amounts to pay")
+	}
+
+	if valueDate.IsZero() {
+		valueDate = time.Now()
+	}
+	reference := fmt.Sprintf("PAY%d%02d-%s", payroll.EndDate.Year(), int(payroll.EndDate.Month()),
+		strings.ToUpper(strings.ReplaceAll(uuid.New().String(), "-", "")[:8]))
+	header := bankfile.Header{
+		BatchReference:     reference,
+		PayerName:          s.company.Name,
+		PayerBankCode:      s.company.BankCode,
+		PayerAccountNumber: s.company.BankAccountNumber,
+		ValueDate:          valueDate,
+		RecordCount:        len(lines),
+		ControlTotal:       controlTotal,
+	}
+	content, err := format.Write(header, lines)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write payment file: %w", err)
+	}
+
+	batch := &models.PaymentBatch{
+		PayrollID:      payrollID,
+		BatchReference: reference,
+		Format:         format.Code(),
+		RecordCount:    header.RecordCount,
+		ControlTotal:   header.ControlTotal,
+		FileName:       fmt.Sprintf("%s.%s", reference, format.Extension()),
+		ContentType:    format.ContentType(),
+		Content:        content,
+		GeneratedBy:    &generatedBy,
+	}
+	if err := s.repo.Create(batch, payslipIDs); err != nil {
+		return nil, fmt.Errorf("failed to save payment batch: %w", err)
+	}
+	return batch, nil
+}
+
+func (s *PaymentBatchService) GetByID(id uuid.UUID) (*models.PaymentBatch, error) {
+	return s.repo.GetByID(id)
+}
+
+func (s *PaymentBatchService) ListByPayroll(payrollID uuid.UUID) ([]models.PaymentBatch, error) {
+	return s.repo.ListByPayroll(payrollID)
+}
+
+// Formats lists the registered payment file formats.
+func (s *PaymentBatchService) Formats() []map[string]string {
+	var list []map[string]string
+	for _, f := range bankfile.List() {
+		list = append(list, map[string]string{"code": f.Code(), "description": f.Description()})
+	}
+	return list
+}
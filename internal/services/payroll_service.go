@@ -4,6 +4,9 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"math"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"hr-system/internal/interfaces"
@@ -14,37 +17,98 @@ import (
 	"github.com/google/uuid"
 )
 
+// DefaultVarianceThresholdPct is the net pay change (in percent) above which an
+// employee is flagged as an outlier in the payroll variance report.
+const DefaultVarianceThresholdPct = 10.0
+
+// payrollWorkers bounds how many payslips are generated concurrently during a run.
+const payrollWorkers = 5
+
 type PayrollService struct {
 	repo           *repository.PayrollRepository
+	runItemRepo    *repository.PayrollRunItemRepository
+	payGroupRepo   *repository.PayGroupRepository
 	payslipService *PayslipService
+	pdfService     *PayslipPDFService
 	empRepo        *repository.EmployeeRepository
 	emailService   *email.EmailService
 }
 
 func NewPayrollService(
 	repo *repository.PayrollRepository,
+	runItemRepo *repository.PayrollRunItemRepository,
+	payGroupRepo *repository.PayGroupRepository,
 	payslipService *PayslipService,
+	pdfService *PayslipPDFService,
 	empRepo *repository.EmployeeRepository,
 	emailService *email.EmailService,
 ) *PayrollService {
 	return &PayrollService{
 		repo:           repo,
+		runItemRepo:    runItemRepo,
+		payGroupRepo:   payGroupRepo,
 		payslipService: payslipService,
+		pdfService:     pdfService,
 		empRepo:        empRepo,
 		emailService:   emailService,
 	}
 }
 
-// Create opens a new payroll period
-func (s *PayrollService) Create(startDate, endDate time.Time) (*models.Payroll, error) {
-	if endDate.Before(startDate) {
-		return nil, errors.New("end_date must be after start_date")
+// Create opens a new payroll run for a pay group, or the default group when payGroupID
+// is nil. A regular run covers exactly one period of the group's calendar - the one
+// starting on startDate, so endDate may be left out - and may not overlap another
+// regular run of the group. Any number of bonus runs may be opened alongside it.
+func (s *PayrollService) Create(payGroupID *uuid.UUID, startDate time.Time, endDate *time.Time, runType models.PayrollRunType, bonusPercent float64) (*models.Payroll, error) {
+	var group *models.PayGroup
+	var err error
+	if payGroupID != nil {
+		group, err = s.payGroupRepo.GetByID(*payGroupID)
+	} else {
+		group, err = s.payGroupRepo.GetDefault()
+	}
+	if err != nil {
+		return nil, errors.New("pay group not found")
+	}
+	if !group.IsActive {
+		return nil, fmt.Errorf("pay group %s is inactive", group.Name)
+	}
+
+	period := group.PeriodContaining(startDate)
+	switch runType {
+	case "", models.PayrollRunRegular:
+		runType = models.PayrollRunRegular
+		bonusPercent = 0
+		if !period.Start.Equal(startDate) || (endDate != nil && !endDate.Equal(period.End)) {
+			return nil, fmt.Errorf("a regular payroll must cover one %s pay period of %s, e.g. %s to %s",
+				group.PayFrequency, group.Name, period.Start.Format("2006-01-02"), period.End.Format("2006-01-02"))
+		}
+		if existing, err := s.repo.FindOverlappingRegular(group.ID, period.Start, period.End); err == nil {
+			return nil, fmt.Errorf("a regular payroll for %s already covers %s (%s)",
+				group.Name, existing.Period().Label(), existing.Status)
+		}
+	case models.PayrollRunBonus:
+		if bonusPercent <= 0 {
+			return nil, errors.New("bonus_percent must be greater than zero for a bonus run")
+		}
+		if endDate != nil {
+			if endDate.Before(startDate) {
+				return nil, errors.New("end_date must be after start_date")
+			}
+			period = models.PayPeriod{Start: startDate, End: *endDate, PayDate: endDate.AddDate(0, 0, group.PayDayOffset)}
+		}
+	default:
+		return nil, fmt.Errorf("invalid run_type: %s", runType)
 	}
 
 	payroll := &models.Payroll{
-		StartDate: startDate,
-		EndDate:   endDate,
-		Status:    models.PayrollStatusOpen,
+		PayGroupID:   &group.ID,
+		StartDate:    period.Start,
+		EndDate:      period.End,
+		PayDate:      &period.PayDate,
+		Status:       models.PayrollStatusOpen,
+		RunType:      runType,
+		BonusPercent: bonusPercent,
+		Currency:     s.payslipService.currency,
 	}
 
 	if err := s.repo.Create(payroll); err != nil {
@@ -54,9 +118,10 @@ func (s *PayrollService) Create(startDate, endDate time.Time) (*models.Payroll,
 	return s.repo.GetByID(payroll.ID)
 }
 
-// Process validates the payroll, marks it as PROCESSING, and kicks off payslip
-// generation for all active employees in the background. The caller gets an
-// immediate response — poll GET /payrolls/{id} to check completion.
+// Process validates the payroll, marks it as PROCESSING, and kicks off draft payslip
+// generation for the pay group's active employees in the background. The caller gets an
+// immediate response — poll GET /payrolls/{id} until the status is DRAFT, review
+// the variance report, then approve or reject the draft.
 func (s *PayrollService) Process(payrollID uuid.UUID, processedBy uuid.UUID) (*models.Payroll, error) {
 	payroll, err := s.repo.GetByID(payrollID)
 	if err != nil {
@@ -80,7 +145,10 @@ func (s *PayrollService) Process(payrollID uuid.UUID, processedBy uuid.UUID) (*m
 	return s.repo.GetByID(payrollID)
 }
 
-// processPayslips generates payslips for all active employees and marks the payroll as completed.
+// processPayslips queues a run item per active member of the payroll's pay group,
+// generates their draft payslips and marks the payroll as DRAFT. Progress and
+// per-employee failures are recorded on the run items so HR can follow the run while
+// it is in flight.
 func (s *PayrollService) processPayslips(payrollID uuid.UUID, processedBy uuid.UUID) {
 	payroll, err := s.repo.GetByID(payrollID)
 	if err != nil {
@@ -88,10 +156,7 @@ func (s *PayrollService) processPayslips(payrollID uuid.UUID, processedBy uuid.U
 		return
 	}
 
-	month := int(payroll.EndDate.Month())
-	year := payroll.EndDate.Year()
-
-	filter := interfaces.EmployeeFilter{EmploymentStatus: "active"}
+	filter := interfaces.EmployeeFilter{EmploymentStatus: "active", PayGroupID: payroll.PayGroupID}
 	employees, _, err := s.empRepo.List(filter, 1, 10000)
 	if err != nil {
 		log.Printf("payroll processing error: failed to fetch employees: %v", err)
@@ -99,41 +164,433 @@ func (s *PayrollService) processPayslips(payrollID uuid.UUID, processedBy uuid.U
 		return
 	}
 
-	period := fmt.Sprintf("%s %d", time.Month(month), year)
-
-	generated := 0
+	employeeIDs := make([]uuid.UUID, 0, len(employees))
 	for _, emp := range employees {
-		_, err := s.payslipService.Generate(emp.ID, month, year)
-		if err != nil {
-			log.Printf("payroll %s: skipped employee %s (%s): %s", payrollID, emp.ID, emp.FullName(), err.Error())
-			continue
-		}
-		generated++
-
-		// Send payslip notification email
-		if s.emailService != nil {
-			empEmail := emp.Email
-			empName := emp.FirstName
-			go func() {
-				htmlBody := email.PayslipReadyTemplate(empName, period)
-				subject := fmt.Sprintf("Your Payslip for %s is Ready", period)
-				if err := s.emailService.SendEmail([]string{empEmail}, subject, htmlBody); err != nil {
-					log.Printf("payroll %s: failed to send payslip email to %s: %v", payrollID, empEmail, err)
+		employeeIDs = append(employeeIDs, emp.ID)
+	}
+	if err := s.clearRun(payrollID); err != nil {
+		log.Printf("payroll processing error: %v", err)
+		s.markFailed(payroll)
+		return
+	}
+	items, err := s.runItemRepo.CreatePending(payrollID, employeeIDs)
+	if err != nil {
+		log.Printf("payroll processing error: failed to queue run items for payroll %s: %v", payrollID, err)
+		s.markFailed(payroll)
+		return
+	}
+	if err := s.repo.RefreshProgress(payrollID); err != nil {
+		log.Printf("payroll %s: failed to update progress: %v", payrollID, err)
+	}
+
+	generated := s.runItems(payroll, items)
+
+	now := time.Now()
+	payroll.Status = models.PayrollStatusDraft
+	payroll.ProcessedBy = &processedBy
+	payroll.ProcessedAt = &now
+	if err := s.repo.Update(payroll); err != nil {
+		log.Printf("payroll processing error: failed to mark payroll %s as draft: %v", payrollID, err)
+		return
+	}
+
+	log.Printf("payroll %s drafted: %d of %d payslips generated, awaiting approval", payrollID, generated, len(items))
+}
+
+// runItems generates draft payslips for the given run items, at most payrollWorkers at
+// a time, and returns how many succeeded.
+func (s *PayrollService) runItems(payroll *models.Payroll, items []models.PayrollRunItem) int {
+	period := payroll.Period()
+	frequency := payrollFrequency(payroll)
+
+	var succeeded atomic.Int64
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, payrollWorkers)
+	for _, item := range items {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			var payslip *models.Payslip
+			var err error
+			if payroll.RunType == models.PayrollRunBonus {
+				payslip, err = s.payslipService.GenerateBonusDraft(payroll.ID, item.EmployeeID, period, payroll.BonusPercent)
+			} else {
+				payslip, err = s.payslipService.GenerateDraft(payroll.ID, item.EmployeeID, period, frequency)
+			}
+			if err != nil {
+				log.Printf("payroll %s: failed for employee %s: %s", payroll.ID, item.EmployeeID, err.Error())
+				if err := s.runItemRepo.MarkFailed(item.ID, err.Error()); err != nil {
+					log.Printf("payroll %s: failed to record failure for employee %s: %v", payroll.ID, item.EmployeeID, err)
 				}
-			}()
+			} else {
+				succeeded.Add(1)
+				if err := s.runItemRepo.MarkSucceeded(item.ID, payslip.ID); err != nil {
+					log.Printf("payroll %s: failed to record payslip for employee %s: %v", payroll.ID, item.EmployeeID, err)
+				}
+			}
+
+			if err := s.repo.RefreshProgress(payroll.ID); err != nil {
+				log.Printf("payroll %s: failed to update progress: %v", payroll.ID, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	return int(succeeded.Load())
+}
+
+// RetryFailed re-runs payslip generation for the employees that failed in a DRAFT
+// payroll. The payroll is PROCESSING while the retry runs and returns to DRAFT after.
+func (s *PayrollService) RetryFailed(payrollID uuid.UUID) (*models.Payroll, error) {
+	payroll, err := s.repo.GetByID(payrollID)
+	if err != nil {
+		return nil, errors.New("payroll not found")
+	}
+	if payroll.Status != models.PayrollStatusDraft {
+		return nil, fmt.Errorf("can only retry failed employees on DRAFT payrolls, current: %s", payroll.Status)
+	}
+	if payroll.FailedCount == 0 {
+		return nil, errors.New("payroll has no failed employees to retry")
+	}
+
+	items, err := s.runItemRepo.ResetFailed(payrollID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reset failed employees: %w", err)
+	}
+
+	payroll.Status = models.PayrollStatusProcessing
+	if err := s.repo.Update(payroll); err != nil {
+		return nil, err
+	}
+	if err := s.repo.RefreshProgress(payrollID); err != nil {
+		return nil, err
+	}
+
+	go func() {
+		generated := s.runItems(payroll, items)
+		payroll.Status = models.PayrollStatusDraft
+		if err := s.repo.Update(payroll); err != nil {
+			log.Printf("payroll processing error: failed to return payroll %s to draft: %v", payrollID, err)
+			return
 		}
+		log.Printf("payroll %s retry: %d of %d failed payslips generated", payrollID, generated, len(items))
+	}()
+
+	return s.repo.GetByID(payrollID)
+}
+
+// ListRunItems returns the per-employee outcome of a payroll's run, optionally filtered by status.
+func (s *PayrollService) ListRunItems(payrollID uuid.UUID, status string) ([]models.PayrollRunItem, error) {
+	if _, err := s.repo.GetByID(payrollID); err != nil {
+		return nil, errors.New("payroll not found")
+	}
+	return s.runItemRepo.ListByPayroll(payrollID, status)
+}
+
+// clearRun discards a payroll's run items and zeroes its progress counters.
+func (s *PayrollService) clearRun(payrollID uuid.UUID) error {
+	if err := s.runItemRepo.DeleteByPayroll(payrollID); err != nil {
+		return fmt.Errorf("failed to clear run items: %w", err)
+	}
+	return s.repo.RefreshProgress(payrollID)
+}
+
+// Approve locks a DRAFT payroll: its payslips become final, the payroll is COMPLETED
+// and employees are notified that their payslips are ready.
+func (s *PayrollService) Approve(payrollID uuid.UUID, approvedBy uuid.UUID) (*models.Payroll, error) {
+	payroll, err := s.repo.GetByID(payrollID)
+	if err != nil {
+		return nil, errors.New("payroll not found")
+	}
+	if payroll.Status != models.PayrollStatusDraft {
+		return nil, fmt.Errorf("can only approve payrolls in DRAFT status, current: %s", payroll.Status)
+	}
+
+	finalized, err := s.payslipService.FinalizeDrafts(payrollID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to finalise payslips: %w", err)
 	}
 
 	now := time.Now()
 	payroll.Status = models.PayrollStatusCompleted
-	payroll.ProcessedBy = &processedBy
-	payroll.ProcessedAt = &now
+	payroll.ApprovedBy = &approvedBy
+	payroll.ApprovedAt = &now
+	if err := s.repo.Update(payroll); err != nil {
+		return nil, err
+	}
+	log.Printf("payroll %s approved: %d payslips finalised", payrollID, finalized)
+
+	if payslips, err := s.payslipService.ListByPayroll(payrollID, models.PayslipStatusFinal); err == nil {
+		s.notifyPayslipsReady(payrollID, payslips, payrollPeriod(payroll))
+	}
+
+	return s.GetByID(payrollID)
+}
+
+// Reject discards a DRAFT payroll's payslips and reopens the payroll so it can be reprocessed.
+func (s *PayrollService) Reject(payrollID uuid.UUID) (*models.Payroll, error) {
+	payroll, err := s.repo.GetByID(payrollID)
+	if err != nil {
+		return nil, errors.New("payroll not found")
+	}
+	if payroll.Status != models.PayrollStatusDraft {
+		return nil, fmt.Errorf("can only reject payrolls in DRAFT status, current: %s", payroll.Status)
+	}
+
+	if err := s.payslipService.DeleteDrafts(payrollID); err != nil {
+		return nil, fmt.Errorf("failed to discard draft payslips: %w", err)
+	}
+	if err := s.clearRun(payrollID); err != nil {
+		return nil, err
+	}
+
+	payroll.Status = models.PayrollStatusOpen
+	payroll.ProcessedBy = nil
+	payroll.ProcessedAt = nil
 	if err := s.repo.Update(payroll); err != nil {
-		log.Printf("payroll processing error: failed to mark payroll %s as completed: %v", payrollID, err)
+		return nil, err
+	}
+
+	return s.repo.GetByID(payrollID)
+}
+
+// Reverse voids every payslip of a COMPLETED payroll and reopens it so it can be
+// processed again. Voided payslips are kept with the reason for audit, and the
+// re-run payslips link back to them. Payrolls with payslips already in a payment batch
+// cannot be reversed, as the re-run would be paid again; they are corrected with
+// supplementary payslips.
+func (s *PayrollService) Reverse(payrollID uuid.UUID, reason string, reversedBy uuid.UUID) (*models.Payroll, error) {
+	if reason == "" {
+		return nil, errors.New("reason is required to reverse a payroll")
+	}
+
+	payroll, err := s.repo.GetByID(payrollID)
+	if err != nil {
+		return nil, errors.New("payroll not found")
+	}
+	if payroll.Status != models.PayrollStatusCompleted {
+		return nil, fmt.Errorf("can only reverse payrolls in COMPLETED status, current: %s", payroll.Status)
+	}
+
+	reversal := &models.PayrollReversal{
+		Reason:     reason,
+		ReversedBy: &reversedBy,
+	}
+	if err := s.repo.Reverse(payroll, reversal); err != nil {
+		return nil, fmt.Errorf("failed to reverse payroll: %w", err)
+	}
+	s.payslipService.RefreshPayrollYTD(payrollID)
+	log.Printf("payroll %s reversed: %d payslips voided (net %s): %s",
+		payrollID, reversal.PayslipsVoided, reversal.TotalNetVoided, reason)
+
+	return s.GetByID(payrollID)
+}
+
+// IssueSupplementary creates an off-cycle payslip for one employee on a COMPLETED
+// payroll, paying only the difference from what was already issued.
+func (s *PayrollService) IssueSupplementary(payrollID, employeeID uuid.UUID, reason string) (*models.Payslip, error) {
+	payroll, err := s.repo.GetByID(payrollID)
+	if err != nil {
+		return nil, errors.New("payroll not found")
+	}
+	if payroll.Status != models.PayrollStatusCompleted {
+		return nil, fmt.Errorf("supplementary payslips can only be issued on COMPLETED payrolls, current: %s", payroll.Status)
+	}
+	if payroll.RunType != models.PayrollRunRegular {
+		return nil, errors.New("supplementary payslips can only be issued on regular payrolls")
+	}
+
+	payslip, err := s.payslipService.GenerateSupplementary(payrollID, employeeID, payroll.Period(), payrollFrequency(payroll), reason)
+	if err != nil {
+		return nil, err
+	}
+
+	s.notifyPayslipsReady(payrollID, []models.Payslip{*payslip}, payrollPeriod(payroll)+" (supplementary)")
+	return payslip, nil
+}
+
+// payrollPeriod describes a payroll's pay period for notifications, e.g. "March 2026 bonus".
+func payrollPeriod(payroll *models.Payroll) string {
+	period := payroll.Period().Label()
+	if payroll.RunType == models.PayrollRunBonus {
+		period += " bonus"
+	}
+	return period
+}
+
+// payrollFrequency returns how often the payroll's pay group is paid. Runs from before
+// pay groups existed were all monthly.
+func payrollFrequency(payroll *models.Payroll) models.PayFrequency {
+	if payroll.PayFrequency == "" {
+		return models.PayFrequencyMonthly
+	}
+	return payroll.PayFrequency
+}
+
+// notifyPayslipsReady emails each employee on the payroll in the background.
+func (s *PayrollService) notifyPayslipsReady(payrollID uuid.UUID, payslips []models.Payslip, period string) {
+	if s.emailService == nil {
 		return
 	}
+	for _, p := range payslips {
+		emp, err := s.empRepo.GetByID(p.EmployeeID)
+		if err != nil {
+			continue
+		}
+		empEmail := emp.Email
+		empName := emp.FirstName
+		payslipID := p.ID
+		go func() {
+			// Attach the PDF when it renders; the email still goes out without it otherwise,
+			// pointing the employee to the portal. A PDF that should be password protected
+			// but cannot be is never attached.
+			var attachments []email.Attachment
+			data, filename, passwordHint, err := s.pdfService.RenderForEmail(payslipID)
+			switch {
+			case err == nil:
+				attachments = append(attachments, email.Attachment{Filename: filename, ContentType: "application/pdf", Data: data})
+			case errors.Is(err, ErrPayslipPasswordUnavailable):
+				log.Printf("payroll %s: withholding payslip %s attachment: %v", payrollID, payslipID, err)
+			default:
+				log.Printf("payroll %s: failed to render payslip %s: %v", payrollID, payslipID, err)
+			}
+
+			htmlBody := email.PayslipReadyTemplate(empName, period, len(attachments) > 0, passwordHint)
+			subject := fmt.Sprintf("Your Payslip for %s is Ready", period)
+			if err := s.emailService.SendEmailWithAttachments([]string{empEmail}, subject, htmlBody, attachments); err != nil {
+				log.Printf("payroll %s: failed to send payslip email to %s: %v", payrollID, empEmail, err)
+			}
+		}()
+	}
+}
 
-	log.Printf("payroll %s processed: %d payslips generated", payrollID, generated)
+// VarianceReport compares each employee's payslip in the payroll against the pay group's
+// previous period. Joiners and leavers are flagged as headcount changes; employees whose net pay
+// moved by more than thresholdPct percent are flagged as outliers.
+func (s *PayrollService) VarianceReport(payrollID uuid.UUID, thresholdPct float64) (*models.PayrollVarianceReport, error) {
+	payroll, err := s.repo.GetByID(payrollID)
+	if err != nil {
+		return nil, errors.New("payroll not found")
+	}
+	if payroll.Status != models.PayrollStatusDraft && payroll.Status != models.PayrollStatusCompleted {
+		return nil, fmt.Errorf("variance report is only available for DRAFT or COMPLETED payrolls, current: %s", payroll.Status)
+	}
+	if thresholdPct <= 0 {
+		thresholdPct = DefaultVarianceThresholdPct
+	}
+
+	previousPeriod := models.CalendarMonth(int(payroll.StartDate.Month()), payroll.StartDate.Year())
+	if payroll.PayGroupID != nil {
+		if group, err := s.payGroupRepo.GetByID(*payroll.PayGroupID); err == nil {
+			previousPeriod = group.PeriodContaining(payroll.StartDate.AddDate(0, 0, -1))
+		}
+	}
+
+	current, err := s.payslipService.ListByPayroll(payrollID, "")
+	if err != nil {
+		return nil, err
+	}
+
+	// Regular runs compare against the group's previous completed regular run; bonus runs have no baseline
+	var previous []models.Payslip
+	if payroll.RunType == models.PayrollRunRegular && payroll.PayGroupID != nil {
+		if prevPayroll, err := s.repo.FindPreviousRegular(*payroll.PayGroupID, payroll.StartDate); err == nil && prevPayroll.Status == models.PayrollStatusCompleted {
+			previousPeriod = prevPayroll.Period()
+			previous, err = s.payslipService.ListByPayroll(prevPayroll.ID, models.PayslipStatusFinal)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Supplementary payslips are folded into the employee's regular one
+	current = combineByEmployee(current)
+	previous = combineByEmployee(previous)
+
+	report := &models.PayrollVarianceReport{
+		PayrollID:         payrollID,
+		Period:            payroll.Period().Label(),
+		PreviousPeriod:    previousPeriod.Label(),
+		ThresholdPercent:  thresholdPct,
+		CurrentHeadcount:  len(current),
+		PreviousHeadcount: len(previous),
+		Lines:             []models.PayrollVarianceLine{},
+	}
+
+	prevByEmployee := make(map[uuid.UUID]models.Payslip, len(previous))
+	for _, p := range previous {
+		prevByEmployee[p.EmployeeID] = p
+		report.PreviousTotalNet += p.NetSalary
+	}
+
+	for _, c := range current {
+		report.CurrentTotalNet += c.NetSalary
+		line := models.PayrollVarianceLine{
+			EmployeeID:   c.EmployeeID,
+			EmployeeName: c.EmployeeName,
+			CurrentGross: c.GrossSalary,
+			CurrentNet:   c.NetSalary,
+		}
+
+		p, ok := prevByEmployee[c.EmployeeID]
+		if !ok {
+			line.Difference = c.NetSalary
+			line.Flag = models.VarianceFlagJoiner
+			report.Joiners++
+		} else {
+			delete(prevByEmployee, c.EmployeeID)
+			line.PreviousGross = p.GrossSalary
+			line.PreviousNet = p.NetSalary
+			line.Difference = c.NetSalary - p.NetSalary
+			if p.NetSalary != 0 {
+				line.PercentChange = math.Round(line.Difference.Float64()/p.NetSalary.Float64()*10000) / 100
+			}
+			if math.Abs(line.PercentChange) > thresholdPct {
+				line.Flag = models.VarianceFlagOutlier
+				report.Outliers++
+			}
+		}
+		report.Lines = append(report.Lines, line)
+	}
+
+	for _, p := range previous {
+		if _, left := prevByEmployee[p.EmployeeID]; !left {
+			continue
+		}
+		report.Lines = append(report.Lines, models.PayrollVarianceLine{
+			EmployeeID:    p.EmployeeID,
+			EmployeeName:  p.EmployeeName,
+			PreviousGross: p.GrossSalary,
+			PreviousNet:   p.NetSalary,
+			Difference:    -p.NetSalary,
+			PercentChange: -100,
+			Flag:          models.VarianceFlagLeaver,
+		})
+		report.Leavers++
+	}
+
+	return report, nil
+}
+
+// combineByEmployee sums payslips belonging to the same employee, keeping the first
+// payslip's order and identity.
+func combineByEmployee(payslips []models.Payslip) []models.Payslip {
+	index := make(map[uuid.UUID]int, len(payslips))
+	combined := make([]models.Payslip, 0, len(payslips))
+	for _, p := range payslips {
+		i, ok := index[p.EmployeeID]
+		if !ok {
+			index[p.EmployeeID] = len(combined)
+			combined = append(combined, p)
+			continue
+		}
+		combined[i].GrossSalary += p.GrossSalary
+		combined[i].NetSalary += p.NetSalary
+	}
+	return combined
 }
 
 // markFailed reverts a payroll back to OPEN if background processing fails before generating any payslips.
@@ -150,23 +607,20 @@ func (s *PayrollService) GetByID(id uuid.UUID) (*models.Payroll, error) {
 		return nil, err
 	}
 
-	// Derive month/year and load payslip summary
-	month := int(payroll.EndDate.Month())
-	year := payroll.EndDate.Year()
-	m := month
-	y := year
-	payslips, total, _ := s.payslipService.List(nil, &m, &y, 1, 10000)
+	// Load the run's payslips (drafts included while awaiting approval)
+	payslips, _ := s.payslipService.ListByPayroll(id, "")
 	payroll.Payslips = payslips
-	payroll.EmployeeCount = total
+	payroll.EmployeeCount = len(combineByEmployee(payslips))
 	for _, p := range payslips {
 		payroll.TotalNetSalary += p.NetSalary
 	}
+	payroll.Reversals, _ = s.repo.ListReversals(id)
 
 	return payroll, nil
 }
 
-func (s *PayrollService) List(status string, page, pageSize int) ([]models.Payroll, int, error) {
-	return s.repo.List(status, page, pageSize)
+func (s *PayrollService) List(status string, payGroupID *uuid.UUID, page, pageSize int) ([]models.Payroll, int, error) {
+	return s.repo.List(status, payGroupID, page, pageSize)
 }
 
 func (s *PayrollService) Cancel(id uuid.UUID) error {
@@ -0,0 +1,167 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"hr-system/internal/config"
+	"hr-system/internal/models"
+	"hr-system/internal/repository"
+	"hr-system/internal/utils/pdf"
+
+	"github.com/google/uuid"
+)
+
+// Payslip PDF password sources (config.CompanyConfig.PayslipPassword)
+const (
+	PayslipPasswordNationalID  = "national_id"
+	PayslipPasswordDateOfBirth = "date_of_birth"
+)
+
+// PayslipPDFService renders payslips as branded PDF documents
+type PayslipPDFService struct {
+	repo         *repository.PayslipRepository
+	empRepo      *repository.EmployeeRepository
+	lbRepo       *repository.LeaveBalanceRepository
+	payInputRepo *repository.PayInputRepository
+	retroRepo    *repository.RetroPayRepository
+	company      config.CompanyConfig
+	leaveYear    models.LeaveYear
+}
+
+func NewPayslipPDFService(
+	repo *repository.PayslipRepository,
+	empRepo *repository.EmployeeRepository,
+	lbRepo *repository.LeaveBalanceRepository,
+	payInputRepo *repository.PayInputRepository,
+	retroRepo *repository.RetroPayRepository,
+	company config.CompanyConfig,
+	leaveYear models.LeaveYear,
+) *PayslipPDFService {
+	return &PayslipPDFService{
+		repo:         repo,
+		empRepo:      empRepo,
+		lbRepo:       lbRepo,
+		payInputRepo: payInputRepo,
+		retroRepo:    retroRepo,
+		company:      company,
+		leaveYear:    leaveYear,
+	}
+}
+
+// ErrPayslipPasswordUnavailable is returned by RenderForEmail when payslips are password
+// protected but the employee lacks the detail the password is made from
+var ErrPayslipPasswordUnavailable = errors.New("no payslip password can be derived for this employee")
+
+// Render builds the PDF for a payslip, returning the document and a download filename.
+// The document includes year-to-date totals and the employee's leave balances, and is
+// password protected when a password source is configured.
+func (s *PayslipPDFService) Render(payslipID uuid.UUID) ([]byte, string, error) {
+	data, filename, _, err := s.render(payslipID)
+	return data, filename, err
+}
+
+// RenderForEmail is Render for sending the PDF by email, also returning a description of
+// its password for the employee ("" when protection is off). When protection is on but
+// the employee's password cannot be derived it returns ErrPayslipPasswordUnavailable
+// rather than an unprotected document.
+func (s *PayslipPDFService) RenderForEmail(payslipID uuid.UUID) ([]byte, string, string, error) {
+	data, filename, password, err := s.render(payslipID)
+	if err != nil {
+		return nil, "", "", err
+	}
+	if s.company.PayslipPassword == "" {
+		return data, filename, "", nil
+	}
+	if password == "" {
+		return nil, "", "", ErrPayslipPasswordUnavailable
+	}
+	return data, filename, s.passwordHint(), nil
+}
+
+func (s *PayslipPDFService) render(payslipID uuid.UUID) ([]byte, string, string, error) {
+	payslip, err := s.repo.GetByID(payslipID)
+	if err != nil {
+		return nil, "", "", errors.New("payslip not found")
+	}
+	if payslip.Status == models.PayslipStatusVoid {
+		return nil, "", "", errors.New("payslip has been voided")
+	}
+
+	emp, err := s.empRepo.GetByID(payslip.EmployeeID)
+	if err != nil {
+		return nil, "", "", errors.New("employee not found")
+	}
+
+	// Final payslips carry the YTD figures stamped when they were issued
+	ytd := payslip.YTD
+	if ytd == nil {
+		ytd, err = s.repo.YTDTotals(payslip.EmployeeID, payslip.Year, payslip.Month)
+		if err != nil {
+			return nil, "", "", fmt.Errorf("failed to load year-to-date totals: %w", err)
+		}
+	}
+	payslip.PayInputs, err = s.payInputRepo.ListByPayslip(payslipID)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("failed to load pay inputs: %w", err)
+	}
+	payslip.Arrears, err = s.retroRepo.ListLinesByPayslip(payslipID)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("failed to load arrears: %w", err)
+	}
+	balances, err := s.lbRepo.GetByEmployeeAndYear(payslip.EmployeeID, s.leaveYear.Of(payslip.PeriodEnd))
+	if err != nil {
+		log.Printf("payslip %s: failed to load leave balances: %v", payslipID, err)
+	}
+
+	password := s.password(emp)
+	data, err := pdf.RenderPayslip(pdf.PayslipDocument{
+		Company:       s.company,
+		Payslip:       payslip,
+		Employee:      emp,
+		YTD:           ytd,
+		LeaveBalances: balances,
+		Password:      password,
+	})
+	if err != nil {
+		return nil, "", "", fmt.Errorf("failed to render payslip: %w", err)
+	}
+
+	filename := fmt.Sprintf("payslip-%s-%d-%02d", emp.EmployeeNumber, payslip.Year, payslip.Month)
+	if payslip.PayslipType != models.PayslipTypeRegular {
+		filename += "-" + string(payslip.PayslipType)
+	}
+	return data, filename + ".pdf", password, nil
+}
+
+// password returns the PDF open password for an employee, or "" when protection is off
+// or the employee lacks the configured detail.
+func (s *PayslipPDFService) password(emp *models.Employee) string {
+	switch s.company.PayslipPassword {
+	case PayslipPasswordNationalID:
+		if id := strings.TrimSpace(emp.NationalID); id != "" {
+			return id
+		}
+	case PayslipPasswordDateOfBirth:
+		if emp.DateOfBirth != nil {
+			return emp.DateOfBirth.Format("02012006")
+		}
+	case "":
+		return ""
+	}
+	log.Printf("payslip pdf: no %s on record for employee %s, document is unprotected", s.company.PayslipPassword, emp.ID)
+	return ""
+}
+
+// passwordHint describes the configured password for the payslip email, or "" if none.
+func (s *PayslipPDFService) passwordHint() string {
+	switch s.company.PayslipPassword {
+	case PayslipPasswordNationalID:
+		return "your national ID number"
+	case PayslipPasswordDateOfBirth:
+		return "your date of birth in DDMMYYYY format"
+	}
+	return ""
+}
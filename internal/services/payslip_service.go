@@ -1,40 +1,59 @@
 package services
 
 import (
+	"database/sql"
 	"errors"
 	"fmt"
-	"time"
+	"log"
 
 	"hr-system/internal/models"
 	"hr-system/internal/repository"
+	"hr-system/pkg/money"
 	"hr-system/pkg/utils"
 
 	"github.com/google/uuid"
 )
 
 type PayslipService struct {
-	repo    *repository.PayslipRepository
-	empRepo *repository.EmployeeRepository
-	posRepo *repository.PositionRepository
-	lbRepo  *repository.LeaveBalanceRepository
+	repo            *repository.PayslipRepository
+	empRepo         *repository.EmployeeRepository
+	posRepo         *repository.PositionRepository
+	encashmentRepo  *repository.LeaveEncashmentRepository
+	loanRepo        *repository.LoanRepository
+	payInputRepo    *repository.PayInputRepository
+	retroRepo       *repository.RetroPayRepository
+	settlementRepo  *repository.FinalSettlementRepository
+	overtimeService *OvertimeService
+	currency        money.Currency
 }
 
 func NewPayslipService(
 	repo *repository.PayslipRepository,
 	empRepo *repository.EmployeeRepository,
 	posRepo *repository.PositionRepository,
-	lbRepo *repository.LeaveBalanceRepository,
+	encashmentRepo *repository.LeaveEncashmentRepository,
+	loanRepo *repository.LoanRepository,
+	payInputRepo *repository.PayInputRepository,
+	retroRepo *repository.RetroPayRepository,
+	settlementRepo *repository.FinalSettlementRepository,
+	overtimeService *OvertimeService,
+	currency money.Currency,
 ) *PayslipService {
 	return &PayslipService{
-		repo:    repo,
-		empRepo: empRepo,
-		posRepo: posRepo,
-		lbRepo:  lbRepo,
+		repo:            repo,
+		empRepo:         empRepo,
+		posRepo:         posRepo,
+		encashmentRepo:  encashmentRepo,
+		loanRepo:        loanRepo,
+		payInputRepo:    payInputRepo,
+		retroRepo:       retroRepo,
+		settlementRepo:  settlementRepo,
+		overtimeService: overtimeService,
+		currency:        currency,
 	}
 }
 
-// Generate creates a payslip for an employee for the given month/year.
-// It pulls salary data from the employee's position and unused leave days from leave balances.
+// Generate creates a final payslip for an employee for a calendar month outside any payroll run.
 func (s *PayslipService) Generate(employeeID uuid.UUID, month, year int) (*models.Payslip, error) {
 	if month < 1 || month > 12 {
 		return nil, errors.New("month must be between 1 and 12")
@@ -42,19 +61,216 @@ func (s *PayslipService) Generate(employeeID uuid.UUID, month, year int) (*model
 	if year < 2000 {
 		return nil, errors.New("invalid year")
 	}
+	return s.generate(nil, employeeID, models.CalendarMonth(month, year), models.PayFrequencyMonthly, models.PayslipStatusFinal)
+}
+
+// GenerateDraft creates a draft payslip for one pay period of a payroll run that only
+// becomes final when the payroll is approved.
+func (s *PayslipService) GenerateDraft(payrollID, employeeID uuid.UUID, period models.PayPeriod, frequency models.PayFrequency) (*models.Payslip, error) {
+	return s.generate(&payrollID, employeeID, period, frequency, models.PayslipStatusDraft)
+}
+
+// GenerateBonusDraft creates a draft bonus payslip for a bonus payroll run. The bonus is
+// bonusPercent of base salary and is taxed at the employee's marginal rate, i.e. the
+// extra PAYE due when the bonus is added on top of the regular monthly gross.
+func (s *PayslipService) GenerateBonusDraft(payrollID, employeeID uuid.UUID, period models.PayPeriod, bonusPercent float64) (*models.Payslip, error) {
+	if bonusPercent <= 0 {
+		return nil, errors.New("bonus percent must be greater than zero")
+	}
+
+	emp, err := s.empRepo.GetByID(employeeID)
+	if err != nil {
+		return nil, errors.New("employee not found")
+	}
+	if emp.EmploymentStatus != models.EmploymentStatusActive {
+		return nil, errors.New("employee is not active")
+	}
+	pos, err := s.posRepo.GetByID(emp.PositionID)
+	if err != nil {
+		return nil, errors.New("employee position not found")
+	}
+
+	regularGross := utils.CalculateSalaryBreakdown(pos.BaseSalary).GrossSalary
+	bonus := pos.BaseSalary.Percent(bonusPercent)
+	tax := utils.CalculatePAYE(regularGross+bonus) - utils.CalculatePAYE(regularGross)
+
+	payslip := &models.Payslip{
+		PayrollID:   &payrollID,
+		EmployeeID:  employeeID,
+		Month:       period.Month(),
+		Year:        period.Year(),
+		PeriodStart: period.Start,
+		PeriodEnd:   period.End,
+		BonusPay:    bonus,
+		GrossSalary: bonus,
+		IncomeTax:   tax,
+		NetSalary:   bonus - tax,
+		Currency:    s.currency,
+		Status:      models.PayslipStatusDraft,
+		PayslipType: models.PayslipTypeBonus,
+	}
+	if err := s.repo.Create(payslip); err != nil {
+		return nil, fmt.Errorf("failed to create bonus payslip: %w", err)
+	}
+	return s.repo.GetByID(payslip.ID)
+}
 
-	// Check if payslip already exists for this period
-	existing, err := s.repo.GetByEmployeeAndPeriod(employeeID, month, year)
+// generate builds and stores a regular payslip for an employee for a pay period, adding
+// any retro pay arrears due. If the period was previously paid and then reversed, the
+// new payslip is linked to the voided original so both sets of figures stay traceable.
+func (s *PayslipService) generate(payrollID *uuid.UUID, employeeID uuid.UUID, period models.PayPeriod, frequency models.PayFrequency, status models.PayslipStatus) (*models.Payslip, error) {
+	// Check if a payslip already covers any of this period, e.g. from another pay group
+	existing, err := s.repo.FindOverlappingRegular(employeeID, period.Start, period.End)
 	if err == nil && existing != nil {
-		return nil, fmt.Errorf("payslip already exists for %s %d", time.Month(month), year)
+		return nil, fmt.Errorf("payslip already exists for %s", models.PayPeriod{Start: existing.PeriodStart, End: existing.PeriodEnd}.Label())
 	}
 
+	payslip, err := s.calculate(employeeID, period, frequency)
+	if err != nil {
+		return nil, err
+	}
+	payslip.PayrollID = payrollID
+	payslip.Status = status
+	if err := s.addArrears(payslip); err != nil {
+		return nil, err
+	}
+	if voided, err := s.repo.GetLatestVoided(employeeID, period.Start, period.End); err == nil {
+		payslip.OriginalPayslipID = &voided.ID
+	}
+	repayments, err := s.loanDeductions(payslip)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(payslip, repayments...); err != nil {
+		return nil, fmt.Errorf("failed to create payslip: %w", err)
+	}
+	if status == models.PayslipStatusFinal {
+		s.refreshYTD(employeeID, payslip.Year)
+	}
+
+	// Re-fetch to populate relations
+	return s.repo.GetByID(payslip.ID)
+}
+
+// GenerateSupplementary issues an off-cycle payslip for a pay period that has already been
+// paid. The period is recalculated and the supplementary payslip carries only the
+// difference from what the employee has received so far, so the original stays intact.
+// A leaver's final pay is recalculated with the same settlement, so notice pay, gratuity
+// and leave encashment are only corrected, never clawed back.
+func (s *PayslipService) GenerateSupplementary(payrollID, employeeID uuid.UUID, period models.PayPeriod, frequency models.PayFrequency, reason string) (*models.Payslip, error) {
+	if reason == "" {
+		return nil, errors.New("reason is required for a supplementary payslip")
+	}
+
+	paid, err := s.repo.ListActiveForEmployeePeriod(employeeID, period.Start, period.End)
+	if err != nil {
+		return nil, err
+	}
+	var original *models.Payslip
+	for i := range paid {
+		if paid[i].PayslipType == models.PayslipTypeRegular {
+			original = &paid[i]
+		}
+	}
+	if original == nil {
+		return nil, fmt.Errorf("no final payslip for %s to supplement", period.Label())
+	}
+	if gross, tax, err := s.retroRepo.PeriodArrears(employeeID, period.Start, period.End); err != nil {
+		return nil, err
+	} else if gross != 0 || tax != 0 {
+		return nil, fmt.Errorf("%s has retro pay arrears; correct it with a retro pay calculation instead", period.Label())
+	}
+
+	var final *models.FinalSettlement
+	if st, err := s.settlementRepo.GetByPayslipID(original.ID); err == nil {
+		final = st
+	} else if err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to load final settlement: %w", err)
+	}
+	recalculated, err := s.calculatePay(employeeID, period, frequency, final)
+	if err != nil {
+		return nil, err
+	}
+
+	delta := supplementaryDelta(recalculated, paid)
+	if delta.GrossSalary.IsZero() && delta.NetSalary.IsZero() {
+		return nil, fmt.Errorf("no difference to pay for %s", period.Label())
+	}
+
+	delta.PayrollID = &payrollID
+	delta.Status = models.PayslipStatusFinal
+	delta.PayslipType = models.PayslipTypeSupplementary
+	delta.OriginalPayslipID = &original.ID
+	delta.AdjustmentReason = reason
+
+	if err := s.repo.Create(&delta); err != nil {
+		return nil, fmt.Errorf("failed to create supplementary payslip: %w", err)
+	}
+	s.refreshYTD(employeeID, delta.Year)
+	return s.repo.GetByID(delta.ID)
+}
+
+// supplementaryDelta subtracts everything already paid for a period, including earlier
+// supplements, from its recalculated pay. Arrears for earlier periods paid on these
+// payslips are not part of this period's pay, and bonus payslips are paid by bonus runs
+// rather than the regular calculation, so neither is taken off.
+func supplementaryDelta(recalculated *models.Payslip, paid []models.Payslip) models.Payslip {
+	delta := *recalculated
+	for _, p := range paid {
+		if p.PayslipType == models.PayslipTypeBonus {
+			continue
+		}
+		delta.BaseSalary -= p.BaseSalary
+		delta.HousingAllowance -= p.HousingAllowance
+		delta.TransportAllowance -= p.TransportAllowance
+		delta.MedicalAllowance -= p.MedicalAllowance
+		delta.OvertimeHours -= p.OvertimeHours
+		delta.OvertimePay -= p.OvertimePay
+		delta.GrossSalary -= p.GrossSalary - p.ArrearsPay
+		delta.IncomeTax -= p.IncomeTax - p.ArrearsTax
+		delta.LeaveDays -= p.LeaveDays
+		delta.EncashedLeave -= p.EncashedLeave
+		delta.LeaveEncashment -= p.LeaveEncashment
+		delta.NoticePay -= p.NoticePay
+		delta.Gratuity -= p.Gratuity
+		delta.BonusPay -= p.BonusPay
+		delta.AdditionalEarnings -= p.AdditionalEarnings
+		delta.Reimbursements -= p.Reimbursements
+		delta.PreTaxDeductions -= p.PreTaxDeductions
+		delta.OtherDeductions -= p.OtherDeductions
+		delta.NetSalary -= p.NetSalary + p.LoanDeduction - (p.ArrearsPay - p.ArrearsTax) // loan repayments are not refunded
+	}
+	return delta
+}
+
+// calculate works out an employee's pay for a pay period without storing it. It pulls
+// salary data from the employee's position, approved overtime from attendance, approved
+// leave encashments not yet paid and the one-off pay inputs recorded for the period's
+// tax month. Monthly amounts are pro-rated to the pay frequency, and PAYE is worked out
+// on the monthly equivalent so weekly earners fall in the same bands. Employees who have
+// left can still be paid for the period their termination date falls in.
+func (s *PayslipService) calculate(employeeID uuid.UUID, period models.PayPeriod, frequency models.PayFrequency) (*models.Payslip, error) {
+	return s.calculatePay(employeeID, period, frequency, nil)
+}
+
+// calculatePay is calculate, optionally for an employee's final pay. With a settlement the
+// salary is pro-rated to the days still owed, and the encashment of the leave left, notice
+// pay and gratuity are added to gross pay.
+func (s *PayslipService) calculatePay(employeeID uuid.UUID, period models.PayPeriod, frequency models.PayFrequency, final *models.FinalSettlement) (*models.Payslip, error) {
+	month, year := period.Month(), period.Year()
+	periodsPerYear := frequency.PeriodsPerYear()
+	if periodsPerYear == 0 {
+		return nil, fmt.Errorf("invalid pay frequency: %s", frequency)
+	}
+	share := 12 / float64(periodsPerYear) // fraction of a month the period pays
+
 	// Get employee
 	emp, err := s.empRepo.GetByID(employeeID)
 	if err != nil {
 		return nil, errors.New("employee not found")
 	}
-	if emp.EmploymentStatus != models.EmploymentStatusActive {
+	if emp.EmploymentStatus != models.EmploymentStatusActive && !isFinalPayPeriod(emp, period) && final == nil {
 		return nil, errors.New("employee is not active")
 	}
 
@@ -64,56 +280,259 @@ func (s *PayslipService) Generate(employeeID uuid.UUID, month, year int) (*model
 		return nil, errors.New("employee position not found")
 	}
 
-	// Calculate salary breakdown from position's base salary
+	// Calculate salary breakdown from position's base salary, pro-rated to the period
+	salaryShare := share
+	if final != nil {
+		salaryShare *= final.SalaryShare()
+	}
 	breakdown := utils.CalculateSalaryBreakdown(pos.BaseSalary)
+	breakdown.BaseSalary = breakdown.BaseSalary.Mul(salaryShare)
+	breakdown.HousingAllowance = breakdown.HousingAllowance.Mul(salaryShare)
+	breakdown.TransportAllowance = breakdown.TransportAllowance.Mul(salaryShare)
+	breakdown.MedicalAllowance = breakdown.MedicalAllowance.Mul(salaryShare)
+	breakdown.GrossSalary = money.Sum(breakdown.BaseSalary, breakdown.HousingAllowance,
+		breakdown.TransportAllowance, breakdown.MedicalAllowance)
 
-	// Calculate leave days compensation (unused leave days × fixed rate)
-	leaveDaysAmount := s.calculateLeaveDaysCompensation(employeeID, year)
+	// Approved overtime worked in the period is added to gross pay and taxed with it
+	overtime, err := s.overtimeService.CalculateForPeriod(employeeID, pos.BaseSalary, period.Start, period.End)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate overtime: %w", err)
+	}
 
-	// Build payslip
-	netSalary := breakdown.NetSalary + leaveDaysAmount
+	// One-off earnings and deductions for the tax month; taxable earnings join gross pay
+	// and pre-tax deductions come off the pay PAYE is worked out on. Inputs already paid
+	// on another period's payslip in the month (weekly and bi-weekly groups) are skipped.
+	monthInputs, err := s.payInputRepo.List(&employeeID, month, year)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load pay inputs: %w", err)
+	}
+	paidHere := map[uuid.UUID]bool{}
+	if paid, err := s.repo.ListActiveForEmployeePeriod(employeeID, period.Start, period.End); err == nil {
+		for _, p := range paid {
+			paidHere[p.ID] = true
+		}
+	}
+	var inputs []models.PayInput
+	var additional, reimbursements, preTax, postTax money.Money
+	for _, in := range monthInputs {
+		if in.Processed && (in.PayslipID == nil || !paidHere[*in.PayslipID]) {
+			continue
+		}
+		inputs = append(inputs, in)
+		switch in.TaxTreatment {
+		case models.TaxTreatmentTaxable:
+			additional += in.Amount
+		case models.TaxTreatmentNonTaxable:
+			reimbursements += in.Amount
+		case models.TaxTreatmentPreTax:
+			preTax += in.Amount
+		case models.TaxTreatmentPostTax:
+			postTax += in.Amount
+		}
+	}
 
-	payslip := &models.Payslip{
+	// Approved leave encashments are taxable pay on the first payslip after approval
+	encashments, err := s.encashmentRepo.ListPayable(employeeID, period.Start, period.End)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load leave encashments: %w", err)
+	}
+	var encashed money.Money
+	for _, e := range encashments {
+		encashed += e.Amount
+	}
+
+	var leaveEncashment, noticePay, gratuity money.Money
+	if final != nil {
+		leaveEncashment, noticePay, gratuity = final.LeaveEncashment, final.NoticePay, final.Gratuity
+	}
+
+	grossSalary := breakdown.GrossSalary + overtime.Amount + additional + encashed + leaveEncashment + noticePay + gratuity
+	taxable := (grossSalary - preTax).Max(0)
+	incomeTax := utils.CalculatePAYE(taxable)
+	if periodsPerYear != 12 {
+		incomeTax = utils.CalculatePAYE(taxable.Div(share)).Mul(share)
+	}
+
+	netSalary := grossSalary - incomeTax + reimbursements - preTax - postTax
+	if netSalary < 0 {
+		return nil, fmt.Errorf("deductions exceed pay by %s", (-netSalary).Format())
+	}
+
+	return &models.Payslip{
 		EmployeeID:         employeeID,
 		Month:              month,
 		Year:               year,
+		PeriodStart:        period.Start,
+		PeriodEnd:          period.End,
 		BaseSalary:         breakdown.BaseSalary,
 		HousingAllowance:   breakdown.HousingAllowance,
 		TransportAllowance: breakdown.TransportAllowance,
 		MedicalAllowance:   breakdown.MedicalAllowance,
-		GrossSalary:        breakdown.GrossSalary,
-		IncomeTax:          breakdown.IncomeTax,
-		LeaveDays:          leaveDaysAmount,
+		OvertimeHours:      overtime.PaidHours,
+		OvertimePay:        overtime.Amount,
+		GrossSalary:        grossSalary,
+		IncomeTax:          incomeTax,
+		AdditionalEarnings: additional,
+		Reimbursements:     reimbursements,
+		PreTaxDeductions:   preTax,
+		OtherDeductions:    postTax,
+		EncashedLeave:      encashed,
+		LeaveEncashment:    leaveEncashment,
+		NoticePay:          noticePay,
+		Gratuity:           gratuity,
 		NetSalary:          netSalary,
+		Currency:           s.currency,
+		PayInputs:          inputs,
+		Encashments:        encashments,
+	}, nil
+}
+
+// finalPay works out the final payslip for a settlement without storing it, returning
+// the loan repayments it would recover.
+func (s *PayslipService) finalPay(st *models.FinalSettlement) (*models.Payslip, []models.LoanRepayment, error) {
+	period := st.Period()
+	payslip, err := s.calculatePay(st.EmployeeID, period, period.Frequency(), st)
+	if err != nil {
+		return nil, nil, err
 	}
+	if err := s.addArrears(payslip); err != nil {
+		return nil, nil, err
+	}
+	repayments, err := s.loanDeductions(payslip)
+	if err != nil {
+		return nil, nil, err
+	}
+	return payslip, repayments, nil
+}
 
-	if err := s.repo.Create(payslip); err != nil {
-		return nil, fmt.Errorf("failed to create payslip: %w", err)
+// GenerateFinalSettlement issues the final payslip for an approved settlement. It is a
+// regular payslip for the settlement's pay period, so the period cannot be paid twice.
+func (s *PayslipService) GenerateFinalSettlement(st *models.FinalSettlement) (*models.Payslip, error) {
+	existing, err := s.repo.FindOverlappingRegular(st.EmployeeID, st.PeriodStart, st.PeriodEnd)
+	if err == nil && existing != nil {
+		return nil, fmt.Errorf("payslip already exists for %s", models.PayPeriod{Start: existing.PeriodStart, End: existing.PeriodEnd}.Label())
 	}
 
-	// Re-fetch to populate relations
+	payslip, repayments, err := s.finalPay(st)
+	if err != nil {
+		return nil, err
+	}
+	payslip.Status = models.PayslipStatusFinal
+	payslip.AdjustmentReason = "Final settlement"
+	if voided, err := s.repo.GetLatestVoided(st.EmployeeID, st.PeriodStart, st.PeriodEnd); err == nil {
+		payslip.OriginalPayslipID = &voided.ID
+	}
+
+	if err := s.repo.Create(payslip, repayments...); err != nil {
+		return nil, fmt.Errorf("failed to create final payslip: %w", err)
+	}
+	s.refreshYTD(st.EmployeeID, payslip.Year)
 	return s.repo.GetByID(payslip.ID)
 }
 
-// calculateLeaveDaysCompensation computes compensation for unused leave days
-func (s *PayslipService) calculateLeaveDaysCompensation(employeeID uuid.UUID, year int) float64 {
-	balances, err := s.lbRepo.GetByEmployeeAndYear(employeeID, year)
+// addArrears puts an employee's unpaid retro pay arrears on a regular payslip. The tax
+// on them was worked out period by period when they were calculated, so it is added as
+// is rather than taxing the arrears again at this period's rates.
+func (s *PayslipService) addArrears(p *models.Payslip) error {
+	adjustments, err := s.retroRepo.ListPayable(p.EmployeeID, p.PeriodStart)
+	if err != nil {
+		return fmt.Errorf("failed to load retro pay arrears: %w", err)
+	}
+	for _, a := range adjustments {
+		p.ArrearsPay += a.GrossArrears
+		p.ArrearsTax += a.TaxArrears
+		p.Arrears = append(p.Arrears, a.Lines...)
+	}
+	p.GrossSalary += p.ArrearsPay
+	p.IncomeTax += p.ArrearsTax
+	p.NetSalary += p.ArrearsPay - p.ArrearsTax
+	if p.NetSalary < 0 {
+		return fmt.Errorf("arrears recovered exceed pay by %s", (-p.NetSalary).Format())
+	}
+	return nil
+}
+
+// loanDeductions takes the loan instalments due in the payslip's month off its net pay
+// and returns the repayments to record with it. Instalments are monthly, so with weekly
+// or bi-weekly pay only the first payslip of the month carries them. On an employee's
+// final pay (any period ending on or after their termination date) the whole outstanding
+// balance is recovered instead. Deductions never take
+// net pay below zero; whatever cannot be recovered stays outstanding for the next payslip.
+func (s *PayslipService) loanDeductions(p *models.Payslip) ([]models.LoanRepayment, error) {
+	emp, err := s.empRepo.GetByID(p.EmployeeID)
+	if err != nil {
+		return nil, errors.New("employee not found")
+	}
+	loans, err := s.loanRepo.ListOutstanding(p.EmployeeID)
 	if err != nil {
-		return 0
+		return nil, fmt.Errorf("failed to load loans: %w", err)
+	}
+	finalPay := emp.TerminationDate != nil && !emp.TerminationDate.After(p.PeriodEnd)
+	deducted, err := s.loanRepo.LoansDeductedInMonth(p.EmployeeID, p.Month, p.Year)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load loan repayments: %w", err)
 	}
 
-	totalUnused := 0
-	for _, b := range balances {
-		if b.Balance > 0 {
-			totalUnused += b.EarnedLeaveDays
+	var repayments []models.LoanRepayment
+	for _, l := range loans {
+		due := l.InstalmentAmount.Min(l.OutstandingBalance)
+		repaymentType := models.LoanRepaymentPayroll
+		if finalPay {
+			due = l.OutstandingBalance
+			repaymentType = models.LoanRepaymentFinalPay
+		} else if l.StartYear*12+l.StartMonth > p.Year*12+p.Month {
+			continue // schedule has not started yet
+		} else if deducted[l.ID] {
+			continue // this month's instalment is already on an earlier payslip
+		}
+		amount := due.Min(p.NetSalary)
+		if amount <= 0 {
+			continue
 		}
+		p.LoanDeduction += amount
+		p.NetSalary -= amount
+		repayments = append(repayments, models.LoanRepayment{
+			LoanID:        l.ID,
+			RepaymentType: repaymentType,
+			Amount:        amount,
+			Month:         p.Month,
+			Year:          p.Year,
+		})
 	}
+	return repayments, nil
+}
+
+// isFinalPeriod reports whether an employee's termination date falls in the given month.
+func isFinalPeriod(emp *models.Employee, month, year int) bool {
+	return isFinalPayPeriod(emp, models.CalendarMonth(month, year))
+}
 
-	return float64(totalUnused) * utils.LeaveDayRate
+// isFinalPayPeriod reports whether an employee's termination date falls in the given pay
+// period, making that period's payslip their final pay.
+func isFinalPayPeriod(emp *models.Employee, period models.PayPeriod) bool {
+	return emp.TerminationDate != nil && period.Contains(*emp.TerminationDate)
 }
 
+// GetByID returns a payslip with the one-off pay inputs, retro pay arrears and leave
+// encashments paid on it
 func (s *PayslipService) GetByID(id uuid.UUID) (*models.Payslip, error) {
-	return s.repo.GetByID(id)
+	payslip, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	payslip.PayInputs, err = s.payInputRepo.ListByPayslip(id)
+	if err != nil {
+		return nil, err
+	}
+	payslip.Arrears, err = s.retroRepo.ListLinesByPayslip(id)
+	if err != nil {
+		return nil, err
+	}
+	payslip.Encashments, err = s.encashmentRepo.ListByPayslip(id)
+	if err != nil {
+		return nil, err
+	}
+	return payslip, nil
 }
 
 func (s *PayslipService) GetByEmployeeAndPeriod(employeeID uuid.UUID, month, year int) (*models.Payslip, error) {
@@ -124,8 +543,59 @@ func (s *PayslipService) List(employeeID *uuid.UUID, month *int, year *int, page
 	return s.repo.List(employeeID, month, year, page, pageSize)
 }
 
+func (s *PayslipService) ListByPayroll(payrollID uuid.UUID, status models.PayslipStatus) ([]models.Payslip, error) {
+	return s.repo.ListByPayroll(payrollID, status)
+}
+
+// FinalizeDrafts locks a payroll's draft payslips and updates the employees' YTD totals.
+func (s *PayslipService) FinalizeDrafts(payrollID uuid.UUID) (int, error) {
+	n, err := s.repo.FinalizeDrafts(payrollID)
+	if err != nil {
+		return 0, err
+	}
+	if err := s.repo.RefreshYTDForPayroll(payrollID); err != nil {
+		log.Printf("payroll %s: failed to update YTD totals: %v", payrollID, err)
+	}
+	return n, nil
+}
+
+func (s *PayslipService) DeleteDrafts(payrollID uuid.UUID) error {
+	return s.repo.DeleteDrafts(payrollID)
+}
+
+// RefreshPayrollYTD rebuilds the YTD totals of every employee on a payroll, e.g. after
+// its payslips were voided.
+func (s *PayslipService) RefreshPayrollYTD(payrollID uuid.UUID) {
+	if err := s.repo.RefreshYTDForPayroll(payrollID); err != nil {
+		log.Printf("payroll %s: failed to update YTD totals: %v", payrollID, err)
+	}
+}
+
 func (s *PayslipService) Delete(id uuid.UUID) error {
-	return s.repo.Delete(id)
+	payslip, err := s.repo.GetByID(id)
+	if err != nil {
+		return fmt.Errorf("payslip not found")
+	}
+	if err := s.repo.Delete(id); err != nil {
+		return err
+	}
+	if payslip.Status == models.PayslipStatusFinal {
+		s.refreshYTD(payslip.EmployeeID, payslip.Year)
+	}
+	return nil
+}
+
+// GetYTD returns an employee's year-to-date totals for a tax year
+func (s *PayslipService) GetYTD(employeeID uuid.UUID, year int) (*models.PayslipYTD, error) {
+	return s.repo.GetYTD(employeeID, year)
+}
+
+// refreshYTD updates an employee's YTD totals after a final payslip changes. The payslip
+// itself is already saved, so a failure here is logged rather than returned.
+func (s *PayslipService) refreshYTD(employeeID uuid.UUID, year int) {
+	if err := s.repo.RefreshYTD(employeeID, year); err != nil {
+		log.Printf("employee %s: failed to update %d YTD totals: %v", employeeID, year, err)
+	}
 }
 
 // GetEmployeeByUserID returns the employee record linked to the given user ID
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"testing"
+
+	"hr-system/internal/models"
+	"hr-system/pkg/money"
+)
+
+// finalSettlementPayslip is a leaver's final pay: a part month of salary plus the
+// settlement's leave encashment, notice pay and gratuity
+func finalSettlementPayslip() models.Payslip {
+	p := models.Payslip{
+		PayslipType:        models.PayslipTypeRegular,
+		BaseSalary:         money.MustParse("2000.00"),
+		HousingAllowance:   money.MustParse("500.00"),
+		TransportAllowance: money.MustParse("200.00"),
+		MedicalAllowance:   money.MustParse("100.00"),
+		LeaveEncashment:    money.MustParse("1500.00"),
+		NoticePay:          money.MustParse("4000.00"),
+		Gratuity:           money.MustParse("3000.00"),
+		IncomeTax:          money.MustParse("2500.00"),
+	}
+	p.GrossSalary = money.Sum(p.BaseSalary, p.HousingAllowance, p.TransportAllowance, p.MedicalAllowance,
+		p.LeaveEncashment, p.NoticePay, p.Gratuity)
+	p.NetSalary = p.GrossSalary - p.IncomeTax
+	return p
+}
+
+func TestSupplementaryDeltaKeepsFinalSettlement(t *testing.T) {
+	paid := finalSettlementPayslip()
+
+	// Recalculated with the same settlement, plus overtime approved after the period was paid
+	recalculated := finalSettlementPayslip()
+	recalculated.OvertimeHours = 4
+	recalculated.OvertimePay = money.MustParse("250.00")
+	recalculated.GrossSalary += recalculated.OvertimePay
+	recalculated.IncomeTax += money.MustParse("75.00")
+	recalculated.NetSalary = recalculated.GrossSalary - recalculated.IncomeTax
+
+	delta := supplementaryDelta(&recalculated, []models.Payslip{paid})
+
+	if !delta.LeaveEncashment.IsZero() || !delta.NoticePay.IsZero() || !delta.Gratuity.IsZero() {
+		t.Errorf("settlement components should not change, got leave encashment %s, notice pay %s, gratuity %s",
+			delta.LeaveEncashment, delta.NoticePay, delta.Gratuity)
+	}
+	if want := money.MustParse("250.00"); delta.GrossSalary != want {
+		t.Errorf("gross = %s, want %s", delta.GrossSalary, want)
+	}
+	if want := money.MustParse("75.00"); delta.IncomeTax != want {
+		t.Errorf("income tax = %s, want %s", delta.IncomeTax, want)
+	}
+	if want := money.MustParse("175.00"); delta.NetSalary != want {
+		t.Errorf("net = %s, want %s", delta.NetSalary, want)
+	}
+	if !delta.BaseSalary.IsZero() {
+		t.Errorf("base salary = %s, want 0.00", delta.BaseSalary)
+	}
+}
+
+func TestSupplementaryDeltaCorrectsSettlementComponents(t *testing.T) {
+	paid := finalSettlementPayslip()
+
+	// The settlement was recalculated with a larger gratuity
+	recalculated := finalSettlementPayslip()
+	recalculated.Gratuity += money.MustParse("600.00")
+	recalculated.GrossSalary += money.MustParse("600.00")
+	recalculated.NetSalary += money.MustParse("600.00")
+
+	delta := supplementaryDelta(&recalculated, []models.Payslip{paid})
+
+	if want := money.MustParse("600.00"); delta.Gratuity != want || delta.GrossSalary != want {
+		t.Errorf("gratuity = %s, gross = %s, want both %s", delta.Gratuity, delta.GrossSalary, want)
+	}
+	if !delta.NoticePay.IsZero() || !delta.LeaveEncashment.IsZero() {
+		t.Errorf("notice pay = %s, leave encashment = %s, want 0.00", delta.NoticePay, delta.LeaveEncashment)
+	}
+}
+
+func TestSupplementaryDeltaIgnoresBonusPayslips(t *testing.T) {
+	regular := models.Payslip{
+		PayslipType: models.PayslipTypeRegular,
+		BaseSalary:  money.MustParse("5000.00"),
+		GrossSalary: money.MustParse("5000.00"),
+		IncomeTax:   money.MustParse("1000.00"),
+		NetSalary:   money.MustParse("4000.00"),
+	}
+	bonus := models.Payslip{
+		PayslipType: models.PayslipTypeBonus,
+		BonusPay:    money.MustParse("2500.00"),
+		GrossSalary: money.MustParse("2500.00"),
+		IncomeTax:   money.MustParse("925.00"),
+		NetSalary:   money.MustParse("1575.00"),
+	}
+	recalculated := regular
+	recalculated.BaseSalary += money.MustParse("100.00")
+	recalculated.GrossSalary += money.MustParse("100.00")
+	recalculated.NetSalary += money.MustParse("100.00")
+
+	delta := supplementaryDelta(&recalculated, []models.Payslip{regular, bonus})
+
+	if want := money.MustParse("100.00"); delta.GrossSalary != want || delta.NetSalary != want {
+		t.Errorf("gross = %s, net = %s, want both %s", delta.GrossSalary, delta.NetSalary, want)
+	}
+	if !delta.BonusPay.IsZero() {
+		t.Errorf("bonus pay = %s, want 0.00", delta.BonusPay)
+	}
+}
+
+func TestSupplementaryDeltaSubtractsEarlierSupplements(t *testing.T) {
+	regular := models.Payslip{
+		PayslipType: models.PayslipTypeRegular,
+		BaseSalary:  money.MustParse("5000.00"),
+		GrossSalary: money.MustParse("5000.00"),
+		NetSalary:   money.MustParse("5000.00"),
+	}
+	supplement := models.Payslip{
+		PayslipType: models.PayslipTypeSupplementary,
+		OvertimePay: money.MustParse("200.00"),
+		GrossSalary: money.MustParse("200.00"),
+		NetSalary:   money.MustParse("200.00"),
+	}
+	recalculated := regular
+	recalculated.OvertimePay = money.MustParse("300.00")
+	recalculated.GrossSalary += recalculated.OvertimePay
+	recalculated.NetSalary += recalculated.OvertimePay
+
+	delta := supplementaryDelta(&recalculated, []models.Payslip{regular, supplement})
+
+	if want := money.MustParse("100.00"); delta.OvertimePay != want || delta.GrossSalary != want {
+		t.Errorf("overtime = %s, gross = %s, want both %s", delta.OvertimePay, delta.GrossSalary, want)
+	}
+}
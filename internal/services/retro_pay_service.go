@@ -0,0 +1,228 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"hr-system/internal/interfaces"
+	"hr-system/internal/models"
+	"hr-system/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+type RetroPayService struct {
+	repo           *repository.RetroPayRepository
+	payslipRepo    *repository.PayslipRepository
+	empRepo        *repository.EmployeeRepository
+	payslipService *PayslipService
+}
+
+func NewRetroPayService(
+	repo *repository.RetroPayRepository,
+	payslipRepo *repository.PayslipRepository,
+	empRepo *repository.EmployeeRepository,
+	payslipService *PayslipService,
+) *RetroPayService {
+	return &RetroPayService{
+		repo:           repo,
+		payslipRepo:    payslipRepo,
+		empRepo:        empRepo,
+		payslipService: payslipService,
+	}
+}
+
+// RetroPayRequest selects the employees to recalculate: those listed, plus every active
+// employee in the position when one is given (e.g. after the position's salary changed).
+type RetroPayRequest struct {
+	EmployeeIDs   []uuid.UUID
+	PositionID    *uuid.UUID
+	EffectiveFrom time.Time
+	Reason        string
+	Preview       bool
+}
+
+// Calculate recalculates the pay periods already paid since the effective date under
+// the current compensation and tax rules, e.g. after a raise approved in March is
+// backdated to January. Each employee's difference from what was paid becomes arrears
+// on their next regular payslip, with a line per period. Employees with nothing to pay
+// or who cannot be recalculated are reported as skipped. A preview saves nothing.
+func (s *RetroPayService) Calculate(req RetroPayRequest, createdBy uuid.UUID) (*models.RetroPayReport, error) {
+	req.Reason = strings.TrimSpace(req.Reason)
+	switch {
+	case req.EffectiveFrom.IsZero():
+		return nil, errors.New("effective_from is required")
+	case req.EffectiveFrom.After(time.Now()):
+		return nil, errors.New("effective_from cannot be in the future")
+	case req.Reason == "" && !req.Preview:
+		return nil, errors.New("reason is required")
+	case len(req.Reason) > 255:
+		return nil, errors.New("reason must be at most 255 characters")
+	}
+
+	employeeIDs, err := s.selectEmployees(req)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.RetroPayReport{
+		EffectiveFrom: req.EffectiveFrom,
+		Preview:       req.Preview,
+		Adjustments:   []models.RetroPayAdjustment{},
+		Skipped:       []models.RetroPaySkip{},
+	}
+	for _, id := range employeeIDs {
+		a, err := s.calculateFor(id, req.EffectiveFrom)
+		if err == nil && !req.Preview {
+			a.Reason = req.Reason
+			a.CreatedBy = &createdBy
+			if err = s.repo.Create(a); err != nil {
+				err = fmt.Errorf("failed to save arrears: %w", err)
+			}
+		}
+		if err != nil {
+			skip := models.RetroPaySkip{EmployeeID: id, Message: err.Error()}
+			if emp, empErr := s.empRepo.GetByID(id); empErr == nil {
+				skip.EmployeeName = emp.FullName()
+			}
+			report.Skipped = append(report.Skipped, skip)
+			continue
+		}
+		report.GrossArrears += a.GrossArrears
+		report.TaxArrears += a.TaxArrears
+		report.NetArrears += a.NetArrears
+		report.Adjustments = append(report.Adjustments, *a)
+	}
+	return report, nil
+}
+
+func (s *RetroPayService) selectEmployees(req RetroPayRequest) ([]uuid.UUID, error) {
+	seen := map[uuid.UUID]bool{}
+	var ids []uuid.UUID
+	add := func(id uuid.UUID) {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	for _, id := range req.EmployeeIDs {
+		add(id)
+	}
+	if req.PositionID != nil {
+		filter := interfaces.EmployeeFilter{EmploymentStatus: "active", PositionID: req.PositionID}
+		employees, _, err := s.empRepo.List(filter, 1, 10000)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list employees: %w", err)
+		}
+		for _, e := range employees {
+			add(e.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, errors.New("employee_ids or position_id is required")
+	}
+	return ids, nil
+}
+
+// calculateFor works out one employee's arrears without storing them. Each paid period
+// is compared with everything paid for it so far, including supplementary payslips and
+// arrears from earlier retro calculations, so running it again never pays twice. When
+// the effective date falls part-way through a period the difference is pro-rated by days.
+func (s *RetroPayService) calculateFor(employeeID uuid.UUID, effectiveFrom time.Time) (*models.RetroPayAdjustment, error) {
+	emp, err := s.empRepo.GetByID(employeeID)
+	if err != nil {
+		return nil, errors.New("employee not found")
+	}
+	if emp.EmploymentStatus != models.EmploymentStatusActive {
+		return nil, errors.New("employee is not active")
+	}
+
+	originals, err := s.payslipRepo.ListFinalRegularSince(employeeID, effectiveFrom)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load payslips: %w", err)
+	}
+	if len(originals) == 0 {
+		return nil, errors.New("no periods paid since the effective date")
+	}
+
+	a := &models.RetroPayAdjustment{
+		EmployeeID:    employeeID,
+		EffectiveFrom: effectiveFrom,
+		Status:        models.RetroPayStatusPending,
+		EmployeeName:  emp.FullName(),
+	}
+	for _, original := range originals {
+		period := models.PayPeriod{Start: original.PeriodStart, End: original.PeriodEnd}
+		recalculated, err := s.payslipService.calculate(employeeID, period, period.Frequency())
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", period.Label(), err)
+		}
+
+		paid, err := s.payslipRepo.ListActiveForEmployeePeriod(employeeID, period.Start, period.End)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load payslips: %w", err)
+		}
+		prevGross, prevTax, err := s.repo.PeriodArrears(employeeID, period.Start, period.End)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load earlier arrears: %w", err)
+		}
+		for _, p := range paid {
+			prevGross += p.GrossSalary - p.ArrearsPay
+			prevTax += p.IncomeTax - p.ArrearsTax
+		}
+
+		grossDiff := recalculated.GrossSalary - prevGross
+		taxDiff := recalculated.IncomeTax - prevTax
+		if effectiveFrom.After(period.Start) {
+			share := float64(daysBetween(effectiveFrom, period.End)) / float64(daysBetween(period.Start, period.End))
+			grossDiff = grossDiff.Mul(share)
+			taxDiff = taxDiff.Mul(share)
+		}
+		if grossDiff.IsZero() && taxDiff.IsZero() {
+			continue
+		}
+
+		a.Lines = append(a.Lines, models.RetroPayLine{
+			PayslipID:         original.ID,
+			Month:             original.Month,
+			Year:              original.Year,
+			PeriodStart:       period.Start,
+			PeriodEnd:         period.End,
+			PreviousGross:     prevGross,
+			RecalculatedGross: recalculated.GrossSalary,
+			GrossDifference:   grossDiff,
+			PreviousTax:       prevTax,
+			RecalculatedTax:   recalculated.IncomeTax,
+			TaxDifference:     taxDiff,
+			NetDifference:     grossDiff - taxDiff,
+		})
+		a.GrossArrears += grossDiff
+		a.TaxArrears += taxDiff
+	}
+	if len(a.Lines) == 0 {
+		return nil, errors.New("no difference to pay")
+	}
+	a.NetArrears = a.GrossArrears - a.TaxArrears
+	return a, nil
+}
+
+func (s *RetroPayService) GetByID(id uuid.UUID) (*models.RetroPayAdjustment, error) {
+	return s.repo.GetByID(id)
+}
+
+// List returns adjustments, optionally for one employee and by state (pending, paid or cancelled)
+func (s *RetroPayService) List(employeeID *uuid.UUID, state string) ([]models.RetroPayAdjustment, error) {
+	switch state {
+	case "", "pending", "paid", "cancelled":
+	default:
+		return nil, fmt.Errorf("invalid status: %s", state)
+	}
+	return s.repo.List(employeeID, state)
+}
+
+// Cancel withdraws arrears that have not been paid yet
+func (s *RetroPayService) Cancel(id uuid.UUID) error {
+	return s.repo.Cancel(id)
+}
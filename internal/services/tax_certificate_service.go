@@ -0,0 +1,143 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"time"
+
+	"hr-system/internal/config"
+	"hr-system/internal/models"
+	"hr-system/internal/repository"
+	"hr-system/internal/utils/pdf"
+	"hr-system/pkg/money"
+
+	"github.com/google/uuid"
+)
+
+// TaxCertificateService produces annual tax documents from final payslips: the
+// employee tax deduction certificate (P9-style) and the employer annual return.
+type TaxCertificateService struct {
+	payslipRepo *repository.PayslipRepository
+	empRepo     *repository.EmployeeRepository
+	posRepo     *repository.PositionRepository
+	company     config.CompanyConfig
+}
+
+func NewTaxCertificateService(
+	payslipRepo *repository.PayslipRepository,
+	empRepo *repository.EmployeeRepository,
+	posRepo *repository.PositionRepository,
+	company config.CompanyConfig,
+) *TaxCertificateService {
+	return &TaxCertificateService{
+		payslipRepo: payslipRepo,
+		empRepo:     empRepo,
+		posRepo:     posRepo,
+		company:     company,
+	}
+}
+
+// Certificate builds an employee's tax certificate for a year.
+func (s *TaxCertificateService) Certificate(employeeID uuid.UUID, year int) (*models.TaxCertificate, error) {
+	if year < 2000 {
+		return nil, errors.New("invalid year")
+	}
+	emp, err := s.empRepo.GetByID(employeeID)
+	if err != nil {
+		return nil, errors.New("employee not found")
+	}
+
+	months, err := s.payslipRepo.MonthlyTaxTotals(employeeID, year)
+	if err != nil {
+		return nil, err
+	}
+	if len(months) == 0 {
+		return nil, fmt.Errorf("no final payslips for %d", year)
+	}
+
+	cert := &models.TaxCertificate{
+		Year:            year,
+		EmployerName:    s.company.Name,
+		EmployerAddress: s.company.Address,
+		EmployeeID:      emp.ID,
+		EmployeeNumber:  emp.EmployeeNumber,
+		EmployeeName:    emp.FullName(),
+		NationalID:      emp.NationalID,
+		Months:          months,
+		GeneratedAt:     time.Now(),
+	}
+	if pos, err := s.posRepo.GetByID(emp.PositionID); err == nil {
+		cert.PositionName = pos.Title
+	}
+	for _, m := range months {
+		cert.Totals.Add(m.TaxAmounts)
+	}
+	return cert, nil
+}
+
+// CertificatePDF renders an employee's tax certificate, returning the PDF and a filename.
+func (s *TaxCertificateService) CertificatePDF(employeeID uuid.UUID, year int) ([]byte, string, error) {
+	cert, err := s.Certificate(employeeID, year)
+	if err != nil {
+		return nil, "", err
+	}
+	data, err := pdf.RenderTaxCertificate(cert, s.company.Address)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to render tax certificate: %w", err)
+	}
+	return data, fmt.Sprintf("tax-certificate-%s-%d.pdf", cert.EmployeeNumber, year), nil
+}
+
+// AnnualReturn summarises pay and tax deducted for every employee paid in a year.
+func (s *TaxCertificateService) AnnualReturn(year int) (*models.AnnualReturn, error) {
+	if year < 2000 {
+		return nil, errors.New("invalid year")
+	}
+	lines, err := s.payslipRepo.AnnualTaxTotals(year)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := &models.AnnualReturn{
+		Year:          year,
+		EmployerName:  s.company.Name,
+		EmployeeCount: len(lines),
+		Lines:         lines,
+		GeneratedAt:   time.Now(),
+	}
+	if ret.Lines == nil {
+		ret.Lines = []models.AnnualReturnLine{}
+	}
+	for _, l := range lines {
+		ret.Totals.Add(l.TaxAmounts)
+	}
+	return ret, nil
+}
+
+// AnnualReturnCSV renders the annual return as CSV with a closing totals row.
+func (s *TaxCertificateService) AnnualReturnCSV(ret *models.AnnualReturn) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"employee_number", "employee_name", "national_id", "months_paid", "basic_salary", "allowances",
+		"overtime_pay", "bonus_pay", "leave_pay", "gross_salary", "income_tax", "net_salary"})
+	amounts := func(a models.TaxAmounts) []string {
+		var out []string
+		for _, v := range []money.Money{a.BasicSalary, a.Allowances, a.OvertimePay, a.BonusPay, a.LeavePay, a.GrossSalary, a.IncomeTax, a.NetSalary} {
+			out = append(out, v.String())
+		}
+		return out
+	}
+	for _, l := range ret.Lines {
+		w.Write(append([]string{l.EmployeeNumber, l.EmployeeName, l.NationalID, fmt.Sprint(l.MonthsPaid)}, amounts(l.TaxAmounts)...))
+	}
+	w.Write(append([]string{"TOTAL", fmt.Sprintf("%d employees", ret.EmployeeCount), "", ""}, amounts(ret.Totals)...))
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// GetEmployeeByUserID returns the employee record linked to the given user ID
+func (s *TaxCertificateService) GetEmployeeByUserID(userID uuid.UUID) (*models.Employee, error) {
+	return s.empRepo.GetByUserID(userID)
+}
@@ -21,6 +21,8 @@ type WorkflowService struct {
 	userRepo            *repository.UserRepository
 	employeeRepo        *repository.EmployeeRepository
 	leaveRequestRepo    *repository.LeaveRequestRepository
+	loanRepo            *repository.LoanRepository
+	encashmentRepo      *repository.LeaveEncashmentRepository
 	leaveBalanceService *LeaveBalanceService
 	emailService        *email.EmailService
 }
@@ -33,6 +35,8 @@ func NewWorkflowService(
 	userRepo *repository.UserRepository,
 	employeeRepo *repository.EmployeeRepository,
 	leaveRequestRepo *repository.LeaveRequestRepository,
+	loanRepo *repository.LoanRepository,
+	encashmentRepo *repository.LeaveEncashmentRepository,
 	leaveBalanceService *LeaveBalanceService,
 	emailService *email.EmailService,
 ) *WorkflowService {
@@ -44,6 +48,8 @@ func NewWorkflowService(
 		userRepo:            userRepo,
 		employeeRepo:        employeeRepo,
 		leaveRequestRepo:    leaveRequestRepo,
+		loanRepo:            loanRepo,
+		encashmentRepo:      encashmentRepo,
 		leaveBalanceService: leaveBalanceService,
 		emailService:        emailService,
 	}
@@ -323,6 +329,51 @@ func (s *WorkflowService) GetInstanceByTaskID(taskID string) (*models.WorkflowIn
 	return s.instanceRepo.GetByTaskID(taskID)
 }
 
+// WithdrawWorkflow cancels the open workflow for an underlying task, e.g. when the request
+// it approves is amended and has to be approved again. The instance and its history are
+// kept; there is nothing to do when the latest workflow is already finished.
+func (s *WorkflowService) WithdrawWorkflow(taskID, userID, comment string) error {
+	instance, err := s.instanceRepo.GetByTaskID(taskID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return fmt.Errorf("failed to get workflow instance: %w", err)
+	}
+	if instance.Status != "in_progress" && instance.Status != "pending" {
+		return nil
+	}
+
+	if task, err := s.taskRepo.GetActiveTaskForInstance(instance.ID); err == nil {
+		if err := s.taskRepo.UpdateStatus(task.ID, "skipped"); err != nil {
+			return fmt.Errorf("failed to close task: %w", err)
+		}
+	}
+	if err := s.instanceRepo.Cancel(instance.ID); err != nil {
+		return fmt.Errorf("failed to cancel instance: %w", err)
+	}
+
+	performedByName := ""
+	if userUUID, err := uuid.Parse(userID); err == nil {
+		if emp, err := s.employeeRepo.GetByUserID(userUUID); err == nil {
+			performedByName = emp.FirstName + " " + emp.LastName
+		}
+	}
+	history := &models.WorkflowHistory{
+		InstanceID:      instance.ID,
+		FromStepID:      &instance.CurrentStepID,
+		ToStepID:        instance.CurrentStepID,
+		ActionTaken:     "withdraw",
+		PerformedBy:     userID,
+		PerformedByName: performedByName,
+		Comments:        comment,
+	}
+	if err := s.historyRepo.Create(history); err != nil {
+		return fmt.Errorf("failed to create history: %w", err)
+	}
+	return nil
+}
+
 // Helper: Determine who to assign the task to based on step configuration
 // Uses intelligent load balancing - assigns to the user with the required role who has the fewest pending tasks
 func (s *WorkflowService) determineAssignee(step *models.WorkflowStep, taskDetails models.TaskDetails) (string, error) {
@@ -399,6 +450,27 @@ func (s *WorkflowService) updateUnderlyingTaskStatus(instance *models.WorkflowIn
 			return s.updateLeaveRequestStatus(leaveRequestID, reviewer.ID, "approved")
 		}
 
+	case "loan_request":
+		loanID, err := uuid.Parse(instance.TaskDetails.TaskID)
+		if err != nil {
+			return fmt.Errorf("invalid loan ID: %w", err)
+		}
+		status := models.LoanStatusActive
+		if isRejection {
+			status = models.LoanStatusRejected
+		}
+		return s.loanRepo.UpdateStatus(loanID, status, &reviewer.ID)
+
+	case "leave_encashment":
+		encashmentID, err := uuid.Parse(instance.TaskDetails.TaskID)
+		if err != nil {
+			return fmt.Errorf("invalid leave encashment ID: %w", err)
+		}
+		if isRejection {
+			return s.encashmentRepo.Close(encashmentID, models.LeaveEncashmentRejected, &reviewer.ID)
+		}
+		return s.encashmentRepo.Approve(encashmentID, reviewer.ID, &reviewerID)
+
 	default:
 		// For other task types, do nothing for now
 		return nil
@@ -413,8 +485,6 @@ func (s *WorkflowService) updateLeaveRequestStatus(leaveRequestID, reviewerEmplo
 		return fmt.Errorf("failed to get leave request: %w", err)
 	}
 
-	year := req.StartDate.Year()
-
 	// Convert string status to LeaveRequestStatus type and update balances
 	var leaveStatus models.LeaveRequestStatus
 	switch status {
@@ -424,8 +494,8 @@ func (s *WorkflowService) updateLeaveRequestStatus(leaveRequestID, reviewerEmplo
 		if err := s.leaveRequestRepo.UpdateStatus(leaveRequestID, leaveStatus, &reviewerEmployeeID, ""); err != nil {
 			return err
 		}
-		// Update balance: move from pending to used
-		return s.leaveBalanceService.ApproveLeave(req.EmployeeID, req.LeaveTypeID, year, req.TotalDays)
+		// Update balance: move each leave year's portion from pending to used
+		return s.leaveBalanceService.ApproveRequest(req)
 
 	case "rejected":
 		leaveStatus = models.LeaveStatusRejected
@@ -434,7 +504,7 @@ func (s *WorkflowService) updateLeaveRequestStatus(leaveRequestID, reviewerEmplo
 			return err
 		}
 		// Update balance: decrement pending (return the days)
-		return s.leaveBalanceService.DecrementPending(req.EmployeeID, req.LeaveTypeID, year, req.TotalDays)
+		return s.leaveBalanceService.ReleaseRequest(req)
 
 	default:
 		return fmt.Errorf("invalid leave request status: %s", status)
@@ -0,0 +1,67 @@
+// Package bankfile writes bulk credit-transfer files for salary payments.
+//
+// Each bank layout is a Format registered under a code. Banks with their own
+// layout add a Format in this package and register it in init().
+package bankfile
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"hr-system/internal/models"
+	"hr-system/pkg/money"
+)
+
+// Header carries the batch-level details written to a payment file
+type Header struct {
+	BatchReference     string
+	PayerName          string
+	PayerBankCode      string
+	PayerAccountNumber string
+	ValueDate          time.Time
+	RecordCount        int
+	ControlTotal       money.Money
+}
+
+// Format renders payment lines in one bank's bulk payment layout
+type Format interface {
+	Code() string
+	Description() string
+	ContentType() string
+	Extension() string
+	// Validate rejects a line the layout cannot represent (e.g. an over-long account number)
+	Validate(line models.PaymentLine) error
+	Write(h Header, lines []models.PaymentLine) ([]byte, error)
+}
+
+var formats = map[string]Format{}
+
+// Register makes a format available by its code. Registering a code twice panics.
+func Register(f Format) {
+	if _, dup := formats[f.Code()]; dup {
+		panic(fmt.Sprintf("bankfile: format %q registered twice", f.Code()))
+	}
+	formats[f.Code()] = f
+}
+
+// Get returns the format registered under code.
+func Get(code string) (Format, bool) {
+	f, ok := formats[code]
+	return f, ok
+}
+
+// List returns all registered formats ordered by code.
+func List() []Format {
+	list := make([]Format, 0, len(formats))
+	for _, f := range formats {
+		list = append(list, f)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Code() < list[j].Code() })
+	return list
+}
+
+func init() {
+	Register(csvFormat{})
+	Register(fixedWidthFormat{})
+}
@@ -0,0 +1,56 @@
+package bankfile
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+
+	"hr-system/internal/models"
+)
+
+// csvFormat is a generic comma-separated layout accepted by most bank portals:
+// a header row, one row per payment and a CONTROL row with the record count and total.
+type csvFormat struct{}
+
+func (csvFormat) Code() string        { return "csv" }
+func (csvFormat) Description() string { return "Generic CSV bulk payment file with control row" }
+func (csvFormat) ContentType() string { return "text/csv" }
+func (csvFormat) Extension() string   { return "csv" }
+
+func (csvFormat) Validate(line models.PaymentLine) error {
+	if line.AccountNumber == "" || line.BankCode == "" {
+		return fmt.Errorf("%s: bank code and account number are required", line.AccountName)
+	}
+	return nil
+}
+
+func (csvFormat) Write(h Header, lines []models.PaymentLine) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.UseCRLF = true
+
+	rows := [][]string{{"batch_reference", "value_date", "sequence", "employee_number", "account_name",
+		"bank_code", "branch_code", "account_number", "amount", "narrative"}}
+	for i, l := range lines {
+		rows = append(rows, []string{
+			h.BatchReference,
+			h.ValueDate.Format("2006-01-02"),
+			strconv.Itoa(i + 1),
+			l.EmployeeNumber,
+			l.AccountName,
+			l.BankCode,
+			l.BranchCode,
+			l.AccountNumber,
+			l.Amount.String(),
+			l.Narrative,
+		})
+	}
+	rows = append(rows, []string{"CONTROL", h.BatchReference, strconv.Itoa(h.RecordCount),
+		h.ControlTotal.String()})
+
+	if err := w.WriteAll(rows); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
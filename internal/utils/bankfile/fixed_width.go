@@ -0,0 +1,73 @@
+package bankfile
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"hr-system/internal/models"
+)
+
+// fixedWidthFormat is a generic bulk-payment layout of 120-character records:
+//
+//	H  batch reference(20) value date YYYYMMDD(8) payer bank(10) payer account(20) payer name(35) filler
+//	D  sequence(6) bank(10) branch(10) account(20) name(35) amount in cents(15) narrative(18) filler
+//	T  record count(6) control total in cents(18) filler
+//
+// Text fields are upper-case, left-aligned and space-padded; numbers are zero-padded.
+type fixedWidthFormat struct{}
+
+const fixedWidthRecordLen = 120
+
+func (fixedWidthFormat) Code() string        { return "fixed_width" }
+func (fixedWidthFormat) Description() string { return "Generic 120-column bulk payment file" }
+func (fixedWidthFormat) ContentType() string { return "text/plain" }
+func (fixedWidthFormat) Extension() string   { return "txt" }
+
+func (fixedWidthFormat) Validate(line models.PaymentLine) error {
+	switch {
+	case line.AccountNumber == "" || line.BankCode == "":
+		return fmt.Errorf("%s: bank code and account number are required", line.AccountName)
+	case len(line.AccountNumber) > 20:
+		return fmt.Errorf("%s: account number longer than 20 characters", line.AccountName)
+	case len(line.BankCode) > 10 || len(line.BranchCode) > 10:
+		return fmt.Errorf("%s: bank or branch code longer than 10 characters", line.AccountName)
+	case line.Amount.Cents() >= 1e15:
+		return fmt.Errorf("%s: amount too large for layout", line.AccountName)
+	}
+	return nil
+}
+
+func (fixedWidthFormat) Write(h Header, lines []models.PaymentLine) ([]byte, error) {
+	var buf bytes.Buffer
+	record := func(fields ...string) {
+		rec := strings.Join(fields, "")
+		buf.WriteString(pad(rec, fixedWidthRecordLen))
+		buf.WriteString("\r\n")
+	}
+
+	record("H", pad(h.BatchReference, 20), h.ValueDate.Format("20060102"),
+		pad(h.PayerBankCode, 10), pad(h.PayerAccountNumber, 20), pad(h.PayerName, 35))
+	for i, l := range lines {
+		record("D", fmt.Sprintf("%06d", i+1), pad(l.BankCode, 10), pad(l.BranchCode, 10),
+			pad(l.AccountNumber, 20), pad(l.AccountName, 35), fmt.Sprintf("%015d", l.Amount.Cents()),
+			pad(l.Narrative, 18))
+	}
+	record("T", fmt.Sprintf("%06d", h.RecordCount), fmt.Sprintf("%018d", h.ControlTotal.Cents()))
+
+	return buf.Bytes(), nil
+}
+
+// pad upper-cases s, strips characters banks commonly reject and fits it to width.
+func pad(s string, width int) string {
+	s = strings.Map(func(r rune) rune {
+		if r < 32 || r > 126 {
+			return ' '
+		}
+		return r
+	}, strings.ToUpper(s))
+	if len(s) > width {
+		return s[:width]
+	}
+	return s + strings.Repeat(" ", width-len(s))
+}
@@ -1,7 +1,10 @@
 package email
 
 import (
+	"crypto/rand"
 	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
 	"fmt"
 	"log"
 	"net/smtp"
@@ -55,6 +58,63 @@ func (s *EmailService) SendEmail(to []string, subject, htmlBody string) error {
 	return smtp.SendMail(addr, auth, s.config.FromEmail, to, []byte(message.String()))
 }
 
+// Attachment is a file sent along with an email
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+// SendEmailWithAttachments sends an HTML email with file attachments as multipart/mixed
+func (s *EmailService) SendEmailWithAttachments(to []string, subject, htmlBody string, attachments []Attachment) error {
+	if len(attachments) == 0 {
+		return s.SendEmail(to, subject, htmlBody)
+	}
+	log.Printf("[EMAIL] Sending email to %v with subject: %s (%d attachments)", to, subject, len(attachments))
+
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Errorf("failed to generate MIME boundary: %w", err)
+	}
+	boundary := "hr-system-" + hex.EncodeToString(b)
+
+	from := fmt.Sprintf("%s <%s>", s.config.FromName, s.config.FromEmail)
+	var message strings.Builder
+	message.WriteString(fmt.Sprintf("From: %s\r\n", from))
+	message.WriteString(fmt.Sprintf("To: %s\r\n", strings.Join(to, ", ")))
+	message.WriteString(fmt.Sprintf("Subject: %s\r\n", subject))
+	message.WriteString("MIME-Version: 1.0\r\n")
+	message.WriteString(fmt.Sprintf("Date: %s\r\n", time.Now().Format(time.RFC1123Z)))
+	message.WriteString(fmt.Sprintf("Content-Type: multipart/mixed; boundary=%q\r\n\r\n", boundary))
+
+	message.WriteString(fmt.Sprintf("--%s\r\n", boundary))
+	message.WriteString("Content-Type: text/html; charset=UTF-8\r\n\r\n")
+	message.WriteString(htmlBody)
+	message.WriteString("\r\n")
+
+	for _, a := range attachments {
+		message.WriteString(fmt.Sprintf("--%s\r\n", boundary))
+		message.WriteString(fmt.Sprintf("Content-Type: %s; name=%q\r\n", a.ContentType, a.Filename))
+		message.WriteString("Content-Transfer-Encoding: base64\r\n")
+		message.WriteString(fmt.Sprintf("Content-Disposition: attachment; filename=%q\r\n\r\n", a.Filename))
+		encoded := base64.StdEncoding.EncodeToString(a.Data)
+		for len(encoded) > 76 {
+			message.WriteString(encoded[:76] + "\r\n")
+			encoded = encoded[76:]
+		}
+		message.WriteString(encoded + "\r\n")
+	}
+	message.WriteString(fmt.Sprintf("--%s--\r\n", boundary))
+
+	auth := s.getAuth()
+	addr := s.config.Host + ":" + s.config.Port
+	if s.config.UseTLS {
+		return s.sendWithTLS(addr, auth, s.config.FromEmail, to, []byte(message.String()))
+	}
+
+	return smtp.SendMail(addr, auth, s.config.FromEmail, to, []byte(message.String()))
+}
+
 // getAuth returns the appropriate SMTP auth based on config
 func (s *EmailService) getAuth() smtp.Auth {
 	if s.config.AuthMethod == "LOGIN" {
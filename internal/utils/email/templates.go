@@ -20,7 +20,7 @@ func loginButton() string {
 }
 
 // LeaveRequestAssignedTemplate generates HTML for leave request assignment notification
-func LeaveRequestAssignedTemplate(requesterName, leaveType string, days int, startDate, endDate string) string {
+func LeaveRequestAssignedTemplate(requesterName, leaveType string, days float64, startDate, endDate string) string {
 	return fmt.Sprintf(`
 <!DOCTYPE html>
 <html lang="en">
@@ -66,7 +66,7 @@ func LeaveRequestAssignedTemplate(requesterName, leaveType string, days int, sta
                       </tr>
                       <tr>
                         <td style="padding:6px 0; font-weight:bold; color:#555;">Duration:</td>
-                        <td style="padding:6px 0; color:#333;">%d days</td>
+                        <td style="padding:6px 0; color:#333;">%g days</td>
                       </tr>
                       <tr>
                         <td style="padding:6px 0; font-weight:bold; color:#555;">Period:</td>
@@ -107,7 +107,7 @@ func LeaveRequestAssignedTemplate(requesterName, leaveType string, days int, sta
 }
 
 // LeaveRequestApprovedTemplate generates HTML for leave request approval notification
-func LeaveRequestApprovedTemplate(employeeName, leaveType string, days int, startDate, endDate, approverName string) string {
+func LeaveRequestApprovedTemplate(employeeName, leaveType string, days float64, startDate, endDate, approverName string) string {
 	return fmt.Sprintf(`
 <!DOCTYPE html>
 <html lang="en">
@@ -149,7 +149,7 @@ func LeaveRequestApprovedTemplate(employeeName, leaveType string, days int, star
                       </tr>
                       <tr>
                         <td style="padding:6px 0; font-weight:bold; color:#555;">Duration:</td>
-                        <td style="padding:6px 0; color:#333;">%d days</td>
+                        <td style="padding:6px 0; color:#333;">%g days</td>
                       </tr>
                       <tr>
                         <td style="padding:6px 0; font-weight:bold; color:#555;">Period:</td>
@@ -194,7 +194,7 @@ func LeaveRequestApprovedTemplate(employeeName, leaveType string, days int, star
 }
 
 // LeaveRequestRejectedTemplate generates HTML for leave request rejection notification
-func LeaveRequestRejectedTemplate(employeeName, leaveType string, days int, startDate, endDate, reviewerName, reason string) string {
+func LeaveRequestRejectedTemplate(employeeName, leaveType string, days float64, startDate, endDate, reviewerName, reason string) string {
 	return fmt.Sprintf(`
 <!DOCTYPE html>
 <html lang="en">
@@ -236,7 +236,7 @@ func LeaveRequestRejectedTemplate(employeeName, leaveType string, days int, star
                       </tr>
                       <tr>
                         <td style="padding:6px 0; font-weight:bold; color:#555;">Duration:</td>
-                        <td style="padding:6px 0; color:#333;">%d days</td>
+                        <td style="padding:6px 0; color:#333;">%g days</td>
                       </tr>
                       <tr>
                         <td style="padding:6px 0; font-weight:bold; color:#555;">Period:</td>
@@ -292,6 +292,413 @@ func formatReason(reason string) string {
 	return ""
 }
 
+// LeaveDocumentReminderTemplate generates HTML reminding an employee to hand in the document their leave needs
+func LeaveDocumentReminderTemplate(firstName, leaveType, startDate, endDate, dueDate string) string {
+	return fmt.Sprintf(`
+<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="UTF-8" />
+<meta name="viewport" content="width=device-width, initial-scale=1.0" />
+<title>Document Needed for Your Leave</title>
+</head>
+<body style="margin:0; padding:0; background-color:#f4f6f9; font-family: Arial, Helvetica, sans-serif;">
+
+  <table width="100%%" cellpadding="0" cellspacing="0" style="background-color:#f4f6f9; padding:40px 0;">
+    <tr>
+      <td align="center">
+
+        <table width="600" cellpadding="0" cellspacing="0" style="background:#ffffff; border-radius:8px; box-shadow:0 4px 12px rgba(0,0,0,0.08); overflow:hidden;">
+
+          <tr>
+            <td style="background:linear-gradient(135deg, #e67e22, #f39c12); padding:30px; text-align:center;">
+              <h1 style="color:#ffffff; margin:0; font-size:22px; letter-spacing:0.5px;">
+                Document Needed for Your Leave
+              </h1>
+            </td>
+          </tr>
+
+          <tr>
+            <td style="padding:40px 30px; color:#333333; font-size:15px; line-height:1.6;">
+
+              <p style="margin-top:0;">Hi %s,</p>
+
+              <p>Welcome back. Your leave needs a supporting document, such as a medical certificate, and we have not received one yet.</p>
+
+              <table width="100%%" cellpadding="0" cellspacing="0" style="margin:25px 0;">
+                <tr>
+                  <td style="background-color:#fff8e1; border:1px solid #ffe0b2; padding:20px; border-radius:6px;">
+                    <table width="100%%" cellpadding="0" cellspacing="0">
+                      <tr>
+                        <td style="padding:6px 0; font-weight:bold; color:#555; width:140px;">Leave Type:</td>
+                        <td style="padding:6px 0; color:#333;">%s</td>
+                      </tr>
+                      <tr>
+                        <td style="padding:6px 0; font-weight:bold; color:#555;">Period:</td>
+                        <td style="padding:6px 0; color:#333;">%s to %s</td>
+                      </tr>
+                      <tr>
+                        <td style="padding:6px 0; font-weight:bold; color:#555;">Due by:</td>
+                        <td style="padding:6px 0; color:#333;">%s</td>
+                      </tr>
+                    </table>
+                  </td>
+                </tr>
+              </table>
+
+              <p>Please log in to the HR System and attach the document to your leave request before it is due.</p>
+
+              %s
+
+              <p style="margin-bottom:0;">
+                Best regards,<br/>
+                <strong>HR System</strong>
+              </p>
+
+            </td>
+          </tr>
+
+          <tr>
+            <td style="background-color:#f8f9fc; padding:20px; text-align:center; font-size:12px; color:#888;">
+              This is an automated message from the HR System. Please do not reply to this email.
+            </td>
+          </tr>
+
+        </table>
+
+      </td>
+    </tr>
+  </table>
+
+</body>
+</html>
+`, firstName, leaveType, startDate, endDate, dueDate, loginButton())
+}
+
+// LeaveDocumentOverdueTemplate generates HTML escalating leave whose required document is overdue to HR
+func LeaveDocumentOverdueTemplate(employeeName, leaveType, startDate, endDate, dueDate string) string {
+	return fmt.Sprintf(`
+<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="UTF-8" />
+<meta name="viewport" content="width=device-width, initial-scale=1.0" />
+<title>Leave Document Overdue</title>
+</head>
+<body style="margin:0; padding:0; background-color:#f4f6f9; font-family: Arial, Helvetica, sans-serif;">
+
+  <table width="100%%" cellpadding="0" cellspacing="0" style="background-color:#f4f6f9; padding:40px 0;">
+    <tr>
+      <td align="center">
+
+        <table width="600" cellpadding="0" cellspacing="0" style="background:#ffffff; border-radius:8px; box-shadow:0 4px 12px rgba(0,0,0,0.08); overflow:hidden;">
+
+          <tr>
+            <td style="background:linear-gradient(135deg, #c0392b, #e74c3c); padding:30px; text-align:center;">
+              <h1 style="color:#ffffff; margin:0; font-size:22px; letter-spacing:0.5px;">
+                Leave Document Overdue
+              </h1>
+            </td>
+          </tr>
+
+          <tr>
+            <td style="padding:40px 30px; color:#333333; font-size:15px; line-height:1.6;">
+
+              <p style="margin-top:0;">Hello,</p>
+
+              <p>The supporting document for the leave below was not handed in by its due date and needs following up.</p>
+
+              <table width="100%%" cellpadding="0" cellspacing="0" style="margin:25px 0;">
+                <tr>
+                  <td style="background-color:#fff8e1; border:1px solid #ffe0b2; padding:20px; border-radius:6px;">
+                    <table width="100%%" cellpadding="0" cellspacing="0">
+                      <tr>
+                        <td style="padding:6px 0; font-weight:bold; color:#555; width:140px;">Employee:</td>
+                        <td style="padding:6px 0; color:#333;">%s</td>
+                      </tr>
+                      <tr>
+                        <td style="padding:6px 0; font-weight:bold; color:#555;">Leave Type:</td>
+                        <td style="padding:6px 0; color:#333;">%s</td>
+                      </tr>
+                      <tr>
+                        <td style="padding:6px 0; font-weight:bold; color:#555;">Period:</td>
+                        <td style="padding:6px 0; color:#333;">%s to %s</td>
+                      </tr>
+                      <tr>
+                        <td style="padding:6px 0; font-weight:bold; color:#555;">Was due by:</td>
+                        <td style="padding:6px 0; color:#333;">%s</td>
+                      </tr>
+                    </table>
+                  </td>
+                </tr>
+              </table>
+
+              <p>Please log in to the HR System to follow up. You can mark the document as received once you have it.</p>
+
+              %s
+
+              <p style="margin-bottom:0;">
+                Best regards,<br/>
+                <strong>HR System</strong>
+              </p>
+
+            </td>
+          </tr>
+
+          <tr>
+            <td style="background-color:#f8f9fc; padding:20px; text-align:center; font-size:12px; color:#888;">
+              This is an automated message from the HR System. Please do not reply to this email.
+            </td>
+          </tr>
+
+        </table>
+
+      </td>
+    </tr>
+  </table>
+
+</body>
+</html>
+`, employeeName, leaveType, startDate, endDate, dueDate, loginButton())
+}
+
+// LeaveConvertedToUnpaidTemplate generates HTML telling an employee their leave became unpaid leave for lack of a document
+func LeaveConvertedToUnpaidTemplate(firstName, leaveType string, days float64, startDate, endDate string) string {
+	return fmt.Sprintf(`
+<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="UTF-8" />
+<meta name="viewport" content="width=device-width, initial-scale=1.0" />
+<title>Leave Changed to Unpaid Leave</title>
+</head>
+<body style="margin:0; padding:0; background-color:#f4f6f9; font-family: Arial, Helvetica, sans-serif;">
+
+  <table width="100%%" cellpadding="0" cellspacing="0" style="background-color:#f4f6f9; padding:40px 0;">
+    <tr>
+      <td align="center">
+
+        <table width="600" cellpadding="0" cellspacing="0" style="background:#ffffff; border-radius:8px; box-shadow:0 4px 12px rgba(0,0,0,0.08); overflow:hidden;">
+
+          <tr>
+            <td style="background:linear-gradient(135deg, #c0392b, #e74c3c); padding:30px; text-align:center;">
+              <h1 style="color:#ffffff; margin:0; font-size:22px; letter-spacing:0.5px;">
+                Leave Changed to Unpaid Leave
+              </h1>
+            </td>
+          </tr>
+
+          <tr>
+            <td style="padding:40px 30px; color:#333333; font-size:15px; line-height:1.6;">
+
+              <p style="margin-top:0;">Hi %s,</p>
+
+              <p>The supporting document for your leave was not handed in by its due date, so the leave has been recorded as unpaid leave. The days have been returned to your %s balance.</p>
+
+              <table width="100%%" cellpadding="0" cellspacing="0" style="margin:25px 0;">
+                <tr>
+                  <td style="background-color:#fff8e1; border:1px solid #ffe0b2; padding:20px; border-radius:6px;">
+                    <table width="100%%" cellpadding="0" cellspacing="0">
+                      <tr>
+                        <td style="padding:6px 0; font-weight:bold; color:#555; width:140px;">Duration:</td>
+                        <td style="padding:6px 0; color:#333;">%g days</td>
+                      </tr>
+                      <tr>
+                        <td style="padding:6px 0; font-weight:bold; color:#555;">Period:</td>
+                        <td style="padding:6px 0; color:#333;">%s to %s</td>
+                      </tr>
+                    </table>
+                  </td>
+                </tr>
+              </table>
+
+              <p>If you have the document, please contact your HR department.</p>
+
+              %s
+
+              <p style="margin-bottom:0;">
+                Best regards,<br/>
+                <strong>HR System</strong>
+              </p>
+
+            </td>
+          </tr>
+
+          <tr>
+            <td style="background-color:#f8f9fc; padding:20px; text-align:center; font-size:12px; color:#888;">
+              This is an automated message from the HR System. Please do not reply to this email.
+            </td>
+          </tr>
+
+        </table>
+
+      </td>
+    </tr>
+  </table>
+
+</body>
+</html>
+`, firstName, leaveType, days, startDate, endDate, loginButton())
+}
+
+// CarryForwardExpiryReminderTemplate generates HTML reminding an employee to use carried-forward leave before it expires
+func CarryForwardExpiryReminderTemplate(firstName, leaveType string, days float64, expiresOn string) string {
+	return fmt.Sprintf(`
+<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="UTF-8" />
+<meta name="viewport" content="width=device-width, initial-scale=1.0" />
+<title>Carried-Forward Leave Expiring Soon</title>
+</head>
+<body style="margin:0; padding:0; background-color:#f4f6f9; font-family: Arial, Helvetica, sans-serif;">
+
+  <table width="100%%" cellpadding="0" cellspacing="0" style="background-color:#f4f6f9; padding:40px 0;">
+    <tr>
+      <td align="center">
+
+        <table width="600" cellpadding="0" cellspacing="0" style="background:#ffffff; border-radius:8px; box-shadow:0 4px 12px rgba(0,0,0,0.08); overflow:hidden;">
+
+          <tr>
+            <td style="background:linear-gradient(135deg, #e67e22, #f39c12); padding:30px; text-align:center;">
+              <h1 style="color:#ffffff; margin:0; font-size:22px; letter-spacing:0.5px;">
+                Carried-Forward Leave Expiring Soon
+              </h1>
+            </td>
+          </tr>
+
+          <tr>
+            <td style="padding:40px 30px; color:#333333; font-size:15px; line-height:1.6;">
+
+              <p style="margin-top:0;">Hi %s,</p>
+
+              <p>Some of the %s you carried forward from last year has not been used yet. Carried-forward days must be taken by their expiry date or they will be lost.</p>
+
+              <table width="100%%" cellpadding="0" cellspacing="0" style="margin:25px 0;">
+                <tr>
+                  <td style="background-color:#fff8e1; border:1px solid #ffe0b2; padding:20px; border-radius:6px;">
+                    <table width="100%%" cellpadding="0" cellspacing="0">
+                      <tr>
+                        <td style="padding:6px 0; font-weight:bold; color:#555; width:140px;">Days at risk:</td>
+                        <td style="padding:6px 0; color:#333;">%g days</td>
+                      </tr>
+                      <tr>
+                        <td style="padding:6px 0; font-weight:bold; color:#555;">Use by:</td>
+                        <td style="padding:6px 0; color:#333;">%s</td>
+                      </tr>
+                    </table>
+                  </td>
+                </tr>
+              </table>
+
+              <p>Leave you have already booked before the expiry date has been taken into account. Book the remaining days before they expire to keep them.</p>
+
+              %s
+
+              <p style="margin-bottom:0;">
+                Best regards,<br/>
+                <strong>HR System</strong>
+              </p>
+
+            </td>
+          </tr>
+
+          <tr>
+            <td style="background-color:#f8f9fc; padding:20px; text-align:center; font-size:12px; color:#888;">
+              This is an automated message from the HR System. Please do not reply to this email.
+            </td>
+          </tr>
+
+        </table>
+
+      </td>
+    </tr>
+  </table>
+
+</body>
+</html>
+`, firstName, leaveType, days, expiresOn, loginButton())
+}
+
+// CarryForwardForfeitedTemplate generates HTML telling an employee their unused carried-forward leave has expired
+func CarryForwardForfeitedTemplate(firstName, leaveType string, days float64, expiredOn string) string {
+	return fmt.Sprintf(`
+<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="UTF-8" />
+<meta name="viewport" content="width=device-width, initial-scale=1.0" />
+<title>Carried-Forward Leave Expired</title>
+</head>
+<body style="margin:0; padding:0; background-color:#f4f6f9; font-family: Arial, Helvetica, sans-serif;">
+
+  <table width="100%%" cellpadding="0" cellspacing="0" style="background-color:#f4f6f9; padding:40px 0;">
+    <tr>
+      <td align="center">
+
+        <table width="600" cellpadding="0" cellspacing="0" style="background:#ffffff; border-radius:8px; box-shadow:0 4px 12px rgba(0,0,0,0.08); overflow:hidden;">
+
+          <tr>
+            <td style="background:linear-gradient(135deg, #c0392b, #e74c3c); padding:30px; text-align:center;">
+              <h1 style="color:#ffffff; margin:0; font-size:22px; letter-spacing:0.5px;">
+                Carried-Forward Leave Expired
+              </h1>
+            </td>
+          </tr>
+
+          <tr>
+            <td style="padding:40px 30px; color:#333333; font-size:15px; line-height:1.6;">
+
+              <p style="margin-top:0;">Hi %s,</p>
+
+              <p>The %s you carried forward from last year expired before it was all used, so the unused days have been removed from your balance.</p>
+
+              <table width="100%%" cellpadding="0" cellspacing="0" style="margin:25px 0;">
+                <tr>
+                  <td style="background-color:#fff8e1; border:1px solid #ffe0b2; padding:20px; border-radius:6px;">
+                    <table width="100%%" cellpadding="0" cellspacing="0">
+                      <tr>
+                        <td style="padding:6px 0; font-weight:bold; color:#555; width:140px;">Days forfeited:</td>
+                        <td style="padding:6px 0; color:#333;">%g days</td>
+                      </tr>
+                      <tr>
+                        <td style="padding:6px 0; font-weight:bold; color:#555;">Expired on:</td>
+                        <td style="padding:6px 0; color:#333;">%s</td>
+                      </tr>
+                    </table>
+                  </td>
+                </tr>
+              </table>
+
+              <p>If you think this is wrong, please contact your HR department.</p>
+
+              %s
+
+              <p style="margin-bottom:0;">
+                Best regards,<br/>
+                <strong>HR System</strong>
+              </p>
+
+            </td>
+          </tr>
+
+          <tr>
+            <td style="background-color:#f8f9fc; padding:20px; text-align:center; font-size:12px; color:#888;">
+              This is an automated message from the HR System. Please do not reply to this email.
+            </td>
+          </tr>
+
+        </table>
+
+      </td>
+    </tr>
+  </table>
+
+</body>
+</html>
+`, firstName, leaveType, days, expiredOn, loginButton())
+}
+
 // GenericTaskAssignedTemplate generates HTML for generic task assignment notification
 func GenericTaskAssignedTemplate(recipientName, taskName, taskDescription string) string {
 	return fmt.Sprintf(`
@@ -543,8 +950,22 @@ func WelcomeEmployeeTemplate(firstName, lastName, userEmail, password string) st
 `, firstName, lastName, userEmail, password, loginButton())
 }
 
-// PayslipReadyTemplate generates HTML for payslip ready notification
-func PayslipReadyTemplate(firstName, period string) string {
+// PayslipReadyTemplate generates HTML for payslip ready notification.
+// attached says whether the PDF is attached; without it the employee is sent to the portal
+// to download it. passwordHint describes the attached PDF's password; pass "" if it is
+// unprotected.
+func PayslipReadyTemplate(firstName, period string, attached bool, passwordHint string) string {
+	pdfNote := `<p>A PDF copy is attached to this email. You can also log in to the HR portal to view your full payslip breakdown.</p>`
+	if passwordHint != "" {
+		pdfNote += fmt.Sprintf(`
+
+              <p>The attachment is password protected. Open it with %s.</p>`, passwordHint)
+	}
+	if !attached {
+		pdfNote = `<p>Log in to the HR portal to view your full payslip breakdown and download the PDF.</p>
+
+              ` + loginButton()
+	}
 	return fmt.Sprintf(`
 <!DOCTYPE html>
 <html lang="en">
@@ -555,7 +976,7 @@ func PayslipReadyTemplate(firstName, period string) string {
 </head>
 <body style="margin:0; padding:0; background-color:#f4f6f9; font-family: Arial, Helvetica, sans-serif;">
 
-  <table width="100%%%%" cellpadding="0" cellspacing="0" style="background-color:#f4f6f9; padding:40px 0;">
+  <table width="100%%" cellpadding="0" cellspacing="0" style="background-color:#f4f6f9; padding:40px 0;">
     <tr>
       <td align="center">
 
@@ -576,7 +997,7 @@ func PayslipReadyTemplate(firstName, period string) string {
 
               <p>Your payslip for <strong>%s</strong> has been processed and is now available.</p>
 
-              <p>Log in to the HR portal to view your full payslip breakdown.</p>
+              %s
 
               <p style="margin-bottom:0;">
                 Best regards,<br/>
@@ -600,5 +1021,5 @@ func PayslipReadyTemplate(firstName, period string) string {
 
 </body>
 </html>
-`, firstName, period)
+`, firstName, period, pdfNote)
 }
@@ -0,0 +1,103 @@
+// Package ical writes iCalendar (RFC 5545) subscription feeds of all-day events.
+package ical
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Event statuses
+const (
+	StatusConfirmed = "CONFIRMED"
+	StatusTentative = "TENTATIVE"
+	StatusCancelled = "CANCELLED"
+)
+
+// Event is an all-day event. Calendar apps match updates to earlier copies by UID, so it
+// must stay the same for the life of the thing the event shows; Sequence must grow with
+// every change.
+type Event struct {
+	UID          string
+	Summary      string
+	Description  string
+	Start        time.Time // first day
+	End          time.Time // last day, inclusive
+	Status       string
+	Sequence     int
+	LastModified time.Time
+	Yearly       bool // repeats every year on the same date
+}
+
+// Calendar is a named feed of events
+type Calendar struct {
+	ProductID string // e.g. "-//Acme Ltd//HR System//EN"
+	Name      string
+	Events    []Event
+}
+
+// Render writes the calendar as an iCalendar stream
+func Render(c Calendar) []byte {
+	var b bytes.Buffer
+	w := func(name, value string) { writeLine(&b, name+":"+value) }
+
+	w("BEGIN", "VCALENDAR")
+	w("VERSION", "2.0")
+	w("PRODID", c.ProductID)
+	w("CALSCALE", "GREGORIAN")
+	w("METHOD", "PUBLISH")
+	w("X-WR-CALNAME", escape(c.Name))
+	w("REFRESH-INTERVAL;VALUE=DURATION", "PT1H")
+	w("X-PUBLISHED-TTL", "PT1H")
+
+	stamp := time.Now().UTC().Format("20060102T150405Z")
+	for _, e := range c.Events {
+		w("BEGIN", "VEVENT")
+		w("UID", e.UID)
+		w("DTSTAMP", stamp)
+		w("DTSTART;VALUE=DATE", e.Start.Format("20060102"))
+		w("DTEND;VALUE=DATE", e.End.AddDate(0, 0, 1).Format("20060102"))
+		if e.Yearly {
+			w("RRULE", "FREQ=YEARLY")
+		}
+		w("SUMMARY", escape(e.Summary))
+		if e.Description != "" {
+			w("DESCRIPTION", escape(e.Description))
+		}
+		if e.Status != "" {
+			w("STATUS", e.Status)
+		}
+		w("SEQUENCE", strconv.Itoa(e.Sequence))
+		if !e.LastModified.IsZero() {
+			w("LAST-MODIFIED", e.LastModified.UTC().Format("20060102T150405Z"))
+		}
+		w("END", "VEVENT")
+	}
+	w("END", "VCALENDAR")
+	return b.Bytes()
+}
+
+var escaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+func escape(s string) string {
+	return escaper.Replace(s)
+}
+
+// writeLine ends a content line with CRLF, folding it so no line is longer than 75 octets
+// and no UTF-8 character is split
+func writeLine(b *bytes.Buffer, line string) {
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		limit = 74 // the leading space of a continuation line counts
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
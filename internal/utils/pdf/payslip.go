@@ -0,0 +1,303 @@
+package pdf
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"hr-system/internal/config"
+	"hr-system/internal/models"
+	pkgmoney "hr-system/pkg/money"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// PayslipDocument is everything printed on a payslip PDF
+type PayslipDocument struct {
+	Company       config.CompanyConfig
+	Payslip       *models.Payslip
+	Employee      *models.Employee
+	YTD           *models.PayslipYTD
+	LeaveBalances []models.LeaveBalance
+	// Password, if set, is required to open the PDF
+	Password string
+}
+
+var (
+	brandGreen = [3]int{30, 109, 58}
+	rowShade   = [3]int{244, 246, 249}
+)
+
+// RenderPayslip draws a one-page A4 payslip and returns the PDF bytes.
+func RenderPayslip(doc PayslipDocument) ([]byte, error) {
+	p := doc.Payslip
+	if p == nil || doc.Employee == nil {
+		return nil, fmt.Errorf("payslip and employee are required")
+	}
+
+	f := fpdf.New("P", "mm", "A4", "")
+	if doc.Password != "" {
+		// Random owner password so recipients cannot lift the restrictions
+		owner := make([]byte, 16)
+		if _, err := rand.Read(owner); err != nil {
+			return nil, err
+		}
+		f.SetProtection(fpdf.CnProtectPrint, doc.Password, hex.EncodeToString(owner))
+	}
+	tr := f.UnicodeTranslatorFromDescriptor("")
+	f.SetTitle("Payslip "+payPeriod(p), true)
+	f.SetAuthor(doc.Company.Name, true)
+	f.SetMargins(15, 15, 15)
+	f.AddPage()
+
+	// Header band with optional logo
+	f.SetFillColor(brandGreen[0], brandGreen[1], brandGreen[2])
+	f.Rect(0, 0, 210, 32, "F")
+	textX := 15.0
+	if doc.Company.LogoPath != "" {
+		if _, err := os.Stat(doc.Company.LogoPath); err == nil {
+			imgType := strings.TrimPrefix(strings.ToUpper(filepath.Ext(doc.Company.LogoPath)), ".")
+			f.ImageOptions(doc.Company.LogoPath, 15, 6, 0, 20, false, fpdf.ImageOptions{ImageType: imgType, ReadDpi: true}, 0, "")
+			textX = 45
+		}
+	}
+	f.SetTextColor(255, 255, 255)
+	f.SetXY(textX, 8)
+	f.SetFont("Helvetica", "B", 16)
+	f.CellFormat(0, 8, tr(doc.Company.Name), "", 2, "L", false, 0, "")
+	f.SetFont("Helvetica", "", 9)
+	if doc.Company.Address != "" {
+		f.CellFormat(0, 5, tr(doc.Company.Address), "", 2, "L", false, 0, "")
+	}
+	f.SetXY(15, 8)
+	f.SetFont("Helvetica", "B", 12)
+	f.CellFormat(0, 8, "PAYSLIP", "", 2, "R", false, 0, "")
+	f.SetFont("Helvetica", "", 9)
+	f.CellFormat(0, 5, tr(payslipTitle(p)), "", 0, "R", false, 0, "")
+
+	// Employee details
+	f.SetTextColor(33, 33, 33)
+	f.SetY(40)
+	emp := doc.Employee
+	details := [][2]string{
+		{"Employee", emp.FullName()},
+		{"Employee No.", emp.EmployeeNumber},
+		{"Position", p.PositionName},
+		{"Pay Period", payPeriod(p)},
+		{"Issued", p.CreatedAt.Format("02 Jan 2006")},
+	}
+	if p.Status != models.PayslipStatusFinal {
+		details = append(details, [2]string{"Status", strings.ToUpper(string(p.Status))})
+	}
+	for _, d := range details {
+		f.SetFont("Helvetica", "B", 9)
+		f.CellFormat(35, 6, d[0], "", 0, "L", false, 0, "")
+		f.SetFont("Helvetica", "", 9)
+		f.CellFormat(0, 6, tr(d[1]), "", 1, "L", false, 0, "")
+	}
+	f.Ln(4)
+
+	// Earnings and deductions side by side
+	earnings := [][2]string{
+		{"Basic Salary", money(p.BaseSalary)},
+		{"Housing Allowance", money(p.HousingAllowance)},
+		{"Transport Allowance", money(p.TransportAllowance)},
+		{"Medical Allowance", money(p.MedicalAllowance)},
+	}
+	if p.OvertimePay != 0 {
+		earnings = append(earnings, [2]string{fmt.Sprintf("Overtime (%.2f hrs)", p.OvertimeHours), money(p.OvertimePay)})
+	}
+	if p.BonusPay != 0 {
+		earnings = append(earnings, [2]string{"Bonus", money(p.BonusPay)})
+	}
+	if p.LeaveDays != 0 {
+		earnings = append(earnings, [2]string{"Leave Days", money(p.LeaveDays)})
+	}
+	if p.EncashedLeave != 0 {
+		earnings = append(earnings, [2]string{"Encashed Leave", money(p.EncashedLeave)})
+	}
+	if len(p.Arrears) > 0 {
+		for _, l := range p.Arrears {
+			earnings = append(earnings, [2]string{"Arrears " + l.Period().Label(), money(l.GrossDifference)})
+		}
+	} else if p.ArrearsPay != 0 {
+		earnings = append(earnings, [2]string{"Arrears", money(p.ArrearsPay)})
+	}
+	if p.LeaveEncashment != 0 {
+		earnings = append(earnings, [2]string{"Leave Encashment", money(p.LeaveEncashment)})
+	}
+	if p.NoticePay != 0 {
+		earnings = append(earnings, [2]string{"Pay in Lieu of Notice", money(p.NoticePay)})
+	}
+	if p.Gratuity != 0 {
+		earnings = append(earnings, [2]string{"Gratuity", money(p.Gratuity)})
+	}
+	deductions := [][2]string{
+		{"PAYE Income Tax", money(p.IncomeTax)},
+	}
+	if len(p.PayInputs) > 0 {
+		for _, in := range p.PayInputs {
+			line := [2]string{payInputLabel(in), money(in.Amount)}
+			if in.InputType == models.PayInputEarning {
+				earnings = append(earnings, line)
+			} else {
+				deductions = append(deductions, line)
+			}
+		}
+	} else {
+		// Supplementary payslips carry only the differences, not the inputs themselves
+		if p.AdditionalEarnings != 0 {
+			earnings = append(earnings, [2]string{"Additional Earnings", money(p.AdditionalEarnings)})
+		}
+		if p.Reimbursements != 0 {
+			earnings = append(earnings, [2]string{"Reimbursements", money(p.Reimbursements)})
+		}
+		if p.PreTaxDeductions != 0 {
+			deductions = append(deductions, [2]string{"Pre-tax Deductions", money(p.PreTaxDeductions)})
+		}
+		if p.OtherDeductions != 0 {
+			deductions = append(deductions, [2]string{"Other Deductions", money(p.OtherDeductions)})
+		}
+	}
+	if p.LoanDeduction != 0 {
+		deductions = append(deductions, [2]string{"Loan Repayment", money(p.LoanDeduction)})
+	}
+
+	top := f.GetY()
+	table(f, 15, top, 88, "Earnings", earnings, "Total Earnings", money(p.GrossSalary+p.LeaveDays+p.Reimbursements))
+	leftBottom := f.GetY()
+	table(f, 107, top, 88, "Deductions", deductions, "Total Deductions", money(p.IncomeTax+p.PreTaxDeductions+p.OtherDeductions+p.LoanDeduction))
+	f.SetY(max(leftBottom, f.GetY()) + 4)
+
+	// Net pay band
+	f.SetFillColor(brandGreen[0], brandGreen[1], brandGreen[2])
+	f.SetTextColor(255, 255, 255)
+	f.SetFont("Helvetica", "B", 12)
+	f.CellFormat(120, 10, "  NET PAY", "", 0, "L", true, 0, "")
+	f.CellFormat(60, 10, money(p.NetSalary)+"  ", "", 1, "R", true, 0, "")
+	f.SetTextColor(33, 33, 33)
+	f.Ln(6)
+
+	// Year to date
+	if doc.YTD != nil {
+		ytd := [][2]string{
+			{"Gross Pay", money(doc.YTD.GrossSalary)},
+			{"Overtime", money(doc.YTD.OvertimePay)},
+			{"Bonus", money(doc.YTD.BonusPay)},
+			{"Income Tax", money(doc.YTD.IncomeTax)},
+			{"Net Pay", money(doc.YTD.NetSalary)},
+		}
+		table(f, 15, f.GetY(), 88, fmt.Sprintf("Year to Date (%d)", doc.YTD.Year), ytd, "", "")
+	}
+
+	// Leave balances
+	if len(doc.LeaveBalances) > 0 {
+		f.Ln(4)
+		heading(f, 15, f.GetY(), 180, "Leave Balances")
+		f.SetFont("Helvetica", "B", 8)
+		for _, h := range []string{"Leave Type", "Entitled", "Carried Fwd", "Earned", "Used", "Pending", "Balance"} {
+			w := 20.0
+			if h == "Leave Type" {
+				w = 60
+			}
+			f.CellFormat(w, 6, h, "B", 0, "L", false, 0, "")
+		}
+		f.Ln(-1)
+		f.SetFont("Helvetica", "", 8)
+		for i, b := range doc.LeaveBalances {
+			name := ""
+			if b.LeaveType != nil {
+				name = b.LeaveType.Name
+			}
+			fill := i%2 == 1
+			f.SetFillColor(rowShade[0], rowShade[1], rowShade[2])
+			f.CellFormat(60, 6, tr(name), "", 0, "L", fill, 0, "")
+			// Carried days that expired unused are no longer shown as carried forward
+			carried := models.RoundLeaveDays(b.CarriedForward - b.Forfeited)
+			for _, v := range []float64{b.TotalEntitled, carried, b.EarnedLeaveDays, b.Used, b.Pending, b.Balance} {
+				f.CellFormat(20, 6, fmt.Sprintf("%g", v), "", 0, "L", fill, 0, "")
+			}
+			f.Ln(-1)
+		}
+	}
+
+	// Footer
+	f.SetY(-20)
+	f.SetFont("Helvetica", "I", 7)
+	f.SetTextColor(136, 136, 136)
+	f.CellFormat(0, 4, tr(fmt.Sprintf("Generated by %s on %s. This payslip is confidential.", doc.Company.Name, time.Now().Format("02 Jan 2006 15:04"))), "", 1, "C", false, 0, "")
+	f.CellFormat(0, 4, fmt.Sprintf("Reference: %s", p.ID), "", 0, "C", false, 0, "")
+
+	var buf bytes.Buffer
+	if err := f.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// table draws a titled two-column amount table at (x, y) with an optional total row.
+func table(f *fpdf.Fpdf, x, y, w float64, title string, rows [][2]string, totalLabel, total string) {
+	tr := f.UnicodeTranslatorFromDescriptor("")
+	heading(f, x, y, w, title)
+	f.SetFont("Helvetica", "", 9)
+	f.SetFillColor(rowShade[0], rowShade[1], rowShade[2])
+	for i, r := range rows {
+		f.SetX(x)
+		f.CellFormat(w-30, 6, tr(r[0]), "", 0, "L", i%2 == 1, 0, "")
+		f.CellFormat(30, 6, r[1], "", 1, "R", i%2 == 1, 0, "")
+	}
+	if totalLabel != "" {
+		f.SetX(x)
+		f.SetFont("Helvetica", "B", 9)
+		f.CellFormat(w-30, 7, totalLabel, "T", 0, "L", false, 0, "")
+		f.CellFormat(30, 7, total, "T", 1, "R", false, 0, "")
+	}
+}
+
+func heading(f *fpdf.Fpdf, x, y, w float64, title string) {
+	f.SetXY(x, y)
+	f.SetFont("Helvetica", "B", 10)
+	f.SetTextColor(brandGreen[0], brandGreen[1], brandGreen[2])
+	f.CellFormat(w, 7, title, "B", 1, "L", false, 0, "")
+	f.SetTextColor(33, 33, 33)
+}
+
+func payslipTitle(p *models.Payslip) string {
+	switch p.PayslipType {
+	case models.PayslipTypeSupplementary:
+		return "Supplementary payslip"
+	case models.PayslipTypeBonus:
+		return "Bonus payslip"
+	}
+	return payPeriod(p)
+}
+
+// payPeriod describes the payslip's pay period, e.g. "March 2026" or "02 Mar - 08 Mar 2026"
+func payPeriod(p *models.Payslip) string {
+	if p.PeriodStart.IsZero() {
+		return fmt.Sprintf("%s %d", time.Month(p.Month), p.Year)
+	}
+	return models.PayPeriod{Start: p.PeriodStart, End: p.PeriodEnd}.Label()
+}
+
+// payInputLabel names a one-off earning or deduction by its description, falling back
+// to the category name.
+func payInputLabel(in models.PayInput) string {
+	if in.Description != "" {
+		return in.Description
+	}
+	if c, ok := models.FindPayInputCategory(in.Category); ok {
+		return c.Name
+	}
+	return in.Category
+}
+
+// money formats an amount with thousands separators, e.g. 12,345.60
+func money(v pkgmoney.Money) string {
+	return v.Format()
+}
@@ -0,0 +1,111 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"hr-system/internal/models"
+	pkgmoney "hr-system/pkg/money"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// RenderTaxCertificate draws an employee's annual tax certificate as a landscape A4 PDF.
+func RenderTaxCertificate(c *models.TaxCertificate, employerAddress string) ([]byte, error) {
+	if c == nil {
+		return nil, fmt.Errorf("certificate is required")
+	}
+
+	f := fpdf.New("L", "mm", "A4", "")
+	tr := f.UnicodeTranslatorFromDescriptor("")
+	f.SetTitle(fmt.Sprintf("Tax Deduction Certificate %d", c.Year), true)
+	f.SetAuthor(c.EmployerName, true)
+	f.SetMargins(12, 12, 12)
+	f.AddPage()
+
+	// Header band
+	f.SetFillColor(brandGreen[0], brandGreen[1], brandGreen[2])
+	f.Rect(0, 0, 297, 26, "F")
+	f.SetTextColor(255, 255, 255)
+	f.SetXY(12, 6)
+	f.SetFont("Helvetica", "B", 15)
+	f.CellFormat(0, 8, tr(c.EmployerName), "", 2, "L", false, 0, "")
+	f.SetFont("Helvetica", "", 9)
+	if employerAddress != "" {
+		f.CellFormat(0, 5, tr(employerAddress), "", 0, "L", false, 0, "")
+	}
+	f.SetXY(12, 6)
+	f.SetFont("Helvetica", "B", 12)
+	f.CellFormat(0, 8, "TAX DEDUCTION CERTIFICATE", "", 2, "R", false, 0, "")
+	f.SetFont("Helvetica", "", 9)
+	f.CellFormat(0, 5, fmt.Sprintf("Year ended 31 December %d", c.Year), "", 0, "R", false, 0, "")
+
+	// Employee details
+	f.SetTextColor(33, 33, 33)
+	f.SetY(32)
+	for _, d := range [][2]string{
+		{"Employee", c.EmployeeName},
+		{"Employee No.", c.EmployeeNumber},
+		{"National ID", c.NationalID},
+		{"Position", c.PositionName},
+	} {
+		f.SetFont("Helvetica", "B", 9)
+		f.CellFormat(30, 5.5, d[0], "", 0, "L", false, 0, "")
+		f.SetFont("Helvetica", "", 9)
+		f.CellFormat(0, 5.5, tr(d[1]), "", 1, "L", false, 0, "")
+	}
+	f.Ln(3)
+
+	// Monthly table
+	heading(f, 12, f.GetY(), 273, "Monthly Pay and Tax Deducted")
+	cols := []string{"Month", "Basic Salary", "Allowances", "Overtime", "Bonus", "Leave Pay", "Gross Pay", "PAYE", "Net Pay"}
+	widths := []float64{33, 30, 30, 30, 30, 30, 30, 30, 30}
+	f.SetFont("Helvetica", "B", 8)
+	for i, h := range cols {
+		align := "R"
+		if i == 0 {
+			align = "L"
+		}
+		f.CellFormat(widths[i], 6, h, "B", 0, align, false, 0, "")
+	}
+	f.Ln(-1)
+
+	row := func(label string, a models.TaxAmounts, fill bool, style string, border string) {
+		f.SetFont("Helvetica", style, 8)
+		f.SetFillColor(rowShade[0], rowShade[1], rowShade[2])
+		values := []pkgmoney.Money{a.BasicSalary, a.Allowances, a.OvertimePay, a.BonusPay, a.LeavePay, a.GrossSalary, a.IncomeTax, a.NetSalary}
+		f.CellFormat(widths[0], 6, label, border, 0, "L", fill, 0, "")
+		for i, v := range values {
+			f.CellFormat(widths[i+1], 6, money(v), border, 0, "R", fill, 0, "")
+		}
+		f.Ln(-1)
+	}
+	paid := map[int]models.TaxAmounts{}
+	for _, m := range c.Months {
+		paid[m.Month] = m.TaxAmounts
+	}
+	for m := 1; m <= 12; m++ {
+		row(time.Month(m).String(), paid[m], m%2 == 0, "", "")
+	}
+	row("Total", c.Totals, false, "B", "T")
+
+	// Declaration
+	f.Ln(6)
+	f.SetFont("Helvetica", "", 9)
+	f.MultiCell(0, 5, tr(fmt.Sprintf(
+		"We certify that the above is a true statement of the emoluments paid to %s and the PAYE tax deducted "+
+			"during the year %d. Total tax deducted: %s.", c.EmployeeName, c.Year, money(c.Totals.IncomeTax))), "", "L", false)
+
+	// Footer
+	f.SetY(-16)
+	f.SetFont("Helvetica", "I", 7)
+	f.SetTextColor(136, 136, 136)
+	f.CellFormat(0, 4, tr(fmt.Sprintf("Generated by %s on %s.", c.EmployerName, c.GeneratedAt.Format("02 Jan 2006 15:04"))), "", 0, "C", false, 0, "")
+
+	var buf bytes.Buffer
+	if err := f.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
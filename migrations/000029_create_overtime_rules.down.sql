@@ -0,0 +1,10 @@
+ALTER TABLE payslips
+    DROP COLUMN IF EXISTS overtime_hours,
+    DROP COLUMN IF EXISTS overtime_pay;
+
+ALTER TABLE attendance
+    DROP COLUMN IF EXISTS overtime_approved,
+    DROP COLUMN IF EXISTS overtime_approved_by,
+    DROP COLUMN IF EXISTS overtime_approved_at;
+
+DROP TABLE IF EXISTS overtime_rules;
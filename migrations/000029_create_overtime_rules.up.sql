@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS overtime_rules (
+    id                      UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    weekday_multiplier      NUMERIC(4,2) NOT NULL DEFAULT 1.50,
+    weekend_multiplier      NUMERIC(4,2) NOT NULL DEFAULT 2.00,
+    holiday_multiplier      NUMERIC(4,2) NOT NULL DEFAULT 2.50,
+    standard_monthly_hours  NUMERIC(6,2) NOT NULL DEFAULT 176 CHECK (standard_monthly_hours > 0),
+    max_monthly_hours       NUMERIC(6,2) NOT NULL DEFAULT 0 CHECK (max_monthly_hours >= 0),
+    created_at              TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at              TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Only one global overtime rule set
+CREATE UNIQUE INDEX idx_overtime_rules_global ON overtime_rules((1));
+
+INSERT INTO overtime_rules (weekday_multiplier, weekend_multiplier, holiday_multiplier, standard_monthly_hours, max_monthly_hours)
+VALUES (1.50, 2.00, 2.50, 176, 40);
+
+-- Overtime only counts towards pay once a manager has approved it
+ALTER TABLE attendance
+    ADD COLUMN overtime_approved    BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN overtime_approved_by UUID NULL REFERENCES employees(id) ON DELETE SET NULL,
+    ADD COLUMN overtime_approved_at TIMESTAMPTZ NULL;
+
+ALTER TABLE payslips
+    ADD COLUMN overtime_hours NUMERIC(6,2) NOT NULL DEFAULT 0,
+    ADD COLUMN overtime_pay   NUMERIC(15,2) NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN overtime_rules.standard_monthly_hours IS 'Divisor used to derive the hourly rate from monthly base salary';
+COMMENT ON COLUMN overtime_rules.max_monthly_hours IS 'Maximum paid overtime hours per month (0 = no cap)';
@@ -0,0 +1,12 @@
+DELETE FROM payslips WHERE status = 'draft';
+DROP INDEX IF EXISTS idx_payslips_status;
+ALTER TABLE payslips DROP COLUMN IF EXISTS status;
+
+ALTER TABLE payrolls
+    DROP COLUMN IF EXISTS approved_by,
+    DROP COLUMN IF EXISTS approved_at;
+
+UPDATE payrolls SET status = 'OPEN' WHERE status = 'DRAFT';
+ALTER TABLE payrolls DROP CONSTRAINT IF EXISTS payrolls_status_check;
+ALTER TABLE payrolls
+    ADD CONSTRAINT payrolls_status_check CHECK (status IN ('OPEN', 'PROCESSING', 'COMPLETED', 'CANCELLED'));
@@ -0,0 +1,14 @@
+-- Payroll processing now produces a DRAFT that must be approved before it is COMPLETED
+ALTER TABLE payrolls DROP CONSTRAINT IF EXISTS payrolls_status_check;
+ALTER TABLE payrolls
+    ADD CONSTRAINT payrolls_status_check CHECK (status IN ('OPEN', 'PROCESSING', 'DRAFT', 'COMPLETED', 'CANCELLED'));
+
+ALTER TABLE payrolls
+    ADD COLUMN approved_by UUID NULL REFERENCES users(user_id) ON DELETE SET NULL,
+    ADD COLUMN approved_at TIMESTAMPTZ NULL;
+
+-- Draft payslips are visible to HR only and are discarded if the draft is rejected
+ALTER TABLE payslips
+    ADD COLUMN status VARCHAR(10) NOT NULL DEFAULT 'final' CHECK (status IN ('draft', 'final'));
+
+CREATE INDEX idx_payslips_status ON payslips(status);
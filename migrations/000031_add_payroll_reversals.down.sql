@@ -0,0 +1,19 @@
+DROP TABLE IF EXISTS payroll_reversals;
+
+DELETE FROM payslips WHERE status = 'void' OR payslip_type = 'supplementary';
+DROP INDEX IF EXISTS uq_payslips_active_regular;
+
+ALTER TABLE payslips
+    DROP COLUMN IF EXISTS payslip_type,
+    DROP COLUMN IF EXISTS original_payslip_id,
+    DROP COLUMN IF EXISTS adjustment_reason,
+    DROP COLUMN IF EXISTS void_reason,
+    DROP COLUMN IF EXISTS voided_by,
+    DROP COLUMN IF EXISTS voided_at;
+
+ALTER TABLE payslips DROP CONSTRAINT IF EXISTS payslips_status_check;
+ALTER TABLE payslips
+    ADD CONSTRAINT payslips_status_check CHECK (status IN ('draft', 'final'));
+
+ALTER TABLE payslips
+    ADD CONSTRAINT payslips_employee_id_month_year_key UNIQUE (employee_id, month, year);
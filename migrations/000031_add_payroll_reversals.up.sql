@@ -0,0 +1,33 @@
+-- Voided payslips are kept for audit alongside their corrections, so an employee
+-- can have several payslips for a period. Only one active regular payslip is allowed.
+ALTER TABLE payslips DROP CONSTRAINT IF EXISTS payslips_employee_id_month_year_key;
+
+ALTER TABLE payslips DROP CONSTRAINT IF EXISTS payslips_status_check;
+ALTER TABLE payslips
+    ADD CONSTRAINT payslips_status_check CHECK (status IN ('draft', 'final', 'void'));
+
+ALTER TABLE payslips
+    ADD COLUMN payslip_type        VARCHAR(20) NOT NULL DEFAULT 'regular' CHECK (payslip_type IN ('regular', 'supplementary')),
+    ADD COLUMN original_payslip_id UUID NULL REFERENCES payslips(id) ON DELETE SET NULL,
+    ADD COLUMN adjustment_reason   TEXT NOT NULL DEFAULT '',
+    ADD COLUMN void_reason         TEXT NOT NULL DEFAULT '',
+    ADD COLUMN voided_by           UUID NULL REFERENCES users(user_id) ON DELETE SET NULL,
+    ADD COLUMN voided_at           TIMESTAMPTZ NULL;
+
+CREATE UNIQUE INDEX uq_payslips_active_regular
+    ON payslips(employee_id, month, year)
+    WHERE payslip_type = 'regular' AND status <> 'void';
+
+COMMENT ON COLUMN payslips.original_payslip_id IS 'Payslip this one corrects: the voided original for a re-run, or the supplemented payslip for an off-cycle adjustment';
+
+CREATE TABLE IF NOT EXISTS payroll_reversals (
+    id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    payroll_id       UUID NOT NULL REFERENCES payrolls(id) ON DELETE CASCADE,
+    reason           TEXT NOT NULL,
+    payslips_voided  INTEGER NOT NULL DEFAULT 0,
+    total_net_voided NUMERIC(15,2) NOT NULL DEFAULT 0,
+    reversed_by      UUID NULL REFERENCES users(user_id) ON DELETE SET NULL,
+    reversed_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_payroll_reversals_payroll ON payroll_reversals(payroll_id);
@@ -0,0 +1,6 @@
+ALTER TABLE payrolls
+    DROP COLUMN IF EXISTS total_employees,
+    DROP COLUMN IF EXISTS succeeded_count,
+    DROP COLUMN IF EXISTS failed_count;
+
+DROP TABLE IF EXISTS payroll_run_items;
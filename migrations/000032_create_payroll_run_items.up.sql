@@ -0,0 +1,21 @@
+-- One row per employee per payroll run, so HR can see who was paid, who failed and why
+CREATE TABLE IF NOT EXISTS payroll_run_items (
+    id           UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    payroll_id   UUID NOT NULL REFERENCES payrolls(id) ON DELETE CASCADE,
+    employee_id  UUID NOT NULL REFERENCES employees(id) ON DELETE CASCADE,
+    status       VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'succeeded', 'failed')),
+    error_reason TEXT NOT NULL DEFAULT '',
+    payslip_id   UUID NULL REFERENCES payslips(id) ON DELETE SET NULL,
+    attempts     INTEGER NOT NULL DEFAULT 0,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (payroll_id, employee_id)
+);
+
+CREATE INDEX idx_payroll_run_items_payroll_status ON payroll_run_items(payroll_id, status);
+
+-- Progress counters, refreshed from payroll_run_items as the run advances
+ALTER TABLE payrolls
+    ADD COLUMN total_employees INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN succeeded_count INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN failed_count    INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,16 @@
+DELETE FROM payslips WHERE payslip_type = 'bonus';
+
+ALTER TABLE payslips DROP CONSTRAINT IF EXISTS payslips_payslip_type_check;
+ALTER TABLE payslips
+    ADD CONSTRAINT payslips_payslip_type_check CHECK (payslip_type IN ('regular', 'supplementary'));
+
+DROP INDEX IF EXISTS uq_payslips_active_bonus;
+DROP INDEX IF EXISTS idx_payslips_payroll;
+
+ALTER TABLE payslips
+    DROP COLUMN IF EXISTS payroll_id,
+    DROP COLUMN IF EXISTS bonus_pay;
+
+ALTER TABLE payrolls
+    DROP COLUMN IF EXISTS run_type,
+    DROP COLUMN IF EXISTS bonus_percent;
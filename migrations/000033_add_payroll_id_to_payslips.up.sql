@@ -0,0 +1,31 @@
+-- Payslips belong to the payroll run that produced them, so several runs
+-- (e.g. a regular run and a bonus run) can share a month
+ALTER TABLE payrolls
+    ADD COLUMN run_type      VARCHAR(20) NOT NULL DEFAULT 'regular' CHECK (run_type IN ('regular', 'bonus')),
+    ADD COLUMN bonus_percent NUMERIC(6,2) NOT NULL DEFAULT 0;
+
+ALTER TABLE payslips
+    ADD COLUMN payroll_id UUID NULL REFERENCES payrolls(id) ON DELETE SET NULL,
+    ADD COLUMN bonus_pay  NUMERIC(15,2) NOT NULL DEFAULT 0;
+
+ALTER TABLE payslips DROP CONSTRAINT IF EXISTS payslips_payslip_type_check;
+ALTER TABLE payslips
+    ADD CONSTRAINT payslips_payslip_type_check CHECK (payslip_type IN ('regular', 'supplementary', 'bonus'));
+
+CREATE INDEX idx_payslips_payroll ON payslips(payroll_id);
+
+CREATE UNIQUE INDEX uq_payslips_active_bonus
+    ON payslips(employee_id, payroll_id)
+    WHERE payslip_type = 'bonus' AND status <> 'void';
+
+-- Existing payslips are attributed to the latest processed payroll ending in their month
+UPDATE payslips p
+SET payroll_id = (
+    SELECT pr.id FROM payrolls pr
+    WHERE EXTRACT(MONTH FROM pr.end_date) = p.month
+      AND EXTRACT(YEAR FROM pr.end_date) = p.year
+      AND pr.status IN ('DRAFT', 'COMPLETED', 'OPEN')
+    ORDER BY pr.created_at DESC
+    LIMIT 1
+)
+WHERE p.payroll_id IS NULL;
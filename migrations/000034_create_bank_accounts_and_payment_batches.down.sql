@@ -0,0 +1,4 @@
+ALTER TABLE payslips DROP COLUMN IF EXISTS payment_batch_id;
+
+DROP TABLE IF EXISTS payment_batches;
+DROP TABLE IF EXISTS employee_bank_accounts;
@@ -0,0 +1,43 @@
+-- Employee bank accounts. Every change is submitted as a pending record and only
+-- becomes the account salaries are paid into once HR approves it.
+CREATE TABLE IF NOT EXISTS employee_bank_accounts (
+    id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    employee_id      UUID NOT NULL REFERENCES employees(id) ON DELETE CASCADE,
+    bank_name        VARCHAR(100) NOT NULL,
+    bank_code        VARCHAR(20) NOT NULL,
+    branch_code      VARCHAR(20) NOT NULL DEFAULT '',
+    account_number   VARCHAR(34) NOT NULL,
+    account_name     VARCHAR(150) NOT NULL,
+    status           VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected', 'superseded')),
+    requested_by     UUID NULL REFERENCES users(user_id) ON DELETE SET NULL,
+    reviewed_by      UUID NULL REFERENCES users(user_id) ON DELETE SET NULL,
+    reviewed_at      TIMESTAMPTZ NULL,
+    rejection_reason TEXT NOT NULL DEFAULT '',
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_employee_bank_accounts_employee ON employee_bank_accounts(employee_id);
+CREATE UNIQUE INDEX uq_employee_bank_accounts_approved ON employee_bank_accounts(employee_id) WHERE status = 'approved';
+CREATE UNIQUE INDEX uq_employee_bank_accounts_pending ON employee_bank_accounts(employee_id) WHERE status = 'pending';
+
+-- Bank transfer files generated for completed payrolls
+CREATE TABLE IF NOT EXISTS payment_batches (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    payroll_id      UUID NOT NULL REFERENCES payrolls(id) ON DELETE CASCADE,
+    batch_reference VARCHAR(40) NOT NULL UNIQUE,
+    format          VARCHAR(30) NOT NULL,
+    record_count    INTEGER NOT NULL,
+    control_total   NUMERIC(15,2) NOT NULL,
+    file_name       VARCHAR(100) NOT NULL,
+    content_type    VARCHAR(50) NOT NULL,
+    content         BYTEA NOT NULL,
+    generated_by    UUID NULL REFERENCES users(user_id) ON DELETE SET NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_payment_batches_payroll ON payment_batches(payroll_id);
+
+-- Each payslip is paid in at most one batch, so later supplementary payslips go in a new batch
+ALTER TABLE payslips
+    ADD COLUMN payment_batch_id UUID NULL REFERENCES payment_batches(id) ON DELETE SET NULL;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS gl_account_mappings;
@@ -0,0 +1,18 @@
+-- General-ledger accounts that payroll components post to. A row with a department
+-- overrides the company-wide default (department_id NULL) for that department.
+CREATE TABLE IF NOT EXISTS gl_account_mappings (
+    id            UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    component     VARCHAR(30) NOT NULL CHECK (component IN (
+                      'basic_salary', 'housing_allowance', 'transport_allowance', 'medical_allowance',
+                      'overtime', 'bonus', 'leave_pay', 'employer_pension',
+                      'net_pay', 'income_tax', 'pension_payable')),
+    department_id UUID NULL REFERENCES departments(id) ON DELETE CASCADE,
+    account_code  VARCHAR(30) NOT NULL,
+    account_name  VARCHAR(150) NOT NULL DEFAULT '',
+    cost_centre   VARCHAR(30) NOT NULL DEFAULT '',
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at    TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX uq_gl_account_mappings_default ON gl_account_mappings(component) WHERE department_id IS NULL;
+CREATE UNIQUE INDEX uq_gl_account_mappings_department ON gl_account_mappings(component, department_id) WHERE department_id IS NOT NULL;
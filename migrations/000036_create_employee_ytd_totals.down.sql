@@ -0,0 +1,8 @@
+ALTER TABLE payslips
+    DROP COLUMN IF EXISTS ytd_gross_salary,
+    DROP COLUMN IF EXISTS ytd_income_tax,
+    DROP COLUMN IF EXISTS ytd_overtime_pay,
+    DROP COLUMN IF EXISTS ytd_bonus_pay,
+    DROP COLUMN IF EXISTS ytd_net_salary;
+
+DROP TABLE IF EXISTS employee_ytd_totals;
@@ -0,0 +1,50 @@
+-- Year-to-date accumulators per employee per tax year (calendar year), rebuilt from
+-- final payslips whenever payslips are finalised, issued, voided or deleted.
+CREATE TABLE IF NOT EXISTS employee_ytd_totals (
+    employee_id   UUID NOT NULL REFERENCES employees(id) ON DELETE CASCADE,
+    tax_year      INTEGER NOT NULL,
+    gross_salary  NUMERIC(15,2) NOT NULL DEFAULT 0,
+    income_tax    NUMERIC(15,2) NOT NULL DEFAULT 0,
+    overtime_pay  NUMERIC(15,2) NOT NULL DEFAULT 0,
+    bonus_pay     NUMERIC(15,2) NOT NULL DEFAULT 0,
+    leave_pay     NUMERIC(15,2) NOT NULL DEFAULT 0,
+    net_salary    NUMERIC(15,2) NOT NULL DEFAULT 0,
+    payslip_count INTEGER NOT NULL DEFAULT 0,
+    updated_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (employee_id, tax_year)
+);
+
+CREATE INDEX idx_employee_ytd_totals_year ON employee_ytd_totals(tax_year);
+
+-- YTD figures as at each payslip, stamped when the payslip becomes final
+ALTER TABLE payslips
+    ADD COLUMN ytd_gross_salary NUMERIC(15,2) NULL,
+    ADD COLUMN ytd_income_tax   NUMERIC(15,2) NULL,
+    ADD COLUMN ytd_overtime_pay NUMERIC(15,2) NULL,
+    ADD COLUMN ytd_bonus_pay    NUMERIC(15,2) NULL,
+    ADD COLUMN ytd_net_salary   NUMERIC(15,2) NULL;
+
+INSERT INTO employee_ytd_totals (employee_id, tax_year, gross_salary, income_tax, overtime_pay, bonus_pay, leave_pay, net_salary, payslip_count)
+SELECT employee_id, year, SUM(gross_salary), SUM(income_tax), SUM(overtime_pay), SUM(bonus_pay), SUM(leave_days), SUM(net_salary), COUNT(*)
+FROM payslips
+WHERE status = 'final'
+GROUP BY employee_id, year;
+
+UPDATE payslips p
+SET ytd_gross_salary = t.gross_salary,
+    ytd_income_tax   = t.income_tax,
+    ytd_overtime_pay = t.overtime_pay,
+    ytd_bonus_pay    = t.bonus_pay,
+    ytd_net_salary   = t.net_salary
+FROM (
+    SELECT p1.id,
+           SUM(p2.gross_salary) AS gross_salary, SUM(p2.income_tax) AS income_tax,
+           SUM(p2.overtime_pay) AS overtime_pay, SUM(p2.bonus_pay) AS bonus_pay,
+           SUM(p2.net_salary) AS net_salary
+    FROM payslips p1
+    JOIN payslips p2 ON p2.employee_id = p1.employee_id AND p2.year = p1.year AND p2.status = 'final'
+        AND (p2.month < p1.month OR (p2.month = p1.month AND p2.created_at <= p1.created_at))
+    WHERE p1.status = 'final'
+    GROUP BY p1.id
+) t
+WHERE p.id = t.id;
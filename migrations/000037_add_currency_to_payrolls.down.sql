@@ -0,0 +1,5 @@
+ALTER TABLE payslips
+    DROP COLUMN IF EXISTS currency;
+
+ALTER TABLE payrolls
+    DROP COLUMN IF EXISTS currency;
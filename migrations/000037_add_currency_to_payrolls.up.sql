@@ -0,0 +1,7 @@
+-- Every payroll and payslip records the currency its amounts are in. Existing rows
+-- were all paid in kwacha.
+ALTER TABLE payrolls
+    ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'ZMW';
+
+ALTER TABLE payslips
+    ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'ZMW';
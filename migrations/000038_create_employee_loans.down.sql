@@ -0,0 +1,29 @@
+DELETE FROM workflow_instances
+WHERE workflow_id IN (SELECT id FROM workflows WHERE workflow_type = 'LOAN_REQUEST');
+
+DELETE FROM workflows WHERE workflow_type = 'LOAN_REQUEST';
+
+ALTER TABLE workflows
+DROP CONSTRAINT valid_workflow_type;
+
+ALTER TABLE workflows
+ADD CONSTRAINT valid_workflow_type CHECK (
+    workflow_type = 'LEAVE_REQUEST'
+);
+
+DELETE FROM gl_account_mappings WHERE component = 'loan_recovery';
+
+ALTER TABLE gl_account_mappings
+DROP CONSTRAINT IF EXISTS gl_account_mappings_component_check;
+
+ALTER TABLE gl_account_mappings
+ADD CONSTRAINT gl_account_mappings_component_check CHECK (component IN (
+    'basic_salary', 'housing_allowance', 'transport_allowance', 'medical_allowance',
+    'overtime', 'bonus', 'leave_pay', 'employer_pension',
+    'net_pay', 'income_tax', 'pension_payable'));
+
+ALTER TABLE payslips
+    DROP COLUMN IF EXISTS loan_deduction;
+
+DROP TABLE IF EXISTS loan_repayments;
+DROP TABLE IF EXISTS employee_loans;
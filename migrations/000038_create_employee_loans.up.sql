@@ -0,0 +1,105 @@
+-- Employee loans and salary advances, repaid by payroll deductions.
+-- Interest is flat: principal x annual rate x term in years, spread evenly over the instalments.
+CREATE TABLE IF NOT EXISTS employee_loans (
+    id                 UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    employee_id        UUID NOT NULL REFERENCES employees(id) ON DELETE CASCADE,
+    loan_type          VARCHAR(20) NOT NULL CHECK (loan_type IN ('loan', 'advance')),
+    principal          NUMERIC(15,2) NOT NULL CHECK (principal > 0),
+    interest_rate      NUMERIC(7,4) NOT NULL DEFAULT 0 CHECK (interest_rate >= 0),
+    interest_amount    NUMERIC(15,2) NOT NULL DEFAULT 0,
+    total_repayable    NUMERIC(15,2) NOT NULL,
+    instalments        INTEGER NOT NULL CHECK (instalments > 0),
+    instalment_amount  NUMERIC(15,2) NOT NULL,
+    start_month        INTEGER NOT NULL CHECK (start_month BETWEEN 1 AND 12),
+    start_year         INTEGER NOT NULL,
+    reason             TEXT NOT NULL DEFAULT '',
+    status             VARCHAR(20) NOT NULL DEFAULT 'pending'
+                       CHECK (status IN ('pending', 'active', 'rejected', 'cancelled')),
+    requested_by       UUID NULL REFERENCES users(user_id),
+    reviewed_by        UUID NULL REFERENCES employees(id),
+    reviewed_at        TIMESTAMPTZ NULL,
+    created_at         TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at         TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_employee_loans_employee_status ON employee_loans(employee_id, status);
+
+-- Every amount recovered against a loan. Payroll deductions reference the payslip they
+-- were taken on, so deleting the payslip removes the repayment and voiding it stops it
+-- counting towards the balance.
+CREATE TABLE IF NOT EXISTS loan_repayments (
+    id             UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    loan_id        UUID NOT NULL REFERENCES employee_loans(id) ON DELETE CASCADE,
+    payslip_id     UUID NULL REFERENCES payslips(id) ON DELETE CASCADE,
+    repayment_type VARCHAR(20) NOT NULL CHECK (repayment_type IN ('payroll', 'early_settlement', 'final_pay')),
+    amount         NUMERIC(15,2) NOT NULL CHECK (amount > 0),
+    month          INTEGER NOT NULL CHECK (month BETWEEN 1 AND 12),
+    year           INTEGER NOT NULL,
+    notes          TEXT NOT NULL DEFAULT '',
+    recorded_by    UUID NULL REFERENCES users(user_id),
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_loan_repayments_loan ON loan_repayments(loan_id);
+CREATE INDEX idx_loan_repayments_payslip ON loan_repayments(payslip_id);
+
+ALTER TABLE payslips
+    ADD COLUMN IF NOT EXISTS loan_deduction NUMERIC(15,2) NOT NULL DEFAULT 0;
+
+-- Loan deductions credit the loans receivable account in the payroll journal
+ALTER TABLE gl_account_mappings
+DROP CONSTRAINT IF EXISTS gl_account_mappings_component_check;
+
+ALTER TABLE gl_account_mappings
+ADD CONSTRAINT gl_account_mappings_component_check CHECK (component IN (
+    'basic_salary', 'housing_allowance', 'transport_allowance', 'medical_allowance',
+    'overtime', 'bonus', 'leave_pay', 'employer_pension',
+    'net_pay', 'income_tax', 'pension_payable', 'loan_recovery'));
+
+-- Loan requests are approved through the workflow engine
+ALTER TABLE workflows
+DROP CONSTRAINT valid_workflow_type;
+
+ALTER TABLE workflows
+ADD CONSTRAINT valid_workflow_type CHECK (
+    workflow_type IN ('LEAVE_REQUEST', 'LOAN_REQUEST')
+);
+
+DO $$
+DECLARE
+    v_workflow_id     UUID;
+    v_super_admin_id  UUID;
+    v_step_submit_id  UUID;
+    v_step_approve_id UUID;
+BEGIN
+    SELECT user_id INTO v_super_admin_id
+    FROM users WHERE email = 'admin@hr-system.com' LIMIT 1;
+
+    IF v_super_admin_id IS NULL THEN
+        RAISE EXCEPTION 'Super admin user not found. Cannot seed workflow.';
+    END IF;
+
+    INSERT INTO workflows (id, name, description, workflow_type, is_active, created_by)
+    VALUES (
+        gen_random_uuid(),
+        'Loan Request Approval',
+        'Workflow for employee loans and salary advances. The employee submits the request and HR approves the amount and repayment schedule.',
+        'LOAN_REQUEST',
+        true,
+        v_super_admin_id
+    )
+    RETURNING id INTO v_workflow_id;
+
+    -- Step 1: Submission (initial) — employee submits the loan request
+    INSERT INTO workflow_steps (id, workflow_id, step_name, step_order, initial, final, allowed_roles, min_approvals)
+    VALUES (gen_random_uuid(), v_workflow_id, 'Submission', 1, true, false, '["employee"]'::jsonb, 1)
+    RETURNING id INTO v_step_submit_id;
+
+    -- Step 2: Approve (final) — HR manager approves the terms
+    INSERT INTO workflow_steps (id, workflow_id, step_name, step_order, initial, final, allowed_roles, min_approvals)
+    VALUES (gen_random_uuid(), v_workflow_id, 'Approve', 2, false, true, '["hr_manager"]'::jsonb, 1)
+    RETURNING id INTO v_step_approve_id;
+
+    INSERT INTO workflow_transitions (workflow_id, from_step_id, to_step_id, action_name, condition_type)
+    VALUES (v_workflow_id, v_step_submit_id, v_step_approve_id, 'submit', 'always');
+END $$;
@@ -0,0 +1,18 @@
+DELETE FROM gl_account_mappings WHERE component IN ('other_earnings', 'reimbursements', 'other_deductions');
+
+ALTER TABLE gl_account_mappings
+DROP CONSTRAINT IF EXISTS gl_account_mappings_component_check;
+
+ALTER TABLE gl_account_mappings
+ADD CONSTRAINT gl_account_mappings_component_check CHECK (component IN (
+    'basic_salary', 'housing_allowance', 'transport_allowance', 'medical_allowance',
+    'overtime', 'bonus', 'leave_pay', 'employer_pension',
+    'net_pay', 'income_tax', 'pension_payable', 'loan_recovery'));
+
+ALTER TABLE payslips
+    DROP COLUMN IF EXISTS additional_earnings,
+    DROP COLUMN IF EXISTS reimbursements,
+    DROP COLUMN IF EXISTS pre_tax_deductions,
+    DROP COLUMN IF EXISTS other_deductions;
+
+DROP TABLE IF EXISTS pay_inputs;
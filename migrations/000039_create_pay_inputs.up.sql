@@ -0,0 +1,43 @@
+-- One-off earnings and deductions for an employee in a pay period, picked up by the
+-- payslip generated for that period. payslip_id is set once an input has been paid;
+-- inputs on a voided payslip are paid again when the period is regenerated.
+CREATE TABLE IF NOT EXISTS pay_inputs (
+    id            UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    employee_id   UUID NOT NULL REFERENCES employees(id) ON DELETE CASCADE,
+    month         INTEGER NOT NULL CHECK (month BETWEEN 1 AND 12),
+    year          INTEGER NOT NULL,
+    input_type    VARCHAR(20) NOT NULL CHECK (input_type IN ('earning', 'deduction')),
+    category      VARCHAR(30) NOT NULL,
+    description   VARCHAR(255) NOT NULL DEFAULT '',
+    amount        NUMERIC(15,2) NOT NULL CHECK (amount > 0),
+    tax_treatment VARCHAR(20) NOT NULL CHECK (tax_treatment IN ('taxable', 'non_taxable', 'pre_tax', 'post_tax')),
+    reference     VARCHAR(100) NOT NULL DEFAULT '',
+    payslip_id    UUID NULL REFERENCES payslips(id) ON DELETE SET NULL,
+    created_by    UUID NULL REFERENCES users(user_id),
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT valid_pay_input_treatment CHECK (
+        (input_type = 'earning' AND tax_treatment IN ('taxable', 'non_taxable')) OR
+        (input_type = 'deduction' AND tax_treatment IN ('pre_tax', 'post_tax'))
+    )
+);
+
+CREATE INDEX idx_pay_inputs_period ON pay_inputs(year, month, employee_id);
+CREATE INDEX idx_pay_inputs_payslip ON pay_inputs(payslip_id);
+
+-- Totals of the inputs on each payslip. Taxable earnings are part of gross pay;
+-- reimbursements (non-taxable earnings) are added to net pay after tax.
+ALTER TABLE payslips
+    ADD COLUMN IF NOT EXISTS additional_earnings NUMERIC(15,2) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS reimbursements      NUMERIC(15,2) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS pre_tax_deductions  NUMERIC(15,2) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS other_deductions    NUMERIC(15,2) NOT NULL DEFAULT 0;
+
+ALTER TABLE gl_account_mappings
+DROP CONSTRAINT IF EXISTS gl_account_mappings_component_check;
+
+ALTER TABLE gl_account_mappings
+ADD CONSTRAINT gl_account_mappings_component_check CHECK (component IN (
+    'basic_salary', 'housing_allowance', 'transport_allowance', 'medical_allowance',
+    'overtime', 'bonus', 'leave_pay', 'employer_pension', 'other_earnings', 'reimbursements',
+    'net_pay', 'income_tax', 'pension_payable', 'loan_recovery', 'other_deductions'));
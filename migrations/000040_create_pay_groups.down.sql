@@ -0,0 +1,19 @@
+DROP INDEX IF EXISTS uq_payslips_active_regular;
+CREATE UNIQUE INDEX uq_payslips_active_regular
+    ON payslips(employee_id, month, year)
+    WHERE payslip_type = 'regular' AND status <> 'void';
+
+ALTER TABLE payslips
+    DROP COLUMN IF EXISTS period_end,
+    DROP COLUMN IF EXISTS period_start;
+
+DROP INDEX IF EXISTS idx_payrolls_pay_group_period;
+ALTER TABLE payrolls
+    DROP COLUMN IF EXISTS pay_date,
+    DROP COLUMN IF EXISTS pay_group_id;
+
+DROP INDEX IF EXISTS idx_employees_pay_group;
+ALTER TABLE employees
+    DROP COLUMN IF EXISTS pay_group_id;
+
+DROP TABLE IF EXISTS pay_groups;
@@ -0,0 +1,62 @@
+-- Pay groups: sets of employees paid together on their own frequency and calendar.
+-- Pay periods repeat from calendar_start - every month on the same day for monthly
+-- groups, every 7 or 14 days for weekly and bi-weekly ones. Employees are paid
+-- pay_day_offset days after the period ends.
+CREATE TABLE IF NOT EXISTS pay_groups (
+    id             UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name           VARCHAR(100) NOT NULL UNIQUE,
+    description    TEXT NOT NULL DEFAULT '',
+    pay_frequency  VARCHAR(20) NOT NULL CHECK (pay_frequency IN ('monthly', 'biweekly', 'weekly')),
+    calendar_start DATE NOT NULL,
+    pay_day_offset INTEGER NOT NULL DEFAULT 0 CHECK (pay_day_offset BETWEEN 0 AND 31),
+    is_default     BOOLEAN NOT NULL DEFAULT false,
+    is_active      BOOLEAN NOT NULL DEFAULT true,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT pay_groups_monthly_start_check
+        CHECK (pay_frequency <> 'monthly' OR EXTRACT(DAY FROM calendar_start) <= 28)
+);
+
+-- Employees without a pay group are paid with the default group
+CREATE UNIQUE INDEX uq_pay_groups_default ON pay_groups(is_default) WHERE is_default;
+
+INSERT INTO pay_groups (name, description, pay_frequency, calendar_start, is_default)
+VALUES ('Monthly', 'Calendar-month salaried payroll', 'monthly', '2000-01-01', true)
+ON CONFLICT (name) DO NOTHING;
+
+ALTER TABLE employees
+    ADD COLUMN IF NOT EXISTS pay_group_id UUID NULL REFERENCES pay_groups(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_employees_pay_group ON employees(pay_group_id);
+
+-- Payroll runs belong to a pay group; existing runs were all monthly
+ALTER TABLE payrolls
+    ADD COLUMN IF NOT EXISTS pay_group_id UUID NULL REFERENCES pay_groups(id) ON DELETE RESTRICT,
+    ADD COLUMN IF NOT EXISTS pay_date     DATE NULL;
+
+UPDATE payrolls
+SET pay_group_id = (SELECT id FROM pay_groups WHERE is_default),
+    pay_date = end_date
+WHERE pay_group_id IS NULL;
+
+CREATE INDEX idx_payrolls_pay_group_period ON payrolls(pay_group_id, start_date, end_date);
+
+-- A month can hold several weekly payslips, so regular payslips are unique per pay
+-- period rather than per month. month and year stay as the tax month (the period's end).
+ALTER TABLE payslips
+    ADD COLUMN IF NOT EXISTS period_start DATE NULL,
+    ADD COLUMN IF NOT EXISTS period_end   DATE NULL;
+
+UPDATE payslips
+SET period_start = make_date(year, month, 1),
+    period_end   = (make_date(year, month, 1) + INTERVAL '1 month - 1 day')::date
+WHERE period_start IS NULL;
+
+ALTER TABLE payslips
+    ALTER COLUMN period_start SET NOT NULL,
+    ALTER COLUMN period_end SET NOT NULL;
+
+DROP INDEX IF EXISTS uq_payslips_active_regular;
+CREATE UNIQUE INDEX uq_payslips_active_regular
+    ON payslips(employee_id, period_start, period_end)
+    WHERE payslip_type = 'regular' AND status <> 'void';
@@ -0,0 +1,17 @@
+DELETE FROM gl_account_mappings WHERE component = 'arrears';
+
+ALTER TABLE gl_account_mappings
+DROP CONSTRAINT IF EXISTS gl_account_mappings_component_check;
+
+ALTER TABLE gl_account_mappings
+ADD CONSTRAINT gl_account_mappings_component_check CHECK (component IN (
+    'basic_salary', 'housing_allowance', 'transport_allowance', 'medical_allowance',
+    'overtime', 'bonus', 'leave_pay', 'employer_pension', 'other_earnings', 'reimbursements',
+    'net_pay', 'income_tax', 'pension_payable', 'loan_recovery', 'other_deductions'));
+
+ALTER TABLE payslips
+    DROP COLUMN IF EXISTS arrears_pay,
+    DROP COLUMN IF EXISTS arrears_tax;
+
+DROP TABLE IF EXISTS retro_pay_lines;
+DROP TABLE IF EXISTS retro_pay_adjustments;
@@ -0,0 +1,57 @@
+-- Retroactive pay: prior periods recalculated under the current compensation and tax
+-- rules. The difference from what was paid is held as arrears until the employee's next
+-- regular payslip picks it up. payslip_id is set once the arrears have been paid;
+-- arrears on a voided or deleted draft payslip are paid again by the next one.
+CREATE TABLE IF NOT EXISTS retro_pay_adjustments (
+    id             UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    employee_id    UUID NOT NULL REFERENCES employees(id) ON DELETE CASCADE,
+    effective_from DATE NOT NULL,
+    reason         VARCHAR(255) NOT NULL,
+    status         VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'cancelled')),
+    gross_arrears  NUMERIC(15,2) NOT NULL,
+    tax_arrears    NUMERIC(15,2) NOT NULL,
+    net_arrears    NUMERIC(15,2) NOT NULL,
+    payslip_id     UUID NULL REFERENCES payslips(id) ON DELETE SET NULL,
+    created_by     UUID NULL REFERENCES users(user_id),
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_retro_pay_adjustments_employee ON retro_pay_adjustments(employee_id, status);
+CREATE INDEX idx_retro_pay_adjustments_payslip ON retro_pay_adjustments(payslip_id);
+
+-- One line per recalculated pay period. Differences are pro-rated when the effective
+-- date falls part-way through the period.
+CREATE TABLE IF NOT EXISTS retro_pay_lines (
+    id                 UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    adjustment_id      UUID NOT NULL REFERENCES retro_pay_adjustments(id) ON DELETE CASCADE,
+    payslip_id         UUID NOT NULL REFERENCES payslips(id) ON DELETE CASCADE,
+    month              INTEGER NOT NULL,
+    year               INTEGER NOT NULL,
+    period_start       DATE NOT NULL,
+    period_end         DATE NOT NULL,
+    previous_gross     NUMERIC(15,2) NOT NULL,
+    recalculated_gross NUMERIC(15,2) NOT NULL,
+    gross_difference   NUMERIC(15,2) NOT NULL,
+    previous_tax       NUMERIC(15,2) NOT NULL,
+    recalculated_tax   NUMERIC(15,2) NOT NULL,
+    tax_difference     NUMERIC(15,2) NOT NULL,
+    net_difference     NUMERIC(15,2) NOT NULL
+);
+
+CREATE INDEX idx_retro_pay_lines_adjustment ON retro_pay_lines(adjustment_id);
+CREATE INDEX idx_retro_pay_lines_period ON retro_pay_lines(period_start, period_end);
+
+-- Arrears paid on a payslip: arrears_pay is part of gross pay and arrears_tax part of income tax
+ALTER TABLE payslips
+    ADD COLUMN IF NOT EXISTS arrears_pay NUMERIC(15,2) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS arrears_tax NUMERIC(15,2) NOT NULL DEFAULT 0;
+
+ALTER TABLE gl_account_mappings
+DROP CONSTRAINT IF EXISTS gl_account_mappings_component_check;
+
+ALTER TABLE gl_account_mappings
+ADD CONSTRAINT gl_account_mappings_component_check CHECK (component IN (
+    'basic_salary', 'housing_allowance', 'transport_allowance', 'medical_allowance',
+    'overtime', 'bonus', 'leave_pay', 'employer_pension', 'other_earnings', 'reimbursements',
+    'arrears', 'net_pay', 'income_tax', 'pension_payable', 'loan_recovery', 'other_deductions'));
@@ -0,0 +1,58 @@
+-- Final pay for employees who leave. A settlement statement is prepared when an employee
+-- is terminated or resigns, reviewed and approved by HR, and then paid on a final payslip.
+CREATE TABLE IF NOT EXISTS final_settlements (
+    id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    employee_id      UUID NOT NULL REFERENCES employees(id) ON DELETE CASCADE,
+    termination_date DATE NOT NULL,
+    separation_type  VARCHAR(20) NOT NULL CHECK (separation_type IN ('terminated', 'resigned')),
+    period_start     DATE NOT NULL,
+    period_end       DATE NOT NULL,
+    days_worked      INTEGER NOT NULL,
+    period_days      INTEGER NOT NULL,
+    prorated_salary  NUMERIC(15,2) NOT NULL DEFAULT 0,
+    other_earnings   NUMERIC(15,2) NOT NULL DEFAULT 0,
+    leave_days       INTEGER NOT NULL DEFAULT 0,
+    leave_encashment NUMERIC(15,2) NOT NULL DEFAULT 0,
+    notice_days      INTEGER NOT NULL DEFAULT 0,
+    notice_pay       NUMERIC(15,2) NOT NULL DEFAULT 0,
+    service_years    INTEGER NOT NULL DEFAULT 0,
+    gratuity         NUMERIC(15,2) NOT NULL DEFAULT 0,
+    arrears          NUMERIC(15,2) NOT NULL DEFAULT 0,
+    gross_pay        NUMERIC(15,2) NOT NULL DEFAULT 0,
+    income_tax       NUMERIC(15,2) NOT NULL DEFAULT 0,
+    other_deductions NUMERIC(15,2) NOT NULL DEFAULT 0,
+    loan_recovery    NUMERIC(15,2) NOT NULL DEFAULT 0,
+    loan_outstanding NUMERIC(15,2) NOT NULL DEFAULT 0,
+    net_pay          NUMERIC(15,2) NOT NULL DEFAULT 0,
+    notes            TEXT NOT NULL DEFAULT '',
+    status           VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'cancelled')),
+    payslip_id       UUID NULL REFERENCES payslips(id) ON DELETE SET NULL,
+    created_by       UUID NULL REFERENCES users(user_id),
+    approved_by      UUID NULL REFERENCES users(user_id),
+    approved_at      TIMESTAMPTZ NULL,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- At most one live settlement per employee
+CREATE UNIQUE INDEX IF NOT EXISTS uq_final_settlements_employee
+    ON final_settlements(employee_id)
+    WHERE status <> 'cancelled';
+
+CREATE INDEX idx_final_settlements_status ON final_settlements(status);
+
+-- Termination benefits on a final payslip, all part of gross pay
+ALTER TABLE payslips
+    ADD COLUMN IF NOT EXISTS leave_encashment NUMERIC(15,2) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS notice_pay       NUMERIC(15,2) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS gratuity         NUMERIC(15,2) NOT NULL DEFAULT 0;
+
+ALTER TABLE gl_account_mappings
+DROP CONSTRAINT IF EXISTS gl_account_mappings_component_check;
+
+ALTER TABLE gl_account_mappings
+ADD CONSTRAINT gl_account_mappings_component_check CHECK (component IN (
+    'basic_salary', 'housing_allowance', 'transport_allowance', 'medical_allowance',
+    'overtime', 'bonus', 'leave_pay', 'employer_pension', 'other_earnings', 'reimbursements',
+    'arrears', 'termination_benefits', 'net_pay', 'income_tax', 'pension_payable', 'loan_recovery',
+    'other_deductions'));
@@ -0,0 +1,20 @@
+-- Fractional values are rounded to whole days
+ALTER TABLE final_settlements
+    ALTER COLUMN leave_days TYPE INTEGER USING ROUND(leave_days);
+
+ALTER TABLE leave_balances
+    ALTER COLUMN total_entitled    TYPE INT USING ROUND(total_entitled),
+    ALTER COLUMN used              TYPE INT USING ROUND(used),
+    ALTER COLUMN pending           TYPE INT USING ROUND(pending),
+    ALTER COLUMN carried_forward   TYPE INT USING ROUND(carried_forward),
+    ALTER COLUMN earned_leave_days TYPE INT USING ROUND(earned_leave_days);
+
+ALTER TABLE leave_requests
+    DROP COLUMN IF EXISTS hours,
+    DROP COLUMN IF EXISTS end_part,
+    DROP COLUMN IF EXISTS start_part,
+    ALTER COLUMN total_days TYPE INT USING ROUND(total_days);
+
+ALTER TABLE leave_types
+    DROP COLUMN IF EXISTS hours_per_day,
+    DROP COLUMN IF EXISTS allow_hourly;
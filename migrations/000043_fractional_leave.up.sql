@@ -0,0 +1,23 @@
+-- Leave can be taken in half days (morning or afternoon on the start and end dates) and,
+-- for leave types that allow it, in hours. Day counts and balances become fractional.
+ALTER TABLE leave_types
+    ADD COLUMN IF NOT EXISTS allow_hourly  BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS hours_per_day NUMERIC(4,2) NOT NULL DEFAULT 8;
+
+UPDATE leave_types SET allow_hourly = TRUE WHERE code = 'SL';
+
+ALTER TABLE leave_requests
+    ALTER COLUMN total_days TYPE NUMERIC(6,2),
+    ADD COLUMN IF NOT EXISTS start_part VARCHAR(4) NOT NULL DEFAULT 'full' CHECK (start_part IN ('full', 'am', 'pm')),
+    ADD COLUMN IF NOT EXISTS end_part   VARCHAR(4) NOT NULL DEFAULT 'full' CHECK (end_part IN ('full', 'am', 'pm')),
+    ADD COLUMN IF NOT EXISTS hours      NUMERIC(5,2) NOT NULL DEFAULT 0;
+
+ALTER TABLE leave_balances
+    ALTER COLUMN total_entitled    TYPE NUMERIC(6,2),
+    ALTER COLUMN used              TYPE NUMERIC(6,2),
+    ALTER COLUMN pending           TYPE NUMERIC(6,2),
+    ALTER COLUMN carried_forward   TYPE NUMERIC(6,2),
+    ALTER COLUMN earned_leave_days TYPE NUMERIC(6,2);
+
+ALTER TABLE final_settlements
+    ALTER COLUMN leave_days TYPE NUMERIC(6,2);
@@ -0,0 +1,17 @@
+-- A leave request is charged to the balance of each leave year it falls in
+CREATE TABLE IF NOT EXISTS leave_request_portions (
+    id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    leave_request_id UUID NOT NULL REFERENCES leave_requests(id) ON DELETE CASCADE,
+    year             INT NOT NULL,
+    start_date       DATE NOT NULL,
+    end_date         DATE NOT NULL,
+    days             NUMERIC(6,2) NOT NULL,
+
+    CONSTRAINT uq_leave_request_portion_year UNIQUE (leave_request_id, year)
+);
+
+-- Existing requests were charged in full to the year they start in
+INSERT INTO leave_request_portions (leave_request_id, year, start_date, end_date, days)
+SELECT id, EXTRACT(YEAR FROM start_date)::INT, start_date, end_date, total_days
+FROM leave_requests
+ON CONFLICT DO NOTHING;
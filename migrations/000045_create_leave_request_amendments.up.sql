@@ -0,0 +1,22 @@
+-- Changes made to a leave request after it was filed. Each row keeps the values the
+-- request had before the amendment, so earlier approvals stay on record.
+CREATE TABLE IF NOT EXISTS leave_request_amendments (
+    id                     UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    leave_request_id       UUID NOT NULL REFERENCES leave_requests(id) ON DELETE CASCADE,
+    previous_leave_type_id UUID NOT NULL REFERENCES leave_types(id) ON DELETE RESTRICT,
+    previous_start_date    DATE NOT NULL,
+    previous_end_date      DATE NOT NULL,
+    previous_start_part    VARCHAR(4) NOT NULL,
+    previous_end_part      VARCHAR(4) NOT NULL,
+    previous_hours         NUMERIC(5,2) NOT NULL DEFAULT 0,
+    previous_total_days    NUMERIC(6,2) NOT NULL,
+    previous_reason        TEXT NOT NULL DEFAULT '',
+    previous_status        leave_request_status NOT NULL,
+    previous_reviewed_by   UUID NULL REFERENCES employees(id) ON DELETE SET NULL,
+    previous_reviewed_at   TIMESTAMPTZ NULL,
+    auto_approved          BOOLEAN NOT NULL DEFAULT FALSE,
+    amended_by             UUID NOT NULL REFERENCES employees(id) ON DELETE RESTRICT,
+    created_at             TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_leave_request_amendments_request ON leave_request_amendments(leave_request_id);
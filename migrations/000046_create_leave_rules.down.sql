@@ -0,0 +1,12 @@
+ALTER TABLE leave_requests
+    DROP COLUMN IF EXISTS rule_overridden_by,
+    DROP COLUMN IF EXISTS rule_override_reason;
+
+DROP TABLE IF EXISTS leave_blackouts;
+
+ALTER TABLE leave_types
+    DROP COLUMN IF EXISTS blackout_exempt,
+    DROP COLUMN IF EXISTS allow_backdating,
+    DROP COLUMN IF EXISTS max_requests_per_year,
+    DROP COLUMN IF EXISTS max_consecutive_days,
+    DROP COLUMN IF EXISTS min_notice_days;
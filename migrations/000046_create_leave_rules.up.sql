@@ -0,0 +1,31 @@
+-- Rules checked when leave is requested. A zero limit means no limit. Leave types that
+-- may be backdated (sick leave) can be requested after the fact.
+ALTER TABLE leave_types
+    ADD COLUMN IF NOT EXISTS min_notice_days       INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS max_consecutive_days  NUMERIC(6,2) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS max_requests_per_year INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS allow_backdating      BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS blackout_exempt       BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE leave_types SET allow_backdating = TRUE, blackout_exempt = TRUE WHERE code = 'SL';
+
+-- Periods when leave cannot be taken, e.g. year-end close. A blackout without a
+-- department applies to the whole company.
+CREATE TABLE IF NOT EXISTS leave_blackouts (
+    id            UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name          VARCHAR(100) NOT NULL,
+    description   TEXT NOT NULL DEFAULT '',
+    department_id UUID NULL REFERENCES departments(id) ON DELETE CASCADE,
+    start_date    DATE NOT NULL,
+    end_date      DATE NOT NULL CHECK (end_date >= start_date),
+    is_active     BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at    TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_leave_blackouts_dates ON leave_blackouts(start_date, end_date);
+
+-- Requests HR filed in spite of broken rules keep who overrode them and why
+ALTER TABLE leave_requests
+    ADD COLUMN IF NOT EXISTS rule_override_reason TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS rule_overridden_by   UUID NULL REFERENCES employees(id) ON DELETE SET NULL;
@@ -0,0 +1 @@
+ALTER TABLE departments DROP COLUMN IF EXISTS min_staff_on_duty;
@@ -0,0 +1,5 @@
+-- Fewest employees of a department who must be at work on a working day. Leave that
+-- would take the department below it is flagged to the employee and the approver. 0 turns
+-- the check off.
+ALTER TABLE departments
+    ADD COLUMN IF NOT EXISTS min_staff_on_duty INTEGER NOT NULL DEFAULT 0 CHECK (min_staff_on_duty >= 0);
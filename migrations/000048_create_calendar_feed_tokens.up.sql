@@ -0,0 +1,9 @@
+-- Secret tokens in a user's iCalendar subscription URLs. Calendar apps cannot send a login,
+-- so the token alone grants read access to the user's feeds; resetting it revokes old URLs.
+CREATE TABLE IF NOT EXISTS calendar_feed_tokens (
+    id           UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id      UUID NOT NULL UNIQUE REFERENCES users(id) ON DELETE CASCADE,
+    token        VARCHAR(64) NOT NULL UNIQUE,
+    last_used_at TIMESTAMPTZ NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
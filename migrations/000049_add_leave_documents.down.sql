@@ -0,0 +1,12 @@
+DROP TABLE IF EXISTS leave_request_attachments;
+
+DROP INDEX IF EXISTS idx_leave_requests_document_due;
+
+ALTER TABLE leave_requests
+    DROP COLUMN IF EXISTS document_reminded_at,
+    DROP COLUMN IF EXISTS document_due_date,
+    DROP COLUMN IF EXISTS document_status;
+
+ALTER TABLE leave_types
+    DROP COLUMN IF EXISTS missing_document_action,
+    DROP COLUMN IF EXISTS document_grace_days;
@@ -0,0 +1,39 @@
+-- What happens when leave that needs a document (a sick note) is taken without one: the
+-- employee has a number of days after the leave ends to hand it in, after which the request
+-- is escalated to HR or converted to unpaid leave.
+ALTER TABLE leave_types
+    ADD COLUMN IF NOT EXISTS document_grace_days     INTEGER NOT NULL DEFAULT 2 CHECK (document_grace_days >= 0),
+    ADD COLUMN IF NOT EXISTS missing_document_action VARCHAR(20) NOT NULL DEFAULT 'escalate'
+        CHECK (missing_document_action IN ('escalate', 'convert_unpaid'));
+
+ALTER TABLE leave_requests
+    ADD COLUMN IF NOT EXISTS document_status      VARCHAR(20) NOT NULL DEFAULT 'not_required'
+        CHECK (document_status IN ('not_required', 'awaited', 'received', 'escalated', 'converted')),
+    ADD COLUMN IF NOT EXISTS document_due_date    DATE NULL,
+    ADD COLUMN IF NOT EXISTS document_reminded_at TIMESTAMPTZ NULL;
+
+CREATE INDEX idx_leave_requests_document_due ON leave_requests(document_due_date)
+    WHERE document_status = 'awaited';
+
+-- Files uploaded to a leave request; the file itself is kept under LEAVE_ATTACHMENT_DIR
+CREATE TABLE IF NOT EXISTS leave_request_attachments (
+    id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    leave_request_id UUID NOT NULL REFERENCES leave_requests(id) ON DELETE CASCADE,
+    file_name        VARCHAR(255) NOT NULL,
+    content_type     VARCHAR(100) NOT NULL,
+    file_size        BIGINT NOT NULL,
+    storage_path     TEXT NOT NULL,
+    uploaded_by      UUID NULL REFERENCES users(user_id) ON DELETE SET NULL,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_leave_request_attachments_request ON leave_request_attachments(leave_request_id);
+
+-- Live requests still missing their document wait for it from here on; older leave is
+-- left alone rather than escalated the day this runs
+UPDATE leave_requests lr
+SET document_status = CASE WHEN lr.attachment_url <> '' THEN 'received' ELSE 'awaited' END,
+    document_due_date = CASE WHEN lr.attachment_url <> '' THEN NULL ELSE lr.end_date + lt.document_grace_days END
+FROM leave_types lt
+WHERE lr.leave_type_id = lt.id AND lt.requires_document
+  AND lr.status IN ('pending', 'approved') AND lr.end_date >= CURRENT_DATE;
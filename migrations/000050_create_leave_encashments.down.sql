@@ -0,0 +1,23 @@
+DELETE FROM workflow_instances
+WHERE workflow_id IN (SELECT id FROM workflows WHERE workflow_type = 'LEAVE_ENCASHMENT');
+
+DELETE FROM workflows WHERE workflow_type = 'LEAVE_ENCASHMENT';
+
+ALTER TABLE workflows
+DROP CONSTRAINT valid_workflow_type;
+
+ALTER TABLE workflows
+ADD CONSTRAINT valid_workflow_type CHECK (
+    workflow_type IN ('LEAVE_REQUEST', 'LOAN_REQUEST')
+);
+
+ALTER TABLE payslips
+    DROP COLUMN IF EXISTS encashed_leave;
+
+DROP TABLE IF EXISTS leave_encashments;
+DROP TABLE IF EXISTS leave_ledger_entries;
+
+ALTER TABLE leave_types
+    DROP COLUMN IF EXISTS encashment_min_balance,
+    DROP COLUMN IF EXISTS max_encashment_days,
+    DROP COLUMN IF EXISTS allow_encashment;
@@ -0,0 +1,108 @@
+-- Leave encashment: employees can ask to be paid for unused days of leave types that allow
+-- it. Days are reserved as pending while the request goes through the approval workflow;
+-- once approved they are debited from the balance through a ledger entry and the amount is
+-- paid on the employee's next payslip.
+ALTER TABLE leave_types
+    ADD COLUMN IF NOT EXISTS allow_encashment       BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS max_encashment_days    NUMERIC(5,2) NOT NULL DEFAULT 0 CHECK (max_encashment_days >= 0),
+    ADD COLUMN IF NOT EXISTS encashment_min_balance NUMERIC(5,2) NOT NULL DEFAULT 0 CHECK (encashment_min_balance >= 0);
+
+COMMENT ON COLUMN leave_types.max_encashment_days IS 'Days an employee can encash per leave year, 0 for no limit';
+COMMENT ON COLUMN leave_types.encashment_min_balance IS 'Days that must be left in the balance after encashing';
+
+UPDATE leave_types SET allow_encashment = TRUE, max_encashment_days = 10, encashment_min_balance = 5
+WHERE code = 'AL';
+
+-- Every change to a leave balance made outside a leave request, so the days taken off a
+-- balance can be traced to what took them. Debits are negative.
+CREATE TABLE IF NOT EXISTS leave_ledger_entries (
+    id            UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    employee_id   UUID NOT NULL REFERENCES employees(id) ON DELETE CASCADE,
+    leave_type_id UUID NOT NULL REFERENCES leave_types(id),
+    year          INTEGER NOT NULL,
+    entry_type    VARCHAR(20) NOT NULL CHECK (entry_type IN ('encashment')),
+    days          NUMERIC(6,2) NOT NULL,
+    reference_id  UUID NULL,
+    notes         TEXT NOT NULL DEFAULT '',
+    created_by    UUID NULL REFERENCES users(user_id),
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_leave_ledger_balance ON leave_ledger_entries(employee_id, leave_type_id, year);
+
+-- payslip_id is set once an approved encashment has been paid; encashments on a voided
+-- payslip are paid again when the period is regenerated.
+CREATE TABLE IF NOT EXISTS leave_encashments (
+    id            UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    employee_id   UUID NOT NULL REFERENCES employees(id) ON DELETE CASCADE,
+    leave_type_id UUID NOT NULL REFERENCES leave_types(id),
+    year          INTEGER NOT NULL,
+    days          NUMERIC(5,2) NOT NULL CHECK (days > 0),
+    daily_rate    NUMERIC(15,2) NOT NULL CHECK (daily_rate >= 0),
+    amount        NUMERIC(15,2) NOT NULL CHECK (amount >= 0),
+    reason        TEXT NOT NULL DEFAULT '',
+    status        VARCHAR(20) NOT NULL DEFAULT 'pending'
+                  CHECK (status IN ('pending', 'approved', 'rejected', 'cancelled')),
+    payslip_id    UUID NULL REFERENCES payslips(id) ON DELETE SET NULL,
+    requested_by  UUID NULL REFERENCES users(user_id),
+    reviewed_by   UUID NULL REFERENCES employees(id),
+    reviewed_at   TIMESTAMPTZ NULL,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at    TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_leave_encashments_employee_status ON leave_encashments(employee_id, status);
+CREATE INDEX idx_leave_encashments_payslip ON leave_encashments(payslip_id);
+
+-- Encashed leave is taxable pay, included in gross. It replaces the flat-rate leave days
+-- compensation, which is no longer paid; leave_days is kept for payslips already issued.
+ALTER TABLE payslips
+    ADD COLUMN IF NOT EXISTS encashed_leave NUMERIC(15,2) NOT NULL DEFAULT 0;
+
+-- Encashment requests are approved through the workflow engine
+ALTER TABLE workflows
+DROP CONSTRAINT valid_workflow_type;
+
+ALTER TABLE workflows
+ADD CONSTRAINT valid_workflow_type CHECK (
+    workflow_type IN ('LEAVE_REQUEST', 'LOAN_REQUEST', 'LEAVE_ENCASHMENT')
+);
+
+DO $$
+DECLARE
+    v_workflow_id     UUID;
+    v_super_admin_id  UUID;
+    v_step_submit_id  UUID;
+    v_step_approve_id UUID;
+BEGIN
+    SELECT user_id INTO v_super_admin_id
+    FROM users WHERE email = 'admin@hr-system.com' LIMIT 1;
+
+    IF v_super_admin_id IS NULL THEN
+        RAISE EXCEPTION 'Super admin user not found. Cannot seed workflow.';
+    END IF;
+
+    INSERT INTO workflows (id, name, description, workflow_type, is_active, created_by)
+    VALUES (
+        gen_random_uuid(),
+        'Leave Encashment Approval',
+        'Workflow for paying out unused leave. The employee submits the days to encash and HR approves the payout.',
+        'LEAVE_ENCASHMENT',
+        true,
+        v_super_admin_id
+    )
+    RETURNING id INTO v_workflow_id;
+
+    -- Step 1: Submission (initial) — employee submits the encashment request
+    INSERT INTO workflow_steps (id, workflow_id, step_name, step_order, initial, final, allowed_roles, min_approvals)
+    VALUES (gen_random_uuid(), v_workflow_id, 'Submission', 1, true, false, '["employee"]'::jsonb, 1)
+    RETURNING id INTO v_step_submit_id;
+
+    -- Step 2: Approve (final) — HR manager approves the payout
+    INSERT INTO workflow_steps (id, workflow_id, step_name, step_order, initial, final, allowed_roles, min_approvals)
+    VALUES (gen_random_uuid(), v_workflow_id, 'Approve', 2, false, true, '["hr_manager"]'::jsonb, 1)
+    RETURNING id INTO v_step_approve_id;
+
+    INSERT INTO workflow_transitions (workflow_id, from_step_id, to_step_id, action_name, condition_type)
+    VALUES (v_workflow_id, v_step_submit_id, v_step_approve_id, 'submit', 'always');
+END $$;
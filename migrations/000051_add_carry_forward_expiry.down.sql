@@ -0,0 +1,18 @@
+DELETE FROM leave_ledger_entries WHERE entry_type = 'forfeiture';
+
+ALTER TABLE leave_ledger_entries
+    DROP CONSTRAINT IF EXISTS leave_ledger_entries_entry_type_check;
+
+ALTER TABLE leave_ledger_entries
+    ADD CONSTRAINT leave_ledger_entries_entry_type_check CHECK (entry_type IN ('encashment'));
+
+DROP INDEX IF EXISTS idx_leave_balances_carry_forward_expiry;
+
+ALTER TABLE leave_balances
+    DROP COLUMN IF EXISTS carry_forward_reminder_days,
+    DROP COLUMN IF EXISTS carry_forward_settled_at,
+    DROP COLUMN IF EXISTS forfeited,
+    DROP COLUMN IF EXISTS carried_forward_expires_on;
+
+ALTER TABLE leave_types
+    DROP COLUMN IF EXISTS carry_forward_expiry_months;
@@ -0,0 +1,26 @@
+-- Carried-forward leave expires: days carried into a leave year must be used within the
+-- leave type's expiry months or they are forfeited. Carried days are used before the
+-- year's own entitlement, so only carried days still unused at expiry are lost.
+ALTER TABLE leave_types
+    ADD COLUMN IF NOT EXISTS carry_forward_expiry_months INTEGER NOT NULL DEFAULT 0
+        CHECK (carry_forward_expiry_months BETWEEN 0 AND 12);
+
+COMMENT ON COLUMN leave_types.carry_forward_expiry_months IS 'Months into the new leave year that carried-forward days can be used for, 0 for no expiry';
+
+-- carried_forward_expires_on is set when days are carried forward. Once it has passed the
+-- unused carried days are moved to forfeited and carry_forward_settled_at is set.
+-- carry_forward_reminder_days is the last reminder sent, in days before expiry.
+ALTER TABLE leave_balances
+    ADD COLUMN IF NOT EXISTS carried_forward_expires_on  DATE NULL,
+    ADD COLUMN IF NOT EXISTS forfeited                   NUMERIC(6,2) NOT NULL DEFAULT 0 CHECK (forfeited >= 0),
+    ADD COLUMN IF NOT EXISTS carry_forward_settled_at    TIMESTAMPTZ NULL,
+    ADD COLUMN IF NOT EXISTS carry_forward_reminder_days INTEGER NULL;
+
+CREATE INDEX idx_leave_balances_carry_forward_expiry ON leave_balances(carried_forward_expires_on)
+WHERE carry_forward_settled_at IS NULL;
+
+ALTER TABLE leave_ledger_entries
+    DROP CONSTRAINT IF EXISTS leave_ledger_entries_entry_type_check;
+
+ALTER TABLE leave_ledger_entries
+    ADD CONSTRAINT leave_ledger_entries_entry_type_check CHECK (entry_type IN ('encashment', 'forfeiture'));
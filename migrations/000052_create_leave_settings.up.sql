@@ -0,0 +1,11 @@
+-- Organisation-wide leave settings, kept to one row. year_start_month is the leave calendar
+-- the stored balances and request portions were split under. When LEAVE_YEAR_START_MONTH
+-- differs the API moves live requests onto the new leave years at startup and updates it.
+-- Balances and requests so far were kept on calendar years.
+CREATE TABLE IF NOT EXISTS leave_settings (
+    id               BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),
+    year_start_month INTEGER NOT NULL DEFAULT 1 CHECK (year_start_month BETWEEN 1 AND 12),
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO leave_settings (id, year_start_month) VALUES (TRUE, 1) ON CONFLICT (id) DO NOTHING;
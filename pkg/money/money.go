@@ -0,0 +1,314 @@
+// Package money holds monetary amounts as fixed-point integers of minor units
+// (cents/ngwee) so payroll arithmetic is exact and totals reconcile to the cent.
+//
+// Rounding rules:
+//
+//   - Every Money value is a whole number of cents. Adding and subtracting amounts
+//     never rounds, so a total always equals the sum of its lines.
+//   - Rounding only happens when an amount is derived by multiplication or division
+//     (Percent, Mul, Div) or converted from a float or a decimal with more than two
+//     places (FromFloat, Parse). Each such operation rounds once, to the nearest cent,
+//     with halves rounded away from zero (2.345 -> 2.35, -2.345 -> -2.35).
+//   - Percent works in integer arithmetic on the exact amount, with the rate held to
+//     four decimal places of a percent. Mul and Div take non-money quantities such as
+//     hours or multipliers, held to six decimal places.
+//   - Derived components are rounded before they are combined: a payslip's gross is
+//     the sum of its already-rounded components, and tax is computed from that gross.
+//
+// Money encodes to JSON as a number with exactly two decimals (1234.50) and to SQL
+// as a decimal string, matching the NUMERIC(15,2) columns it is stored in.
+package money
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Currency is an ISO 4217 currency code. All currencies used by payroll have two
+// decimal places.
+type Currency string
+
+// DefaultCurrency is used when no payroll currency is configured.
+const DefaultCurrency Currency = "ZMW"
+
+// Money is an amount in minor units (hundredths of the currency unit).
+type Money int64
+
+// Zero is the zero amount.
+const Zero Money = 0
+
+const (
+	percentScale  = 1_000_000 // Percent rate units per 100%: 4 decimal places of a percent
+	quantityScale = 1_000_000 // Mul/Div quantity units per 1: 6 decimal places
+)
+
+var errOutOfRange = errors.New("money: amount out of range")
+
+// FromCents returns an amount of c minor units.
+func FromCents(c int64) Money {
+	return Money(c)
+}
+
+// Major returns a whole amount of currency units, e.g. Major(4800) is 4800.00.
+func Major(units int64) Money {
+	return Money(units * 100)
+}
+
+// FromFloat converts a float to the nearest cent, halves away from zero.
+func FromFloat(f float64) Money {
+	return Money(math.Round(f * 100))
+}
+
+// Parse reads a decimal string such as "1234.5", "-0.75" or "1e3". More than two
+// decimal places are rounded to the nearest cent, halves away from zero.
+func Parse(s string) (Money, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, errors.New("money: empty amount")
+	}
+	if strings.ContainsAny(s, "eE") {
+		f, err := strconv.ParseFloat(s, 64)
+		if errors.Is(err, strconv.ErrRange) {
+			return 0, errOutOfRange
+		}
+		if err != nil {
+			return 0, fmt.Errorf("money: invalid amount %q", s)
+		}
+		// float64(math.MaxInt64) is 2^63, the first value int64 cannot hold
+		cents := math.Round(f * 100)
+		if math.IsNaN(cents) || cents >= math.MaxInt64 || cents < math.MinInt64 {
+			return 0, errOutOfRange
+		}
+		return Money(cents), nil
+	}
+
+	in := s
+	neg := false
+	switch s[0] {
+	case '-':
+		neg = true
+		s = s[1:]
+	case '+':
+		s = s[1:]
+	}
+	intPart, frac, _ := strings.Cut(s, ".")
+	// Only one sign is allowed, and there must be at least one digit
+	if intPart == "" && frac == "" || strings.HasPrefix(intPart, "-") || strings.HasPrefix(intPart, "+") {
+		return 0, fmt.Errorf("money: invalid amount %q", in)
+	}
+	if intPart == "" {
+		intPart = "0"
+	}
+	units, err := strconv.ParseInt(intPart, 10, 64)
+	if errors.Is(err, strconv.ErrRange) {
+		return 0, errOutOfRange
+	}
+	if err != nil {
+		return 0, fmt.Errorf("money: invalid amount %q", in)
+	}
+	// Leave room for the cents and the rounding of a third decimal place
+	if units > (math.MaxInt64-100)/100 {
+		return 0, errOutOfRange
+	}
+	for _, c := range frac {
+		if c < '0' || c > '9' {
+			return 0, fmt.Errorf("money: invalid amount %q", in)
+		}
+	}
+
+	frac += "00"
+	cents := units*100 + int64(frac[0]-'0')*10 + int64(frac[1]-'0')
+	if len(frac) > 2 && frac[2] >= '5' {
+		cents++
+	}
+	if neg {
+		cents = -cents
+	}
+	return Money(cents), nil
+}
+
+// MustParse is like Parse but panics on error. It is intended for constants.
+func MustParse(s string) Money {
+	m, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// Cents returns the amount in minor units.
+func (m Money) Cents() int64 {
+	return int64(m)
+}
+
+// Float64 returns the amount in currency units. Use it only for ratios and display,
+// never to accumulate totals.
+func (m Money) Float64() float64 {
+	return float64(m) / 100
+}
+
+// IsZero reports whether the amount is zero.
+func (m Money) IsZero() bool {
+	return m == 0
+}
+
+// Abs returns the absolute amount.
+func (m Money) Abs() Money {
+	if m < 0 {
+		return -m
+	}
+	return m
+}
+
+// Percent returns pct percent of m, e.g. m.Percent(20) is a fifth of m.
+func (m Money) Percent(pct float64) Money {
+	rate := int64(math.Round(pct * (percentScale / 100)))
+	return Money(divRound(int64(m)*rate, percentScale))
+}
+
+// Mul multiplies m by a quantity such as hours or a rate multiplier.
+func (m Money) Mul(q float64) Money {
+	scaled := int64(math.Round(q * quantityScale))
+	return Money(divRound(int64(m)*scaled, quantityScale))
+}
+
+// Div divides m by a quantity such as the number of hours in a month. Dividing by
+// zero returns zero.
+func (m Money) Div(q float64) Money {
+	scaled := int64(math.Round(q * quantityScale))
+	if scaled == 0 {
+		return 0
+	}
+	a := int64(m) * quantityScale
+	if scaled < 0 {
+		a, scaled = -a, -scaled
+	}
+	return Money(divRound(a, scaled))
+}
+
+// Max returns the larger of m and o.
+func (m Money) Max(o Money) Money {
+	if m > o {
+		return m
+	}
+	return o
+}
+
+// Min returns the smaller of m and o.
+func (m Money) Min(o Money) Money {
+	if m < o {
+		return m
+	}
+	return o
+}
+
+// String formats the amount with two decimals and no separators, e.g. -1234.50.
+func (m Money) String() string {
+	sign := ""
+	c := int64(m)
+	if c < 0 {
+		sign = "-"
+		c = -c
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, c/100, c%100)
+}
+
+// Format formats the amount with thousands separators, e.g. 12,345.60.
+func (m Money) Format() string {
+	s := m.String()
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+	intPart, frac := s[:len(s)-3], s[len(s)-3:]
+	var b strings.Builder
+	for i, c := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(c)
+	}
+	return sign + b.String() + frac
+}
+
+// MarshalJSON encodes the amount as a JSON number with two decimals.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON accepts a JSON number or a numeric string.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	v, err := Parse(strings.Trim(s, `"`))
+	if err != nil {
+		return err
+	}
+	*m = v
+	return nil
+}
+
+// Value stores the amount as a decimal string for NUMERIC columns.
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+// Scan reads a NUMERIC column. NULL scans as zero; use sql.Null[Money] where NULL
+// must be told apart from zero.
+func (m *Money) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*m = 0
+	case []byte:
+		return m.scanString(string(v))
+	case string:
+		return m.scanString(v)
+	case int64:
+		*m = Major(v)
+	case float64:
+		*m = FromFloat(v)
+	default:
+		return fmt.Errorf("money: cannot scan %T", src)
+	}
+	return nil
+}
+
+func (m *Money) scanString(s string) error {
+	v, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*m = v
+	return nil
+}
+
+// Sum adds amounts without intermediate rounding.
+func Sum(amounts ...Money) Money {
+	var total Money
+	for _, a := range amounts {
+		total += a
+	}
+	return total
+}
+
+// divRound divides a by b (b > 0), rounding halves away from zero.
+func divRound(a, b int64) int64 {
+	q, r := a/b, a%b
+	if r < 0 {
+		r = -r
+	}
+	if 2*r >= b {
+		if a < 0 {
+			q--
+		} else {
+			q++
+		}
+	}
+	return q
+}
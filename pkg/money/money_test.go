@@ -0,0 +1,235 @@
+package money
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestFromFloatRoundsHalvesAwayFromZero(t *testing.T) {
+	tests := []struct {
+		in   float64
+		want Money
+	}{
+		{0, 0},
+		{2.345, 235},
+		{-2.345, -235},
+		{0.004, 0},
+		{-0.004, 0},
+		{0.005, 1},
+		{-0.005, -1},
+		{1234.5, 123450},
+	}
+	for _, tt := range tests {
+		if got := FromFloat(tt.in); got != tt.want {
+			t.Errorf("FromFloat(%v) = %d cents, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestPercent(t *testing.T) {
+	tests := []struct {
+		m    Money
+		pct  float64
+		want Money
+	}{
+		{MustParse("1000.00"), 20, MustParse("200.00")},
+		{MustParse("0.05"), 50, MustParse("0.03")},   // 2.5 cents rounds up
+		{MustParse("-0.05"), 50, MustParse("-0.03")}, // and away from zero when negative
+		{MustParse("0.03"), 50, MustParse("0.02")},
+		{MustParse("1234.56"), 12.5, MustParse("154.32")},
+		{MustParse("100.00"), 0.0001, MustParse("0.00")},
+		{MustParse("5000.00"), 0.0001, MustParse("0.01")}, // 0.5 cents rounds up
+		{MustParse("100.00"), 0, 0},
+		{MustParse("100.00"), -10, MustParse("-10.00")},
+	}
+	for _, tt := range tests {
+		if got := tt.m.Percent(tt.pct); got != tt.want {
+			t.Errorf("%s.Percent(%v) = %s, want %s", tt.m, tt.pct, got, tt.want)
+		}
+	}
+}
+
+func TestMul(t *testing.T) {
+	tests := []struct {
+		m    Money
+		q    float64
+		want Money
+	}{
+		{MustParse("10.00"), 1.5, MustParse("15.00")},
+		{MustParse("0.01"), 0.5, MustParse("0.01")},
+		{MustParse("-0.01"), 0.5, MustParse("-0.01")},
+		{MustParse("0.01"), 0.49, 0},
+		{MustParse("33.33"), 3, MustParse("99.99")},
+		{MustParse("123.45"), 0, 0},
+		{MustParse("100.00"), -2, MustParse("-200.00")},
+	}
+	for _, tt := range tests {
+		if got := tt.m.Mul(tt.q); got != tt.want {
+			t.Errorf("%s.Mul(%v) = %s, want %s", tt.m, tt.q, got, tt.want)
+		}
+	}
+}
+
+func TestDiv(t *testing.T) {
+	tests := []struct {
+		m    Money
+		q    float64
+		want Money
+	}{
+		{MustParse("100.00"), 3, MustParse("33.33")},
+		{MustParse("200.00"), 3, MustParse("66.67")},
+		{MustParse("-200.00"), 3, MustParse("-66.67")},
+		{MustParse("200.00"), -3, MustParse("-66.67")},
+		{MustParse("0.05"), 2, MustParse("0.03")},
+		{MustParse("-0.05"), 2, MustParse("-0.03")},
+		{MustParse("100.00"), 0, 0},
+		{MustParse("4800.00"), 173.33, MustParse("27.69")},
+	}
+	for _, tt := range tests {
+		if got := tt.m.Div(tt.q); got != tt.want {
+			t.Errorf("%s.Div(%v) = %s, want %s", tt.m, tt.q, got, tt.want)
+		}
+	}
+}
+
+func TestSumDoesNotRound(t *testing.T) {
+	third := MustParse("100.00").Div(3)
+	if got, want := Sum(third, third, third), MustParse("99.99"); got != want {
+		t.Errorf("Sum of thirds = %s, want %s", got, want)
+	}
+	if got := Sum(); got != 0 {
+		t.Errorf("Sum() = %s, want 0.00", got)
+	}
+	if got, want := Sum(MustParse("10.10"), MustParse("-0.10"), MustParse("0.01")), MustParse("10.01"); got != want {
+		t.Errorf("Sum = %s, want %s", got, want)
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Money
+	}{
+		{"0", 0},
+		{"1234.5", 123450},
+		{"1234.50", 123450},
+		{" 12 ", 1200},
+		{"-0.75", -75},
+		{"+0.75", 75},
+		{".5", 50},
+		{"-.5", -50},
+		{"1.", 100},
+		{"2.345", 235},
+		{"-2.345", -235},
+		{"2.344999", 234},
+		{"1e3", 100000},
+		{"-1.5E2", -15000},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.in)
+		if err != nil {
+			t.Errorf("Parse(%q) returned error: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Parse(%q) = %d cents, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseRejectsMalformedInput(t *testing.T) {
+	for _, in := range []string{
+		"", " ", ".", "-", "+", "-.", "--5", "+-5", "-+5", "++5",
+		"1.2.3", "1,000.00", "abc", "12a", "1.-5", "1.+5", "e5",
+	} {
+		if got, err := Parse(in); err == nil {
+			t.Errorf("Parse(%q) = %s, want an error", in, got)
+		}
+	}
+}
+
+func TestParseRejectsAmountsOutOfRange(t *testing.T) {
+	for _, in := range []string{
+		"1e20", "-1e20", "1e400", "-1e400", "9.3e16",
+		"92233720368547758", "-92233720368547758", "99999999999999999999",
+	} {
+		got, err := Parse(in)
+		if err == nil {
+			t.Errorf("Parse(%q) = %s, want an error", in, got)
+			continue
+		}
+		if err.Error() != "money: amount out of range" {
+			t.Errorf("Parse(%q) error = %q, want \"money: amount out of range\"", in, err)
+		}
+	}
+
+	// The largest amounts still parse
+	for _, in := range []string{"92233720368547757.07", "-92233720368547757.07", "9e16"} {
+		if _, err := Parse(in); err != nil {
+			t.Errorf("Parse(%q) returned error: %v", in, err)
+		}
+	}
+}
+
+func TestStringParseRoundTrip(t *testing.T) {
+	for _, cents := range []int64{0, 1, -1, 9, 10, 99, 100, -100, 123450, -123456, 100000000} {
+		m := FromCents(cents)
+		back, err := Parse(m.String())
+		if err != nil {
+			t.Errorf("Parse(%q) returned error: %v", m.String(), err)
+			continue
+		}
+		if back != m {
+			t.Errorf("round trip of %d cents via %q gave %d", cents, m.String(), back)
+		}
+	}
+}
+
+func TestStringAndFormat(t *testing.T) {
+	tests := []struct {
+		m      Money
+		str    string
+		format string
+	}{
+		{0, "0.00", "0.00"},
+		{5, "0.05", "0.05"},
+		{-5, "-0.05", "-0.05"},
+		{123450, "1234.50", "1,234.50"},
+		{-1234567, "-12345.67", "-12,345.67"},
+		{100000000, "1000000.00", "1,000,000.00"},
+	}
+	for _, tt := range tests {
+		if got := tt.m.String(); got != tt.str {
+			t.Errorf("String(%d) = %q, want %q", int64(tt.m), got, tt.str)
+		}
+		if got := tt.m.Format(); got != tt.format {
+			t.Errorf("Format(%d) = %q, want %q", int64(tt.m), got, tt.format)
+		}
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	in := struct {
+		Amount Money `json:"amount"`
+	}{Amount: MustParse("-1234.5")}
+	data, err := json.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"amount":-1234.50}` {
+		t.Errorf("Marshal = %s", data)
+	}
+
+	var out struct {
+		Amount Money `json:"amount"`
+	}
+	if err := json.Unmarshal([]byte(`{"amount":"99.995"}`), &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Amount != MustParse("100.00") {
+		t.Errorf("Unmarshal of \"99.995\" = %s, want 100.00", out.Amount)
+	}
+	if err := json.Unmarshal([]byte(`{"amount":"--5"}`), &out); err == nil {
+		t.Error("Unmarshal of \"--5\" succeeded, want an error")
+	}
+}
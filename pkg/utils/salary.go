@@ -1,31 +1,40 @@
 package utils
 
-import "math"
+import "hr-system/pkg/money"
 
-// Allowance percentages (proportion of base salary)
+// Allowance percentages (percent of base salary)
 const (
-	HousingAllowancePct   = 0.20  // 20% of base salary
-	TransportAllowancePct = 0.10  // 10% of base salary
-	MedicalAllowancePct   = 0.08  // 8% of base salary
-	LeaveDayRate          = 100.0 // fixed amount per unused leave day
+	HousingAllowancePct   = 20.0 // 20% of base salary
+	TransportAllowancePct = 10.0 // 10% of base salary
+	MedicalAllowancePct   = 8.0  // 8% of base salary
+)
+
+// WorkingDaysPerYear is used to turn a monthly salary into a daily rate
+const WorkingDaysPerYear = 260
+
+// PAYE band thresholds
+var (
+	payeBand1Limit = money.Major(4800)
+	payeBand2Limit = money.Major(6800)
 )
 
 // SalaryBreakdown holds the calculated salary components
 type SalaryBreakdown struct {
-	BaseSalary         float64
-	HousingAllowance   float64
-	TransportAllowance float64
-	MedicalAllowance   float64
-	GrossSalary        float64
-	IncomeTax          float64
-	NetSalary          float64
+	BaseSalary         money.Money
+	HousingAllowance   money.Money
+	TransportAllowance money.Money
+	MedicalAllowance   money.Money
+	GrossSalary        money.Money
+	IncomeTax          money.Money
+	NetSalary          money.Money
 }
 
-// CalculateSalaryBreakdown computes all salary components from a base salary
-func CalculateSalaryBreakdown(baseSalary float64) SalaryBreakdown {
-	housing := round(baseSalary * HousingAllowancePct)
-	transport := round(baseSalary * TransportAllowancePct)
-	medical := round(baseSalary * MedicalAllowancePct)
+// CalculateSalaryBreakdown computes all salary components from a base salary.
+// Each allowance is rounded to the cent on its own; gross is their exact sum.
+func CalculateSalaryBreakdown(baseSalary money.Money) SalaryBreakdown {
+	housing := baseSalary.Percent(HousingAllowancePct)
+	transport := baseSalary.Percent(TransportAllowancePct)
+	medical := baseSalary.Percent(MedicalAllowancePct)
 	gross := baseSalary + housing + transport + medical
 	tax := CalculatePAYE(gross)
 
@@ -45,20 +54,28 @@ func CalculateSalaryBreakdown(baseSalary float64) SalaryBreakdown {
 //	Band 1: 0 – 4,800       → 0%
 //	Band 2: 4,801 – 6,800   → 20%
 //	Band 3: 6,801+           → 30%
-func CalculatePAYE(grossIncome float64) float64 {
-	var tax float64
+//
+// Each band's tax is rounded to the cent before the bands are added.
+func CalculatePAYE(grossIncome money.Money) money.Money {
 	switch {
-	case grossIncome <= 4800:
-		tax = 0
-	case grossIncome <= 6800:
-		tax = (grossIncome - 4800) * 0.20
+	case grossIncome <= payeBand1Limit:
+		return money.Zero
+	case grossIncome <= payeBand2Limit:
+		return (grossIncome - payeBand1Limit).Percent(20)
 	default:
-		tax = (2000 * 0.20) + (grossIncome-6800)*0.30
+		return (payeBand2Limit - payeBand1Limit).Percent(20) + (grossIncome - payeBand2Limit).Percent(30)
+	}
+}
+
+// OvertimeHourlyRate derives the hourly rate from a monthly base salary
+func OvertimeHourlyRate(baseSalary money.Money, standardMonthlyHours float64) money.Money {
+	if standardMonthlyHours <= 0 {
+		return money.Zero
 	}
-	return round(tax)
+	return baseSalary.Div(standardMonthlyHours)
 }
 
-// round2 rounds to 2 decimal places
-func round(v float64) float64 {
-	return math.Round(v*100) / 100
+// DailyRate derives the rate for one working day from a monthly amount
+func DailyRate(monthly money.Money) money.Money {
+	return monthly.Mul(12).Div(WorkingDaysPerYear)
 }